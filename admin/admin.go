@@ -11,6 +11,9 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,6 +33,14 @@ var (
 
 	errorWaitingForAcks    = errors.New("waiting for acks")
 	errorInvalidAdminState = errors.New("invalid admin state")
+	errorNotEnoughPlayers  = errors.New("not enough players")
+	errorStaleDecisions    = errors.New(messages.StaleTurnError)
+
+	errorNotWaitingForDecisions = errors.New(messages.NotWaitingForDecisionsError)
+	errorDrawDeckTopNotDrawn    = errors.New("shown the top of the draw deck without drawing it")
+	errorDecidedOnTimeout       = errors.New("only the admin decides on timeout")
+
+	errorIllegalStateTransition = errors.New("illegal admin state transition")
 )
 
 type AdminState string
@@ -46,6 +57,29 @@ const (
 	HaveWinner                        AdminState = "have_winner"
 )
 
+// States the admin can go to from each state. Restart resets the admin to
+// AddingPlayers from any state and is not listed here. CardsServed and
+// WaitingForChallengePlayerDecision are not entered yet. Redeal goes back to
+// ReadyToServeCards while waiting for a decision.
+var allowedAdminTransitions = map[AdminState][]AdminState{
+	AddingPlayers:             {ReadyToServeCards},
+	ReadyToServeCards:         {WaitingForPlayerDecision},
+	PlayerChosenForTurn:       {WaitingForPlayerDecision},
+	WaitingForPlayerDecision:  {SyncingPlayerDecision, ReadyToServeCards},
+	SyncingPlayerDecision:     {DoneSyncingPlayerDecision},
+	DoneSyncingPlayerDecision: {PlayerChosenForTurn, HaveWinner},
+	HaveWinner:                {ReadyToServeCards},
+}
+
+func isAllowedAdminTransition(from, to AdminState) bool {
+	for _, state := range allowedAdminTransitions[from] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
 func makeAckIdConnectedPlayer(ackerPlayer, connectedPlayer string) string {
 	return fmt.Sprintf("%s_connected_to_%s", ackerPlayer, connectedPlayer)
 }
@@ -58,8 +92,6 @@ func makeAckIdWaitingForPlayerDecision(ackerPlayer string, decisionCounter int)
 	return fmt.Sprintf("waiting_for_decision.%s.%d", ackerPlayer, decisionCounter)
 }
 
-const pauseBeforeChoosingPlayer = 2 * time.Second
-
 type Admin struct {
 	table      *uknow.Table
 	stateMutex sync.Mutex
@@ -73,21 +105,65 @@ type Admin struct {
 
 	sseWriterForPlayer map[string]sseWriter
 
+	// Streams of the clients watching the game without being dealt in. They
+	// receive the same events as players, with every hand redacted, and are
+	// never asked for acks.
+	sseWriterForSpectator map[string]sseWriter
+
 	shuffler                string
 	readyPlayerName         string
+	roomCode                string
 	httpServer              *http.Server
 	logger                  *log.Logger
 	decisionEventsCompleted int
 
+	// Token issued to each player on first joining, which the player presents
+	// when reconnecting.
+	sessionTokenOfPlayer map[string]string
+
+	// Counter of the last decisions request accepted from each player. Used
+	// to drop retried requests that were already applied.
+	lastDecisionEventCounterOfPlayer map[string]int
+
+	// Number of games won by each player since the admin started, or since
+	// the last rematch that reset the scores.
+	winCountOfPlayer map[string]int
+
+	// Set once the player of the turn is shown the top of the draw deck, in
+	// which case the player's decisions must draw it. Cleared on each new
+	// turn.
+	drawDeckTopRevealed bool
+
+	// Set by ForceNextPlayer along with the decision counter of the turn it
+	// started, so that runNewTurn doesn't start that turn again.
+	forcedTurn                bool
+	forcedTurnDecisionCounter int
+
+	// Seed of the current game's shuffle and its commitment, empty if the
+	// table was not shuffled by the admin, e.g. with a debug starting hand.
+	shuffleSeed           int64
+	shuffleSeedCommitment string
+
+	// Record of the game in progress and its file, nil unless
+	// record_games_dir is set. See startGameRecordNoLock.
+	gameRecord     *uknow.GameRecordWriter
+	gameRecordFile *os.File
+
 	expectedAcksList *expectedAcksList
+	clock            Clock
 	rl               *readline.Instance
 
 	sseControllerEventChan chan sseEvent
 	sseControllerStopChan  chan struct{}
+	// Closes sseControllerStopChan, Shutdown can be called more than once.
+	stopSSEControllerOnce sync.Once
 }
 
 type sseWriter struct {
 	responseWriter http.ResponseWriter
+
+	// Closing this lets the handler that created the SSE stream return.
+	notifyControllerExit chan<- struct{}
 }
 
 func (w *sseWriter) writeEventMessage(ctx context.Context, event messages.ServerEvent) error {
@@ -115,12 +191,30 @@ type sseEvent interface {
 
 type sseCommandSyncPlayerJoinedEventToAll struct {
 	NewPlayerName        string
+	SessionToken         string
 	ResponseWriter       http.ResponseWriter
 	NotifyControllerExit chan<- struct{}
 }
 
 func (sseCommandSyncPlayerJoinedEventToAll) IsSseEvent() {}
 
+type sseCommandReconnectPlayer struct {
+	PlayerName           string
+	SessionToken         string
+	ResponseWriter       http.ResponseWriter
+	NotifyControllerExit chan<- struct{}
+}
+
+func (sseCommandReconnectPlayer) IsSseEvent() {}
+
+type sseCommandAddSpectator struct {
+	SpectatorName        string
+	ResponseWriter       http.ResponseWriter
+	NotifyControllerExit chan<- struct{}
+}
+
+func (sseCommandAddSpectator) IsSseEvent() {}
+
 type sseCommandSendServedCardsEventToAll struct {
 	Table uknow.Table
 }
@@ -134,34 +228,89 @@ func (sseCommandSendChosenPlayerEventToAll) IsSseEvent() {}
 
 type sseCommandSyncPlayerDecisionEvent struct {
 	messages.PlayerDecisionsRequest
+
+	// Set when the admin decided for the player, who then has to take the
+	// decisions and ack them like every other player.
+	DecidedByAdmin bool
 }
 
 func (sseCommandSyncPlayerDecisionEvent) IsSseEvent() {}
 
+type sseCommandSendRematchEventToAll struct {
+}
+
+func (sseCommandSendRematchEventToAll) IsSseEvent() {}
+
+type sseCommandSendRedealEventToAll struct {
+}
+
+func (sseCommandSendRedealEventToAll) IsSseEvent() {}
+
+type sseCommandSendHeartbeatToAll struct {
+}
+
+func (sseCommandSendHeartbeatToAll) IsSseEvent() {}
+
+type sseCommandSendHouseRulesChangedEventToAll struct {
+	HouseRules uknow.HouseRules
+}
+
+func (sseCommandSendHouseRulesChangedEventToAll) IsSseEvent() {}
+
 type ConfigNewAdmin struct {
 	ListenAddr      utils.HostPortProtocol
 	Table           *uknow.Table
 	ReadyPlayerName string
 	aesCipher       *uknow.AESCipher
+
+	// Used for the ack timeouts and the pauses between turns. Defaults to the
+	// real clock.
+	Clock Clock
+
+	// Code of the room whose game the admin runs, if it's hosted by a Server.
+	// Players must join with the same code. Empty for a single-game admin.
+	RoomCode string
+
+	// Set by tests that receive from sseControllerEventChan themselves.
+	skipSSEController bool
 }
 
 const logFilePrefix = "admin"
 
+// Name of the admin's log file, one per room.
+func logFileNameOfRoom(roomCode string) string {
+	if roomCode == "" {
+		return logFilePrefix
+	}
+	return logFilePrefix + "-room-" + roomCode
+}
+
 func NewAdmin(config *ConfigNewAdmin, userConfig *AdminUserConfig) *Admin {
-	logger := uknow.CreateFileLogger(false, logFilePrefix)
+	logger := newAdminFileLogger(userConfig, logFileNameOfRoom(config.RoomCode))
+	clock := config.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
 	admin := &Admin{
 		table:                  config.Table,
 		userConfig:             userConfig,
 		listenAddrOfPlayer:     make(map[string]utils.HostPortProtocol),
 		sseWriterForPlayer:     make(map[string]sseWriter),
+		sessionTokenOfPlayer:   make(map[string]string),
+		sseWriterForSpectator:  make(map[string]sseWriter),
 		shuffler:               "",
 		aesCipher:              config.aesCipher,
 		state:                  AddingPlayers,
-		expectedAcksList:       newExpectedAcksState(logger),
+		expectedAcksList:       newExpectedAcksState(logger, clock),
+		clock:                  clock,
 		logger:                 logger,
 		readyPlayerName:        config.ReadyPlayerName,
+		roomCode:               config.RoomCode,
 		sseControllerEventChan: make(chan sseEvent),
 		sseControllerStopChan:  make(chan struct{}),
+
+		lastDecisionEventCounterOfPlayer: make(map[string]int),
+		winCountOfPlayer:                 make(map[string]int),
 	}
 
 	r := admin.setRouterHandlers()
@@ -173,13 +322,17 @@ func NewAdmin(config *ConfigNewAdmin, userConfig *AdminUserConfig) *Admin {
 		// TODO: Experiment with low-ish write timeouts - we're writing
 		// text/event-stream so the admin should have fairly long write timeouts
 		// (the duration of a game basically). Read timeouts however can be short as
-		// admin itself doesn't read any stream message.
+		// admin itself doesn't read any stream message. The same timeouts apply
+		// when serving https.
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Minute,
 		IdleTimeout:  10 * time.Minute,
 	}
 
-	go admin.runSSEController()
+	if !config.skipSSEController {
+		go admin.runSSEController()
+		go admin.runHeartbeats()
+	}
 
 	return admin
 }
@@ -187,11 +340,23 @@ func NewAdmin(config *ConfigNewAdmin, userConfig *AdminUserConfig) *Admin {
 func (admin *Admin) setRouterHandlers() *mux.Router {
 	r := mux.NewRouter()
 	r.Path("/player").Methods("POST").HandlerFunc(admin.handleAddNewPlayerAndCreateSSE)
+	r.Path("/spectate").Methods("POST").HandlerFunc(admin.handleAddSpectatorAndCreateSSE)
 	r.Path("/ack_player_added").Methods("POST").HandlerFunc(admin.handleAckNewPlayerAdded)
 	r.Path("/set_ready").Methods("POST").HandlerFunc(admin.handleSetReady)
 	r.Path("/player_decisions").Methods("POST").HandlerFunc(admin.handlePlayerDecisionsEvent)
 	r.Path("/ack-decision-sync").Methods("POST").HandlerFunc(admin.handleAckPlayerDecisionSynced)
+	r.Path("/counts").Methods("GET").HandlerFunc(admin.handleGetCounts)
+	r.Path("/game_state").Methods("GET").HandlerFunc(admin.handleGetGameState)
+	r.Path("/turn_context").Methods("GET").HandlerFunc(admin.handleGetTurnContext)
+	r.Path("/draw_deck_top").Methods("GET").HandlerFunc(admin.handleGetDrawDeckTop)
+	r.Path("/seed_commitment").Methods("GET").HandlerFunc(admin.handleGetSeedCommitment)
+	r.Path("/rules").Methods("GET").HandlerFunc(admin.handleGetHouseRules)
+	r.Path("/rules").Methods("POST").HandlerFunc(admin.handleSetHouseRules)
 	r.Path("/test_command").Methods("POST")
+	if admin.userConfig.WebSocket {
+		r.Path("/ws").Methods("GET").HandlerFunc(admin.handleWebSocket)
+	}
+	r.Use(messages.RecoverPanics(admin.logger))
 	utils.RoutesSummary(r, admin.logger)
 	return r
 }
@@ -200,29 +365,248 @@ func (admin *Admin) Restart() {
 	admin.stateMutex.Lock()
 	defer admin.stateMutex.Unlock()
 
+	admin.finishGameRecordNoLock("")
 	admin.table = createStartingTable(admin.userConfig)
 
-	admin.logger = uknow.CreateFileLogger(false, logFilePrefix)
+	admin.logger = newAdminFileLogger(admin.userConfig, logFileNameOfRoom(admin.roomCode))
 
 	admin.listenAddrOfPlayer = make(map[string]utils.HostPortProtocol)
+	admin.sessionTokenOfPlayer = make(map[string]string)
 	admin.shuffler = ""
+	admin.shuffleSeedCommitment = ""
+	admin.decisionEventsCompleted = 0
+	admin.lastDecisionEventCounterOfPlayer = make(map[string]int)
+	admin.forcedTurn = false
+	admin.drawDeckTopRevealed = false
 	admin.state = AddingPlayers
-	admin.expectedAcksList = newExpectedAcksState(admin.logger)
+	admin.expectedAcksList = newExpectedAcksState(admin.logger, admin.clock)
 
 	log.Print("Admin restarted...")
 }
 
+// Starts a new game with the currently connected players once the current game
+// has a winner. The win counts are kept unless resetScores is set.
+func (admin *Admin) Rematch(resetScores bool) error {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	if admin.state != HaveWinner {
+		return fmt.Errorf("rematch: %w: %s", errorInvalidAdminState, admin.state)
+	}
+
+	table, err := admin.dealNewTableNoLock()
+	if err != nil {
+		return fmt.Errorf("rematch: %w", err)
+	}
+
+	if resetScores {
+		admin.winCountOfPlayer = make(map[string]int)
+	}
+
+	admin.table = table
+	admin.setStateChecked(ReadyToServeCards)
+	admin.startGameRecordNoLock()
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandSendRematchEventToAll{}
+	}()
+	return nil
+}
+
+// Debugging aid that deals new hands from a full deck to the same players,
+// without them having to join again. Only allowed while waiting for the
+// decision of a turn, i.e. not while decisions are being synced. The player
+// being asked stops being asked, and the decision counter starts over as in a
+// newly served game.
+func (admin *Admin) Redeal() error {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	if admin.state != WaitingForPlayerDecision {
+		return fmt.Errorf("redeal: %w: %s", errorInvalidAdminState, admin.state)
+	}
+
+	table, err := admin.dealNewTableNoLock()
+	if err != nil {
+		return fmt.Errorf("redeal: %w", err)
+	}
+
+	admin.expectedAcksList.dropAll()
+	admin.table = table
+	admin.decisionEventsCompleted = 0
+	admin.lastDecisionEventCounterOfPlayer = make(map[string]int)
+	admin.forcedTurn = false
+	admin.setStateChecked(ReadyToServeCards)
+	admin.startGameRecordNoLock()
+
+	log.Print("Dealt new hands")
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandSendRedealEventToAll{}
+	}()
+	return nil
+}
+
+// Returns a table with new hands dealt from full decks to the players of the
+// current table, keeping the shuffler and the house rules. Caller must hold
+// the stateMutex.
+func (admin *Admin) dealNewTableNoLock() (*uknow.Table, error) {
+	table := uknow.NewAdminTable(admin.table.Logger)
+	table.DrawDeck = uknow.NewFullDeckN(admin.userConfig.DeckCount)
+	for _, playerName := range admin.table.PlayerNames {
+		table.AddPlayer(playerName)
+	}
+	table.ShufflerName = admin.table.ShufflerName
+	table.HouseRules = admin.table.HouseRules
+
+	if err := admin.commitToShuffleSeed(table); err != nil {
+		return nil, err
+	}
+
+	if err := table.ShuffleDeckAndDistribute(admin.userConfig.StartingHandCount); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// Debugging aid that gives the next turn to the given player, bypassing the
+// normal turn progression. Only allowed between turns, i.e. after the
+// decisions of the last turn have been synced and before the next player is
+// chosen.
+func (admin *Admin) ForceNextPlayer(playerName string) error {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	if admin.state != DoneSyncingPlayerDecision || admin.table.TableState == uknow.HaveWinner {
+		return fmt.Errorf("set_next: %w: %s", errorInvalidAdminState, admin.state)
+	}
+
+	if _, ok := admin.table.IndexOfPlayer[playerName]; !ok {
+		return fmt.Errorf("set_next: %w: %s", uknow.ErrUnknownPlayer, playerName)
+	}
+
+	admin.logger.Printf("!!! MANUAL OVERRIDE: set_next changed player of next turn from %s to %s, decisionCounter: %d", admin.table.PlayerOfNextTurn, playerName, admin.decisionEventsCompleted)
+	log.Printf("!!! MANUAL OVERRIDE: next turn is %s's", playerName)
+
+	// The decision counter is not touched, the forced turn takes the place of
+	// the turn that would have been started otherwise.
+	admin.table.PlayerOfNextTurn = playerName
+	admin.forcedTurn = true
+	admin.forcedTurnDecisionCounter = admin.decisionEventsCompleted
+	admin.setStateChecked(PlayerChosenForTurn)
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandSendChosenPlayerEventToAll{}
+	}()
+	return nil
+}
+
+// Seats the joined players in the given order before the game starts.
+func (admin *Admin) SetSeating(order []string) error {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	if admin.state != AddingPlayers {
+		return fmt.Errorf("seat: %w: %s", errorInvalidAdminState, admin.state)
+	}
+
+	if err := admin.table.SetSeating(order); err != nil {
+		return fmt.Errorf("seat: %w", err)
+	}
+
+	admin.logger.Printf("Seating set to %v", admin.table.PlayerNames)
+	return nil
+}
+
+// Replaces the house rules of the game. Only allowed before the game starts,
+// the joined players are told about the new rules.
+func (admin *Admin) SetHouseRules(houseRules uknow.HouseRules) error {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	if admin.state != AddingPlayers {
+		return fmt.Errorf("rules: %w: %s", errorInvalidAdminState, admin.state)
+	}
+
+	admin.table.HouseRules = houseRules
+	admin.logger.Printf("House rules set to: %s", houseRules.String())
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandSendHouseRulesChangedEventToAll{HouseRules: houseRules}
+	}()
+	return nil
+}
+
 func (admin *Admin) RunServer() {
-	admin.logger.Printf("Running admin server at addr: %s", admin.httpServer.Addr)
 	go admin.expectedAcksList.waitForAcks()
-	err := admin.httpServer.ListenAndServe()
+
+	var err error
+	if admin.userConfig.servesTLS() {
+		admin.logger.Printf("Running admin server with https at addr: %s", admin.httpServer.Addr)
+		err = admin.httpServer.ListenAndServeTLS(admin.userConfig.TLSCertFile, admin.userConfig.TLSKeyFile)
+	} else {
+		admin.logger.Printf("Running admin server at addr: %s", admin.httpServer.Addr)
+		err = admin.httpServer.ListenAndServe()
+	}
 
 	admin.updatePromptWithStateInfo()
-	if err != nil {
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("Admin.RunServer() failed: %s", err.Error())
 	}
 }
 
+// Use instead of RunServer to serve the admin's HTTP API with a server of the
+// caller's own, e.g. an httptest.Server. Starts waiting for acks like RunServer
+// and returns the handler to serve.
+func (admin *Admin) RunWithoutServer() http.Handler {
+	go admin.expectedAcksList.waitForAcks()
+	return admin.httpServer.Handler
+}
+
+const shutdownTimeout = 5 * time.Second
+
+// Stops the SSE controller, tells each connected player that the admin is
+// shutting down and closes their SSE streams, then shuts down the http server.
+func (admin *Admin) Shutdown(ctx context.Context) error {
+	admin.logger.Printf("Shutting down admin...")
+
+	admin.stopSSEControllerOnce.Do(func() { close(admin.sseControllerStopChan) })
+
+	admin.stateMutex.Lock()
+	for playerName, writer := range admin.sseWriterForPlayer {
+		if err := writer.writeEventMessage(ctx, messages.ServerShuttingDownEvent{}); err != nil {
+			admin.logger.Printf("failed to send shutting down event to player %s: %v", playerName, err)
+		}
+		if writer.notifyControllerExit != nil {
+			close(writer.notifyControllerExit)
+		}
+	}
+	admin.sseWriterForPlayer = make(map[string]sseWriter)
+
+	for spectatorName, writer := range admin.sseWriterForSpectator {
+		if err := writer.writeEventMessage(ctx, messages.ServerShuttingDownEvent{}); err != nil {
+			admin.logger.Printf("failed to send shutting down event to spectator %s: %v", spectatorName, err)
+		}
+		if writer.notifyControllerExit != nil {
+			close(writer.notifyControllerExit)
+		}
+	}
+	admin.sseWriterForSpectator = make(map[string]sseWriter)
+	admin.finishGameRecordNoLock("")
+	admin.stateMutex.Unlock()
+
+	return admin.httpServer.Shutdown(ctx)
+}
+
+func (admin *Admin) shutdownWithTimeout(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := admin.Shutdown(ctx); err != nil {
+		log.Printf("Admin.Shutdown() failed: %v", err)
+	}
+}
+
 // Increase this timeout before debugging.
 // TODO: Have a config for this timeout
 const allPlayersSyncCommandTimeout = time.Duration(10) * time.Second
@@ -233,13 +617,6 @@ const allPlayersSyncCommandTimeout = time.Duration(10) * time.Second
 // Resp:	AddNewPlayerMessage
 func (admin *Admin) handleAddNewPlayerAndCreateSSE(w http.ResponseWriter, r *http.Request) {
 	admin.logger.Printf("addNewPlayer receeived from %s", r.RemoteAddr)
-	admin.stateMutex.Lock()
-
-	if admin.state != AddingPlayers {
-		fmt.Fprintf(w, "Not accepting new players, currently in state: %s", admin.state)
-		w.WriteHeader(http.StatusForbidden)
-		return
-	}
 
 	var requestMessage messages.AddNewPlayersMessage
 	if err := messages.DecryptAndDecodeJSON(&requestMessage, r.Body, admin.aesCipher); err != nil {
@@ -250,21 +627,57 @@ func (admin *Admin) handleAddNewPlayerAndCreateSSE(w http.ResponseWriter, r *htt
 
 	if len(requestMessage.PlayerNames) == 0 {
 		http.Error(w, fmt.Sprintf("Expected exactly 1 player name, got %d", len(requestMessage.PlayerNames)), http.StatusBadRequest)
+		return
+	}
+
+	if requestMessage.RoomCode != admin.roomCode {
+		http.Error(w, fmt.Sprintf("cannot add new player: room code %q does not match room %q", requestMessage.RoomCode, admin.roomCode), http.StatusNotFound)
+		return
 	}
 
 	joinerPlayerName := requestMessage.PlayerNames[0]
+	if !uknow.IsUserNameAllowed(joinerPlayerName) {
+		http.Error(w, fmt.Sprintf("cannot add new player: name %q is not allowed, expected at most %d alphabet and underscore characters and not a reserved name", joinerPlayerName, uknow.MaxPlayerNameLength), http.StatusBadRequest)
+		return
+	}
+
+	admin.stateMutex.Lock()
+
+	// A name that was issued a token belongs to a player that already joined,
+	// so this is that player reconnecting. Accepted in any state, but only
+	// with the token.
+	if issuedToken, ok := admin.sessionTokenOfPlayer[joinerPlayerName]; ok {
+		admin.stateMutex.Unlock()
+		admin.reconnectPlayer(w, joinerPlayerName, issuedToken, requestMessage.SessionToken)
+		return
+	}
+
+	if admin.state != AddingPlayers {
+		admin.stateMutex.Unlock()
+		http.Error(w, fmt.Sprintf("Not accepting new players, currently in state: %s", admin.state), http.StatusForbidden)
+		return
+	}
+
 	utils.SetSSEResponseHeaders(w)
 
 	// Add the player to the local table. **But don't if it's already added
 	// by hand-reader - in which case check that we have this player in the
 	// table module.**
 	if admin.table.IsShuffled {
-		_, ok := admin.table.HandOfPlayer[joinerPlayerName]
-		if !ok {
+		if _, err := admin.table.Hand(joinerPlayerName); err != nil {
 			admin.logger.Printf("player %s has not been loaded by hand-reader. see the JSON config.", joinerPlayerName)
 			w.WriteHeader(http.StatusUnprocessableEntity)
 		}
 	} else {
+		// AddPlayer rejects exact duplicates, but a "Bob" joining when "bob"
+		// exists would be too confusing.
+		if existingName, ok := admin.table.FindPlayerNameFold(joinerPlayerName); ok && existingName != joinerPlayerName {
+			admin.stateMutex.Unlock()
+			http.Error(w, fmt.Sprintf("cannot add new player %s: collides with existing player %s", joinerPlayerName, existingName), http.StatusConflict)
+			admin.logger.Printf("Cannot add new player %s: collides with existing player %s", joinerPlayerName, existingName)
+			return
+		}
+
 		err := admin.table.AddPlayer(joinerPlayerName)
 		if errors.Is(err, uknow.ErrPlayerAlreadyExists) {
 			w.WriteHeader(http.StatusOK)
@@ -279,21 +692,113 @@ func (admin *Admin) handleAddNewPlayerAndCreateSSE(w http.ResponseWriter, r *htt
 		}
 	}
 
+	sessionToken, err := newSessionToken()
+	if err != nil {
+		admin.stateMutex.Unlock()
+		http.Error(w, fmt.Sprintf("cannot add new player: %s", err), http.StatusInternalServerError)
+		return
+	}
+	admin.sessionTokenOfPlayer[joinerPlayerName] = sessionToken
+
 	// Rest of the work is going to be done in the controller
 	notifyControllerExit := make(chan struct{})
 
 	go func() {
 		admin.sseControllerEventChan <- sseCommandSyncPlayerJoinedEventToAll{
 			NewPlayerName:        joinerPlayerName,
+			SessionToken:         sessionToken,
 			ResponseWriter:       w,
 			NotifyControllerExit: notifyControllerExit,
 		}
 	}()
 
 	admin.stateMutex.Unlock()
-	// Prevent returning from this handler until controller notifies.
-	//
-	// TODO: No notification yet. Just pauses indefinitely.
+	// Prevent returning from this handler until controller notifies. Only
+	// notified on shutdown, or when the player reconnects with a new stream.
+	<-notifyControllerExit
+}
+
+// Replaces the SSE stream of a player that already joined, if the presented
+// token is the one issued to the player. Otherwise anyone could take over the
+// player's hand by joining with the same name.
+func (admin *Admin) reconnectPlayer(w http.ResponseWriter, playerName, issuedToken, presentedToken string) {
+	if !sessionTokenMatches(issuedToken, presentedToken) {
+		admin.logger.Printf("Rejected reconnect of player %s: wrong session token", playerName)
+		http.Error(w, fmt.Sprintf("cannot reconnect as %s: wrong session token", playerName), http.StatusForbidden)
+		return
+	}
+
+	admin.logger.Printf("Player %s reconnected", playerName)
+	utils.SetSSEResponseHeaders(w)
+
+	notifyControllerExit := make(chan struct{})
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandReconnectPlayer{
+			PlayerName:           playerName,
+			SessionToken:         issuedToken,
+			ResponseWriter:       w,
+			NotifyControllerExit: notifyControllerExit,
+		}
+	}()
+
+	<-notifyControllerExit
+}
+
+// Req:		POST /spectate AddNewPlayersMessage (with the spectator's name)
+// Resp:	SSE stream of ExistingPlayersListEvent, then the same events as players
+func (admin *Admin) handleAddSpectatorAndCreateSSE(w http.ResponseWriter, r *http.Request) {
+	admin.logger.Printf("addSpectator received from %s", r.RemoteAddr)
+
+	var requestMessage messages.AddNewPlayersMessage
+	if err := messages.DecryptAndDecodeJSON(&requestMessage, r.Body, admin.aesCipher); err != nil {
+		admin.logger.Printf("failed to decode add spectator request: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(requestMessage.PlayerNames) != 1 {
+		http.Error(w, fmt.Sprintf("Expected exactly 1 spectator name, got %d", len(requestMessage.PlayerNames)), http.StatusBadRequest)
+		return
+	}
+
+	if requestMessage.RoomCode != admin.roomCode {
+		http.Error(w, fmt.Sprintf("cannot spectate: room code %q does not match room %q", requestMessage.RoomCode, admin.roomCode), http.StatusNotFound)
+		return
+	}
+
+	spectatorName := requestMessage.PlayerNames[0]
+	if !uknow.IsUserNameAllowed(spectatorName) {
+		http.Error(w, fmt.Sprintf("cannot spectate: name %q is not allowed, expected at most %d alphabet and underscore characters and not a reserved name", spectatorName, uknow.MaxPlayerNameLength), http.StatusBadRequest)
+		return
+	}
+
+	admin.stateMutex.Lock()
+	if existingName, ok := admin.table.FindPlayerNameFold(spectatorName); ok {
+		admin.stateMutex.Unlock()
+		http.Error(w, fmt.Sprintf("cannot spectate as %s: collides with player %s", spectatorName, existingName), http.StatusConflict)
+		return
+	}
+	if _, ok := admin.sseWriterForSpectator[spectatorName]; ok {
+		admin.stateMutex.Unlock()
+		http.Error(w, fmt.Sprintf("cannot spectate as %s: already spectating", spectatorName), http.StatusConflict)
+		return
+	}
+	admin.stateMutex.Unlock()
+
+	utils.SetSSEResponseHeaders(w)
+
+	notifyControllerExit := make(chan struct{})
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandAddSpectator{
+			SpectatorName:        spectatorName,
+			ResponseWriter:       w,
+			NotifyControllerExit: notifyControllerExit,
+		}
+	}()
+
+	// Same as for players, only notified on shutdown for now.
 	<-notifyControllerExit
 }
 
@@ -338,10 +843,199 @@ func (admin *Admin) handleAckPlayerDecisionSynced(w http.ResponseWriter, r *http
 	w.WriteHeader(http.StatusOK)
 }
 
+// Req: GET /counts
+//
+// Resp: TableCountsMessage
+func (admin *Admin) handleGetCounts(w http.ResponseWriter, r *http.Request) {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	countsMessage := messages.TableCountsMessage{
+		DrawDeck:    admin.table.DrawDeck.Len(),
+		DiscardPile: admin.table.DiscardedPile.Len(),
+		HandCounts:  make(map[string]int, len(admin.table.HandOfPlayer)),
+	}
+
+	for playerName, hand := range admin.table.HandOfPlayer {
+		countsMessage.HandCounts[playerName] = hand.Len()
+	}
+
+	if err := messages.EncodeJSONAndEncrypt(&countsMessage, w, admin.aesCipher); err != nil {
+		admin.logger.Printf("GET /counts error: %s", err)
+	}
+}
+
+// Req: GET /game_state?player=<name>
+//
+// Resp: GameStateMessage, with only the given player's hand revealed if the
+// player is given
+func (admin *Admin) handleGetGameState(w http.ResponseWriter, r *http.Request) {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	gameStateMessage := messages.GameStateMessage{
+		Table: *admin.table,
+	}
+
+	// Players ask for their own view of the table, see Table.RedactedFor.
+	if playerName := r.URL.Query().Get("player"); playerName != "" {
+		gameStateMessage.Table = *admin.table.RedactedFor(playerName)
+	}
+
+	if err := messages.EncodeJSONAndEncrypt(&gameStateMessage, w, admin.aesCipher); err != nil {
+		admin.logger.Printf("GET /game_state error: %s", err)
+	}
+}
+
+// Req: GET /turn_context
+//
+// Resp: TurnContextMessage
+// Resp: Conflict (if the cards have not been served yet)
+func (admin *Admin) handleGetTurnContext(w http.ResponseWriter, r *http.Request) {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	discardTop, err := admin.table.DiscardedPile.Top()
+	if err != nil {
+		http.Error(w, "cards have not been served yet", http.StatusConflict)
+		return
+	}
+
+	turnContextMessage := messages.TurnContextMessage{
+		RequiredColor:  admin.table.RequiredColorOfCurrentTurn,
+		RequiredNumber: admin.table.RequiredNumberOfCurrentTurn,
+		DiscardTop:     discardTop,
+		CurrentPlayer:  admin.table.PlayerOfNextTurn,
+		Direction:      admin.table.Direction,
+	}
+
+	if err := messages.EncodeJSONAndEncrypt(&turnContextMessage, w, admin.aesCipher); err != nil {
+		admin.logger.Printf("GET /turn_context error: %s", err)
+	}
+}
+
+// Req: GET /draw_deck_top?player=<name>
+//
+// Resp: DrawDeckTopMessage
+// Resp: BadRequest (if it's not the player's turn)
+// Resp: Conflict (if the admin is not waiting for decisions or the draw deck is empty)
+func (admin *Admin) handleGetDrawDeckTop(w http.ResponseWriter, r *http.Request) {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	if admin.state != WaitingForPlayerDecision {
+		http.Error(w, errorNotWaitingForDecisions.Error(), http.StatusConflict)
+		return
+	}
+
+	playerName := r.URL.Query().Get("player")
+	if playerName != admin.table.PlayerOfNextTurn {
+		http.Error(w, uknow.ErrNotYourTurn.Error(), http.StatusBadRequest)
+		return
+	}
+
+	drawDeckTop, err := admin.table.DrawDeck.Top()
+	if err != nil {
+		http.Error(w, uknow.ErrDrawDeckIsEmpty.Error(), http.StatusConflict)
+		return
+	}
+
+	admin.drawDeckTopRevealed = true
+	admin.logger.Printf("Revealed the top of the draw deck to %s, decisionCounter: %d", playerName, admin.decisionEventsCompleted)
+
+	drawDeckTopMessage := messages.DrawDeckTopMessage{Card: drawDeckTop}
+	if err := messages.EncodeJSONAndEncrypt(&drawDeckTopMessage, w, admin.aesCipher); err != nil {
+		admin.logger.Printf("GET /draw_deck_top error: %s", err)
+	}
+}
+
+// Seeds the table's shuffle with a new random seed and commits to it. Does not
+// lock stateMutex.
+func (admin *Admin) commitToShuffleSeed(table *uknow.Table) error {
+	seed, err := uknow.NewShuffleSeed()
+	if err != nil {
+		return fmt.Errorf("failed to create shuffle seed: %w", err)
+	}
+
+	table.Rand = uknow.NewSeededRand(seed)
+	admin.shuffleSeed = seed
+	admin.shuffleSeedCommitment = uknow.SeedCommitment(seed)
+	admin.logger.Printf("Committed to shuffle seed: %s", admin.shuffleSeedCommitment)
+	return nil
+}
+
+// Req: GET /seed_commitment
+//
+// Resp: SeedCommitmentMessage, with the seed once the game has a winner
+// Resp: NotFound (if the table was not shuffled by the admin)
+func (admin *Admin) handleGetSeedCommitment(w http.ResponseWriter, r *http.Request) {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	if admin.shuffleSeedCommitment == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	seedCommitmentMessage := messages.SeedCommitmentMessage{
+		Commitment: admin.shuffleSeedCommitment,
+	}
+	if admin.state == HaveWinner {
+		seedCommitmentMessage.Revealed = true
+		seedCommitmentMessage.Seed = admin.shuffleSeed
+	}
+
+	if err := messages.EncodeJSONAndEncrypt(&seedCommitmentMessage, w, admin.aesCipher); err != nil {
+		admin.logger.Printf("GET /seed_commitment error: %s", err)
+	}
+}
+
+// Req: GET /rules
+//
+// Resp: HouseRulesMessage
+func (admin *Admin) handleGetHouseRules(w http.ResponseWriter, r *http.Request) {
+	admin.stateMutex.Lock()
+	houseRulesMessage := messages.HouseRulesMessage{HouseRules: admin.table.HouseRules}
+	admin.stateMutex.Unlock()
+
+	if err := messages.EncodeJSONAndEncrypt(&houseRulesMessage, w, admin.aesCipher); err != nil {
+		admin.logger.Printf("GET /rules error: %s", err)
+	}
+}
+
+// Req: POST /rules HouseRulesMessage
+//
+// Resp: HouseRulesMessage
+// Resp: StatusForbidden, UnwrappedErrorPayload (once the game has started)
+func (admin *Admin) handleSetHouseRules(w http.ResponseWriter, r *http.Request) {
+	var houseRulesMessage messages.HouseRulesMessage
+	if err := messages.DecryptAndDecodeJSON(&houseRulesMessage, r.Body, admin.aesCipher); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		messages.WriteErrorPayload(w, err)
+		return
+	}
+
+	if err := admin.SetHouseRules(houseRulesMessage.HouseRules); err != nil {
+		admin.logger.Printf("handleSetHouseRules: %s", err)
+		w.WriteHeader(http.StatusForbidden)
+
+		errorResponse := messages.UnwrappedErrorPayload{}
+		errorResponse.Add(err)
+		messages.EncodeJSONAndEncrypt(&errorResponse, w, admin.aesCipher)
+		return
+	}
+
+	if err := messages.EncodeJSONAndEncrypt(&houseRulesMessage, w, admin.aesCipher); err != nil {
+		admin.logger.Printf("POST /rules error: %s", err)
+	}
+}
+
 // Req: POST /set_ready SetReadyMessage
 //
 // Resp: StatusForbidden
 // Resp: SeeOther, UnwrappedErrorPayload
+// Resp: BadRequest, UnwrappedErrorPayload (if the starting hand count is invalid)
+// Resp: BadRequest, UnwrappedErrorPayload (if there are fewer players than min_players)
 func (admin *Admin) handleSetReady(w http.ResponseWriter, r *http.Request) {
 	admin.stateMutex.Lock()
 	defer admin.stateMutex.Unlock()
@@ -385,6 +1079,16 @@ func (admin *Admin) handleSetReady(w http.ResponseWriter, r *http.Request) {
 
 	admin.expectedAcksList.mu.Unlock()
 
+	if err := admin.checkEnoughPlayers(); err != nil {
+		admin.logger.Printf("handleSetReady: %s", err)
+		w.WriteHeader(http.StatusBadRequest)
+
+		errorResponse := messages.UnwrappedErrorPayload{}
+		errorResponse.Add(fmt.Errorf("handleSetReady: %w", err))
+		messages.EncodeJSONAndEncrypt(&errorResponse, w, admin.aesCipher)
+		return
+	}
+
 	var setReadyMessage messages.SetReadyMessage
 	err = messages.DecryptAndDecodeJSON(&setReadyMessage, r.Body, admin.aesCipher)
 
@@ -394,18 +1098,46 @@ func (admin *Admin) handleSetReady(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	admin.setState(ReadyToServeCards)
-
 	// shuffle and serve cards. then sync the table state with each player.
+	if !admin.table.IsShuffled {
+		err := admin.table.ValidateStartingHandCount(admin.userConfig.StartingHandCount)
+		if err != nil {
+			admin.logger.Printf("handleSetReady: %s", err)
+			w.WriteHeader(http.StatusBadRequest)
+
+			errorResponse := messages.UnwrappedErrorPayload{}
+			errorResponse.Add(fmt.Errorf("handleSetReady: %w", err))
+			messages.EncodeJSONAndEncrypt(&errorResponse, w, admin.aesCipher)
+			return
+		}
+	}
+
 	admin.table.ShufflerName = setReadyMessage.ShufflerName
 
 	if !admin.table.IsShuffled {
-		admin.table.ShuffleDeckAndDistribute(8)
+		if err := admin.commitToShuffleSeed(admin.table); err != nil {
+			admin.logger.Printf("handleSetReady: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		if err := admin.table.ShuffleDeckAndDistribute(admin.userConfig.StartingHandCount); err != nil {
+			admin.logger.Printf("handleSetReady: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			errorResponse := messages.UnwrappedErrorPayload{}
+			errorResponse.Add(fmt.Errorf("handleSetReady: %w", err))
+			messages.EncodeJSONAndEncrypt(&errorResponse, w, admin.aesCipher)
+			return
+		}
 	}
 
+	admin.setStateChecked(ReadyToServeCards)
+
 	if setReadyMessage.ShufflerIsFirstPlayer {
 		admin.table.PlayerOfNextTurn = admin.table.ShufflerName
 	}
+	admin.startGameRecordNoLock()
 
 	// go admin.sendServeCardsEventToAllPlayers()
 	go func() {
@@ -416,51 +1148,216 @@ func (admin *Admin) handleSetReady(w http.ResponseWriter, r *http.Request) {
 }
 
 // Req: POST /player_decisions_event
+// Resp: OK (also for a retry of decisions that were already accepted)
+// Resp: Conflict, UnwrappedErrorPayload (if the admin is not waiting for
+// decisions, or the decisions are for a turn that is already over)
+// Resp: BadRequest, UnwrappedErrorPayload (if the decisions are rejected)
 func (admin *Admin) handlePlayerDecisionsEvent(w http.ResponseWriter, r *http.Request) {
 	admin.stateMutex.Lock()
 	defer admin.stateMutex.Unlock()
 
-	switch admin.state {
-	case WaitingForPlayerDecision:
-		var event messages.PlayerDecisionsRequest
-		err := messages.DecryptAndDecodeJSON(&event, r.Body, admin.aesCipher)
-		if err != nil {
-			admin.logger.Printf("handlePlayerDecisionsEvent: %s", err)
-			w.WriteHeader(http.StatusBadRequest)
+	var event messages.PlayerDecisionsRequest
+	err := messages.DecryptAndDecodeJSON(&event, r.Body, admin.aesCipher)
+	if err != nil {
+		admin.logger.Printf("handlePlayerDecisionsEvent: %s", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// A client can retry the request if it didn't get the response in time.
+	// Respond OK to it without applying the decisions again, even if the
+	// admin has moved on to syncing them.
+	if lastCounter, ok := admin.lastDecisionEventCounterOfPlayer[event.DecidingPlayer]; ok && event.DecisionEventCounter <= lastCounter && !admin.userConfig.DryRunDecisions {
+		admin.logger.Printf("handlePlayerDecisionsEvent: dropping duplicate decisions from player %s, decisionCounter: %d, last accepted: %d", event.DecidingPlayer, event.DecisionEventCounter, lastCounter)
+		return
+	}
+
+	// DTL(@rk): What happens when the waiting player disconnects? I think anytime we stop receiving heartbeats, we should reset the admin and notify the clients that the admin is resetting.
+	if admin.state != WaitingForPlayerDecision {
+		err := fmt.Errorf("%w: decisions of %s for decision %d, admin state is %s", errorNotWaitingForDecisions, event.DecidingPlayer, event.DecisionEventCounter, admin.state)
+		admin.respondToRejectedDecisions(w, http.StatusConflict, err)
+		return
+	}
+
+	if admin.userConfig.DryRunDecisions {
+		admin.dryRunDecisions(w, event)
+		return
+	}
+
+	// The decisions were made for a turn that is already over, e.g. one that
+	// was moved past by set_next. Applying them would corrupt the table, so
+	// the client has to resync instead.
+	if event.DecisionEventCounter != admin.decisionEventsCompleted {
+		err := fmt.Errorf("%w: decisions of %s are for decision %d, but admin is at decision %d", errorStaleDecisions, event.DecidingPlayer, event.DecisionEventCounter, admin.decisionEventsCompleted)
+		admin.respondToRejectedDecisions(w, http.StatusConflict, err)
+		return
+	}
+
+	// With jump-ins, the player of the turn and a jumping player can race each
+	// other. Only the first to arrive is applied.
+	if otherPlayer, ok := admin.playerWithAcceptedDecisions(event.DecisionEventCounter); ok {
+		err := fmt.Errorf("%w: decisions of %s for decision %d came after the decisions of %s", errorStaleDecisions, event.DecidingPlayer, event.DecisionEventCounter, otherPlayer)
+		admin.respondToRejectedDecisions(w, http.StatusConflict, err)
+		return
+	}
+
+	// The player of the turn is waited on, whoever decided.
+	playerOfTurn := admin.table.PlayerOfNextTurn
+
+	if err := admin.checkDecidingPlayer(event); err != nil {
+		admin.respondToRejectedDecisions(w, http.StatusBadRequest, err)
+		return
+	}
+	if event.DecidingPlayer != playerOfTurn {
+		jumpInCard, _ := uknow.JumpInCard(event.Decisions)
+		admin.logger.Printf("%s jumps in with %s, taking %s's turn", event.DecidingPlayer, jumpInCard.String(), playerOfTurn)
+	} else if admin.drawDeckTopRevealed && !drawsFromDeck(event.Decisions) {
+		err := fmt.Errorf("%w: decisions of %s for decision %d", errorDrawDeckTopNotDrawn, event.DecidingPlayer, event.DecisionEventCounter)
+		admin.respondToRejectedDecisions(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Decisions the admin's table can't apply are rejected before they are
+	// accepted, so that the player can retry with others for the same turn.
+	if err := admin.table.Clone().EvalPlayerDecisionsNoTransferChan(event.DecidingPlayer, event.Decisions); err != nil {
+		admin.respondToRejectedDecisions(w, http.StatusBadRequest, err)
+		return
+	}
+
+	admin.logger.Printf("Received decisions event from player: %s, decisions: %+v, decisionCounter: %d", event.DecidingPlayer, event.Decisions, event.DecisionEventCounter)
+	admin.acceptDecisionsNoLock(event, false)
+}
+
+// Takes the decisions for the current turn and queues them to be synced with
+// the players. Caller must hold the stateMutex.
+func (admin *Admin) acceptDecisionsNoLock(event messages.PlayerDecisionsRequest, decidedByAdmin bool) {
+	admin.lastDecisionEventCounterOfPlayer[event.DecidingPlayer] = event.DecisionEventCounter
+
+	playerOfTurn := admin.table.PlayerOfNextTurn
+	ack := expectedAck{
+		ackId:           makeAckIdWaitingForPlayerDecision(playerOfTurn, event.DecisionEventCounter),
+		ackerPlayerName: playerOfTurn,
+	}
+
+	admin.expectedAcksList.chNewAckReceived <- ack
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandSyncPlayerDecisionEvent{
+			PlayerDecisionsRequest: event,
+			DecidedByAdmin:         decidedByAdmin,
+		}
+	}()
+}
+
+// Decides no_challenge for the player asked to challenge a wild draw 4 if the
+// player's decisions for the turn don't arrive before the challenge timeout.
+// Caller must hold the stateMutex.
+func (admin *Admin) startChallengeTimerNoLock(playerName string, decisionEventCounter int) {
+	timer := admin.clock.NewTimer(admin.userConfig.challengeTimeout())
+
+	go func() {
+		<-timer.Chan()
+
+		admin.stateMutex.Lock()
+		defer admin.stateMutex.Unlock()
+
+		if admin.state != WaitingForPlayerDecision || admin.decisionEventsCompleted != decisionEventCounter || admin.table.TableState != uknow.AwaitingWildDraw4ChallengeDecision {
 			return
 		}
+		if _, ok := admin.playerWithAcceptedDecisions(decisionEventCounter); ok {
+			return
+		}
+
+		admin.logger.Printf("%s did not decide to challenge in time, deciding no_challenge, decisionCounter: %d", playerName, decisionEventCounter)
+		admin.acceptDecisionsNoLock(messages.PlayerDecisionsRequest{
+			Decisions: []uknow.PlayerDecision{{
+				Kind:                uknow.PlayerDecisionDontChallenge,
+				WildCardChosenColor: admin.table.RequiredColorOfCurrentTurn,
+				TimedOut:            true,
+			}},
+			DecidingPlayer:       playerName,
+			DecisionEventCounter: decisionEventCounter,
+		}, true)
+	}()
+}
 
-		admin.logger.Printf("Received decisions event from player: %s, decisions: %+v, decisionCounter: %d", event.DecidingPlayer, event.Decisions, event.DecisionEventCounter)
+// Evaluates the decisions on a copy of the table and responds with the
+// resulting table as seen by the deciding player. The decisions are not synced
+// and the admin's table and state are left as they are.
+//
+// Resp: GameStateMessage
+// Resp: BadRequest, UnwrappedErrorPayload (if the decisions are rejected)
+func (admin *Admin) dryRunDecisions(w http.ResponseWriter, event messages.PlayerDecisionsRequest) {
+	if err := admin.checkDecidingPlayer(event); err != nil {
+		admin.respondToRejectedDecisions(w, http.StatusBadRequest, err)
+		return
+	}
 
-		ack := expectedAck{
-			ackId:           makeAckIdWaitingForPlayerDecision(event.DecidingPlayer, event.DecisionEventCounter),
-			ackerPlayerName: event.DecidingPlayer,
+	table := admin.table.Clone()
+	if err := table.EvalPlayerDecisionsNoTransferChan(event.DecidingPlayer, event.Decisions); err != nil {
+		admin.respondToRejectedDecisions(w, http.StatusBadRequest, err)
+		return
+	}
+
+	admin.logger.Printf("Dry run of decisions from player: %s, decisions: %+v", event.DecidingPlayer, event.Decisions)
+
+	gameStateMessage := messages.GameStateMessage{
+		Table: *table.RedactedFor(event.DecidingPlayer),
+	}
+	if err := messages.EncodeJSONAndEncrypt(&gameStateMessage, w, admin.aesCipher); err != nil {
+		admin.logger.Printf("handlePlayerDecisionsEvent: %s", err)
+	}
+}
+
+// Reports whether any of the decisions draws from the draw deck.
+func drawsFromDeck(decisions []uknow.PlayerDecision) bool {
+	for _, decision := range decisions {
+		if decision.Kind == uknow.PlayerDecisionPullFromDeck {
+			return true
 		}
+	}
+	return false
+}
 
-		if event.DecisionEventCounter != admin.decisionEventsCompleted {
-			admin.logger.Printf("Unexpected decision event counter in ack: %s, but admin decision counter is %d", ack.ackId, admin.decisionEventsCompleted)
+// Returns the player whose decisions were accepted for the given decision
+// counter, if any.
+func (admin *Admin) playerWithAcceptedDecisions(decisionEventCounter int) (string, bool) {
+	for playerName, lastCounter := range admin.lastDecisionEventCounterOfPlayer {
+		if lastCounter == decisionEventCounter {
+			return playerName, true
 		}
+	}
+	return "", false
+}
 
-		admin.expectedAcksList.chNewAckReceived <- ack
+// Returns nil if the deciding player may act on the current turn: the player
+// of the turn, or another player jumping in. Every other decision of the turn,
+// including challenging a wild draw-4, falls to the player of the turn. No
+// player may send decisions made on timeout, only the admin makes those.
+// Caller must hold the stateMutex.
+func (admin *Admin) checkDecidingPlayer(event messages.PlayerDecisionsRequest) error {
+	if uknow.DecidedOnTimeout(event.Decisions) {
+		return fmt.Errorf("%w: decisions of %s", errorDecidedOnTimeout, event.DecidingPlayer)
+	}
 
-		go func() {
-			admin.sseControllerEventChan <- sseCommandSyncPlayerDecisionEvent{
-				PlayerDecisionsRequest: event,
-			}
-		}()
+	playerOfTurn := admin.table.PlayerOfNextTurn
+	if event.DecidingPlayer == playerOfTurn {
+		return nil
+	}
 
-	default:
-		// DTL(@rk): What happens when the waiting player disconnects? I think anytime we stop receiving heartbeats, we should reset the admin and notify the clients that the admin is resetting.
-		if admin.state != WaitingForPlayerDecision {
-			w.WriteHeader(http.StatusSeeOther)
-			err := fmt.Errorf("%w: %s", errorInvalidAdminState, admin.state)
-			admin.logger.Printf("handlePlayerDecisionsEvent: %s", err.Error())
-			errorResponse := messages.UnwrappedErrorPayload{}
-			errorResponse.Add(err)
-			messages.EncodeJSONAndEncrypt(&errorResponse, w, admin.aesCipher)
-			return
-		}
+	jumpInCard, ok := uknow.JumpInCard(event.Decisions)
+	if !ok {
+		return fmt.Errorf("%w: %s, it's %s's turn", uknow.ErrNotYourTurn, event.DecidingPlayer, playerOfTurn)
 	}
+	return admin.table.CanJumpIn(event.DecidingPlayer, jumpInCard)
+}
+
+func (admin *Admin) respondToRejectedDecisions(w http.ResponseWriter, statusCode int, err error) {
+	admin.logger.Printf("handlePlayerDecisionsEvent: %s", err.Error())
+	w.WriteHeader(statusCode)
+	errorResponse := messages.UnwrappedErrorPayload{}
+	errorResponse.Add(err)
+	messages.EncodeJSONAndEncrypt(&errorResponse, w, admin.aesCipher)
 }
 
 func (admin *Admin) runNewTurn() {
@@ -469,14 +1366,24 @@ func (admin *Admin) runNewTurn() {
 		return
 	}
 
-	<-time.After(time.Duration(admin.userConfig.PauseMsecsBeforeNewTurn) * time.Millisecond)
+	<-admin.clock.After(time.Duration(admin.userConfig.PauseMsecsBeforeNewTurn) * time.Millisecond)
 
 	admin.stateMutex.Lock()
 	defer admin.stateMutex.Unlock()
 
+	if admin.forcedTurn && admin.forcedTurnDecisionCounter == admin.decisionEventsCompleted {
+		admin.logger.Printf("startNewTurn: turn of %s was already started by set_next", admin.table.PlayerOfNextTurn)
+		return
+	}
+
 	if admin.table.TableState == uknow.HaveWinner {
 		log.Printf("Have winner: %s", admin.table.WinnerPlayerName)
-		admin.setState(HaveWinner)
+		admin.winCountOfPlayer[admin.table.WinnerPlayerName]++
+		admin.setStateChecked(HaveWinner)
+		admin.finishGameRecordNoLock(admin.table.WinnerPlayerName)
+		if admin.shuffleSeedCommitment != "" {
+			admin.logger.Printf("Revealing shuffle seed %d of commitment %s", admin.shuffleSeed, admin.shuffleSeedCommitment)
+		}
 	} else {
 		admin.logger.Printf("Starting new turn...")
 
@@ -484,7 +1391,7 @@ func (admin *Admin) runNewTurn() {
 			admin.logger.Fatalf("startNewTurn: Unexpected state: %s", admin.state)
 		}
 
-		admin.setState(PlayerChosenForTurn)
+		admin.setStateChecked(PlayerChosenForTurn)
 
 		go func() {
 			admin.sseControllerEventChan <- sseCommandSendChosenPlayerEventToAll{}
@@ -492,17 +1399,40 @@ func (admin *Admin) runNewTurn() {
 	}
 }
 
-func (admin *Admin) runSSEController() {
-	admin.logger.Printf("runSSEController: Starting...")
-
+func (admin *Admin) runSSEController() {
+	admin.logger.Printf("runSSEController: Starting...")
+
+	for {
+		select {
+		case <-admin.sseControllerStopChan:
+			admin.logger.Printf("runSSEController: Stopping...")
+			return
+		case ctlEvent := <-admin.sseControllerEventChan:
+			if _, ok := ctlEvent.(sseCommandSendHeartbeatToAll); !ok {
+				admin.logger.Printf("runSSEController: Received event: %+v, type: %T", ctlEvent, ctlEvent)
+			}
+			admin.dispatchEventWithSSE(ctlEvent)
+		}
+	}
+}
+
+// Queues a heartbeat for the SSE controller every heartbeat interval until the
+// controller is stopped. A heartbeat is late if the controller is busy, e.g.
+// during the pause after serving, which clients must tolerate.
+func (admin *Admin) runHeartbeats() {
 	for {
+		timer := admin.clock.NewTimer(admin.userConfig.heartbeatInterval())
 		select {
 		case <-admin.sseControllerStopChan:
-			admin.logger.Printf("runSSEController: Stopping...")
+			timer.Stop()
 			return
-		case ctlEvent := <-admin.sseControllerEventChan:
-			admin.logger.Printf("runSSEController: Received event: %+v, type: %T", ctlEvent, ctlEvent)
-			admin.dispatchEventWithSSE(ctlEvent)
+		case <-timer.Chan():
+		}
+
+		select {
+		case <-admin.sseControllerStopChan:
+			return
+		case admin.sseControllerEventChan <- sseCommandSendHeartbeatToAll{}:
 		}
 	}
 }
@@ -517,7 +1447,10 @@ func (admin *Admin) dispatchEventWithSSE(ctlEvent sseEvent) {
 	case sseCommandSyncPlayerJoinedEventToAll:
 		func() {
 			admin.stateMutex.Lock()
-			admin.sseWriterForPlayer[e.NewPlayerName] = sseWriter{responseWriter: e.ResponseWriter}
+			admin.sseWriterForPlayer[e.NewPlayerName] = sseWriter{
+				responseWriter:       e.ResponseWriter,
+				notifyControllerExit: e.NotifyControllerExit,
+			}
 			admin.stateMutex.Unlock()
 
 			ctx, cancel := context.WithTimeout(context.Background(), allPlayersSyncCommandTimeout)
@@ -541,7 +1474,9 @@ func (admin *Admin) dispatchEventWithSSE(ctlEvent sseEvent) {
 				defer admin.stateMutex.Unlock()
 
 				existingPlayersMsg := messages.ExistingPlayersListEvent{
-					PlayerNames: make([]string, 0, len(admin.sseWriterForPlayer)),
+					PlayerNames:  make([]string, 0, len(admin.sseWriterForPlayer)),
+					HouseRules:   admin.table.HouseRules,
+					SessionToken: e.SessionToken,
 				}
 				for existingPlayerName := range admin.sseWriterForPlayer {
 					existingPlayerName := existingPlayerName
@@ -589,34 +1524,82 @@ func (admin *Admin) dispatchEventWithSSE(ctlEvent sseEvent) {
 			}
 		}()
 
+	case sseCommandReconnectPlayer:
+		func() {
+			admin.stateMutex.Lock()
+			defer admin.stateMutex.Unlock()
+
+			// Let the handler of the replaced stream return.
+			if oldWriter, ok := admin.sseWriterForPlayer[e.PlayerName]; ok && oldWriter.notifyControllerExit != nil {
+				close(oldWriter.notifyControllerExit)
+			}
+			admin.sseWriterForPlayer[e.PlayerName] = sseWriter{
+				responseWriter:       e.ResponseWriter,
+				notifyControllerExit: e.NotifyControllerExit,
+			}
+
+			// The other players already know the player, so no acks are
+			// expected for this list.
+			existingPlayersMsg := messages.ExistingPlayersListEvent{
+				PlayerNames:  make([]string, 0, len(admin.sseWriterForPlayer)),
+				HouseRules:   admin.table.HouseRules,
+				SessionToken: e.SessionToken,
+			}
+			for playerName := range admin.sseWriterForPlayer {
+				if playerName != e.PlayerName {
+					existingPlayersMsg.PlayerNames = append(existingPlayersMsg.PlayerNames, playerName)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), allPlayersSyncCommandTimeout)
+			defer cancel()
+			if err := admin.sendMessageToSinglePlayerWithSSE(ctx, e.PlayerName, existingPlayersMsg); err != nil {
+				log.Printf("Failed to send existing players to reconnected player %s: %v", e.PlayerName, err)
+			}
+		}()
+
 	case sseCommandSyncPlayerDecisionEvent:
 		func() {
 			admin.stateMutex.Lock()
 			defer admin.stateMutex.Unlock()
 
-			// Evaluate the decisions on the admin table
-			err := admin.table.EvalPlayerDecisionsNoTransferChan(e.DecidingPlayer, e.Decisions)
+			// Evaluate the decisions on the admin table. Its game events
+			// only go to the game record, if any.
+			if admin.gameRecord != nil {
+				admin.gameRecord.RecordDecisions(e.DecidingPlayer, e.Decisions)
+			}
+			err := admin.table.EvalDecisions(e.DecidingPlayer, e.Decisions)
 			if err != nil {
 				admin.logger.Printf("ERROR while evaluating decision on admin board: %v, %+v", err, e.PlayerDecisionsRequest)
 				return
 			}
 
 			// Sync the player decision with all other players
-			admin.setState(SyncingPlayerDecision)
+			admin.setStateChecked(SyncingPlayerDecision)
 			admin.updatePromptWithStateInfo()
 
-			err = admin.sendMessageToAllPlayersWithSSE(context.Background(), e.PlayerDecisionsRequest.DecidingPlayer, messages.PlayerDecisionsSyncEvent{PlayerDecisionsRequest: e.PlayerDecisionsRequest})
+			// The deciding player already has the decisions, unless the
+			// admin made them.
+			excludedPlayer := e.PlayerDecisionsRequest.DecidingPlayer
+			if e.DecidedByAdmin {
+				excludedPlayer = ""
+			}
+
+			err = admin.sendMessageToAllPlayersWithSSE(context.Background(), excludedPlayer, messages.PlayerDecisionsSyncEvent{PlayerDecisionsRequest: e.PlayerDecisionsRequest})
 			if err != nil {
 				admin.logger.Printf("Failed to broadcast player decisions event: %v", err)
 				return
 			}
 
 			var remainingAcksBeforeDoneSyncing atomic.Int32
-			remainingAcksBeforeDoneSyncing.Store(int32(len(admin.sseWriterForPlayer)) - 1)
+			remainingAcksBeforeDoneSyncing.Store(int32(len(admin.sseWriterForPlayer)))
+			if !e.DecidedByAdmin {
+				remainingAcksBeforeDoneSyncing.Add(-1)
+			}
 
 			// Since we're using SSE, instead of HTTP request-response, we need asynchronous acking of the decisions being synced by the server.
 			for playerName := range admin.sseWriterForPlayer {
-				if playerName == e.DecidingPlayer {
+				if playerName == excludedPlayer {
 					continue
 				}
 				admin.expectedAcksList.addPending(
@@ -629,7 +1612,7 @@ func (admin *Admin) dispatchEventWithSSE(ctlEvent sseEvent) {
 						admin.logger.Printf("%s acked decision %d", playerName, e.DecisionEventCounter)
 						if remainingAcksBeforeDoneSyncing.Add(-1) == 0 {
 							admin.stateMutex.Lock()
-							admin.setState(DoneSyncingPlayerDecision)
+							admin.setStateChecked(DoneSyncingPlayerDecision)
 							admin.decisionEventsCompleted++
 							admin.stateMutex.Unlock()
 							go admin.runNewTurn()
@@ -641,46 +1624,138 @@ func (admin *Admin) dispatchEventWithSSE(ctlEvent sseEvent) {
 				)
 			}
 
-			// admin.setState(DoneSyncingPlayerDecision)
+			// admin.setStateChecked(DoneSyncingPlayerDecision)
 			// go admin.runNewTurn()
 		}()
 
+	case sseCommandAddSpectator:
+		func() {
+			admin.stateMutex.Lock()
+			defer admin.stateMutex.Unlock()
+
+			writer := sseWriter{
+				responseWriter:       e.ResponseWriter,
+				notifyControllerExit: e.NotifyControllerExit,
+			}
+			admin.sseWriterForSpectator[e.SpectatorName] = writer
+
+			ctx, cancel := context.WithTimeout(context.Background(), allPlayersSyncCommandTimeout)
+			defer cancel()
+
+			existingPlayersMsg := messages.ExistingPlayersListEvent{
+				PlayerNames: append([]string(nil), admin.table.PlayerNames...),
+				HouseRules:  admin.table.HouseRules,
+			}
+			if err := writer.writeEventMessage(ctx, existingPlayersMsg); err != nil {
+				admin.logger.Printf("failed to send existing players to spectator %s: %v", e.SpectatorName, err)
+				return
+			}
+
+			// A spectator joining a game in progress starts from a snapshot.
+			if admin.table.IsShuffled {
+				eventMsg := &messages.ServedCardsEvent{Table: *admin.table.RedactedFor(e.SpectatorName)}
+				if err := writer.writeEventMessage(ctx, eventMsg); err != nil {
+					admin.logger.Printf("failed to send table snapshot to spectator %s: %v", e.SpectatorName, err)
+				}
+			}
+		}()
+
 	case sseCommandSendServedCardsEventToAll:
 		func() {
 			admin.stateMutex.Lock()
 			defer admin.stateMutex.Unlock()
-			eventMsg := &messages.ServedCardsEvent{
-				Table: *admin.table,
+			makeEventMsg := func(table uknow.Table) messages.ServerEvent {
+				return &messages.ServedCardsEvent{Table: table}
 			}
-			if err := admin.sendMessageToAllPlayersWithSSE(context.Background(), "", eventMsg); err != nil {
+			if err := admin.sendTableEventToAllPlayersWithSSE(context.Background(), makeEventMsg); err != nil {
 				log.Printf("ERROR: failed to send served cards event to player: %v", err)
 			}
 
-			admin.logger.Printf("Waiting %.0f seconds before sending chosen player event", pauseBeforeChoosingPlayer.Seconds())
-			<-time.After(pauseBeforeChoosingPlayer)
-
+			admin.pauseAndChooseFirstPlayer()
 			admin.logger.Printf("Next turn: %s", admin.table.PlayerOfNextTurn)
+		}()
+
+	case sseCommandSendRematchEventToAll:
+		func() {
+			admin.stateMutex.Lock()
+			defer admin.stateMutex.Unlock()
+
+			winCountOfPlayer := make(map[string]int)
+			for playerName, winCount := range admin.winCountOfPlayer {
+				winCountOfPlayer[playerName] = winCount
+			}
+
+			makeEventMsg := func(table uknow.Table) messages.ServerEvent {
+				return &messages.RematchEvent{
+					Table:            table,
+					WinCountOfPlayer: winCountOfPlayer,
+				}
+			}
+			if err := admin.sendTableEventToAllPlayersWithSSE(context.Background(), makeEventMsg); err != nil {
+				log.Printf("ERROR: failed to send rematch event to player: %v", err)
+			}
 
-			go func() {
-				admin.sseControllerEventChan <- sseCommandSendChosenPlayerEventToAll{}
-			}()
+			admin.pauseAndChooseFirstPlayer()
 		}()
 
-	case sseCommandSendChosenPlayerEventToAll:
+	case sseCommandSendHeartbeatToAll:
+		func() {
+			admin.stateMutex.Lock()
+			defer admin.stateMutex.Unlock()
+
+			// Not logged, there's one per interval.
+			for playerName, writer := range admin.sseWriterForPlayer {
+				if err := writer.writeEventMessage(context.Background(), messages.HeartbeatEvent{}); err != nil {
+					admin.logger.Printf("failed to send heartbeat to player %s: %v", playerName, err)
+				}
+			}
+			for spectatorName, writer := range admin.sseWriterForSpectator {
+				admin.writeEventMessageToSpectator(context.Background(), spectatorName, writer, messages.HeartbeatEvent{})
+			}
+		}()
+
+	case sseCommandSendRedealEventToAll:
+		func() {
+			admin.stateMutex.Lock()
+			defer admin.stateMutex.Unlock()
+
+			makeEventMsg := func(table uknow.Table) messages.ServerEvent {
+				return &messages.ServedCardsEvent{Table: table, Redeal: true}
+			}
+			if err := admin.sendTableEventToAllPlayersWithSSE(context.Background(), makeEventMsg); err != nil {
+				log.Printf("ERROR: failed to send redealt cards to player: %v", err)
+			}
+
+			admin.pauseAndChooseFirstPlayer()
+		}()
+
+	case sseCommandSendHouseRulesChangedEventToAll:
 		func() {
 			admin.stateMutex.Lock()
 			defer admin.stateMutex.Unlock()
-			eventMsg := messages.ChosenPlayerEvent{
-				PlayerName:           admin.table.PlayerOfNextTurn,
-				DecisionEventCounter: admin.decisionEventsCompleted,
+			eventMsg := messages.HouseRulesChangedEvent{HouseRules: e.HouseRules}
+			if err := admin.sendMessageToAllPlayersWithSSE(context.Background(), "", eventMsg); err != nil {
+				admin.logger.Printf("sendMessageToAllPlayersWithSSE failed to send house rules changed message: %v", err)
 			}
+		}()
+
+	case sseCommandSendChosenPlayerEventToAll:
+		func() {
+			admin.stateMutex.Lock()
+			defer admin.stateMutex.Unlock()
+			eventMsg := messages.NewChosenPlayerEvent(admin.table, admin.decisionEventsCompleted)
 			if err := admin.sendMessageToAllPlayersWithSSE(context.Background(), "", &eventMsg); err != nil {
 				admin.logger.Printf("sendMessageToAllPlayersWithSSE failed to send chosen player message: %v", err)
 				return
 			}
 			admin.logger.Printf("success: sent chosen player message to all players: %+v", eventMsg)
 
-			admin.setState(WaitingForPlayerDecision)
+			admin.drawDeckTopRevealed = false
+			admin.setStateChecked(WaitingForPlayerDecision)
+
+			if admin.table.TableState == uknow.AwaitingWildDraw4ChallengeDecision {
+				admin.startChallengeTimerNoLock(eventMsg.PlayerName, admin.decisionEventsCompleted)
+			}
 
 			// TODO: We should also wait for acks from each of the player to note the admin they processed the chosen player event.
 			admin.expectedAcksList.addPending(
@@ -699,6 +1774,18 @@ func (admin *Admin) dispatchEventWithSSE(ctlEvent sseEvent) {
 	}
 }
 
+// Waits the pause after serving before the chosen player event of the first
+// turn is sent. Called by the SSE controller with the stateMutex held.
+func (admin *Admin) pauseAndChooseFirstPlayer() {
+	pauseAfterServe := admin.userConfig.pauseAfterServe()
+	admin.logger.Printf("Waiting %s before sending chosen player event", pauseAfterServe)
+	<-admin.clock.After(pauseAfterServe)
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandSendChosenPlayerEventToAll{}
+	}()
+}
+
 func (admin *Admin) sendMessageToAllPlayersWithSSE(ctx context.Context, excludePlayer string, eventMsg messages.ServerEvent) error {
 	// TODO: Call in parallel. Use timeout via ctx.Done. Also, to avoid race conditions, clone the map - but it's unlikely.
 	admin.logger.Printf("sendMessageToAllPlayersWithSSE: (excluded: %s) %T %+v", excludePlayer, eventMsg, eventMsg)
@@ -710,6 +1797,35 @@ func (admin *Admin) sendMessageToAllPlayersWithSSE(ctx context.Context, excludeP
 			return err
 		}
 	}
+
+	for spectatorName, writer := range admin.sseWriterForSpectator {
+		admin.writeEventMessageToSpectator(ctx, spectatorName, writer, eventMsg)
+	}
+	return nil
+}
+
+// Failing to reach a spectator is only logged, it must not hold up the game.
+func (admin *Admin) writeEventMessageToSpectator(ctx context.Context, spectatorName string, writer sseWriter, eventMsg messages.ServerEvent) {
+	if err := writer.writeEventMessage(ctx, eventMsg); err != nil {
+		admin.logger.Printf("failed to send %T to spectator %s: %v", eventMsg, spectatorName, err)
+	}
+}
+
+// Sends each player the event made from the table redacted for that player,
+// so that no player receives the cards of the other players' hands. Spectators
+// get it with every hand redacted.
+func (admin *Admin) sendTableEventToAllPlayersWithSSE(ctx context.Context, makeEventMsg func(table uknow.Table) messages.ServerEvent) error {
+	for playerName, writer := range admin.sseWriterForPlayer {
+		eventMsg := makeEventMsg(*admin.table.RedactedFor(playerName))
+		admin.logger.Printf("sendTableEventToAllPlayersWithSSE: %s %T", playerName, eventMsg)
+		if err := writer.writeEventMessage(ctx, eventMsg); err != nil {
+			return err
+		}
+	}
+
+	for spectatorName, writer := range admin.sseWriterForSpectator {
+		admin.writeEventMessageToSpectator(ctx, spectatorName, writer, makeEventMsg(*admin.table.RedactedFor(spectatorName)))
+	}
 	return nil
 }
 
@@ -722,7 +1838,7 @@ func (admin *Admin) sendMessageToSinglePlayerWithSSE(ctx context.Context, player
 	return writer.writeEventMessage(ctx, eventMsg)
 }
 
-func (admin *Admin) setReady() {
+func (admin *Admin) setReady() error {
 	admin.stateMutex.Lock()
 	defer admin.stateMutex.Unlock()
 
@@ -732,18 +1848,42 @@ func (admin *Admin) setReady() {
 
 	admin.expectedAcksList.mu.Lock()
 	numExpectingAcks := len(admin.expectedAcksList.pendingAcks)
+	admin.expectedAcksList.mu.Unlock()
 
 	if numExpectingAcks != 0 {
-		admin.expectedAcksList.mu.Unlock()
-		admin.logger.Printf("handleSetReady: cannot change to ready state, numExpectingAcks = %d (!= 0)", numExpectingAcks)
+		admin.logger.Printf("setReady: cannot change to ready state, numExpectingAcks = %d (!= 0)", numExpectingAcks)
+		return fmt.Errorf("set_ready: %w, numExpectingAcks = %d", errorWaitingForAcks, numExpectingAcks)
 	}
 
-	admin.expectedAcksList.mu.Unlock()
+	if err := admin.checkEnoughPlayers(); err != nil {
+		admin.logger.Printf("setReady: %s", err)
+		return fmt.Errorf("set_ready: %w", err)
+	}
+	return nil
+}
+
+// Caller must hold the stateMutex.
+func (admin *Admin) checkEnoughPlayers() error {
+	minPlayers := admin.userConfig.MinPlayerCount()
+	if admin.table.PlayerCount() < minPlayers {
+		return fmt.Errorf("%w: have %d, need at least %d", errorNotEnoughPlayers, admin.table.PlayerCount(), minPlayers)
+	}
+	return nil
 }
 
-func (admin *Admin) setState(state AdminState) {
+// Changes the admin state if allowedAdminTransitions allows it. Otherwise logs
+// and returns an error, keeping the current state.
+func (admin *Admin) setStateChecked(state AdminState) error {
+	if !isAllowedAdminTransition(admin.state, state) {
+		err := fmt.Errorf("%w: from %s to %s", errorIllegalStateTransition, admin.state, state)
+		admin.logger.Printf("setStateChecked: %s", err)
+		log.Printf("ERROR: %s", err)
+		return err
+	}
+
 	admin.state = state
 	admin.updatePromptWithStateInfo()
+	return nil
 }
 
 func (admin *Admin) updatePromptWithStateInfo() {
@@ -751,30 +1891,66 @@ func (admin *Admin) updatePromptWithStateInfo() {
 		return
 	}
 
+	var prompt string
+
 	switch admin.state {
 	case AddingPlayers:
-		admin.rl.SetPrompt("[adding_players]> ")
+		prompt = "[adding_players]> "
 	case ReadyToServeCards:
-		admin.rl.SetPrompt("[ready_to_serve_cards]> ")
+		prompt = "[ready_to_serve_cards]> "
 	case CardsServed:
-		admin.rl.SetPrompt("[cards_served]> ")
+		prompt = "[cards_served]> "
 	case PlayerChosenForTurn:
-		admin.rl.SetPrompt(fmt.Sprintf("[player_chosen_for_turn:%s]> ", admin.table.PlayerOfNextTurn))
+		prompt = fmt.Sprintf("[player_chosen_for_turn:%s]> ", admin.table.PlayerOfNextTurn)
 	case WaitingForPlayerDecision:
-		admin.rl.SetPrompt(fmt.Sprintf("[waiting_for_player_decision:%s]> ", admin.table.PlayerOfNextTurn))
+		prompt = fmt.Sprintf("[waiting_for_player_decision:%s]> ", admin.table.PlayerOfNextTurn)
 	case WaitingForChallengePlayerDecision:
-		admin.rl.SetPrompt(fmt.Sprintf("[waiting_for_challenge_decision:%s]> ", admin.table.PlayerOfNextTurn))
+		prompt = fmt.Sprintf("[waiting_for_challenge_decision:%s]> ", admin.table.PlayerOfNextTurn)
 	case SyncingPlayerDecision:
-		admin.rl.SetPrompt(fmt.Sprintf("[syncing_player_decision:%s]> ", admin.table.PlayerOfNextTurn))
+		prompt = fmt.Sprintf("[syncing_player_decision:%s]> ", admin.table.PlayerOfNextTurn)
 	case DoneSyncingPlayerDecision:
-		admin.rl.SetPrompt(fmt.Sprintf("[done_syncing_player_decision:(decider:%s, next:%s)]> ", admin.table.PlayerOfLastTurn, admin.table.PlayerOfNextTurn))
+		prompt = fmt.Sprintf("[done_syncing_player_decision:(decider:%s, next:%s)]> ", admin.table.PlayerOfLastTurn, admin.table.PlayerOfNextTurn)
 	case HaveWinner:
-		admin.rl.SetPrompt(fmt.Sprintf("[have_winner:%s]> ", admin.table.WinnerPlayerName))
+		prompt = fmt.Sprintf("[have_winner:%s]> ", admin.table.WinnerPlayerName)
+	default:
+		return
 	}
 
+	if admin.userConfig.ColorPrompt {
+		prompt = colorPromptOfState(prompt, admin.state)
+	}
+	admin.rl.SetPrompt(prompt)
 	admin.rl.Write([]byte("\n"))
 }
 
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+// Wraps the prompt in the ANSI color of the state. Readline leaves color
+// sequences out when measuring the prompt, so the cursor stays in place.
+func colorPromptOfState(prompt string, state AdminState) string {
+	var color string
+
+	switch state {
+	case AddingPlayers, ReadyToServeCards, CardsServed:
+		color = ansiCyan
+	case PlayerChosenForTurn, WaitingForPlayerDecision, WaitingForChallengePlayerDecision:
+		color = ansiGreen
+	case SyncingPlayerDecision, DoneSyncingPlayerDecision:
+		color = ansiYellow
+	case HaveWinner:
+		color = ansiRed
+	default:
+		return prompt
+	}
+	return color + prompt + ansiReset
+}
+
 func (admin *Admin) RunREPL() {
 	var err error
 	admin.rl, err = readline.New("> ")
@@ -798,6 +1974,65 @@ func (admin *Admin) RunREPL() {
 			continue
 		}
 
+		if line == "redeal" {
+			if err := admin.Redeal(); err != nil {
+				log.Print(err)
+			}
+			continue
+		}
+
+		if line == "rematch" || line == "rematch reset_scores" {
+			if err := admin.Rematch(line == "rematch reset_scores"); err != nil {
+				log.Print(err)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "set_next ") {
+			if err := admin.ForceNextPlayer(strings.TrimSpace(strings.TrimPrefix(line, "set_next "))); err != nil {
+				log.Print(err)
+			}
+			continue
+		}
+
+		if line == "rules" {
+			admin.stateMutex.Lock()
+			log.Printf("house rules: %s", admin.table.HouseRules.String())
+			admin.stateMutex.Unlock()
+			continue
+		}
+
+		if strings.HasPrefix(line, "rules ") {
+			if err := admin.setHouseRuleFromREPL(strings.Fields(strings.TrimPrefix(line, "rules "))); err != nil {
+				log.Print(err)
+			}
+			continue
+		}
+
+		if line == "players" {
+			log.Print(admin.playersStatus())
+			continue
+		}
+
+		if line == "stats" {
+			admin.stateMutex.Lock()
+			stats := admin.table.Stats()
+			admin.stateMutex.Unlock()
+			log.Printf("stats:\n%s", strings.Join(uknow.PlayerStatsLines(stats), "\n"))
+			continue
+		}
+
+		if strings.HasPrefix(line, "seat ") {
+			order := strings.Split(strings.TrimPrefix(line, "seat "), ",")
+			for i := range order {
+				order[i] = strings.TrimSpace(order[i])
+			}
+			if err := admin.SetSeating(order); err != nil {
+				log.Print(err)
+			}
+			continue
+		}
+
 		if line == "acks" {
 			log.Printf("Expecting acks:\n%s", admin.expectedAcksList.ackIds())
 			continue
@@ -824,6 +2059,16 @@ func (admin *Admin) RunREPL() {
 			log.Print(sb.String())
 		}
 
+		if line == "dump_full" {
+			var sb strings.Builder
+			admin.stateMutex.Lock()
+			admin.table.DebugDump(&sb)
+			admin.stateMutex.Unlock()
+			admin.logger.Print(sb.String())
+			log.Print(sb.String())
+			continue
+		}
+
 		if line == "dump_drawdeck" {
 			var sb strings.Builder
 			admin.table.PrintDrawDeck(&sb, 15)
@@ -832,15 +2077,83 @@ func (admin *Admin) RunREPL() {
 		}
 
 		if line == "set_ready" || line == "sr" {
-			admin.setReady()
+			if err := admin.setReady(); err != nil {
+				log.Print(err)
+			}
+		}
+	}
+}
+
+// Handles "rules NAME on|off" from the REPL.
+func (admin *Admin) setHouseRuleFromREPL(args []string) error {
+	if len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+		return fmt.Errorf("rules: expected NAME on|off, where NAME is one of %s", strings.Join(uknow.HouseRuleNames, "|"))
+	}
+
+	admin.stateMutex.Lock()
+	houseRules := admin.table.HouseRules
+	admin.stateMutex.Unlock()
+
+	if err := houseRules.Set(args[0], args[1] == "on"); err != nil {
+		return fmt.Errorf("rules: %w", err)
+	}
+	return admin.SetHouseRules(houseRules)
+}
+
+// Lists the players in turn order with their seat, hand count, whether their
+// SSE stream is connected and the acks still expected from them. Connected
+// players that are not seated yet are listed last.
+func (admin *Admin) playersStatus() string {
+	admin.stateMutex.Lock()
+	defer admin.stateMutex.Unlock()
+
+	playerNames := make([]string, 0, len(admin.sseWriterForPlayer))
+	if _, ok := admin.table.IndexOfPlayer[admin.table.PlayerOfNextTurn]; ok {
+		for _, playerIndex := range admin.table.PlayerIndicesSortedByTurn() {
+			playerNames = append(playerNames, admin.table.PlayerNames[playerIndex])
+		}
+	} else {
+		playerNames = append(playerNames, admin.table.PlayerNames...)
+	}
+
+	unseatedPlayerNames := make([]string, 0)
+	for playerName := range admin.sseWriterForPlayer {
+		if _, seated := admin.table.IndexOfPlayer[playerName]; !seated {
+			unseatedPlayerNames = append(unseatedPlayerNames, playerName)
+		}
+	}
+	sort.Strings(unseatedPlayerNames)
+	playerNames = append(playerNames, unseatedPlayerNames...)
+
+	var sb strings.Builder
+	for _, playerName := range playerNames {
+		_, connected := admin.sseWriterForPlayer[playerName]
+
+		seat := "-"
+		if playerIndex, ok := admin.table.IndexOfPlayer[playerName]; ok {
+			seat = strconv.Itoa(playerIndex)
+		}
+
+		pendingAcks := "none"
+		if ackIds := admin.expectedAcksList.pendingAckIdsOfPlayer(playerName); len(ackIds) != 0 {
+			pendingAcks = strings.Join(ackIds, ", ")
+		}
+
+		turnMarker := " "
+		if playerName == admin.table.PlayerOfNextTurn {
+			turnMarker = "*"
 		}
+
+		fmt.Fprintf(&sb, "%s %s: seat %s, %d cards, connected: %t, pending acks: %s\n", turnMarker, playerName, seat, admin.table.HandOfPlayer[playerName].Len(), connected, pendingAcks)
 	}
+	return sb.String()
 }
 
 // If there's a starting hand-config specified for debugging, we create a table accordingly
 func createStartingTable(c *AdminUserConfig) *uknow.Table {
-	tableLogger := uknow.CreateFileLogger(false, "table_admin")
+	tableLogger := newAdminFileLogger(c, "table_admin")
 	table := uknow.NewAdminTable(tableLogger)
+	table.DrawDeck = uknow.NewFullDeckN(c.DeckCount)
 	var err error
 
 	if c.DebugStartingHandConfigFile != "" {
@@ -848,61 +2161,100 @@ func createStartingTable(c *AdminUserConfig) *uknow.Table {
 	} else if c.DebugStartingHandConfig != nil {
 		table, err = hand_reader.LoadConfig(c.DebugStartingHandConfig, table, log.Default())
 	} else {
+		table.HouseRules = c.HouseRules
 		return table
 	}
 
+	if err == nil {
+		err = table.Validate()
+	}
+
 	if err != nil {
 		log.Fatalf("failed to load hand-config: %s", err)
 	} else {
 		log.Printf("loaded hand-config")
 	}
+	table.HouseRules = c.HouseRules
 	return table
 }
 
+// Loads the admin config from the JSON file, if given, with the environment
+// overrides applied on top, see AdminEnvPrefix. Exits if the config is invalid.
 func LoadConfig(configFile string) (AdminUserConfig, *uknow.AESCipher) {
-	f, err := os.Open(configFile)
+	adminConfig, err := readConfig(configFile)
 	if err != nil {
-		log.Fatalf("failed to open config file %s: %v", configFile, err)
+		log.Fatal(err)
 	}
-	defer f.Close()
 
-	configBytes, err := io.ReadAll(f)
-	if err != nil {
-		log.Fatalf("failed to read config file %s: %v", configFile, err)
+	var aesCipher *uknow.AESCipher
+	if adminConfig.EncryptMessages {
+		aesCipher, err = uknow.NewAESCipherFromConfig(adminConfig.AESKeyString, adminConfig.AESPassphrase, adminConfig.AESSalt)
+		if err != nil {
+			log.Fatalf("failed to create aes cipger: %v", err)
+		}
 	}
 
+	return adminConfig, aesCipher
+}
+
+func readConfig(configFile string) (AdminUserConfig, error) {
 	var adminConfig AdminUserConfig
-	err = json.NewDecoder(bytes.NewReader(configBytes)).Decode(&adminConfig)
-	if err != nil {
-		log.Fatalf("failed to parse admin config: %v", err)
-	}
 
-	if adminConfig.Type != "admin" {
-		log.Fatalf("expected \"type\" field in config to have value \"admin\"")
-	}
+	if configFile == "" {
+		adminConfig.Type = "admin"
+	} else {
+		f, err := os.Open(configFile)
+		if err != nil {
+			return adminConfig, fmt.Errorf("failed to open config file %s: %w", configFile, err)
+		}
+		defer f.Close()
 
-	var aesCipher *uknow.AESCipher
-	if adminConfig.EncryptMessages {
-		aesCipher, err = uknow.NewAESCipher(adminConfig.AESKeyString)
+		configBytes, err := io.ReadAll(f)
 		if err != nil {
-			log.Fatalf("failed to create aes cipger: %v", err)
+			return adminConfig, fmt.Errorf("failed to read config file %s: %w", configFile, err)
+		}
+
+		err = json.NewDecoder(bytes.NewReader(configBytes)).Decode(&adminConfig)
+		if err != nil {
+			return adminConfig, fmt.Errorf("failed to parse admin config: %w", err)
+		}
+
+		if adminConfig.Type != "admin" {
+			return adminConfig, errors.New("expected \"type\" field in config to have value \"admin\"")
 		}
 	}
 
-	return adminConfig, aesCipher
+	if err := adminConfig.applyEnvOverrides(); err != nil {
+		return adminConfig, err
+	}
+
+	if err := adminConfig.validate(); err != nil {
+		return adminConfig, err
+	}
+	return adminConfig, nil
 }
 
 func RunApp() {
 	var adminConfigFile string
-	flag.StringVar(&adminConfigFile, "conf", "", "Dotenv config file for admin server")
+	flag.StringVar(&adminConfigFile, "conf", "", "JSON config file for admin server, optional if configured with UKNOW_ADMIN_* environment variables")
 	flag.Parse()
 
-	if adminConfigFile == "" {
-		log.Fatal("missing flag: -conf config_file")
+	// admin replay FILE narrates a recorded game instead of serving one.
+	if flag.Arg(0) == "replay" {
+		if flag.NArg() != 2 {
+			log.Fatal("usage: admin replay FILE")
+		}
+		runReplay(flag.Arg(1))
+		return
 	}
 
 	adminUserConfig, aesCipher := LoadConfig(adminConfigFile)
 
+	if len(adminUserConfig.Rooms) != 0 {
+		runRooms(&adminUserConfig, aesCipher)
+		return
+	}
+
 	config := &ConfigNewAdmin{}
 	config.ListenAddr = utils.HostPortProtocol{IP: adminUserConfig.ListenIP, Port: adminUserConfig.ListenPort}
 
@@ -916,7 +2268,51 @@ func RunApp() {
 	if adminUserConfig.RunREPL {
 		go admin.RunServer()
 		admin.RunREPL()
+		admin.shutdownWithTimeout(shutdownTimeout)
 	} else {
+		go func() {
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, os.Interrupt)
+			<-sigChan
+			admin.shutdownWithTimeout(shutdownTimeout)
+		}()
 		admin.RunServer()
 	}
 }
+
+// Serves a game in each configured room until interrupted.
+func runRooms(adminUserConfig *AdminUserConfig, aesCipher *uknow.AESCipher) {
+	listenAddr := utils.HostPortProtocol{IP: adminUserConfig.ListenIP, Port: adminUserConfig.ListenPort}
+
+	server := NewServer(listenAddr, func(roomCode string) *Admin {
+		return NewAdmin(&ConfigNewAdmin{
+			ListenAddr:      listenAddr,
+			Table:           createStartingTable(adminUserConfig),
+			ReadyPlayerName: adminUserConfig.ReadyPlayerName,
+			aesCipher:       aesCipher,
+			RoomCode:        roomCode,
+		}, adminUserConfig)
+	})
+
+	for _, roomCode := range adminUserConfig.Rooms {
+		if _, err := server.OpenRoom(roomCode); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt)
+		<-sigChan
+
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("Server.Shutdown() failed: %v", err)
+		}
+	}()
+
+	if err := server.Run(adminUserConfig.TLSCertFile, adminUserConfig.TLSKeyFile); err != nil {
+		log.Fatalf("Server.Run() failed: %s", err.Error())
+	}
+}
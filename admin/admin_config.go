@@ -1,15 +1,267 @@
 package admin
 
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/nrawrx3/uknow"
+)
+
 type AdminUserConfig struct {
 	Type                        string                 `json:"type"` // should always be "admin"
 	ListenPort                  int                    `json:"listen_port"`
 	ListenIP                    string                 `json:"listen_ip"`
 	RunREPL                     bool                   `json:"run_repl"`
+	ColorPrompt                 bool                   `json:"color_prompt"` // Color the REPL prompt by admin state. Some terminals mangle the colors.
 	ReadyPlayerName             string                 `json:"ready_player_name"`
 	PauseMsecsBeforeNewTurn     int                    `json:"pause_msecs_before_new_turn"`
+	StartingHandCount           int                    `json:"starting_hand_count"` // Defaults to uknow.DefaultStartingHandCount
+	DeckCount                   int                    `json:"deck_count"`          // Number of standard decks combined into the draw deck. Defaults to 1.
+	MinPlayers                  int                    `json:"min_players"`         // Players needed before set_ready is accepted. Defaults to DefaultMinPlayers.
+	HouseRules                  uknow.HouseRules       `json:"house_rules"`
+	LogDir                      string                 `json:"log_dir"`               // Directory of the log files. Defaults to os.TempDir().
+	LogRotateMaxKB              int                    `json:"log_rotate_max_kb"`     // Rotate each log file after it reaches this size. Zero disables rotation.
+	LogRotateKeepFiles          int                    `json:"log_rotate_keep_files"` // Rotated files kept per log file. Defaults to DefaultLogRotateKeepFiles.
 	AESKeyString                string                 `json:"aes_key"`
+	AESPassphrase               string                 `json:"aes_passphrase"` // Derives the key instead of aes_key, with aes_salt. Clients must use the same.
+	AESSalt                     string                 `json:"aes_salt"`
 	EncryptMessages             bool                   `json:"encrypt_messages"`
 	DebugStartingHandConfigFile string                 `json:"debug_starting_hand_config_file"`
 	DebugStartingHandConfig     map[string]interface{} `json:"debug_starting_hand_config,omitempty"`
 	DebugSignalNewTurnViaPrompt bool                   `json:"debug_signal_new_turn_via_prompt"`
+
+	// Evaluate posted player decisions on a copy of the table and respond with
+	// the result, without syncing them to the players or moving to the next
+	// turn. For probing the rules through the HTTP API.
+	DryRunDecisions bool `json:"dry_run_decisions"`
+
+	// Pause between serving the cards and choosing the first player, separate
+	// from pause_msecs_before_new_turn. Unset means DefaultPauseAfterServe.
+	PauseMsecsAfterServe *int `json:"pause_msecs_after_serve"`
+
+	// Interval of the heartbeat events sent to every client. Unset means
+	// DefaultHeartbeatInterval.
+	HeartbeatMsecs int `json:"heartbeat_msecs"`
+
+	// Seconds the player asked to challenge a wild draw 4 has to decide,
+	// after which the admin decides no_challenge for them. Unset means
+	// DefaultChallengeTimeout.
+	ChallengeTimeoutSecs int `json:"challenge_timeout_secs"`
+
+	// Record the decisions and game events of each game to a JSON lines
+	// file in this directory, for narrating it with the replay subcommand.
+	// Games are not recorded if empty.
+	RecordGamesDir string `json:"record_games_dir"`
+
+	// Serve GET /ws, a WebSocket endpoint carrying both the events and the
+	// requests of a client over one connection, see handleWebSocket. The SSE
+	// endpoints are served either way.
+	WebSocket bool `json:"websocket"`
+
+	// Host a game in each of these rooms instead of a single game, see
+	// Server. The admin REPL is not run then.
+	Rooms []string `json:"rooms"`
+
+	// PEM files of the certificate and key to serve https with. Both or
+	// neither must be set, plain http is served if neither is.
+	TLSCertFile string `json:"tls_cert_file"`
+	TLSKeyFile  string `json:"tls_key_file"`
+}
+
+// Prefix of the environment variables overriding the config file, e.g.
+// UKNOW_ADMIN_LISTEN_PORT.
+const AdminEnvPrefix = "UKNOW_ADMIN"
+
+// Fields of AdminUserConfig that can be set from the environment, for
+// deployments without a config file. Unset variables keep the file's values.
+type adminEnvConfig struct {
+	ListenIP                *string `envconfig:"LISTEN_IP"`
+	ListenPort              *int    `envconfig:"LISTEN_PORT"`
+	EncryptMessages         *bool   `envconfig:"ENCRYPT_MESSAGES"`
+	AESKeyString            *string `envconfig:"AES_KEY"`
+	AESPassphrase           *string `envconfig:"AES_PASSPHRASE"`
+	AESSalt                 *string `envconfig:"AES_SALT"`
+	PauseMsecsBeforeNewTurn *int    `envconfig:"PAUSE_MSECS_BEFORE_NEW_TURN"`
+	PauseMsecsAfterServe    *int    `envconfig:"PAUSE_MSECS_AFTER_SERVE"`
+	TLSCertFile             *string `envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile              *string `envconfig:"TLS_KEY_FILE"`
+}
+
+func (c *AdminUserConfig) applyEnvOverrides() error {
+	var env adminEnvConfig
+	if err := envconfig.Process(AdminEnvPrefix, &env); err != nil {
+		return fmt.Errorf("failed to read admin config from environment: %w", err)
+	}
+
+	if env.ListenIP != nil {
+		c.ListenIP = *env.ListenIP
+	}
+	if env.ListenPort != nil {
+		c.ListenPort = *env.ListenPort
+	}
+	if env.EncryptMessages != nil {
+		c.EncryptMessages = *env.EncryptMessages
+	}
+	if env.AESKeyString != nil {
+		c.AESKeyString = *env.AESKeyString
+	}
+	if env.AESPassphrase != nil {
+		c.AESPassphrase = *env.AESPassphrase
+	}
+	if env.AESSalt != nil {
+		c.AESSalt = *env.AESSalt
+	}
+	if env.PauseMsecsBeforeNewTurn != nil {
+		c.PauseMsecsBeforeNewTurn = *env.PauseMsecsBeforeNewTurn
+	}
+	if env.PauseMsecsAfterServe != nil {
+		c.PauseMsecsAfterServe = env.PauseMsecsAfterServe
+	}
+	if env.TLSCertFile != nil {
+		c.TLSCertFile = *env.TLSCertFile
+	}
+	if env.TLSKeyFile != nil {
+		c.TLSKeyFile = *env.TLSKeyFile
+	}
+	return nil
+}
+
+var errInvalidAdminConfig = errors.New("invalid admin config")
+
+// Checks the config and fills in the defaults of unset fields.
+func (c *AdminUserConfig) validate() error {
+	if c.ListenPort <= 0 || c.ListenPort > 65535 {
+		return fmt.Errorf("%w: expected \"listen_port\" or %s_LISTEN_PORT to be within 1 and 65535, got %d", errInvalidAdminConfig, AdminEnvPrefix, c.ListenPort)
+	}
+
+	if c.EncryptMessages && c.AESKeyString == "" && c.AESPassphrase == "" {
+		return fmt.Errorf("%w: expected \"aes_key\" or \"aes_passphrase\" to be set when encrypting messages", errInvalidAdminConfig)
+	}
+
+	if c.AESKeyString != "" && c.AESPassphrase != "" {
+		return fmt.Errorf("%w: expected only one of \"aes_key\" and \"aes_passphrase\" to be set", errInvalidAdminConfig)
+	}
+
+	if c.AESPassphrase != "" && len(c.AESSalt) < uknow.MinAESSaltLength {
+		return fmt.Errorf("%w: expected \"aes_salt\" of at least %d bytes with \"aes_passphrase\"", errInvalidAdminConfig, uknow.MinAESSaltLength)
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("%w: expected both or neither of \"tls_cert_file\" and \"tls_key_file\" to be set", errInvalidAdminConfig)
+	}
+
+	if c.PauseMsecsBeforeNewTurn < 0 || (c.PauseMsecsAfterServe != nil && *c.PauseMsecsAfterServe < 0) {
+		return fmt.Errorf("%w: expected the pauses to not be negative", errInvalidAdminConfig)
+	}
+
+	if c.HeartbeatMsecs < 0 {
+		return fmt.Errorf("%w: expected \"heartbeat_msecs\" to not be negative", errInvalidAdminConfig)
+	}
+
+	if c.ChallengeTimeoutSecs < 0 {
+		return fmt.Errorf("%w: expected \"challenge_timeout_secs\" to not be negative", errInvalidAdminConfig)
+	}
+
+	if c.StartingHandCount == 0 {
+		c.StartingHandCount = uknow.DefaultStartingHandCount
+	} else if c.StartingHandCount < 0 || c.StartingHandCount > uknow.MaxStartingHandCount {
+		return fmt.Errorf("%w: expected \"starting_hand_count\" to be within 1 and %d", errInvalidAdminConfig, uknow.MaxStartingHandCount)
+	}
+
+	if c.MinPlayers < 0 {
+		return fmt.Errorf("%w: expected \"min_players\" to be at least 1", errInvalidAdminConfig)
+	}
+
+	if c.LogRotateMaxKB < 0 || c.LogRotateKeepFiles < 0 {
+		return fmt.Errorf("%w: expected \"log_rotate_max_kb\" and \"log_rotate_keep_files\" to not be negative", errInvalidAdminConfig)
+	}
+
+	roomCodes := make(map[string]bool, len(c.Rooms))
+	for _, roomCode := range c.Rooms {
+		if !roomCodeRegexp.MatchString(roomCode) || roomCodes[roomCode] {
+			return fmt.Errorf("%w: expected \"rooms\" to be distinct codes of letters, digits, '_' and '-', got %q", errInvalidAdminConfig, roomCode)
+		}
+		roomCodes[roomCode] = true
+	}
+
+	if c.DeckCount == 0 {
+		c.DeckCount = 1
+	} else if c.DeckCount < 0 || c.DeckCount > uknow.MaxDeckCount {
+		return fmt.Errorf("%w: expected \"deck_count\" to be within 1 and %d", errInvalidAdminConfig, uknow.MaxDeckCount)
+	}
+	return nil
+}
+
+// Reports whether the admin serves https.
+func (c *AdminUserConfig) servesTLS() bool {
+	return c.TLSCertFile != ""
+}
+
+const DefaultMinPlayers = 2
+
+// Returns MinPlayers, or DefaultMinPlayers if it is not set.
+func (c *AdminUserConfig) MinPlayerCount() int {
+	if c.MinPlayers == 0 {
+		return DefaultMinPlayers
+	}
+	return c.MinPlayers
+}
+
+const DefaultPauseAfterServe = 2 * time.Second
+
+// Returns the pause between serving the cards and choosing the first player.
+func (c *AdminUserConfig) pauseAfterServe() time.Duration {
+	if c.PauseMsecsAfterServe == nil {
+		return DefaultPauseAfterServe
+	}
+	return time.Duration(*c.PauseMsecsAfterServe) * time.Millisecond
+}
+
+const DefaultHeartbeatInterval = 2 * time.Second
+
+// Returns the interval of the heartbeat events.
+func (c *AdminUserConfig) heartbeatInterval() time.Duration {
+	if c.HeartbeatMsecs == 0 {
+		return DefaultHeartbeatInterval
+	}
+	return time.Duration(c.HeartbeatMsecs) * time.Millisecond
+}
+
+const DefaultChallengeTimeout = 30 * time.Second
+
+// Returns the time the player asked to challenge a wild draw 4 has to decide.
+func (c *AdminUserConfig) challengeTimeout() time.Duration {
+	if c.ChallengeTimeoutSecs == 0 {
+		return DefaultChallengeTimeout
+	}
+	return time.Duration(c.ChallengeTimeoutSecs) * time.Second
+}
+
+const DefaultLogRotateKeepFiles = 3
+
+func (c *AdminUserConfig) logRotation() uknow.LogRotation {
+	if c.LogRotateMaxKB <= 0 {
+		return uknow.LogRotation{}
+	}
+
+	keepFiles := c.LogRotateKeepFiles
+	if keepFiles == 0 {
+		keepFiles = DefaultLogRotateKeepFiles
+	}
+	return uknow.LogRotation{MaxBytes: int64(c.LogRotateMaxKB) * 1024, KeepFiles: keepFiles}
+}
+
+// Creates a logger for the admin in the configured log dir, rotated as
+// configured. Falls back to the default logger if the log file cannot be
+// created.
+func newAdminFileLogger(c *AdminUserConfig, name string) *log.Logger {
+	logger, err := uknow.CreateRotatingFileLogger(c.LogDir, name, c.logRotation())
+	if err != nil {
+		log.Printf("%v, logging to default logger instead", err)
+		return log.Default()
+	}
+	return logger
 }
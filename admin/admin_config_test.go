@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestEnvOverridesConfigFile(t *testing.T) {
+	t.Setenv("UKNOW_ADMIN_LISTEN_PORT", "9000")
+	t.Setenv("UKNOW_ADMIN_ENCRYPT_MESSAGES", "true")
+	t.Setenv("UKNOW_ADMIN_PAUSE_MSECS_AFTER_SERVE", "0")
+
+	config, err := readConfig("../test_configs/admin_config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.ListenPort != 9000 || !config.EncryptMessages || config.PauseMsecsAfterServe == nil || *config.PauseMsecsAfterServe != 0 {
+		t.Logf("expected the env to override the port, encryption and pause after serve, got %+v", config)
+		t.Fail()
+	}
+
+	// Not set in the env, so taken from the file.
+	if config.ListenIP != "localhost" || config.PauseMsecsBeforeNewTurn != 1000 || config.AESKeyString == "" {
+		t.Logf("expected the file's listen ip, pause before new turn and aes key, got %+v", config)
+		t.Fail()
+	}
+
+	if config.StartingHandCount != uknow.DefaultStartingHandCount || config.DeckCount != 1 {
+		t.Logf("expected the default starting hand and deck count, got %d and %d", config.StartingHandCount, config.DeckCount)
+		t.Fail()
+	}
+}
+
+func TestEnvOnlyConfig(t *testing.T) {
+	t.Setenv("UKNOW_ADMIN_LISTEN_IP", "0.0.0.0")
+	t.Setenv("UKNOW_ADMIN_LISTEN_PORT", "10540")
+
+	config, err := readConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ListenIP != "0.0.0.0" || config.ListenPort != 10540 || config.PauseMsecsAfterServe != nil {
+		t.Logf("expected the listen address from the env and no pause after serve set, got %+v", config)
+		t.Fail()
+	}
+
+	t.Setenv("UKNOW_ADMIN_ENCRYPT_MESSAGES", "true")
+	if _, err := readConfig(""); !errors.Is(err, errInvalidAdminConfig) {
+		t.Logf("expected encrypting without an aes key to be rejected, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestConfigWithoutListenPort(t *testing.T) {
+	t.Setenv("UKNOW_ADMIN_LISTEN_IP", "0.0.0.0")
+
+	if _, err := readConfig(""); !errors.Is(err, errInvalidAdminConfig) {
+		t.Logf("expected a missing listen port to be rejected, got %v", err)
+		t.Fail()
+	}
+
+	t.Setenv("UKNOW_ADMIN_LISTEN_PORT", "port")
+	if _, err := readConfig(""); err == nil {
+		t.Logf("expected a listen port that is not a number to be rejected")
+		t.Fail()
+	}
+}
+
+func TestTLSConfigNeedsCertAndKey(t *testing.T) {
+	t.Setenv("UKNOW_ADMIN_LISTEN_PORT", "10540")
+	t.Setenv("UKNOW_ADMIN_TLS_CERT_FILE", "admin.crt")
+
+	if _, err := readConfig(""); !errors.Is(err, errInvalidAdminConfig) {
+		t.Logf("expected a cert file without a key file to be rejected, got %v", err)
+		t.Fail()
+	}
+
+	t.Setenv("UKNOW_ADMIN_TLS_KEY_FILE", "admin.key")
+	config, err := readConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.servesTLS() {
+		t.Logf("expected the admin to serve https with %+v", config)
+		t.Fail()
+	}
+}
+
+func TestEnvConfigWithAESPassphrase(t *testing.T) {
+	t.Setenv("UKNOW_ADMIN_LISTEN_PORT", "10540")
+	t.Setenv("UKNOW_ADMIN_ENCRYPT_MESSAGES", "true")
+	t.Setenv("UKNOW_ADMIN_AES_PASSPHRASE", "correct horse battery staple")
+
+	if _, err := readConfig(""); !errors.Is(err, errInvalidAdminConfig) {
+		t.Logf("expected a passphrase without a salt to be rejected, got %v", err)
+		t.Fail()
+	}
+
+	t.Setenv("UKNOW_ADMIN_AES_SALT", "uknow-game-night")
+	config, err := readConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.AESPassphrase != "correct horse battery staple" || config.AESSalt != "uknow-game-night" {
+		t.Logf("expected the passphrase and salt from the env, got %+v", config)
+		t.Fail()
+	}
+
+	t.Setenv("UKNOW_ADMIN_AES_KEY", "55cfb2bd7e7803532bfcc3ca9f08c3e601e68b26d98fd4119dacace2ab668ce3")
+	if _, err := readConfig(""); !errors.Is(err, errInvalidAdminConfig) {
+		t.Logf("expected both a key and a passphrase to be rejected, got %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chzyer/readline/runes"
+)
+
+func TestIllegalAdminStateTransitionIsRejected(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+
+	if err := admin.setStateChecked(WaitingForPlayerDecision); !errors.Is(err, errorIllegalStateTransition) {
+		t.Fatalf("expected %v, got %v", errorIllegalStateTransition, err)
+	}
+	if admin.state != AddingPlayers {
+		t.Fatalf("expected state to stay %s, got %s", AddingPlayers, admin.state)
+	}
+
+	if err := admin.setStateChecked(ReadyToServeCards); err != nil {
+		t.Fatal(err)
+	}
+	if admin.state != ReadyToServeCards {
+		t.Fatalf("expected state %s, got %s", ReadyToServeCards, admin.state)
+	}
+}
+
+func TestAdminTurnCycleTransitionsAreAllowed(t *testing.T) {
+	turnCycle := []AdminState{
+		AddingPlayers,
+		ReadyToServeCards,
+		WaitingForPlayerDecision,
+		SyncingPlayerDecision,
+		DoneSyncingPlayerDecision,
+		PlayerChosenForTurn,
+		WaitingForPlayerDecision,
+		SyncingPlayerDecision,
+		DoneSyncingPlayerDecision,
+		HaveWinner,
+		ReadyToServeCards,
+	}
+
+	for i := 1; i < len(turnCycle); i++ {
+		if !isAllowedAdminTransition(turnCycle[i-1], turnCycle[i]) {
+			t.Logf("expected transition from %s to %s to be allowed", turnCycle[i-1], turnCycle[i])
+			t.Fail()
+		}
+	}
+}
+
+func TestColorPromptKeepsVisibleWidth(t *testing.T) {
+	prompt := "[have_winner:alice]> "
+	colored := colorPromptOfState(prompt, HaveWinner)
+
+	if !strings.HasPrefix(colored, ansiRed) || !strings.HasSuffix(colored, ansiReset) {
+		t.Fatalf("expected the have_winner prompt to be red, got %q", colored)
+	}
+	if width := runes.WidthAll(runes.ColorFilter([]rune(colored))); width != len(prompt) {
+		t.Logf("expected readline to measure the colored prompt as %d columns, got %d", len(prompt), width)
+		t.Fail()
+	}
+}
@@ -0,0 +1,137 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+// Creates an admin about to ask bob to challenge the wild draw 4 alice just
+// played, with the clock held and both players connected.
+func newAdminAskingBobToChallenge(t *testing.T) (*Admin, *fakeClock, map[string]*httptest.ResponseRecorder) {
+	wildDraw4 := uknow.Card{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild}
+	admin := newAdminWaitingForAlice(uknow.Deck{wildDraw4, {Number: 1, Color: uknow.ColorBlue}})
+
+	clock := newFakeClock()
+	admin.clock = clock
+
+	decisions := []uknow.PlayerDecision{
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: wildDraw4},
+		{Kind: uknow.PlayerDecisionWildCardChooseColor, WildCardChosenColor: uknow.ColorBlue},
+	}
+	if err := admin.table.EvalPlayerDecisionsNoTransferChan("alice", decisions); err != nil {
+		t.Fatal(err)
+	}
+	if admin.table.TableState != uknow.AwaitingWildDraw4ChallengeDecision || admin.table.PlayerOfNextTurn != "bob" {
+		t.Fatalf("expected bob to be asked to challenge, got state %s for %s", admin.table.TableState, admin.table.PlayerOfNextTurn)
+	}
+	admin.decisionEventsCompleted = 1
+	admin.state = PlayerChosenForTurn
+
+	recorderOfPlayer := make(map[string]*httptest.ResponseRecorder)
+	for _, playerName := range admin.table.PlayerNames {
+		recorderOfPlayer[playerName] = httptest.NewRecorder()
+		admin.sseWriterForPlayer[playerName] = sseWriter{responseWriter: recorderOfPlayer[playerName]}
+	}
+
+	admin.dispatchEventWithSSE(sseCommandSendChosenPlayerEventToAll{})
+	return admin, clock, recorderOfPlayer
+}
+
+func TestAdminDecidesNoChallengeOnTimeout(t *testing.T) {
+	admin, clock, recorderOfPlayer := newAdminAskingBobToChallenge(t)
+	bobHandCount := admin.table.HandOfPlayer["bob"].Len()
+
+	clock.Advance(DefaultChallengeTimeout)
+
+	recorderOfPlayer["bob"].Body.Reset()
+	if _, ok := admin.dispatchOneForTest(t).(sseCommandSyncPlayerDecisionEvent); !ok {
+		t.Fatal("expected the timed out decision to be synced")
+	}
+
+	if admin.table.HandOfPlayer["bob"].Len() != bobHandCount+4 || admin.table.PlayerOfNextTurn != "alice" {
+		t.Logf("expected bob to draw 4 and lose his turn, got %d cards and %s's turn", admin.table.HandOfPlayer["bob"].Len(), admin.table.PlayerOfNextTurn)
+		t.Fail()
+	}
+
+	// bob didn't send the decision, so he is synced and acks it too.
+	serverEvent, err := messages.ParseServerEventMessage(recorderOfPlayer["bob"].Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	syncEvent, ok := serverEvent.(messages.PlayerDecisionsSyncEvent)
+	if !ok || syncEvent.DecidingPlayer != "bob" || len(syncEvent.Decisions) != 1 || !syncEvent.Decisions[0].TimedOut || syncEvent.Decisions[0].Kind != uknow.PlayerDecisionDontChallenge {
+		t.Fatalf("expected bob to be synced a timed out no_challenge, got %+v", serverEvent)
+	}
+
+	wantAckId := makeAckIdOfDecisionSyncPlayer("bob", 1)
+	foundAck := false
+	for _, ackId := range admin.expectedAcksList.pendingAckIdsOfPlayer("bob") {
+		foundAck = foundAck || ackId == wantAckId
+	}
+	if !foundAck {
+		t.Logf("expected an ack %s from bob, got %v", wantAckId, admin.expectedAcksList.pendingAckIdsOfPlayer("bob"))
+		t.Fail()
+	}
+
+	// bob's own decisions come too late.
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionDoChallenge}},
+		DecidingPlayer:       "bob",
+		DecisionEventCounter: 1,
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected the late decisions to be dropped with status 200, got %d", resp.Code)
+	}
+	if admin.table.HandOfPlayer["bob"].Len() != bobHandCount+4 {
+		t.Logf("expected bob's late challenge to not be applied, got %d cards", admin.table.HandOfPlayer["bob"].Len())
+		t.Fail()
+	}
+}
+
+func TestChallengeInTimeStopsTimeout(t *testing.T) {
+	admin, clock, _ := newAdminAskingBobToChallenge(t)
+
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionDontChallenge}},
+		DecidingPlayer:       "bob",
+		DecisionEventCounter: 1,
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+
+	// The timeout passes before bob's decisions are synced.
+	clock.Advance(DefaultChallengeTimeout)
+
+	syncEvent, ok := (<-admin.sseControllerEventChan).(sseCommandSyncPlayerDecisionEvent)
+	if !ok || syncEvent.DecidedByAdmin || uknow.DecidedOnTimeout(syncEvent.Decisions) {
+		t.Fatalf("expected bob's own decisions to be synced, got %+v", syncEvent)
+	}
+
+	select {
+	case e := <-admin.sseControllerEventChan:
+		t.Fatalf("expected no decisions made on timeout, got %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestPlayersCannotSendTimedOutDecisions(t *testing.T) {
+	admin, _, _ := newAdminAskingBobToChallenge(t)
+
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionDontChallenge, TimedOut: true}},
+		DecidingPlayer:       "bob",
+		DecisionEventCounter: 1,
+	})
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.Code)
+	}
+	if _, ok := admin.lastDecisionEventCounterOfPlayer["bob"]; ok {
+		t.Fatal("expected bob's decisions to not be accepted")
+	}
+}
@@ -0,0 +1,29 @@
+package admin
+
+import "time"
+
+// Source of the current time and of the timers behind the admin's timeouts.
+// Tests pass a fake clock to fire timeouts without sleeping.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	After(d time.Duration) <-chan time.Time
+}
+
+type Timer interface {
+	Chan() <-chan time.Time
+	Stop() bool
+}
+
+// Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) NewTimer(d time.Duration) Timer         { return realTimer{time.NewTimer(d)} }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) Chan() <-chan time.Time { return t.C }
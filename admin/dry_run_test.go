@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func TestDryRunDecisions(t *testing.T) {
+	redSeven := uknow.Card{Number: 7, Color: uknow.ColorRed}
+	blueOne := uknow.Card{Number: 1, Color: uknow.ColorBlue}
+	admin := newAdminWaitingForAlice(uknow.Deck{redSeven, blueOne})
+	admin.userConfig.DryRunDecisions = true
+	table := admin.table
+	tableBefore := table.Clone()
+
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redSeven}},
+		DecidingPlayer: "alice",
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected the legal play to succeed, got status %d", resp.Code)
+	}
+
+	var gameState messages.GameStateMessage
+	if err := messages.DecryptAndDecodeJSON(&gameState, resp.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+	if top := gameState.Table.DiscardedPile.MustTop(); !top.IsEqual(redSeven) || gameState.Table.PlayerOfNextTurn != "bob" {
+		t.Logf("expected red 7 on the pile and bob to play next, got %s and %s", top.String(), gameState.Table.PlayerOfNextTurn)
+		t.Fail()
+	}
+	if !gameState.Table.HandOfPlayer["bob"].HasHiddenCards() {
+		t.Logf("expected bob's hand to be hidden in alice's view, got %s", gameState.Table.HandOfPlayer["bob"])
+		t.Fail()
+	}
+
+	resp = postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: blueOne}},
+		DecidingPlayer: "alice",
+	})
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected the illegal play to be rejected, got status %d", resp.Code)
+	}
+
+	var errorPayload messages.UnwrappedErrorPayload
+	if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, nil); err != nil || len(errorPayload.Errors) == 0 {
+		t.Logf("expected an error payload, got %+v, err: %v", errorPayload, err)
+		t.Fail()
+	}
+
+	// Neither decision touched the admin's table or was synced.
+	if !table.Equal(tableBefore) {
+		t.Logf("expected the admin table to be unchanged, alice's hand: %s, discard pile: %s", table.HandOfPlayer["alice"], table.DiscardedPile)
+		t.Fail()
+	}
+	if admin.state != WaitingForPlayerDecision {
+		t.Logf("expected admin state to stay %s, got %s", WaitingForPlayerDecision, admin.state)
+		t.Fail()
+	}
+
+	select {
+	case e := <-admin.sseControllerEventChan:
+		t.Fatalf("dry run decisions were forwarded: %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -1,6 +1,7 @@
 package admin
 
 import (
+	"container/heap"
 	"log"
 	"strings"
 	"sync"
@@ -19,74 +20,195 @@ type expectedAck struct {
 type pendingAck struct {
 	expectedAck
 
-	enqueueTime     time.Time
-	timeout         time.Duration
-	ackReceivedChan chan struct{}
-	onAck           func()
-	onTimeout       func()
+	deadline  time.Time
+	onAck     func()
+	onTimeout func()
+
+	// Set once the ack is received or timed out. The deadline of a done ack
+	// is dropped from the heap lazily.
+	done bool
 }
 
 func (ack expectedAck) equal(ack1 expectedAck) bool {
 	return ack.ackId == ack1.ackId && ack.ackerPlayerName == ack1.ackerPlayerName
 }
 
+// Min-heap of pending acks by deadline, for container/heap.
+type ackDeadlineHeap []*pendingAck
+
+func (h ackDeadlineHeap) Len() int           { return len(h) }
+func (h ackDeadlineHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+func (h ackDeadlineHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *ackDeadlineHeap) Push(x interface{}) {
+	*h = append(*h, x.(*pendingAck))
+}
+
+func (h *ackDeadlineHeap) Pop() interface{} {
+	old := *h
+	ack := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return ack
+}
+
+// Timeouts of all pending acks are handled by a single reaper goroutine with a
+// single timer set to the earliest deadline. The reaper exits when nothing is
+// pending and is started again by addPending.
 type expectedAcksList struct {
 	mu               sync.Mutex
 	pendingAcks      []*pendingAck
+	deadlines        ackDeadlineHeap
 	preemptiveAcks   []expectedAck
 	chNewAckReceived chan expectedAck
 	logger           *log.Logger
+	clock            Clock
+
+	reaperRunning      bool
+	reaperTimer        Timer         // nil if no ack is pending
+	chReaperTimerReset chan struct{} // Tells the reaper to wait on the new reaperTimer
 }
 
-func newExpectedAcksState(logger *log.Logger) *expectedAcksList {
+func newExpectedAcksState(logger *log.Logger, clock Clock) *expectedAcksList {
 	return &expectedAcksList{
-		pendingAcks:      make([]*pendingAck, 0, 16),
-		preemptiveAcks:   make([]expectedAck, 0, 16),
-		chNewAckReceived: make(chan expectedAck),
-		logger:           logger,
+		pendingAcks:        make([]*pendingAck, 0, 16),
+		deadlines:          make(ackDeadlineHeap, 0, 16),
+		preemptiveAcks:     make([]expectedAck, 0, 16),
+		chNewAckReceived:   make(chan expectedAck),
+		chReaperTimerReset: make(chan struct{}, 1),
+		logger:             logger,
+		clock:              clock,
 	}
 }
 
+// Waits for the ack until the timeout. Exactly one of onAck and onTimeout is
+// called, on a goroutine of its own, so they may take the admin's stateMutex.
 func (es *expectedAcksList) addPending(ack expectedAck, timeout time.Duration, onAck, onTimeout func()) {
 	es.mu.Lock()
 	defer es.mu.Unlock()
 	es.logger.Printf("Adding new expecting ack to list %+v", ack)
 
+	// Check if there is already an ack for this pending
+	for i, preemptiveAck := range es.preemptiveAcks {
+		if preemptiveAck.equal(ack) {
+			es.preemptiveAcks = slices.Delete(es.preemptiveAcks, i, i+1)
+			es.logger.Printf("found matching preemptive ack: %+v", preemptiveAck)
+			go onAck()
+			return
+		}
+	}
+
 	pendingAck := &pendingAck{
-		expectedAck:     ack,
-		enqueueTime:     time.Now(),
-		timeout:         timeout,
-		onAck:           onAck,
-		onTimeout:       onTimeout,
-		ackReceivedChan: make(chan struct{}),
+		expectedAck: ack,
+		deadline:    es.clock.Now().Add(timeout),
+		onAck:       onAck,
+		onTimeout:   onTimeout,
 	}
+	es.pendingAcks = append(es.pendingAcks, pendingAck)
+	heap.Push(&es.deadlines, pendingAck)
 
-	// Check if there is already an ack for this pending
-	for i, ack := range es.preemptiveAcks {
-		if ack.equal(pendingAck.expectedAck) {
-			es.preemptiveAcks = slices.Delete(es.preemptiveAcks, i, i+1)
-			es.logger.Printf("found matching preemptive ack: %+v", ack)
+	if es.deadlines[0] == pendingAck {
+		es.resetReaperTimerNoLock()
+	}
+}
+
+// Sets the reaper timer to the earliest deadline of the pending acks, starting
+// the reaper if needed. Caller must hold mu.
+func (es *expectedAcksList) resetReaperTimerNoLock() {
+	if es.reaperTimer != nil {
+		es.reaperTimer.Stop()
+		es.reaperTimer = nil
+	}
+
+	for len(es.deadlines) != 0 && es.deadlines[0].done {
+		heap.Pop(&es.deadlines)
+	}
 
-			// Horrible
-			go func() {
-				pendingAck.ackReceivedChan <- struct{}{}
-			}()
+	if len(es.deadlines) != 0 {
+		es.reaperTimer = es.clock.NewTimer(es.deadlines[0].deadline.Sub(es.clock.Now()))
+
+		if !es.reaperRunning {
+			es.reaperRunning = true
+			go es.runReaper()
 			return
 		}
+	} else if !es.reaperRunning {
+		return
+	}
+
+	// The reaper waits on the new timer, or exits if nothing is pending.
+	select {
+	case es.chReaperTimerReset <- struct{}{}:
+	default:
 	}
+}
+
+func (es *expectedAcksList) runReaper() {
+	for {
+		es.mu.Lock()
+		if es.reaperTimer == nil {
+			es.reaperRunning = false
+			es.mu.Unlock()
+			return
+		}
+		timerChan := es.reaperTimer.Chan()
+		es.mu.Unlock()
 
-	go func() {
-		timer := time.NewTimer(pendingAck.timeout)
 		select {
-		case <-timer.C:
-			onTimeout()
-		case <-pendingAck.ackReceivedChan:
-			timer.Stop()
-			onAck()
+		case <-timerChan:
+			es.reapTimedOutAcks()
+		case <-es.chReaperTimerReset:
 		}
-	}()
+	}
+}
 
-	es.pendingAcks = append(es.pendingAcks, pendingAck)
+// Removes the acks past their deadline and calls their onTimeout.
+func (es *expectedAcksList) reapTimedOutAcks() {
+	es.mu.Lock()
+
+	now := es.clock.Now()
+	var timedOutAcks []*pendingAck
+	for len(es.deadlines) != 0 && !es.deadlines[0].deadline.After(now) {
+		pendingAck := heap.Pop(&es.deadlines).(*pendingAck)
+		if pendingAck.done {
+			continue
+		}
+		pendingAck.done = true
+		es.removePendingNoLock(pendingAck)
+		timedOutAcks = append(timedOutAcks, pendingAck)
+	}
+	es.resetReaperTimerNoLock()
+
+	es.mu.Unlock()
+
+	for _, pendingAck := range timedOutAcks {
+		es.logger.Printf("Ack timed out: %s", pendingAck.ackId)
+		go pendingAck.onTimeout()
+	}
+}
+
+// Caller must hold mu.
+func (es *expectedAcksList) removePendingNoLock(ack *pendingAck) {
+	for i, pendingAck := range es.pendingAcks {
+		if pendingAck == ack {
+			es.pendingAcks = slices.Delete(es.pendingAcks, i, i+1)
+			return
+		}
+	}
+}
+
+// Forgets the pending and preemptive acks, without calling their onAck or
+// onTimeout.
+func (es *expectedAcksList) dropAll() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	for _, pendingAck := range es.pendingAcks {
+		pendingAck.done = true
+	}
+	es.pendingAcks = es.pendingAcks[:0]
+	es.preemptiveAcks = es.preemptiveAcks[:0]
+	es.resetReaperTimerNoLock()
 }
 
 func (es *expectedAcksList) ackIds() string {
@@ -100,29 +222,47 @@ func (es *expectedAcksList) ackIds() string {
 	return sb.String()
 }
 
+// Ids of the acks still pending from the player.
+func (es *expectedAcksList) pendingAckIdsOfPlayer(playerName string) []string {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	var ackIds []string
+	for _, ack := range es.pendingAcks {
+		if ack.ackerPlayerName == playerName {
+			ackIds = append(ackIds, ack.ackId)
+		}
+	}
+	return ackIds
+}
+
 func (es *expectedAcksList) waitForAcks() {
 	for expectedAck := range es.chNewAckReceived {
 		es.mu.Lock()
 
-		haveMatchingPendingAck := false
-
-		for i, pendingAck := range es.pendingAcks {
-			if !pendingAck.expectedAck.equal(expectedAck) {
-				continue
+		var ackedPendingAck *pendingAck
+		for _, pendingAck := range es.pendingAcks {
+			if pendingAck.expectedAck.equal(expectedAck) {
+				ackedPendingAck = pendingAck
+				break
 			}
-
-			es.logger.Printf("Acking the ack: %s", pendingAck.ackId)
-
-			es.pendingAcks = slices.Delete(es.pendingAcks, i, i+1)
-			pendingAck.ackReceivedChan <- struct{}{}
-			haveMatchingPendingAck = true
-			break
 		}
 
-		if !haveMatchingPendingAck {
+		if ackedPendingAck == nil {
 			es.preemptiveAcks = append(es.preemptiveAcks, expectedAck)
+			es.mu.Unlock()
+			continue
+		}
+
+		es.logger.Printf("Acking the ack: %s", ackedPendingAck.ackId)
+
+		ackedPendingAck.done = true
+		es.removePendingNoLock(ackedPendingAck)
+		if es.deadlines[0] == ackedPendingAck {
+			es.resetReaperTimerNoLock()
 		}
 
 		es.mu.Unlock()
+
+		go ackedPendingAck.onAck()
 	}
 }
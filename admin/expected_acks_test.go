@@ -0,0 +1,266 @@
+package admin
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Clock that only moves when advanced. Timers fire once the clock is advanced
+// up to their deadline.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{clock: c, deadline: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		// Already due, like a real timer.
+		timer.c <- c.now
+		return timer
+	}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).Chan()
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+
+	pendingTimers := c.timers[:0]
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pendingTimers = append(pendingTimers, timer)
+		} else {
+			timer.c <- c.now
+		}
+	}
+	c.timers = pendingTimers
+}
+
+func (c *fakeClock) pendingTimerCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+func (t *fakeTimer) Chan() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func TestPendingAckTimesOutOnClockAdvance(t *testing.T) {
+	clock := newFakeClock()
+	acks := newExpectedAcksState(log.Default(), clock)
+
+	timedOut := make(chan struct{})
+	acks.addPending(
+		expectedAck{ackId: "decision_sync_alice", ackerPlayerName: "alice"},
+		5*time.Second,
+		func() { t.Errorf("expected no ack") },
+		func() { close(timedOut) },
+	)
+
+	clock.Advance(4 * time.Second)
+	if clock.pendingTimerCount() != 1 {
+		t.Fatalf("expected the ack timer to be pending before its timeout")
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case <-timedOut:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected onTimeout to be called after advancing the clock past the timeout")
+	}
+}
+
+func TestManyPendingAcksWithMixedOutcomes(t *testing.T) {
+	clock := newFakeClock()
+	acks := newExpectedAcksState(log.Default(), clock)
+	go acks.waitForAcks()
+
+	const ackCount = 100
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	outcomeOfAck := make(map[string][]string)
+	recordOutcome := func(ackId, outcome string) {
+		mu.Lock()
+		defer mu.Unlock()
+		outcomeOfAck[ackId] = append(outcomeOfAck[ackId], outcome)
+		wg.Done()
+	}
+
+	wg.Add(ackCount)
+	for i := 0; i < ackCount; i++ {
+		ackId := fmt.Sprintf("ack_%d", i)
+		acks.addPending(
+			expectedAck{ackId: ackId, ackerPlayerName: "alice"},
+			time.Duration(ackCount-i)*time.Second,
+			func() { recordOutcome(ackId, "ack") },
+			func() { recordOutcome(ackId, "timeout") },
+		)
+	}
+
+	if count := clock.pendingTimerCount(); count != 1 {
+		t.Fatalf("expected a single timer for all pending acks, got %d", count)
+	}
+
+	// Ack the even ones, including the one with the earliest deadline.
+	for i := 0; i < ackCount; i += 2 {
+		acks.chNewAckReceived <- expectedAck{ackId: fmt.Sprintf("ack_%d", i), ackerPlayerName: "alice"}
+	}
+	for len(acks.pendingAckIdsOfPlayer("alice")) != ackCount/2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Time out the odd ones.
+	clock.Advance(ackCount * time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected every ack to be acked or timed out, got %d outcomes", len(outcomeOfAck))
+	}
+
+	for i := 0; i < ackCount; i++ {
+		ackId := fmt.Sprintf("ack_%d", i)
+		wantOutcome := "timeout"
+		if i%2 == 0 {
+			wantOutcome = "ack"
+		}
+		if outcomes := outcomeOfAck[ackId]; len(outcomes) != 1 || outcomes[0] != wantOutcome {
+			t.Logf("%s: expected a single %s, got %v", ackId, wantOutcome, outcomes)
+			t.Fail()
+		}
+	}
+
+	if pendingAckIds := acks.pendingAckIdsOfPlayer("alice"); len(pendingAckIds) != 0 {
+		t.Logf("expected no pending acks, got %v", pendingAckIds)
+		t.Fail()
+	}
+	if count := clock.pendingTimerCount(); count != 0 {
+		t.Logf("expected no timer once nothing is pending, got %d", count)
+		t.Fail()
+	}
+}
+
+func TestReaperExitsWhenNothingIsPending(t *testing.T) {
+	clock := newFakeClock()
+	acks := newExpectedAcksState(log.Default(), clock)
+	go acks.waitForAcks()
+
+	reaperRunning := func() bool {
+		acks.mu.Lock()
+		defer acks.mu.Unlock()
+		return acks.reaperRunning
+	}
+	waitForReaperExit := func(reason string) {
+		deadline := time.Now().Add(5 * time.Second)
+		for reaperRunning() {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the reaper to exit %s", reason)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	ack := expectedAck{ackId: "decision_sync_alice", ackerPlayerName: "alice"}
+	acks.addPending(ack, 5*time.Second, func() {}, func() { t.Errorf("expected no timeout") })
+	if !reaperRunning() {
+		t.Fatal("expected the reaper to run while an ack is pending")
+	}
+	acks.chNewAckReceived <- ack
+	waitForReaperExit("once the ack is received")
+
+	acks.addPending(ack, 5*time.Second, func() { t.Errorf("expected no ack") }, func() { t.Errorf("expected no timeout") })
+	acks.dropAll()
+	waitForReaperExit("once the acks are dropped")
+
+	// A new pending ack starts it again.
+	timedOut := make(chan struct{})
+	acks.addPending(ack, 5*time.Second, func() { t.Errorf("expected no ack") }, func() { close(timedOut) })
+	clock.Advance(5 * time.Second)
+	select {
+	case <-timedOut:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected onTimeout to be called by the restarted reaper")
+	}
+	waitForReaperExit("once the ack times out")
+}
+
+func TestPreemptiveAckCallsOnAck(t *testing.T) {
+	clock := newFakeClock()
+	acks := newExpectedAcksState(log.Default(), clock)
+	go acks.waitForAcks()
+
+	ack := expectedAck{ackId: "alice_connected_to_bob", ackerPlayerName: "alice"}
+	acks.chNewAckReceived <- ack
+
+	// The ack is stored once waitForAcks is done with it.
+	for {
+		acks.mu.Lock()
+		stored := len(acks.preemptiveAcks) == 1
+		acks.mu.Unlock()
+		if stored {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	acked := make(chan struct{})
+	acks.addPending(ack, 5*time.Second, func() { close(acked) }, func() { t.Errorf("expected no timeout") })
+
+	select {
+	case <-acked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the preemptive ack to call onAck")
+	}
+	if count := clock.pendingTimerCount(); count != 0 {
+		t.Logf("expected no timer for an ack received early, got %d", count)
+		t.Fail()
+	}
+}
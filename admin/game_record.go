@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/nrawrx3/uknow"
+)
+
+// Starts recording the game just served to a new file in record_games_dir, if
+// set, finishing the record of the previous game first. The decisions of each
+// turn are recorded as they are synced, and the table's game events through
+// its EventSink. Caller must hold the stateMutex.
+func (admin *Admin) startGameRecordNoLock() {
+	admin.finishGameRecordNoLock("")
+
+	if admin.userConfig.RecordGamesDir == "" {
+		return
+	}
+
+	pattern := fmt.Sprintf("game-%s-*.jsonl", admin.clock.Now().Format("20060102-150405"))
+	if admin.roomCode != "" {
+		pattern = "room-" + admin.roomCode + "-" + pattern
+	}
+	file, err := os.CreateTemp(admin.userConfig.RecordGamesDir, pattern)
+	if err != nil {
+		admin.logger.Printf("Not recording the game: %v", err)
+		return
+	}
+
+	admin.gameRecordFile = file
+	admin.gameRecord = uknow.NewGameRecordWriter(file)
+	admin.gameRecord.RecordStart(admin.table)
+	admin.table.EventSink = admin.gameRecord
+	admin.logger.Printf("Recording the game to %s", file.Name())
+}
+
+// Ends the record of the current game, if any, with the given winner. The
+// winner is empty if the game didn't finish. Caller must hold the stateMutex.
+func (admin *Admin) finishGameRecordNoLock(winner string) {
+	if admin.gameRecord == nil {
+		return
+	}
+
+	admin.gameRecord.RecordEnd(winner)
+	if err := admin.gameRecord.Err(); err != nil {
+		admin.logger.Printf("Failed to record the game to %s: %v", admin.gameRecordFile.Name(), err)
+	}
+	if err := admin.gameRecordFile.Close(); err != nil {
+		admin.logger.Printf("Failed to close the game record %s: %v", admin.gameRecordFile.Name(), err)
+	} else {
+		log.Printf("Recorded the game to %s", admin.gameRecordFile.Name())
+	}
+
+	if admin.table.EventSink == uknow.GameEventSink(admin.gameRecord) {
+		admin.table.EventSink = nil
+	}
+	admin.gameRecord = nil
+	admin.gameRecordFile = nil
+}
+
+// Prints a turn by turn narration of a game recorded with record_games_dir.
+func runReplay(recordFile string) {
+	file, err := os.Open(recordFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	entries, err := uknow.ReadGameRecord(file)
+	if err != nil {
+		log.Fatalf("failed to read game record %s: %v", recordFile, err)
+	}
+	uknow.NarrateGameRecord(os.Stdout, entries)
+}
@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+// Fetches the admin's table with GET /game_state and sets it as the given
+// player's local table, the same way the client resyncs.
+func tableFromGameState(t *testing.T, admin *Admin, playerName string) *uknow.Table {
+	recorder := httptest.NewRecorder()
+	admin.handleGetGameState(recorder, httptest.NewRequest("GET", "/game_state", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	var gameState messages.GameStateMessage
+	if err := messages.DecryptAndDecodeJSON(&gameState, recorder.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	playerTable := uknow.NewTable(playerName, log.Default())
+	gameState.Table.LocalPlayerName = playerName
+	playerTable.Set(&gameState.Table)
+	return playerTable
+}
+
+// bob's local table has lost track of alice's red 1, so syncing alice's play
+// must fail on it, and fetching the admin's game state must bring it back in
+// sync.
+func TestDivergentTableResyncsFromGameState(t *testing.T) {
+	redOne := uknow.Card{Number: 1, Color: uknow.ColorRed}
+	admin := newAdminWaitingForAlice(uknow.Deck{redOne, {Number: 7, Color: uknow.ColorGreen}})
+
+	bobTable := tableFromGameState(t, admin, "bob")
+	bobTable.HandOfPlayer["alice"] = uknow.Deck{{Number: 7, Color: uknow.ColorGreen}}
+
+	decisions := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redOne}}
+	if err := admin.table.EvalPlayerDecisionsNoTransferChan("alice", decisions); err != nil {
+		t.Fatal(err)
+	}
+
+	err := bobTable.EvalPlayerDecisionsNoTransferChan("alice", decisions)
+	var errEvalDecision *uknow.EvalDecisionError
+	if !errors.As(err, &errEvalDecision) || !errors.Is(err, uknow.ErrCardNotInHand) {
+		t.Fatalf("expected divergent table to reject the play with %v, got %v", uknow.ErrCardNotInHand, err)
+	}
+
+	bobTable = tableFromGameState(t, admin, "bob")
+
+	for _, playerName := range admin.table.PlayerNames {
+		if bobTable.HandOfPlayer[playerName].Encode() != admin.table.HandOfPlayer[playerName].Encode() {
+			t.Logf("expected %s's hand %s after resync, got %s", playerName, admin.table.HandOfPlayer[playerName].Encode(), bobTable.HandOfPlayer[playerName].Encode())
+			t.Fail()
+		}
+	}
+
+	if bobTable.LocalPlayerName != "bob" || bobTable.PlayerOfNextTurn != admin.table.PlayerOfNextTurn || !bobTable.DiscardedPile.MustTop().IsEqual(redOne) {
+		t.Fatalf("expected resynced table to match admin's, next turn: %s, discard pile: %s", bobTable.PlayerOfNextTurn, bobTable.DiscardedPile)
+	}
+}
+
+// bob never got the served cards event, the game state snapshot must bring his
+// table to the one the admin served.
+func TestMissedServedCardsRecoveredFromGameState(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+
+	if bobTable := tableFromGameState(t, admin, "bob"); bobTable.IsShuffled {
+		t.Fatal("expected the snapshot before set_ready to not be shuffled")
+	}
+
+	if resp := postSetReady(admin, "alice"); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+
+	bobTable := tableFromGameState(t, admin, "bob")
+
+	if !bobTable.IsShuffled || bobTable.TableState != admin.table.TableState || bobTable.PlayerOfNextTurn != admin.table.PlayerOfNextTurn {
+		t.Fatalf("expected bob's table to be served, got shuffled: %v, state: %s, next turn: %s", bobTable.IsShuffled, bobTable.TableState, bobTable.PlayerOfNextTurn)
+	}
+
+	for _, playerName := range admin.table.PlayerNames {
+		if bobTable.HandOfPlayer[playerName].Encode() != admin.table.HandOfPlayer[playerName].Encode() {
+			t.Logf("expected %s's served hand %s, got %s", playerName, admin.table.HandOfPlayer[playerName].Encode(), bobTable.HandOfPlayer[playerName].Encode())
+			t.Fail()
+		}
+	}
+
+	if bobTable.DrawDeck.Len() != admin.table.DrawDeck.Len() || !bobTable.DiscardedPile.MustTop().IsEqual(admin.table.DiscardedPile.MustTop()) {
+		t.Fatalf("expected bob's decks to match the admin's, draw deck: %d, discard pile: %s", bobTable.DrawDeck.Len(), bobTable.DiscardedPile)
+	}
+}
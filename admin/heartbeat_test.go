@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func TestHeartbeatSentEveryInterval(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+	clock := newFakeClock()
+	admin.clock = clock
+
+	recorder := httptest.NewRecorder()
+	admin.sseWriterForPlayer["alice"] = sseWriter{responseWriter: recorder}
+
+	go admin.runHeartbeats()
+	defer close(admin.sseControllerStopChan)
+
+	// Wait for the heartbeat timer before advancing past it.
+	for clock.pendingTimerCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(DefaultHeartbeatInterval)
+
+	if _, ok := admin.dispatchOneForTest(t).(sseCommandSendHeartbeatToAll); !ok {
+		t.Fatal("expected a heartbeat to be dispatched")
+	}
+
+	serverEvent, err := messages.ParseServerEventMessage(recorder.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := serverEvent.(messages.HeartbeatEvent); !ok {
+		t.Fatalf("expected a heartbeat event, got %T", serverEvent)
+	}
+}
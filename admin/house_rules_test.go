@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func postHouseRules(admin *Admin, houseRules uknow.HouseRules) *httptest.ResponseRecorder {
+	var b bytes.Buffer
+	messages.EncodeJSONAndEncrypt(&messages.HouseRulesMessage{HouseRules: houseRules}, &b, nil)
+
+	recorder := httptest.NewRecorder()
+	admin.handleSetHouseRules(recorder, httptest.NewRequest("POST", "/rules", &b))
+	return recorder
+}
+
+func getHouseRules(t *testing.T, admin *Admin) uknow.HouseRules {
+	recorder := httptest.NewRecorder()
+	admin.handleGetHouseRules(recorder, httptest.NewRequest("GET", "/rules", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	var houseRulesMessage messages.HouseRulesMessage
+	if err := messages.DecryptAndDecodeJSON(&houseRulesMessage, recorder.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+	return houseRulesMessage.HouseRules
+}
+
+func TestHouseRulesLockedAfterReady(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+
+	wantRules := uknow.HouseRules{SevenZero: true, JumpIn: true}
+	if resp := postHouseRules(admin, wantRules); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+	if houseRules := getHouseRules(t, admin); houseRules != wantRules {
+		t.Fatalf("expected house rules %s, got %s", wantRules.String(), houseRules.String())
+	}
+
+	if resp := postSetReady(admin, "alice"); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+	if admin.table.HouseRules != wantRules {
+		t.Fatalf("expected the served table to keep house rules %s, got %s", wantRules.String(), admin.table.HouseRules.String())
+	}
+
+	if resp := postHouseRules(admin, uknow.HouseRules{UndoDrawReturnsCard: true}); resp.Code != http.StatusForbidden {
+		t.Logf("expected changing the rules after set_ready to be forbidden, got status %d", resp.Code)
+		t.Fail()
+	}
+	if houseRules := getHouseRules(t, admin); houseRules != wantRules {
+		t.Logf("expected house rules to stay %s, got %s", wantRules.String(), houseRules.String())
+		t.Fail()
+	}
+}
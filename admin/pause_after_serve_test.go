@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/utils"
+)
+
+func TestPauseAfterServeReadsFromConfig(t *testing.T) {
+	pauseMsecs := 500
+	clock := newFakeClock()
+	admin := NewAdmin(&ConfigNewAdmin{
+		ListenAddr: utils.HostPortProtocol{IP: "127.0.0.1", Port: 0},
+		Table:      uknow.NewAdminTable(log.Default()),
+		Clock:      clock,
+
+		skipSSEController: true,
+	}, &AdminUserConfig{PauseMsecsBeforeNewTurn: 3000, PauseMsecsAfterServe: &pauseMsecs})
+
+	go admin.dispatchEventWithSSE(sseCommandSendServedCardsEventToAll{})
+
+	for clock.pendingTimerCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Advance(499 * time.Millisecond)
+	select {
+	case e := <-admin.sseControllerEventChan:
+		t.Fatalf("expected no event before the pause after serve is over, got %T", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	clock.Advance(time.Millisecond)
+	select {
+	case e := <-admin.sseControllerEventChan:
+		if _, ok := e.(sseCommandSendChosenPlayerEventToAll); !ok {
+			t.Fatalf("expected the chosen player event, got %T", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the first player to be chosen after the pause after serve")
+	}
+}
+
+func TestPauseAfterServeDefault(t *testing.T) {
+	var config AdminUserConfig
+	if pause := config.pauseAfterServe(); pause != DefaultPauseAfterServe {
+		t.Logf("expected default pause %s, got %s", DefaultPauseAfterServe, pause)
+		t.Fail()
+	}
+
+	noPause := 0
+	config.PauseMsecsAfterServe = &noPause
+	if pause := config.pauseAfterServe(); pause != 0 {
+		t.Logf("expected no pause, got %s", pause)
+		t.Fail()
+	}
+}
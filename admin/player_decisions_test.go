@@ -0,0 +1,386 @@
+package admin
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+)
+
+func postDecisions(admin *Admin, request messages.PlayerDecisionsRequest) *httptest.ResponseRecorder {
+	var b bytes.Buffer
+	messages.EncodeJSONAndEncrypt(&request, &b, nil)
+
+	recorder := httptest.NewRecorder()
+	admin.handlePlayerDecisionsEvent(recorder, httptest.NewRequest("POST", "/player_decisions", &b))
+	return recorder
+}
+
+// Creates an admin waiting for alice's decision, with alice holding the given
+// hand and a red 5 on top of the discard pile.
+func newAdminWaitingForAlice(aliceHand uknow.Deck) *Admin {
+	table := uknow.NewAdminTable(log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.HandOfPlayer["alice"] = aliceHand
+	table.HandOfPlayer["bob"] = uknow.Deck{{Number: 2, Color: uknow.ColorBlue}}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+	table.ShufflerName = "alice"
+	table.PlayerOfNextTurn = "alice"
+	table.TableState = uknow.StartOfTurn
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+
+	admin := NewAdmin(&ConfigNewAdmin{
+		ListenAddr: utils.HostPortProtocol{IP: "127.0.0.1", Port: 0},
+		Table:      table,
+
+		skipSSEController: true,
+	}, &AdminUserConfig{StartingHandCount: uknow.DefaultStartingHandCount})
+	admin.state = WaitingForPlayerDecision
+
+	go func() {
+		for range admin.expectedAcksList.chNewAckReceived {
+		}
+	}()
+	return admin
+}
+
+func TestDuplicatePlayerDecisionsAreAppliedOnce(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+	table := admin.table
+	drawDeckSize := table.DrawDeck.Len()
+
+	request := messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+		DecidingPlayer:       "alice",
+		DecisionEventCounter: 0,
+	}
+
+	for i := 0; i < 2; i++ {
+		if resp := postDecisions(admin, request); resp.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i, resp.Code)
+		}
+	}
+
+	// Only the first request should have been forwarded to the SSE controller.
+	syncEvent := <-admin.sseControllerEventChan
+
+	select {
+	case e := <-admin.sseControllerEventChan:
+		t.Fatalf("duplicate decisions were forwarded: %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	admin.dispatchEventWithSSE(syncEvent)
+
+	if table.DrawDeck.Len() != drawDeckSize-1 {
+		t.Logf("expected draw deck size %d, got %d", drawDeckSize-1, table.DrawDeck.Len())
+		t.Fail()
+	}
+
+	if table.HandOfPlayer["alice"].Len() != 2 {
+		t.Logf("expected alice to have 2 cards, got %d", table.HandOfPlayer["alice"].Len())
+		t.Fail()
+	}
+}
+
+func TestIllegalPlayerDecisionsAreRejectedBeforeAccepting(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+	table := admin.table
+
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: 1, Color: uknow.ColorBlue}}},
+		DecidingPlayer:       "alice",
+		DecisionEventCounter: 0,
+	})
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.Code)
+	}
+
+	select {
+	case e := <-admin.sseControllerEventChan:
+		t.Fatalf("illegal decisions were forwarded: %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if _, ok := admin.lastDecisionEventCounterOfPlayer["alice"]; ok || table.HandOfPlayer["alice"].Len() != 1 {
+		t.Fatalf("expected alice's decisions to not be accepted, got alice's hand %s", table.HandOfPlayer["alice"])
+	}
+
+	// alice can still decide for the turn.
+	resp = postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+		DecidingPlayer:       "alice",
+		DecisionEventCounter: 0,
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+	if _, ok := admin.dispatchOneForTest(t).(sseCommandSyncPlayerDecisionEvent); !ok || table.HandOfPlayer["alice"].Len() != 2 {
+		t.Fatalf("expected alice's draw to be synced, got alice's hand %s", table.HandOfPlayer["alice"])
+	}
+}
+
+func TestStalePlayerDecisionsAreRejected(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+	admin.decisionEventsCompleted = 3
+	table := admin.table
+	drawDeckSize := table.DrawDeck.Len()
+
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+		DecidingPlayer:       "alice",
+		DecisionEventCounter: 2,
+	})
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", resp.Code)
+	}
+
+	var errorPayload messages.UnwrappedErrorPayload
+	if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !errorPayload.FirstErrorIs(messages.StaleTurnError) {
+		t.Logf("expected a stale turn error, got %+v", errorPayload)
+		t.Fail()
+	}
+
+	select {
+	case e := <-admin.sseControllerEventChan:
+		t.Fatalf("stale decisions were forwarded: %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if table.DrawDeck.Len() != drawDeckSize || table.HandOfPlayer["alice"].Len() != 1 || admin.state != WaitingForPlayerDecision {
+		t.Logf("expected the table to be unchanged, got draw deck size %d, alice's hand %s, state %s", table.DrawDeck.Len(), table.HandOfPlayer["alice"], admin.state)
+		t.Fail()
+	}
+
+	// The decisions for the current turn are still accepted.
+	resp = postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+		DecidingPlayer:       "alice",
+		DecisionEventCounter: 3,
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+}
+
+func TestPlayerDecisionsRejectedOutOfState(t *testing.T) {
+	wrongStates := []AdminState{
+		AddingPlayers,
+		ReadyToServeCards,
+		PlayerChosenForTurn,
+		SyncingPlayerDecision,
+		DoneSyncingPlayerDecision,
+		HaveWinner,
+	}
+
+	for _, state := range wrongStates {
+		t.Run(string(state), func(t *testing.T) {
+			admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+			admin.state = state
+			table := admin.table
+			drawDeckSize := table.DrawDeck.Len()
+
+			resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+				Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+				DecidingPlayer: "alice",
+			})
+			if resp.Code != http.StatusConflict {
+				t.Fatalf("expected status 409, got %d", resp.Code)
+			}
+
+			var errorPayload messages.UnwrappedErrorPayload
+			if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, nil); err != nil {
+				t.Fatal(err)
+			}
+			if !errorPayload.FirstErrorIs(messages.NotWaitingForDecisionsError) {
+				t.Logf("expected a not waiting for decisions error, got %+v", errorPayload)
+				t.Fail()
+			}
+
+			select {
+			case e := <-admin.sseControllerEventChan:
+				t.Fatalf("decisions were forwarded: %+v", e)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			if table.DrawDeck.Len() != drawDeckSize || admin.state != state {
+				t.Logf("expected the table and state to be unchanged, got draw deck size %d, state %s", table.DrawDeck.Len(), admin.state)
+				t.Fail()
+			}
+		})
+	}
+}
+
+func TestRetriedPlayerDecisionsAcceptedDuringSync(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+
+	request := messages.PlayerDecisionsRequest{
+		Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+		DecidingPlayer: "alice",
+	}
+	if resp := postDecisions(admin, request); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+	<-admin.sseControllerEventChan
+
+	// The retry reaches the admin once it is syncing the accepted decisions.
+	admin.state = SyncingPlayerDecision
+	if resp := postDecisions(admin, request); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for the retry, got %d", resp.Code)
+	}
+}
+
+func TestJumpInDecisionsOutOfTurn(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+	admin.table.HouseRules.JumpIn = true
+	redFive := uknow.Card{Number: 5, Color: uknow.ColorRed}
+	admin.table.HandOfPlayer["bob"] = uknow.Deck{redFive, {Number: 5, Color: uknow.ColorBlue}}
+
+	// Out of turn decisions other than a jump-in are rejected.
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redFive}},
+		DecidingPlayer: "bob",
+	})
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an out of turn play, got %d", resp.Code)
+	}
+
+	resp = postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionJumpIn, ResultCard: uknow.Card{Number: 5, Color: uknow.ColorBlue}}},
+		DecidingPlayer: "bob",
+	})
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for a jump-in with a non-identical card, got %d", resp.Code)
+	}
+
+	jumpIn := messages.PlayerDecisionsRequest{
+		Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionJumpIn, ResultCard: redFive}},
+		DecidingPlayer: "bob",
+	}
+	if resp := postDecisions(admin, jumpIn); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for a jump-in, got %d", resp.Code)
+	}
+
+	// alice decided too late.
+	resp = postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+		DecidingPlayer: "alice",
+	})
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 for decisions after the jump-in, got %d", resp.Code)
+	}
+
+	syncEvent := <-admin.sseControllerEventChan
+	select {
+	case e := <-admin.sseControllerEventChan:
+		t.Fatalf("expected only the jump-in to be forwarded, got %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	admin.dispatchEventWithSSE(syncEvent)
+
+	if admin.table.PlayerOfNextTurn != "alice" || admin.table.HandOfPlayer["bob"].Len() != 1 || !admin.table.DiscardedPile.MustTop().IsEqual(redFive) {
+		t.Logf("expected bob's red 5 on the pile and alice to be next, got next %s, bob's hand %s", admin.table.PlayerOfNextTurn, admin.table.HandOfPlayer["bob"])
+		t.Fail()
+	}
+}
+
+func TestChosenPlayerEventCarriesDiscardTop(t *testing.T) {
+	redSkip := uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed}
+	admin := newAdminWaitingForAlice(uknow.Deck{redSkip, {Number: 1, Color: uknow.ColorBlue}})
+
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redSkip}},
+		DecidingPlayer:       "alice",
+		DecisionEventCounter: 0,
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+	admin.dispatchOneForTest(t)
+
+	recorder := httptest.NewRecorder()
+	admin.dispatchEventWithSSE(sseCommandAddSpectator{SpectatorName: "dave", ResponseWriter: recorder})
+	recorder.Body.Reset()
+
+	admin.dispatchEventWithSSE(sseCommandSendChosenPlayerEventToAll{})
+
+	serverEvent, err := messages.ParseServerEventMessage(recorder.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	chosenPlayer, ok := serverEvent.(messages.ChosenPlayerEvent)
+	if !ok {
+		t.Fatalf("expected a chosen player event, got %T", serverEvent)
+	}
+
+	// The skip passes the turn over bob back to alice.
+	if chosenPlayer.PlayerName != "alice" || !chosenPlayer.DiscardTop.IsEqual(redSkip) {
+		t.Logf("expected alice to play on %s, got %+v", redSkip.String(), chosenPlayer)
+		t.Fail()
+	}
+	if chosenPlayer.RequiredColor != uknow.ColorRed || chosenPlayer.RequiredNumber != uknow.NumberSkip {
+		t.Logf("expected red or skip to be required, got %s", chosenPlayer.TurnContextString())
+		t.Fail()
+	}
+	if !chosenPlayer.MatchesTable(admin.table) {
+		t.Logf("expected the event to match the admin table")
+		t.Fail()
+	}
+}
+
+func TestOffTurnDecisionsAreRejected(t *testing.T) {
+	blueFive := uknow.Card{Number: 5, Color: uknow.ColorBlue}
+	offTurnDecisions := [][]uknow.PlayerDecision{
+		{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: blueFive}},
+		{{Kind: uknow.PlayerDecisionPullFromDeck}, {Kind: uknow.PlayerDecisionPass}},
+		{{Kind: uknow.PlayerDecisionDoChallenge}},
+		{{Kind: uknow.PlayerDecisionWildCardChooseColor, ResultCard: uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorBlue}}},
+	}
+
+	for _, dryRun := range []bool{false, true} {
+		for _, decisions := range offTurnDecisions {
+			admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+			admin.userConfig.DryRunDecisions = dryRun
+			admin.table.HandOfPlayer["bob"] = uknow.Deck{blueFive}
+			tableBefore := admin.table.Clone()
+
+			resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+				Decisions:      decisions,
+				DecidingPlayer: "bob",
+			})
+			if resp.Code != http.StatusBadRequest {
+				t.Fatalf("dry run %v, %v: expected status 400, got %d", dryRun, decisions, resp.Code)
+			}
+
+			var errorPayload messages.UnwrappedErrorPayload
+			if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, nil); err != nil || !errorPayload.FirstErrorIs(messages.NotYourTurnError) {
+				t.Errorf("dry run %v, %v: expected a %q error, got %v", dryRun, decisions, messages.NotYourTurnError, errorPayload.Errors)
+			}
+
+			if !admin.table.Equal(tableBefore) || admin.state != WaitingForPlayerDecision {
+				t.Errorf("dry run %v, %v: expected the table and state to be unchanged, got state %s", dryRun, decisions, admin.state)
+			}
+			if _, ok := admin.lastDecisionEventCounterOfPlayer["bob"]; ok {
+				t.Errorf("dry run %v, %v: expected bob's decisions to not be recorded as accepted", dryRun, decisions)
+			}
+
+			select {
+			case e := <-admin.sseControllerEventChan:
+				t.Fatalf("dry run %v, %v: expected the decisions to not be synced, got %+v", dryRun, decisions, e)
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func TestJoinWithDisallowedNameIsRejected(t *testing.T) {
+	for _, name := range []string{"", "bob1", "Admin", "<deck>", "seventeen_chars_x"} {
+		admin := newAdminAddingPlayers("alice")
+
+		recorder := httptest.NewRecorder()
+		admin.handleAddNewPlayerAndCreateSSE(recorder, newPlayerRequest(name, ""))
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("%q: expected status 400, got %d", name, recorder.Code)
+		}
+
+		if len(admin.table.PlayerNames) != 1 || len(admin.sessionTokenOfPlayer) != 0 {
+			t.Errorf("%q: expected the player to not be added, got players %v", name, admin.table.PlayerNames)
+		}
+
+		select {
+		case e := <-admin.sseControllerEventChan:
+			t.Fatalf("%q: expected the join to not be synced, got %+v", name, e)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestSpectateWithDisallowedNameIsRejected(t *testing.T) {
+	admin := newAdminAddingPlayers("alice")
+
+	var b bytes.Buffer
+	messages.EncodeJSONAndEncrypt(&messages.AddNewPlayersMessage{PlayerNames: []string{"pile"}}, &b, nil)
+
+	recorder := httptest.NewRecorder()
+	admin.handleAddSpectatorAndCreateSSE(recorder, httptest.NewRequest("POST", "/spectate", &b))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", recorder.Code)
+	}
+	if len(admin.sseWriterForSpectator) != 0 {
+		t.Fatalf("expected no spectators, got %d", len(admin.sseWriterForSpectator))
+	}
+}
@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestPlayersStatusInTurnOrder(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}, {Number: 3, Color: uknow.ColorGreen}})
+	admin.table.AddPlayer("carol")
+	admin.table.PlayerOfNextTurn = "bob"
+
+	for _, playerName := range []string{"alice", "bob", "dave"} {
+		admin.sseWriterForPlayer[playerName] = sseWriter{responseWriter: httptest.NewRecorder()}
+	}
+
+	admin.expectedAcksList.addPending(
+		expectedAck{ackId: makeAckIdWaitingForPlayerDecision("bob", 0), ackerPlayerName: "bob"},
+		time.Hour, func() {}, func() {},
+	)
+
+	lines := strings.Split(strings.TrimSpace(admin.playersStatus()), "\n")
+
+	wantLines := []string{
+		"* bob: seat 1, 1 cards, connected: true, pending acks: " + makeAckIdWaitingForPlayerDecision("bob", 0),
+		"  carol: seat 2, 0 cards, connected: false, pending acks: none",
+		"  alice: seat 0, 2 cards, connected: true, pending acks: none",
+		"  dave: seat -, 0 cards, connected: true, pending acks: none",
+	}
+	if len(lines) != len(wantLines) {
+		t.Fatalf("expected %d players, got:\n%s", len(wantLines), strings.Join(lines, "\n"))
+	}
+	for i, want := range wantLines {
+		if lines[i] != want {
+			t.Logf("line %d: expected '%s', got '%s'", i, want, lines[i])
+			t.Fail()
+		}
+	}
+}
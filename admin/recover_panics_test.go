@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func TestAdminSurvivesPanickingHandler(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+
+	router := admin.setRouterHandlers()
+	router.Path("/panic").Methods("GET").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		admin.table.PlayerIndexFromName("nobody")
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/panic")
+	if err != nil {
+		t.Fatalf("expected a response from the panicking handler: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", resp.StatusCode)
+	}
+	var errorPayload messages.UnwrappedErrorPayload
+	if err := json.NewDecoder(resp.Body).Decode(&errorPayload); err != nil {
+		t.Fatal(err)
+	}
+	if !errorPayload.FirstErrorIs(messages.HandlerPanickedError) {
+		t.Fatalf("expected the handler panicked error, got %v", errorPayload.Errors)
+	}
+
+	// The other handlers still work.
+	resp, err = http.Get(server.URL + "/counts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200 from counts after the panic, got %d", resp.StatusCode)
+	}
+}
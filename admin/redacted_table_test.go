@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func TestServedCardsRevealOnlyOwnHand(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob", "carol")
+	if resp := postSetReady(admin, "alice"); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+
+	recorderOfPlayer := make(map[string]*httptest.ResponseRecorder)
+	for _, playerName := range admin.table.PlayerNames {
+		recorderOfPlayer[playerName] = httptest.NewRecorder()
+		admin.sseWriterForPlayer[playerName] = sseWriter{responseWriter: recorderOfPlayer[playerName]}
+	}
+
+	makeEventMsg := func(table uknow.Table) messages.ServerEvent {
+		return &messages.ServedCardsEvent{Table: table}
+	}
+	if err := admin.sendTableEventToAllPlayersWithSSE(context.Background(), makeEventMsg); err != nil {
+		t.Fatal(err)
+	}
+
+	for playerName, recorder := range recorderOfPlayer {
+		serverEvent, err := messages.ParseServerEventMessage(recorder.Body.Bytes())
+		if err != nil {
+			t.Fatalf("%s: %v", playerName, err)
+		}
+		servedCards, ok := serverEvent.(messages.ServedCardsEvent)
+		if !ok {
+			t.Fatalf("%s: expected a served cards event, got %T", playerName, serverEvent)
+		}
+
+		for handOwner, hand := range servedCards.Table.HandOfPlayer {
+			adminHand := admin.table.HandOfPlayer[handOwner]
+			if hand.Len() != adminHand.Len() {
+				t.Fatalf("%s: expected %s to hold %d cards, got %d", playerName, handOwner, adminHand.Len(), hand.Len())
+			}
+
+			if handOwner == playerName {
+				if hand.HasHiddenCards() || hand.String() != adminHand.String() {
+					t.Logf("%s: expected own hand %s, got %s", playerName, adminHand, hand)
+					t.Fail()
+				}
+			} else if hand.String() != uknow.NewHiddenDeck(adminHand.Len()).String() {
+				t.Logf("%s: expected %s's hand to be hidden, got %s", playerName, handOwner, hand)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func getDrawDeckTop(admin *Admin, playerName string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	admin.handleGetDrawDeckTop(recorder, httptest.NewRequest("GET", "/draw_deck_top?player="+playerName, nil))
+	return recorder
+}
+
+func TestDrawDeckTopIsDrawnByPlayerOfTurn(t *testing.T) {
+	blueOne := uknow.Card{Number: 1, Color: uknow.ColorBlue}
+	admin := newAdminWaitingForAlice(uknow.Deck{blueOne})
+
+	if resp := getDrawDeckTop(admin, "bob"); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for bob, got %d", resp.Code)
+	}
+	if admin.drawDeckTopRevealed {
+		t.Fatal("expected the top of the draw deck to not be revealed to bob")
+	}
+
+	resp := getDrawDeckTop(admin, "alice")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for alice, got %d", resp.Code)
+	}
+
+	var drawDeckTop messages.DrawDeckTopMessage
+	if err := messages.DecryptAndDecodeJSON(&drawDeckTop, resp.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+	if wantCard := admin.table.DrawDeck.MustTop(); drawDeckTop.Card != wantCard {
+		t.Fatalf("expected the top of the draw deck %s, got %s", wantCard.String(), drawDeckTop.Card.String())
+	}
+
+	// Having seen the top card, alice can't go on to play from her hand.
+	play := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: blueOne}}
+	if resp := postDecisions(admin, messages.PlayerDecisionsRequest{Decisions: play, DecidingPlayer: "alice"}); resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for a play without drawing, got %d", resp.Code)
+	}
+
+	pull := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}}
+	if resp := postDecisions(admin, messages.PlayerDecisionsRequest{Decisions: pull, DecidingPlayer: "alice"}); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for a draw, got %d", resp.Code)
+	}
+	if _, ok := admin.dispatchOneForTest(t).(sseCommandSyncPlayerDecisionEvent); !ok {
+		t.Fatal("expected the draw to be synced")
+	}
+	if _, err := admin.table.HandOfPlayer["alice"].FindCard(drawDeckTop.Card); err != nil || admin.table.HandOfPlayer["alice"].Len() != 2 {
+		t.Logf("expected alice to draw %s, got %s", drawDeckTop.Card.String(), admin.table.HandOfPlayer["alice"])
+		t.Fail()
+	}
+}
@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func TestRedealDealsFreshHandsToSamePlayers(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 7, Color: uknow.ColorRed}})
+	admin.decisionEventsCompleted = 3
+	admin.lastDecisionEventCounterOfPlayer["alice"] = 2
+	admin.lastDecisionEventCounterOfPlayer["bob"] = 1
+
+	// An opening Draw Two would change the expected hand sizes.
+	admin.table.HouseRules.IgnoreOpeningAction = true
+
+	admin.expectedAcksList.addPending(
+		expectedAck{ackId: "decision_sync_bob", ackerPlayerName: "bob"},
+		5*time.Second,
+		func() { t.Errorf("expected the dropped ack to not be acked") },
+		func() { t.Errorf("expected the dropped ack to not time out") },
+	)
+
+	if err := admin.Redeal(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := (<-admin.sseControllerEventChan).(sseCommandSendRedealEventToAll); !ok {
+		t.Log("expected a redeal event to be sent to the SSE controller")
+		t.Fail()
+	}
+
+	if admin.state != ReadyToServeCards {
+		t.Logf("expected admin state %s, got %s", ReadyToServeCards, admin.state)
+		t.Fail()
+	}
+
+	if err := admin.table.Validate(); err != nil {
+		t.Logf("expected a valid table, got %v", err)
+		t.Fail()
+	}
+
+	if len(admin.table.PlayerNames) != 2 || admin.table.PlayerNames[0] != "alice" || admin.table.PlayerNames[1] != "bob" {
+		t.Logf("expected players [alice bob], got %v", admin.table.PlayerNames)
+		t.Fail()
+	}
+
+	for _, playerName := range []string{"alice", "bob"} {
+		if admin.table.HandOfPlayer[playerName].Len() != uknow.DefaultStartingHandCount {
+			t.Logf("expected %s to have %d cards, got %d", playerName, uknow.DefaultStartingHandCount, admin.table.HandOfPlayer[playerName].Len())
+			t.Fail()
+		}
+	}
+
+	wantDrawDeckSize := uknow.NewFullDeck().Len() - 2*uknow.DefaultStartingHandCount - 1
+	if admin.table.DrawDeck.Len() != wantDrawDeckSize {
+		t.Logf("expected draw deck size %d, got %d", wantDrawDeckSize, admin.table.DrawDeck.Len())
+		t.Fail()
+	}
+
+	if admin.decisionEventsCompleted != 0 {
+		t.Logf("expected the decision counter to start over, got %d", admin.decisionEventsCompleted)
+		t.Fail()
+	}
+
+	if ackIds := admin.expectedAcksList.pendingAckIdsOfPlayer("bob"); len(ackIds) != 0 {
+		t.Logf("expected no pending acks, got %v", ackIds)
+		t.Fail()
+	}
+
+	if err := admin.Redeal(); err == nil {
+		t.Log("expected redeal to fail before the cards are served again")
+		t.Fail()
+	}
+
+	// The first turn after the redeal takes decisions starting from counter 0,
+	// whoever made the decisions before it.
+	admin.dispatchEventWithSSE(sseCommandSendChosenPlayerEventToAll{})
+	playerOfTurn := admin.table.PlayerOfNextTurn
+	drawDeckSize := admin.table.DrawDeck.Len()
+
+	resp := postDecisions(admin, messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+		DecidingPlayer:       playerOfTurn,
+		DecisionEventCounter: 0,
+	})
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for %s's decisions after the redeal, got %d", playerOfTurn, resp.Code)
+	}
+	if _, ok := admin.dispatchOneForTest(t).(sseCommandSyncPlayerDecisionEvent); !ok {
+		t.Fatal("expected the decisions after the redeal to be synced")
+	}
+
+	if admin.table.DrawDeck.Len() != drawDeckSize-1 || admin.table.HandOfPlayer[playerOfTurn].Len() != uknow.DefaultStartingHandCount+1 {
+		t.Logf("expected %s's pull to be applied, got draw deck size %d, hand %s", playerOfTurn, admin.table.DrawDeck.Len(), admin.table.HandOfPlayer[playerOfTurn])
+		t.Fail()
+	}
+}
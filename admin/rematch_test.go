@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func TestRematchAfterWin(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 7, Color: uknow.ColorRed}})
+
+	request := messages.PlayerDecisionsRequest{
+		Decisions: []uknow.PlayerDecision{{
+			Kind:       uknow.PlayerDecisionPlayHandCard,
+			ResultCard: uknow.Card{Number: 7, Color: uknow.ColorRed},
+		}},
+		DecidingPlayer: "alice",
+	}
+
+	if resp := postDecisions(admin, request); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+	admin.dispatchOneForTest(t)
+
+	if admin.table.TableState != uknow.HaveWinner || admin.table.WinnerPlayerName != "alice" {
+		t.Fatalf("expected alice to win, got state %s, winner %q", admin.table.TableState, admin.table.WinnerPlayerName)
+	}
+
+	// No SSE writers to ack the sync, so finish the turn directly.
+	admin.state = DoneSyncingPlayerDecision
+	admin.runNewTurn()
+
+	if admin.state != HaveWinner {
+		t.Fatalf("expected admin state %s, got %s", HaveWinner, admin.state)
+	}
+
+	// An opening Draw Two would change the expected hand and draw deck sizes.
+	admin.table.HouseRules.IgnoreOpeningAction = true
+
+	if err := admin.Rematch(false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := (<-admin.sseControllerEventChan).(sseCommandSendRematchEventToAll); !ok {
+		t.Log("expected a rematch event to be sent to the SSE controller")
+		t.Fail()
+	}
+
+	if admin.state != ReadyToServeCards {
+		t.Logf("expected admin state %s, got %s", ReadyToServeCards, admin.state)
+		t.Fail()
+	}
+
+	if admin.table.TableState != uknow.StartOfTurn || admin.table.WinnerPlayerName != "" {
+		t.Logf("expected a fresh table, got state %s, winner %q", admin.table.TableState, admin.table.WinnerPlayerName)
+		t.Fail()
+	}
+
+	for _, playerName := range []string{"alice", "bob"} {
+		if admin.table.HandOfPlayer[playerName].Len() != uknow.DefaultStartingHandCount {
+			t.Logf("expected %s to have %d cards, got %d", playerName, uknow.DefaultStartingHandCount, admin.table.HandOfPlayer[playerName].Len())
+			t.Fail()
+		}
+	}
+
+	wantDrawDeckSize := uknow.NewFullDeck().Len() - 2*uknow.DefaultStartingHandCount - 1
+	if admin.table.DrawDeck.Len() != wantDrawDeckSize {
+		t.Logf("expected draw deck size %d, got %d", wantDrawDeckSize, admin.table.DrawDeck.Len())
+		t.Fail()
+	}
+
+	if admin.winCountOfPlayer["alice"] != 1 {
+		t.Logf("expected alice's win to be kept, got %d", admin.winCountOfPlayer["alice"])
+		t.Fail()
+	}
+
+	if err := admin.Rematch(true); err == nil {
+		t.Log("expected rematch to fail when there is no winner")
+		t.Fail()
+	}
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestSetSeating(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob", "carol", "dave")
+
+	if err := admin.SetSeating([]string{"dave", "bob", "alice"}); !errors.Is(err, uknow.ErrInvalidSeating) {
+		t.Logf("expected a seating missing a player to be rejected, got %v", err)
+		t.Fail()
+	}
+	if err := admin.SetSeating([]string{"dave", "bob", "alice", "bob"}); !errors.Is(err, uknow.ErrInvalidSeating) {
+		t.Logf("expected a seating listing a player twice to be rejected, got %v", err)
+		t.Fail()
+	}
+	if err := admin.SetSeating([]string{"dave", "bob", "alice", "erin"}); !errors.Is(err, uknow.ErrUnknownPlayer) {
+		t.Logf("expected a seating with an unknown player to be rejected, got %v", err)
+		t.Fail()
+	}
+
+	if err := admin.SetSeating([]string{"dave", "bob", "alice", "carol"}); err != nil {
+		t.Fatal(err)
+	}
+
+	table := admin.table
+	table.PlayerOfNextTurn = "bob"
+	wantOrder := []string{"bob", "alice", "carol", "dave"}
+	for i, playerIndex := range table.PlayerIndicesSortedByTurn() {
+		if table.PlayerNames[playerIndex] != wantOrder[i] {
+			t.Fatalf("expected turn order %v, got %s", wantOrder, table.TurnOrderString())
+		}
+	}
+	for i, playerName := range table.PlayerNames {
+		if table.IndexOfPlayer[playerName] != i {
+			t.Logf("expected %s at index %d, got %d", playerName, i, table.IndexOfPlayer[playerName])
+			t.Fail()
+		}
+	}
+
+	if resp := postSetReady(admin, "alice"); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+	if err := admin.SetSeating([]string{"alice", "bob", "carol", "dave"}); !errors.Is(err, errorInvalidAdminState) {
+		t.Logf("expected seating to be rejected once the game started, got %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func getSeedCommitment(t *testing.T, admin *Admin) messages.SeedCommitmentMessage {
+	recorder := httptest.NewRecorder()
+	admin.handleGetSeedCommitment(recorder, httptest.NewRequest("GET", "/seed_commitment", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	var seedCommitment messages.SeedCommitmentMessage
+	if err := messages.DecryptAndDecodeJSON(&seedCommitment, recorder.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+	return seedCommitment
+}
+
+func TestRevealedSeedReproducesDeal(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob", "carol")
+
+	recorder := httptest.NewRecorder()
+	admin.handleGetSeedCommitment(recorder, httptest.NewRequest("GET", "/seed_commitment", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected no commitment before shuffling, got status %d", recorder.Code)
+	}
+
+	if resp := postSetReady(admin, "alice"); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+
+	committed := getSeedCommitment(t, admin)
+	if committed.Commitment == "" || committed.Revealed || committed.Seed != 0 {
+		t.Fatalf("expected only the commitment before the game ends, got %+v", committed)
+	}
+
+	admin.state = HaveWinner
+	revealed := getSeedCommitment(t, admin)
+	if !revealed.Revealed || revealed.Commitment != committed.Commitment {
+		t.Fatalf("expected the seed to be revealed once the game has a winner, got %+v", revealed)
+	}
+	if !uknow.VerifySeedCommitment(revealed.Seed, committed.Commitment) || uknow.VerifySeedCommitment(revealed.Seed+1, committed.Commitment) {
+		t.Fatalf("expected only the revealed seed to match the commitment")
+	}
+
+	// Deal again with the revealed seed, the same players and the same deck.
+	table := uknow.NewAdminTable(log.Default())
+	table.DrawDeck = uknow.NewFullDeckN(1)
+	for _, playerName := range admin.table.PlayerNames {
+		table.AddPlayer(playerName)
+	}
+	table.ShufflerName = "alice"
+	table.Rand = uknow.NewSeededRand(revealed.Seed)
+	if err := table.ShuffleDeckAndDistribute(admin.userConfig.StartingHandCount); err != nil {
+		t.Fatal(err)
+	}
+
+	if !table.Equal(admin.table) {
+		for _, playerName := range table.PlayerNames {
+			t.Logf("%s: dealt %s, reproduced %s", playerName, admin.table.HandOfPlayer[playerName], table.HandOfPlayer[playerName])
+		}
+		t.Fatalf("expected the revealed seed to reproduce the deal")
+	}
+}
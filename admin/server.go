@@ -0,0 +1,165 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nrawrx3/uknow/internal/utils"
+)
+
+var (
+	errorInvalidRoomCode = errors.New("invalid room code")
+	errorRoomExists      = errors.New("room already exists")
+)
+
+// Room codes go in URL paths, so they're kept to a few safe characters.
+var roomCodeRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// A game hosted by a Server. Each room has an Admin of its own, so the table,
+// the admin state, the SSE streams and the acks of one room never touch those
+// of another.
+type Room struct {
+	Code  string
+	Admin *Admin
+
+	handler http.Handler
+}
+
+// Hosts several games at once, one per room. A room's admin API is served
+// under /room/{code}, e.g. POST /room/abc/player, and rooms are opened with
+// POST /room/{code}.
+type Server struct {
+	mu    sync.Mutex
+	rooms map[string]*Room
+
+	// Creates the admin of a newly opened room.
+	newRoomAdmin func(roomCode string) *Admin
+
+	router     *mux.Router
+	httpServer *http.Server
+}
+
+func NewServer(listenAddr utils.HostPortProtocol, newRoomAdmin func(roomCode string) *Admin) *Server {
+	s := &Server{
+		rooms:        make(map[string]*Room),
+		newRoomAdmin: newRoomAdmin,
+		router:       mux.NewRouter(),
+	}
+
+	s.router.Path("/room/{code}").Methods("POST").HandlerFunc(s.handleOpenRoom)
+	s.router.PathPrefix("/room/{code}/").HandlerFunc(s.handleRoomRequest)
+
+	// Same timeouts as a single-game admin, see NewAdmin.
+	s.httpServer = &http.Server{
+		Handler:      s.router,
+		Addr:         listenAddr.BindString(),
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Minute,
+		IdleTimeout:  10 * time.Minute,
+	}
+	return s
+}
+
+// Opens a room with a new admin.
+func (s *Server) OpenRoom(code string) (*Room, error) {
+	if !roomCodeRegexp.MatchString(code) {
+		return nil, fmt.Errorf("%w: %q", errorInvalidRoomCode, code)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.rooms[code]; ok {
+		return nil, fmt.Errorf("%w: %s", errorRoomExists, code)
+	}
+
+	admin := s.newRoomAdmin(code)
+	room := &Room{
+		Code:    code,
+		Admin:   admin,
+		handler: http.StripPrefix("/room/"+code, admin.RunWithoutServer()),
+	}
+	s.rooms[code] = room
+	log.Printf("Opened room %s", code)
+	return room, nil
+}
+
+// Returns the room with the given code, if it's open.
+func (s *Server) Room(code string) (*Room, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	room, ok := s.rooms[code]
+	return room, ok
+}
+
+// Use instead of Run to serve the rooms with a server of the caller's own,
+// e.g. an httptest.Server.
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+// Serves the rooms, with https if certFile and keyFile are set.
+func (s *Server) Run(certFile, keyFile string) error {
+	var err error
+	if certFile != "" {
+		log.Printf("Serving rooms with https at addr: %s", s.httpServer.Addr)
+		err = s.httpServer.ListenAndServeTLS(certFile, keyFile)
+	} else {
+		log.Printf("Serving rooms at addr: %s", s.httpServer.Addr)
+		err = s.httpServer.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shuts down the admin of every room, then the http server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	rooms := make([]*Room, 0, len(s.rooms))
+	for _, room := range s.rooms {
+		rooms = append(rooms, room)
+	}
+	s.mu.Unlock()
+
+	for _, room := range rooms {
+		if err := room.Admin.Shutdown(ctx); err != nil {
+			log.Printf("failed to shut down room %s: %v", room.Code, err)
+		}
+	}
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Req: POST /room/{code}
+// Resp: Created
+// Resp: BadRequest (if the code is invalid), Conflict (if the room is open)
+func (s *Server) handleOpenRoom(w http.ResponseWriter, r *http.Request) {
+	_, err := s.OpenRoom(mux.Vars(r)["code"])
+	switch {
+	case errors.Is(err, errorInvalidRoomCode):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, errorRoomExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func (s *Server) handleRoomRequest(w http.ResponseWriter, r *http.Request) {
+	code := mux.Vars(r)["code"]
+	room, ok := s.Room(code)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no room %s", code), http.StatusNotFound)
+		return
+	}
+	room.handler.ServeHTTP(w, r)
+}
@@ -0,0 +1,26 @@
+package admin
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// Returns a random token that a player presents when reconnecting, to prove it
+// is the client that first joined with the name.
+func newSessionToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// Reports whether the presented token is the one issued to the player. An
+// empty token never matches.
+func sessionTokenMatches(issued, presented string) bool {
+	if issued == "" || presented == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(issued), []byte(presented)) == 1
+}
@@ -0,0 +1,129 @@
+package admin
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+)
+
+func newPlayerRequest(playerName, sessionToken string) *http.Request {
+	var b bytes.Buffer
+	messages.EncodeJSONAndEncrypt(&messages.AddNewPlayersMessage{PlayerNames: []string{playerName}, SessionToken: sessionToken}, &b, nil)
+	return httptest.NewRequest("POST", "/player", &b)
+}
+
+// Runs the add player handler on its own goroutine, since it only returns
+// once its SSE stream is closed. The returned channel is closed when it does.
+func serveAddPlayer(admin *Admin, recorder *httptest.ResponseRecorder, req *http.Request) <-chan struct{} {
+	handlerDone := make(chan struct{})
+	go func() {
+		defer close(handlerDone)
+		admin.handleAddNewPlayerAndCreateSSE(recorder, req)
+	}()
+	return handlerDone
+}
+
+func TestFirstJoinIssuesSessionToken(t *testing.T) {
+	admin := newAdminAddingPlayers("alice")
+
+	handlerDone := serveAddPlayer(admin, httptest.NewRecorder(), newPlayerRequest("bob", ""))
+
+	var joined sseCommandSyncPlayerJoinedEventToAll
+	select {
+	case e := <-admin.sseControllerEventChan:
+		var ok bool
+		if joined, ok = e.(sseCommandSyncPlayerJoinedEventToAll); !ok {
+			t.Fatalf("expected bob's join to be synced, got %T", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected bob's join to be queued for the SSE controller")
+	}
+	if joined.SessionToken == "" || joined.SessionToken != admin.sessionTokenOfPlayer["bob"] {
+		t.Fatalf("expected the token issued to bob to be sent in the handshake, got %q, issued %q", joined.SessionToken, admin.sessionTokenOfPlayer["bob"])
+	}
+
+	close(joined.NotifyControllerExit)
+	<-handlerDone
+}
+
+func TestReconnectWithSessionToken(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+	admin.sessionTokenOfPlayer["alice"] = "token-of-alice"
+	admin.sessionTokenOfPlayer["bob"] = "token-of-bob"
+
+	oldStreamClosed := make(chan struct{})
+	admin.sseWriterForPlayer["alice"] = sseWriter{responseWriter: httptest.NewRecorder(), notifyControllerExit: oldStreamClosed}
+	admin.sseWriterForPlayer["bob"] = sseWriter{responseWriter: httptest.NewRecorder()}
+
+	recorder := httptest.NewRecorder()
+	handlerDone := serveAddPlayer(admin, recorder, newPlayerRequest("alice", "token-of-alice"))
+
+	if _, ok := admin.dispatchOneForTest(t).(sseCommandReconnectPlayer); !ok {
+		t.Fatal("expected alice's reconnect to be handled by the SSE controller")
+	}
+
+	select {
+	case <-oldStreamClosed:
+	case <-time.After(time.Second):
+		t.Fatal("expected the replaced stream of alice to be closed")
+	}
+
+	close(admin.sseWriterForPlayer["alice"].notifyControllerExit)
+	<-handlerDone
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	lineReader := utils.NewLineReader(bytes.NewReader(recorder.Body.Bytes()), log.Default())
+	lineBytes, err := io.ReadAll(lineReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existingPlayers, err := messages.DecodeEvent[messages.ExistingPlayersListEvent](lineBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(existingPlayers.PlayerNames) != 1 || existingPlayers.PlayerNames[0] != "bob" {
+		t.Logf("expected only bob in the existing players, got %v", existingPlayers.PlayerNames)
+		t.Fail()
+	}
+	if existingPlayers.SessionToken != "token-of-alice" {
+		t.Logf("expected alice's token in the handshake, got %q", existingPlayers.SessionToken)
+		t.Fail()
+	}
+}
+
+func TestReconnectWithWrongSessionTokenRejected(t *testing.T) {
+	for _, presentedToken := range []string{"token-of-bob", ""} {
+		admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+		admin.sessionTokenOfPlayer["alice"] = "token-of-alice"
+		admin.sessionTokenOfPlayer["bob"] = "token-of-bob"
+
+		aliceWriter := sseWriter{responseWriter: httptest.NewRecorder()}
+		admin.sseWriterForPlayer["alice"] = aliceWriter
+
+		recorder := httptest.NewRecorder()
+		admin.handleAddNewPlayerAndCreateSSE(recorder, newPlayerRequest("alice", presentedToken))
+
+		if recorder.Code != http.StatusForbidden {
+			t.Fatalf("token %q: expected status 403, got %d", presentedToken, recorder.Code)
+		}
+		if admin.sseWriterForPlayer["alice"] != aliceWriter {
+			t.Fatalf("token %q: expected alice's stream to be kept", presentedToken)
+		}
+		select {
+		case e := <-admin.sseControllerEventChan:
+			t.Fatalf("token %q: expected nothing queued for the SSE controller, got %T", presentedToken, e)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestForceNextPlayer(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 7, Color: uknow.ColorRed}})
+
+	if err := admin.ForceNextPlayer("bob"); !errors.Is(err, errorInvalidAdminState) {
+		t.Logf("expected set_next to fail while waiting for a decision, got %v", err)
+		t.Fail()
+	}
+
+	admin.state = DoneSyncingPlayerDecision
+	admin.decisionEventsCompleted = 3
+
+	if err := admin.ForceNextPlayer("dave"); !errors.Is(err, uknow.ErrUnknownPlayer) {
+		t.Logf("expected set_next to fail for an unknown player, got %v", err)
+		t.Fail()
+	}
+
+	if err := admin.ForceNextPlayer("bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := (<-admin.sseControllerEventChan).(sseCommandSendChosenPlayerEventToAll); !ok {
+		t.Log("expected a chosen player event to be sent to the SSE controller")
+		t.Fail()
+	}
+
+	if admin.table.PlayerOfNextTurn != "bob" || admin.state != PlayerChosenForTurn || admin.decisionEventsCompleted != 3 {
+		t.Fatalf("expected bob's turn with decision counter 3, got %s in state %s with counter %d", admin.table.PlayerOfNextTurn, admin.state, admin.decisionEventsCompleted)
+	}
+
+	// The turn that was due after the sync must not be started again.
+	admin.runNewTurn()
+
+	if admin.state != PlayerChosenForTurn {
+		t.Logf("expected admin state to stay %s, got %s", PlayerChosenForTurn, admin.state)
+		t.Fail()
+	}
+}
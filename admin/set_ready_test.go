@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+)
+
+func newAdminAddingPlayers(playerNames ...string) *Admin {
+	table := uknow.NewAdminTable(log.Default())
+	for _, playerName := range playerNames {
+		table.AddPlayer(playerName)
+	}
+
+	return NewAdmin(&ConfigNewAdmin{
+		ListenAddr: utils.HostPortProtocol{IP: "127.0.0.1", Port: 0},
+		Table:      table,
+
+		skipSSEController: true,
+	}, &AdminUserConfig{StartingHandCount: uknow.DefaultStartingHandCount, DeckCount: 1})
+}
+
+func postSetReady(admin *Admin, shufflerName string) *httptest.ResponseRecorder {
+	var b bytes.Buffer
+	messages.EncodeJSONAndEncrypt(&messages.SetReadyMessage{ShufflerName: shufflerName}, &b, nil)
+
+	recorder := httptest.NewRecorder()
+	admin.handleSetReady(recorder, httptest.NewRequest("POST", "/set_ready", &b))
+	return recorder
+}
+
+func TestSetReadyBelowMinPlayers(t *testing.T) {
+	admin := newAdminAddingPlayers("alice")
+
+	resp := postSetReady(admin, "alice")
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.Code)
+	}
+
+	var errorPayload messages.UnwrappedErrorPayload
+	if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(errorPayload.Errors) == 0 || !strings.Contains(errorPayload.Errors[0], errorNotEnoughPlayers.Error()) {
+		t.Logf("expected a not enough players error, got %+v", errorPayload)
+		t.Fail()
+	}
+
+	if admin.state != AddingPlayers || admin.table.IsShuffled {
+		t.Fatalf("expected the admin to keep adding players, got state %s", admin.state)
+	}
+}
+
+func TestSetReadyFromREPLIsRefused(t *testing.T) {
+	admin := newAdminAddingPlayers("alice")
+	if err := admin.setReady(); !errors.Is(err, errorNotEnoughPlayers) {
+		t.Errorf("expected %v with one player, got %v", errorNotEnoughPlayers, err)
+	}
+
+	admin = newAdminAddingPlayers("alice", "bob")
+	admin.expectedAcksList.addPending(expectedAck{ackId: "test", ackerPlayerName: "alice"}, time.Minute, nil, nil)
+	if err := admin.setReady(); !errors.Is(err, errorWaitingForAcks) {
+		t.Errorf("expected %v with an ack pending, got %v", errorWaitingForAcks, err)
+	}
+	if admin.state != AddingPlayers {
+		t.Fatalf("expected the admin to keep adding players, got state %s", admin.state)
+	}
+}
+
+func TestSetReadyWithMinPlayers(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob", "carol")
+	admin.userConfig.MinPlayers = 3
+
+	if resp := postSetReady(admin, "alice"); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+
+	if admin.state != ReadyToServeCards || !admin.table.IsShuffled {
+		t.Fatalf("expected the cards to be served, got state %s", admin.state)
+	}
+}
+
+func TestSetReadyWithTooFewCardsToDeal(t *testing.T) {
+	playerNames := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		playerNames = append(playerNames, fmt.Sprintf("player_%c", 'a'+i))
+	}
+	admin := newAdminAddingPlayers(playerNames...)
+	admin.userConfig.StartingHandCount = uknow.MaxStartingHandCount
+
+	resp := postSetReady(admin, "player_a")
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.Code)
+	}
+
+	var errorPayload messages.UnwrappedErrorPayload
+	if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(errorPayload.Errors) == 0 || !strings.Contains(errorPayload.Errors[0], uknow.ErrNotEnoughCardsToDeal.Error()) {
+		t.Logf("expected a not enough cards error, got %+v", errorPayload)
+		t.Fail()
+	}
+
+	if admin.state != AddingPlayers || admin.table.IsShuffled {
+		t.Fatalf("expected the admin to keep adding players, got state %s", admin.state)
+	}
+}
@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func TestShutdownTwice(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+
+	recorder := httptest.NewRecorder()
+	admin.sseWriterForPlayer["alice"] = sseWriter{responseWriter: recorder}
+
+	if err := admin.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	serverEvent, err := messages.ParseServerEventMessage(recorder.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := serverEvent.(messages.ServerShuttingDownEvent); !ok {
+		t.Fatalf("expected alice to be told of the shutdown, got %+v", serverEvent)
+	}
+
+	select {
+	case <-admin.sseControllerStopChan:
+	default:
+		t.Fatal("expected the SSE controller to be stopped")
+	}
+
+	recorder.Body.Reset()
+	if err := admin.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected the second shutdown to succeed, got %v", err)
+	}
+	if recorder.Body.Len() != 0 {
+		t.Errorf("expected alice to be told of the shutdown only once, got %q", recorder.Body.String())
+	}
+}
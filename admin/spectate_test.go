@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+)
+
+func TestSpectatorGetsRedactedSnapshot(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+	if resp := postSetReady(admin, "alice"); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+
+	recorder := httptest.NewRecorder()
+	admin.dispatchEventWithSSE(sseCommandAddSpectator{SpectatorName: "dave", ResponseWriter: recorder})
+
+	if _, ok := admin.sseWriterForSpectator["dave"]; !ok {
+		t.Fatalf("expected dave to be registered as a spectator")
+	}
+
+	lineReader := utils.NewLineReader(bytes.NewReader(recorder.Body.Bytes()), log.Default())
+
+	lineBytes, err := io.ReadAll(lineReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existingPlayers, err := messages.DecodeEvent[messages.ExistingPlayersListEvent](lineBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(existingPlayers.PlayerNames) != 2 {
+		t.Logf("expected alice and bob in the existing players, got %v", existingPlayers.PlayerNames)
+		t.Fail()
+	}
+
+	lineBytes, err = io.ReadAll(lineReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverEvent, err := messages.ParseServerEventMessage(lineBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	servedCards, ok := serverEvent.(messages.ServedCardsEvent)
+	if !ok {
+		t.Fatalf("expected a served cards event, got %T", serverEvent)
+	}
+
+	for playerName, hand := range servedCards.Table.HandOfPlayer {
+		if hand.String() != uknow.NewHiddenDeck(admin.table.HandOfPlayer[playerName].Len()).String() {
+			t.Logf("expected %s's hand to be hidden from the spectator, got %s", playerName, hand)
+			t.Fail()
+		}
+	}
+
+	// Spectators follow the events sent to all players.
+	recorder.Body.Reset()
+	if err := admin.sendMessageToAllPlayersWithSSE(context.Background(), "", messages.ChosenPlayerEvent{PlayerName: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := messages.ParseServerEventMessage(recorder.Body.Bytes()); err != nil {
+		t.Logf("expected the spectator to receive the chosen player event: %v", err)
+		t.Fail()
+	}
+}
+
+func TestSpectatorNameCollidesWithPlayer(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+
+	var b bytes.Buffer
+	messages.EncodeJSONAndEncrypt(&messages.AddNewPlayersMessage{PlayerNames: []string{"Alice"}}, &b, nil)
+
+	recorder := httptest.NewRecorder()
+	admin.handleAddSpectatorAndCreateSSE(recorder, httptest.NewRequest("POST", "/spectate", &b))
+
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected status 409, got %d", recorder.Code)
+	}
+	if len(admin.sseWriterForSpectator) != 0 {
+		t.Fatalf("expected no spectators, got %d", len(admin.sseWriterForSpectator))
+	}
+}
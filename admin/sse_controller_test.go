@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+// Dispatches the next event queued for the SSE controller on the calling
+// goroutine, the way runSSEController would. For admins created with
+// skipSSEController. Fails the test if nothing is queued within a second.
+func (admin *Admin) dispatchOneForTest(t *testing.T) sseEvent {
+	t.Helper()
+
+	select {
+	case ctlEvent := <-admin.sseControllerEventChan:
+		admin.dispatchEventWithSSE(ctlEvent)
+		return ctlEvent
+	case <-time.After(time.Second):
+		t.Fatal("expected an event queued for the SSE controller")
+		return nil
+	}
+}
+
+func TestDispatchOneChosenPlayerEvent(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+	if resp := postSetReady(admin, "alice"); resp.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.Code)
+	}
+
+	// Drop the served cards event, dispatching it would wait for the pause
+	// after serving.
+	if _, ok := (<-admin.sseControllerEventChan).(sseCommandSendServedCardsEventToAll); !ok {
+		t.Fatal("expected a served cards event to be queued by set_ready")
+	}
+
+	recorderOfPlayer := make(map[string]*httptest.ResponseRecorder)
+	for _, playerName := range admin.table.PlayerNames {
+		recorderOfPlayer[playerName] = httptest.NewRecorder()
+		admin.sseWriterForPlayer[playerName] = sseWriter{responseWriter: recorderOfPlayer[playerName]}
+	}
+
+	go func() {
+		admin.sseControllerEventChan <- sseCommandSendChosenPlayerEventToAll{}
+	}()
+
+	if _, ok := admin.dispatchOneForTest(t).(sseCommandSendChosenPlayerEventToAll); !ok {
+		t.Fatal("expected the chosen player event to be dispatched")
+	}
+
+	if admin.state != WaitingForPlayerDecision {
+		t.Fatalf("expected admin state %s, got %s", WaitingForPlayerDecision, admin.state)
+	}
+
+	for playerName, recorder := range recorderOfPlayer {
+		serverEvent, err := messages.ParseServerEventMessage(recorder.Body.Bytes())
+		if err != nil {
+			t.Fatalf("%s: %v", playerName, err)
+		}
+		chosenPlayer, ok := serverEvent.(messages.ChosenPlayerEvent)
+		if !ok {
+			t.Fatalf("%s: expected a chosen player event, got %T", playerName, serverEvent)
+		}
+		if chosenPlayer.PlayerName != admin.table.PlayerOfNextTurn || chosenPlayer.DecisionEventCounter != 0 {
+			t.Logf("%s: expected %s to be chosen for decision 0, got %+v", playerName, admin.table.PlayerOfNextTurn, chosenPlayer)
+			t.Fail()
+		}
+	}
+
+	ackIds := admin.expectedAcksList.pendingAckIdsOfPlayer(admin.table.PlayerOfNextTurn)
+	if len(ackIds) != 1 || ackIds[0] != makeAckIdWaitingForPlayerDecision(admin.table.PlayerOfNextTurn, 0) {
+		t.Logf("expected to wait for the decision of %s, pending acks: %v", admin.table.PlayerOfNextTurn, ackIds)
+		t.Fail()
+	}
+}
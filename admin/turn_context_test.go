@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+func getTurnContext(t *testing.T, admin *Admin) messages.TurnContextMessage {
+	recorder := httptest.NewRecorder()
+	admin.handleGetTurnContext(recorder, httptest.NewRequest("GET", "/turn_context", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	var turnContext messages.TurnContextMessage
+	if err := messages.DecryptAndDecodeJSON(&turnContext, recorder.Body, nil); err != nil {
+		t.Fatal(err)
+	}
+	return turnContext
+}
+
+func TestTurnContextMatchesTableAfterPlays(t *testing.T) {
+	redOne := uknow.Card{Number: 1, Color: uknow.ColorRed}
+	blueOne := uknow.Card{Number: 1, Color: uknow.ColorBlue}
+	blueSeven := uknow.Card{Number: 7, Color: uknow.ColorBlue}
+
+	admin := newAdminWaitingForAlice(uknow.Deck{redOne, blueSeven, {Number: 3, Color: uknow.ColorGreen}})
+	admin.table.HandOfPlayer["bob"] = uknow.Deck{blueOne, {Number: 2, Color: uknow.ColorYellow}}
+
+	plays := []struct {
+		playerName string
+		card       uknow.Card
+	}{
+		{"alice", redOne},
+		{"bob", blueOne},
+		{"alice", blueSeven},
+	}
+
+	for _, play := range plays {
+		decisions := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: play.card}}
+		if err := admin.table.EvalPlayerDecisionsNoTransferChan(play.playerName, decisions); err != nil {
+			t.Fatal(err)
+		}
+
+		table := admin.table
+		turnContext := getTurnContext(t, admin)
+		if turnContext.RequiredColor != table.RequiredColorOfCurrentTurn || turnContext.RequiredNumber != table.RequiredNumberOfCurrentTurn {
+			t.Errorf("after %s played %s: expected %s %s to be required, got %s %s", play.playerName, play.card.String(),
+				table.RequiredColorOfCurrentTurn.String(), table.RequiredNumberOfCurrentTurn.String(),
+				turnContext.RequiredColor.String(), turnContext.RequiredNumber.String())
+		}
+		if !turnContext.DiscardTop.IsEqual(play.card) || !turnContext.DiscardTop.IsEqual(table.DiscardedPile.MustTop()) {
+			t.Errorf("after %s played %s: expected it on top of the pile, got %s", play.playerName, play.card.String(), turnContext.DiscardTop.String())
+		}
+		if turnContext.CurrentPlayer != table.PlayerOfNextTurn || turnContext.Direction != table.Direction {
+			t.Errorf("after %s played %s: expected %s to play in direction %d, got %s in direction %d", play.playerName, play.card.String(),
+				table.PlayerOfNextTurn, table.Direction, turnContext.CurrentPlayer, turnContext.Direction)
+		}
+	}
+
+	if turnContext := getTurnContext(t, admin); turnContext.CurrentPlayer != "bob" || turnContext.RequiredColor != uknow.ColorBlue || turnContext.RequiredNumber != 7 {
+		t.Fatalf("expected bob to play on a blue 7, got %+v", turnContext)
+	}
+}
+
+func TestTurnContextBeforeCardsAreServed(t *testing.T) {
+	admin := newAdminAddingPlayers("alice", "bob")
+
+	recorder := httptest.NewRecorder()
+	admin.handleGetTurnContext(recorder, httptest.NewRequest("GET", "/turn_context", nil))
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected status 409 before the cards are served, got %d", recorder.Code)
+	}
+}
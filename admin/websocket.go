@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+)
+
+// Endpoints whose response is an event stream. Requested over a WebSocket
+// connection, their events are sent over the connection.
+var webSocketStreamEndpoints = map[string]bool{
+	"player":   true,
+	"spectate": true,
+}
+
+// Req:		GET /ws, a WebSocket handshake
+// Then:	messages.WebSocketRequest from the client, each served like the
+// same request over HTTP and answered with a messages.ResponseEvent. After a
+// request to join as a player or spectator, the events of its stream are sent
+// over the connection as messages.ServerEventMessage, one per message.
+func (admin *Admin) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	utils.ServeWebSocket(w, r, func(conn *utils.WebSocketConn) {
+		admin.serveWebSocketConn(conn, r)
+	})
+}
+
+func (admin *Admin) serveWebSocketConn(conn *utils.WebSocketConn, r *http.Request) {
+	defer conn.Close()
+
+	admin.logger.Printf("websocket connected from %s", r.RemoteAddr)
+
+	streaming := false
+	for {
+		message, err := conn.ReadMessage()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				admin.logger.Printf("websocket from %s: %v", r.RemoteAddr, err)
+			}
+			return
+		}
+
+		var request messages.WebSocketRequest
+		if err := json.Unmarshal(message, &request); err != nil {
+			admin.logger.Printf("websocket from %s sent a malformed request: %v", r.RemoteAddr, err)
+			return
+		}
+
+		endpoint, err := url.Parse(request.Endpoint)
+		if err != nil {
+			admin.respondOverWebSocket(conn, request.ID, http.StatusBadRequest, []byte(err.Error()))
+			continue
+		}
+
+		if !webSocketStreamEndpoints[endpoint.Path] {
+			// Not waited on, a handler can block on the SSE controller
+			// while it waits on an ack that arrives over this connection.
+			go admin.serveOverWebSocket(conn, r, request, false)
+			continue
+		}
+
+		if streaming {
+			admin.respondOverWebSocket(conn, request.ID, http.StatusConflict, []byte("connection already streams events"))
+			continue
+		}
+		streaming = true
+		go func() {
+			admin.serveOverWebSocket(conn, r, request, true)
+			// The stream ended, e.g. on shutdown or when the player
+			// reconnected with another stream.
+			conn.Close()
+		}()
+	}
+}
+
+// Serves the request with the admin's handlers. A streaming request's events
+// are sent as they are flushed, any other response is sent as a ResponseEvent
+// once the handler returns.
+func (admin *Admin) serveOverWebSocket(conn *utils.WebSocketConn, upgradeRequest *http.Request, request messages.WebSocketRequest, streaming bool) {
+	req, err := http.NewRequestWithContext(upgradeRequest.Context(), request.Method, "/"+request.Endpoint, bytes.NewReader(request.Body))
+	if err != nil {
+		admin.respondOverWebSocket(conn, request.ID, http.StatusBadRequest, []byte(err.Error()))
+		return
+	}
+	req.RemoteAddr = upgradeRequest.RemoteAddr
+
+	writer := &webSocketResponseWriter{
+		conn:    conn,
+		header:  make(http.Header),
+		streams: streaming,
+	}
+	admin.httpServer.Handler.ServeHTTP(writer, req)
+
+	if statusCode, body, streamed := writer.finish(); !streamed {
+		admin.respondOverWebSocket(conn, request.ID, statusCode, body)
+	}
+}
+
+func (admin *Admin) respondOverWebSocket(conn *utils.WebSocketConn, requestID, statusCode int, body []byte) {
+	response := messages.ServerEventMessage{
+		Type:  messages.EventTypeResponse,
+		Event: messages.ResponseEvent{ID: requestID, StatusCode: statusCode, Body: body},
+	}
+	message, err := json.Marshal(response)
+	if err == nil {
+		err = conn.WriteMessage(message)
+	}
+	if err != nil {
+		admin.logger.Printf("failed to respond to websocket request %d: %v", requestID, err)
+	}
+}
+
+// Response writer of a request served over a WebSocket connection. The
+// response is buffered. If the request streams, each flush of a successful
+// response sends what was written since the last one as a message, which is
+// how sseWriter's events go over the connection.
+type webSocketResponseWriter struct {
+	conn    *utils.WebSocketConn
+	header  http.Header
+	streams bool
+
+	mutex      sync.Mutex
+	statusCode int
+	buffered   bytes.Buffer
+	streamed   bool
+	err        error
+}
+
+func (w *webSocketResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *webSocketResponseWriter) WriteHeader(statusCode int) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *webSocketResponseWriter) Write(b []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.err != nil {
+		return 0, w.err
+	}
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.buffered.Write(b)
+}
+
+func (w *webSocketResponseWriter) Flush() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if !w.streams || w.statusCode != http.StatusOK || w.buffered.Len() == 0 || w.err != nil {
+		return
+	}
+
+	message := bytes.TrimSpace(w.buffered.Bytes())
+	if err := w.conn.WriteMessage(message); err != nil {
+		w.err = fmt.Errorf("failed to send event over websocket: %w", err)
+	}
+	w.buffered.Reset()
+	w.streamed = true
+}
+
+// Returns the response left to send, and whether events were streamed instead.
+func (w *webSocketResponseWriter) finish() (statusCode int, body []byte, streamed bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.statusCode, w.buffered.Bytes(), w.streamed
+}
@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+)
+
+// Sends the request over the connection and reads the response to it.
+func requestOverWebSocket(t *testing.T, conn *utils.WebSocketConn, request messages.WebSocketRequest) messages.ResponseEvent {
+	t.Helper()
+
+	message, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.WriteMessage(message); err != nil {
+		t.Fatal(err)
+	}
+
+	message, err = conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverEvent, err := messages.ParseServerEventMessage(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, ok := serverEvent.(messages.ResponseEvent)
+	if !ok || response.ID != request.ID {
+		t.Fatalf("expected the response to request %d, got %+v", request.ID, serverEvent)
+	}
+	return response
+}
+
+func TestPlayerDecisionsOverWebSocket(t *testing.T) {
+	admin := newAdminWaitingForAlice(uknow.Deck{{Number: 1, Color: uknow.ColorBlue}})
+	admin.userConfig.WebSocket = true
+	server := httptest.NewServer(admin.setRouterHandlers())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := utils.DialWebSocket(ctx, server.URL+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	encodeDecisions := func(request messages.PlayerDecisionsRequest) []byte {
+		var b bytes.Buffer
+		messages.EncodeJSONAndEncrypt(&request, &b, nil)
+		return b.Bytes()
+	}
+
+	// It's alice's turn, not bob's.
+	response := requestOverWebSocket(t, conn, messages.WebSocketRequest{
+		ID:       1,
+		Method:   http.MethodPost,
+		Endpoint: "player_decisions",
+		Body: encodeDecisions(messages.PlayerDecisionsRequest{
+			Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+			DecidingPlayer: "bob",
+		}),
+	})
+	if response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for bob's decisions, got %d: %s", response.StatusCode, response.Body)
+	}
+	var errorPayload messages.UnwrappedErrorPayload
+	if err := json.Unmarshal(response.Body, &errorPayload); err != nil || len(errorPayload.Errors) == 0 {
+		t.Fatalf("expected the rejection to carry an error payload, got %s", response.Body)
+	}
+
+	response = requestOverWebSocket(t, conn, messages.WebSocketRequest{
+		ID:       2,
+		Method:   http.MethodPost,
+		Endpoint: "player_decisions",
+		Body: encodeDecisions(messages.PlayerDecisionsRequest{
+			Decisions:      []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}},
+			DecidingPlayer: "alice",
+		}),
+	})
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", response.StatusCode, response.Body)
+	}
+
+	select {
+	case ctlEvent := <-admin.sseControllerEventChan:
+		syncEvent, ok := ctlEvent.(sseCommandSyncPlayerDecisionEvent)
+		if !ok || syncEvent.DecidingPlayer != "alice" || len(syncEvent.Decisions) != 1 || syncEvent.Decisions[0].Kind != uknow.PlayerDecisionPullFromDeck {
+			t.Fatalf("expected alice's draw to be synced, got %+v", ctlEvent)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the decisions sent over the websocket to be synced")
+	}
+}
+
+func TestWebSocketEndpointIsOptional(t *testing.T) {
+	admin := newAdminAddingPlayers("alice")
+	server := httptest.NewServer(admin.setRouterHandlers())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if conn, err := utils.DialWebSocket(ctx, server.URL+"/ws", nil); err == nil {
+		conn.Close()
+		t.Fatal("expected no websocket endpoint unless enabled in the config")
+	}
+}
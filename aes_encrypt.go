@@ -8,8 +8,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log"
+
+	"golang.org/x/crypto/scrypt"
 )
 
 type AESCipher struct {
@@ -29,6 +32,53 @@ func NewAESCipher(hexKey string) (*AESCipher, error) {
 		return nil, err
 	}
 
+	return newAESCipherOfKey(key)
+}
+
+// Cost parameters of scrypt when deriving a key from a passphrase. The admin
+// and clients must use the same, changing them changes every derived key.
+const (
+	aesPassphraseScryptN = 1 << 15
+	aesPassphraseScryptR = 8
+	aesPassphraseScryptP = 1
+)
+
+// Shortest salt accepted with a passphrase.
+const MinAESSaltLength = 8
+
+// Creates a cipher whose key is derived from the passphrase and salt with
+// scrypt, so that players can share a password instead of a hex
+// key. Everyone deriving from the same passphrase and salt gets the same key.
+func NewAESCipherFromPassphrase(passphrase string, salt []byte) (*AESCipher, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyAESPassphrase
+	}
+	if len(salt) < MinAESSaltLength {
+		return nil, fmt.Errorf("%w: expected at least %d bytes, got %d", ErrAESSaltTooShort, MinAESSaltLength, len(salt))
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, aesPassphraseScryptN, aesPassphraseScryptR, aesPassphraseScryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	return newAESCipherOfKey(key)
+}
+
+// Creates the cipher of an admin or client config, which sets either a hex key
+// or a passphrase with its salt.
+func NewAESCipherFromConfig(hexKey, passphrase, salt string) (*AESCipher, error) {
+	switch {
+	case hexKey != "" && passphrase != "":
+		return nil, fmt.Errorf("%w, got both", ErrAESKeyOrPassphrase)
+	case hexKey != "":
+		return NewAESCipher(hexKey)
+	case passphrase != "":
+		return NewAESCipherFromPassphrase(passphrase, []byte(salt))
+	default:
+		return nil, fmt.Errorf("%w, got neither", ErrAESKeyOrPassphrase)
+	}
+}
+
+func newAESCipherOfKey(key []byte) (*AESCipher, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		log.Printf("failed to create cipher: %s", err)
@@ -49,47 +99,55 @@ func NewAESCipher(hexKey string) (*AESCipher, error) {
 
 var ErrInvalidAESKeyLength = errors.New("invalid AES key length")
 
+var (
+	ErrEmptyAESPassphrase = errors.New("empty AES passphrase")
+	ErrAESSaltTooShort    = errors.New("AES salt too short")
+	ErrAESKeyOrPassphrase = errors.New("expected either an AES key or a passphrase")
+)
+
+var ErrCiphertextTooShort = errors.New("ciphertext is shorter than the nonce")
+
+// Returned when a message fails GCM authentication, i.e. it was tampered with
+// or encrypted with a different key.
+var ErrMessageAuthFailed = errors.New("message authentication failed")
+
+// Encrypts with AES-GCM using a fresh random nonce. The result is laid out as
+// [nonceBytes..., encryptedBytes...], where encryptedBytes ends with the
+// authentication tag.
 func (a AESCipher) Encrypt(plaintextBytes []byte) ([]byte, error) {
 	nonceSize := a.gcm.NonceSize()
 
-	nonce := make([]byte, nonceSize)
+	// EXPLAIN: gcm.Seal(dst, ...) *appends* the encrypted bytes and the tag
+	// to dst, so we read the nonce into the head of dst and seal right after
+	// it. The capacity is enough for Seal not to reallocate.
+	dst := make([]byte, nonceSize, nonceSize+len(plaintextBytes)+a.gcm.Overhead())
+
+	nonce := dst[:nonceSize]
 	_, err := io.ReadFull(rand.Reader, nonce)
 	if err != nil {
 		return nil, err
 	}
 
-	// EXPLAIN: gcm.Seal(dst, ...) *appends* the encrypted bytes to the dst
-	// array. If underlying array of dst does not have enough capacity for
-	// the result, it will reallocate a new slice and copy the current
-	// dst[:] to the head of that slice and then append encrypted bytes.
-	// Strangely enough, Seal does *not* put the nonce as part of the bytes
-	// appended. As a convention, we can append the nonce bytes and obtain
-	// the final result of encryption like so: [nonceBytes...,
-	// encryptedBytes...], The following allocates the dst slice to have
-	// enough capacity so Seal doesn't need to reallocate.
-
-	// EXPLAIN: Being conservative by allocating more than enough space. In
-	// the cipher/gcm implementation, it looks like a `plainText + 16` bytes
-	// would be the exact capacity required, but it's private code. We don't
-	// usually have large messages anyway.
-	encryptedLen := len(plaintextBytes) * 2
-
-	dst := make([]byte, 0, nonceSize+encryptedLen)
-
-	encryptedBytes := a.gcm.Seal(dst[:0], nonce, plaintextBytes, nil)
-
-	withNonce := append(encryptedBytes, nonce...)
-	return withNonce, nil
+	return a.gcm.Seal(dst, nonce, plaintextBytes, nil), nil
 }
 
-func (a *AESCipher) Decrypt(encryptedWithNonceBytes []byte) ([]byte, error) {
+// Decrypts a message produced by Encrypt. Fails with ErrMessageAuthFailed if the
+// message doesn't pass authentication.
+func (a *AESCipher) Decrypt(nonceWithEncryptedBytes []byte) ([]byte, error) {
 	nonceSize := a.gcm.NonceSize()
 
-	encryptedBytesLen := len(encryptedWithNonceBytes) - nonceSize
+	if len(nonceWithEncryptedBytes) < nonceSize+a.gcm.Overhead() {
+		return nil, fmt.Errorf("%w: have %d bytes", ErrCiphertextTooShort, len(nonceWithEncryptedBytes))
+	}
+
+	nonce := nonceWithEncryptedBytes[:nonceSize]
+	cipherText := nonceWithEncryptedBytes[nonceSize:]
 
-	cipherText := encryptedWithNonceBytes[0:encryptedBytesLen]
-	nonce := encryptedWithNonceBytes[encryptedBytesLen : encryptedBytesLen+nonceSize]
-	return a.gcm.Open(nil, nonce, cipherText, nil)
+	plaintextBytes, err := a.gcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMessageAuthFailed, err)
+	}
+	return plaintextBytes, nil
 }
 
 func (aesCipher *AESCipher) MustEncryptJSON(value interface{}) io.Reader {
@@ -106,16 +164,19 @@ func (aesCipher *AESCipher) MustEncryptJSON(value interface{}) io.Reader {
 	return bytes.NewReader(encryptedBytes)
 }
 
-func (aesCipher *AESCipher) MustDecryptJSON(source io.Reader) *json.Decoder {
+// Reads and decrypts the whole source, returning a decoder of the plaintext
+// JSON. Unlike encryption failures, decryption failures come from what peers
+// send, so they are returned rather than being fatal.
+func (aesCipher *AESCipher) DecryptJSON(source io.Reader) (*json.Decoder, error) {
 	encryptedBytes, err := io.ReadAll(source)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	decryptedBytes, err := aesCipher.Decrypt(encryptedBytes)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	return json.NewDecoder(bytes.NewReader(decryptedBytes))
+	return json.NewDecoder(bytes.NewReader(decryptedBytes)), nil
 }
@@ -0,0 +1,125 @@
+package uknow
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Compact text format of cards. A colored card is written as its color's
+// letter followed by the number, e.g. "r7", or by a dash and the action name,
+// e.g. "g-skip". Wild cards are written as "wild" and "wild4", and the hidden
+// cards of a redacted hand as "hidden". Used for JSON encoding, logging and test
+// fixtures.
+
+var ErrInvalidCardText = errors.New("invalid card text")
+
+var colorLetters = [...]string{"w", "r", "g", "b", "y"}
+
+var actionTexts = [...]string{"skip", "rev", "draw2", "wild", "wild4"}
+
+func (c Card) MarshalText() ([]byte, error) {
+	if c == HiddenCard {
+		return []byte("hidden"), nil
+	}
+	if c.Color < ColorWild || c.Color > ColorYellow {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidCardColor, c.Color)
+	}
+	if c.Number < 0 || c.Number > NumberWildDrawFour {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidCardNumber, c.Number)
+	}
+
+	if c.Color == ColorWild && c.IsWild() {
+		return []byte(actionTexts[c.Number-NumberSkip]), nil
+	}
+
+	if c.Number.IsAction() {
+		return []byte(colorLetters[c.Color] + "-" + actionTexts[c.Number-NumberSkip]), nil
+	}
+	return []byte(colorLetters[c.Color] + strconv.Itoa(int(c.Number))), nil
+}
+
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// Parses a card written in the format produced by Card.MarshalText.
+func ParseCard(s string) (Card, error) {
+	switch s {
+	case "wild":
+		return Card{Number: NumberWild, Color: ColorWild}, nil
+	case "wild4":
+		return Card{Number: NumberWildDrawFour, Color: ColorWild}, nil
+	case "hidden":
+		return HiddenCard, nil
+	}
+
+	if len(s) < 2 {
+		return Card{}, fmt.Errorf("%w: %q", ErrInvalidCardText, s)
+	}
+
+	color := Color(-1)
+	for i, letter := range colorLetters {
+		if s[:1] == letter {
+			color = Color(i)
+			break
+		}
+	}
+	if color < 0 {
+		return Card{}, fmt.Errorf("%w: unknown color in %q", ErrInvalidCardText, s)
+	}
+
+	numberText := s[1:]
+	if strings.HasPrefix(numberText, "-") {
+		for i, actionText := range actionTexts {
+			if numberText[1:] == actionText {
+				return Card{Number: NumberSkip + Number(i), Color: color}, nil
+			}
+		}
+		return Card{}, fmt.Errorf("%w: unknown action in %q", ErrInvalidCardText, s)
+	}
+
+	n, err := strconv.Atoi(numberText)
+	if err != nil || n < 0 || n > 9 {
+		return Card{}, fmt.Errorf("%w: unknown number in %q", ErrInvalidCardText, s)
+	}
+	return Card{Number: Number(n), Color: color}, nil
+}
+
+// Returns the cards as comma-separated card texts, bottom to top. The result
+// can be parsed back with ParseDeck.
+func (d Deck) Encode() string {
+	texts := make([]string, len(d))
+	for i, card := range d {
+		text, err := card.MarshalText()
+		if err != nil {
+			panic(fmt.Sprintf("Deck.Encode: %v", err))
+		}
+		texts[i] = string(text)
+	}
+	return strings.Join(texts, ",")
+}
+
+// Parses a deck encoded by Deck.Encode. Whitespace around card texts is
+// ignored.
+func ParseDeck(s string) (Deck, error) {
+	deck := NewEmptyDeck()
+	if strings.TrimSpace(s) == "" {
+		return deck, nil
+	}
+
+	for _, cardText := range strings.Split(s, ",") {
+		card, err := ParseCard(strings.TrimSpace(cardText))
+		if err != nil {
+			return nil, err
+		}
+		deck = deck.Push(card)
+	}
+	return deck, nil
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"time"
 
 	ui "github.com/gizak/termui/v3"
 	"github.com/nrawrx3/uknow"
@@ -42,7 +43,7 @@ func LoadConfig(configFile string) (client.ClientUserConfig, *uknow.AESCipher) {
 
 	var aesCipher *uknow.AESCipher
 	if clientConfig.EncryptMessages {
-		aesCipher, err = uknow.NewAESCipher(clientConfig.AESKeyString)
+		aesCipher, err = uknow.NewAESCipherFromConfig(clientConfig.AESKeyString, clientConfig.AESPassphrase, clientConfig.AESSalt)
 		if err != nil {
 			log.Fatalf("failed to create aes cipger: %v", err)
 		}
@@ -56,25 +57,33 @@ func RunApp() {
 	// flag.StringVar(&configPrefix, "conf-prefix", "", "config key prefix")
 
 	flag.StringVar(&configFile, "conf", "", "config file")
+	observe := flag.Bool("observe", false, "watch the game as a spectator without being dealt in")
 	flag.Parse()
 
 	clientConfig, aesCipher := LoadConfig(configFile)
+	clientConfig.Observe = clientConfig.Observe || *observe
 
-	if !client.IsUserNameAllowed(clientConfig.PlayerName) {
-		log.Fatalf("Only names with alphabet and underscore characters allowed, name given: %s", clientConfig.PlayerName)
+	if !uknow.IsUserNameAllowed(clientConfig.PlayerName) {
+		log.Fatalf("Only non-reserved names with alphabet and underscore characters and at most %d characters allowed, name given: %s", uknow.MaxPlayerNameLength, clientConfig.PlayerName)
 	}
 
-	tableLogger := uknow.CreateFileLogger(false, fmt.Sprintf("table_%s", clientConfig.PlayerName))
+	tableLogger, err := uknow.CreateFileLogger(false, clientConfig.LogDir, fmt.Sprintf("table_%s", clientConfig.PlayerName))
+	if err != nil {
+		log.Fatal(err)
+	}
 	table := uknow.NewTable(clientConfig.PlayerName, tableLogger)
 
 	// Channels used for comms events, etc.
 	commChannels := client.MakeCommChannels()
+	gameEventBuffer := uknow.NewGameEventBuffer(uknow.DefaultGameEventBufferCapacity, commChannels.GameEventChan, tableLogger)
+	defer gameEventBuffer.Close()
+
 	clientChannels := client.ClientChannels{
 		GeneralUICommandPushChan:       commChannels.GeneralUICommandChan,
 		AskUserForDecisionPushChan:     commChannels.AskUIForUserTurnChan,
 		NonDecisionReplCommandPullChan: commChannels.NonDecisionReplCommandsChan,
 		LogWindowPushChan:              commChannels.LogWindowChan,
-		GameEventPushChan:              commChannels.GameEventChan,
+		GameEventPushChan:              gameEventBuffer.PushChan(),
 	}
 
 	playerClientConfig := &client.ConfigNewPlayerClient{
@@ -85,12 +94,28 @@ func RunApp() {
 			Port:     clientConfig.CommandListenPort,
 			Protocol: "http",
 		},
-		AESCipher:   aesCipher,
-		AdvertiseIP: clientConfig.AdvertiseIP,
+		AESCipher:           aesCipher,
+		AdvertiseIP:         clientConfig.AdvertiseIP,
+		LogDir:              clientConfig.LogDir,
+		AutoReady:           clientConfig.AutoReady,
+		AutoReadyMinPlayers: clientConfig.AutoReadyMinPlayers,
+		Observe:             clientConfig.Observe,
+		RoomCode:            clientConfig.RoomCode,
+		WebSocket:           clientConfig.WebSocket,
 	}
 
 	if clientConfig.AdminHostIP != "" && clientConfig.AdminPort != 0 {
 		playerClientConfig.DefaultAdminAddr = utils.HostPortProtocol{IP: clientConfig.AdminHostIP, Port: clientConfig.AdminPort}
+		if clientConfig.AdminTLS {
+			playerClientConfig.DefaultAdminAddr.Protocol = "https"
+		}
+	}
+
+	if clientConfig.AdminCACertFile != "" {
+		playerClientConfig.AdminRootCAs, err = utils.LoadCertPool(clientConfig.AdminCACertFile)
+		if err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	// FILTHY(@rk):TODO(@rk): Delete this when done with proper implementation in ui
@@ -102,7 +127,7 @@ func RunApp() {
 	}
 
 	if commonConfig.EncryptMessages {
-		playerClientConfig.AESCipher, err = uknow.NewAESCipher(commonConfig.AESKey)
+		playerClientConfig.AESCipher, err = uknow.NewAESCipherFromConfig(commonConfig.AESKey, commonConfig.AESPassphrase, commonConfig.AESSalt)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -113,7 +138,20 @@ func RunApp() {
 	// go c.RunServer()
 	go c.RunGeneralCommandHandler()
 
-	uiLogger := uknow.CreateFileLogger(false, fmt.Sprintf("ui_%s", clientConfig.PlayerName))
+	uiLogger, err := uknow.CreateFileLogger(false, clientConfig.LogDir, fmt.Sprintf("ui_%s", clientConfig.PlayerName))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	commandHistoryFile := clientConfig.CommandHistoryFile
+	if commandHistoryFile == "" {
+		commandHistoryFile = client.DefaultCommandHistoryFile(clientConfig.PlayerName)
+	}
+
+	cardTransferDelay := client.DefaultCardTransferDelay
+	if clientConfig.CardTransferDelayMsecs != nil {
+		cardTransferDelay = time.Duration(*clientConfig.CardTransferDelayMsecs) * time.Millisecond
+	}
 
 	var clientUI client.ClientUI
 	clientUI.Init(uiLogger,
@@ -121,9 +159,17 @@ func RunApp() {
 		commChannels.AskUIForUserTurnChan,
 		commChannels.NonDecisionReplCommandsChan,
 		commChannels.GameEventChan,
-		commChannels.LogWindowChan)
+		commChannels.LogWindowChan,
+		commandHistoryFile,
+		cardTransferDelay,
+		clientConfig.DiscardPileCells,
+		clientConfig.LogDir)
 	defer ui.Close()
 
+	if clientConfig.Observe {
+		clientUI.SetSpectating()
+	}
+
 	go clientUI.RunPollInputEvents(clientConfig.PlayerName)
 	go clientUI.RunGeneralUICommandConsumer(clientConfig.PlayerName)
 	go clientUI.RunGameEventProcessor(clientConfig.PlayerName)
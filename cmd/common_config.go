@@ -4,6 +4,8 @@ import "github.com/kelseyhightower/envconfig"
 
 type CommonConfig struct {
 	AESKey          string `split_words:"true"`
+	AESPassphrase   string `split_words:"true"`
+	AESSalt         string `split_words:"true"`
 	EncryptMessages bool   `split_words:"true"`
 }
 
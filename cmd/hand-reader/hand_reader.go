@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nrawrx3/uknow/hand_reader"
+)
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-decks N] validate <hand-config-file>\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+func main() {
+	var deckCount int
+	flag.IntVar(&deckCount, "decks", 1, "number of full decks the admin plays with")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 2 || flag.Arg(0) != "validate" {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := hand_reader.Validate(flag.Arg(1), deckCount, os.Stdout); err != nil {
+		os.Exit(1)
+	}
+}
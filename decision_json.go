@@ -0,0 +1,128 @@
+package uknow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Decisions are encoded with the kind and the chosen color as names, e.g.
+// {"Kind":"PlayerDecisionPlayHandCard","ResultCard":"r-skip","WildCardChosenColor":"wild",...},
+// so that logged and captured payloads can be read as is. The integer forms
+// used before are still accepted when decoding.
+
+var ErrInvalidDecisionJSON = errors.New("invalid player decision json")
+
+type playerDecisionJSON struct {
+	Kind                string
+	ResultCard          Card
+	WildCardChosenColor string
+	TimedOut            bool
+	SwapTargetPlayer    string
+}
+
+// Same fields as playerDecisionJSON, but the kind and color can be either
+// names or integers.
+type playerDecisionAnyJSON struct {
+	Kind                json.RawMessage
+	ResultCard          Card
+	WildCardChosenColor json.RawMessage
+	TimedOut            bool
+	SwapTargetPlayer    string
+}
+
+func (d PlayerDecision) MarshalJSON() ([]byte, error) {
+	if d.Kind < PlayerDecisionPullFromDeck || d.Kind > PlayerDecisionJumpIn {
+		return nil, fmt.Errorf("%w: unknown kind %d", ErrInvalidDecisionJSON, d.Kind)
+	}
+	if d.WildCardChosenColor != ColorWild && !d.WildCardChosenColor.IsReal() {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidCardColor, d.WildCardChosenColor)
+	}
+
+	return json.Marshal(playerDecisionJSON{
+		Kind:                d.Kind.String(),
+		ResultCard:          d.ResultCard,
+		WildCardChosenColor: d.WildCardChosenColor.String(),
+		TimedOut:            d.TimedOut,
+		SwapTargetPlayer:    d.SwapTargetPlayer,
+	})
+}
+
+func (d *PlayerDecision) UnmarshalJSON(b []byte) error {
+	var decoded playerDecisionAnyJSON
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+
+	kind, err := decodeDecisionKind(decoded.Kind)
+	if err != nil {
+		return err
+	}
+	color, err := decodeDecisionColor(decoded.WildCardChosenColor)
+	if err != nil {
+		return err
+	}
+
+	*d = PlayerDecision{
+		Kind:                kind,
+		ResultCard:          decoded.ResultCard,
+		WildCardChosenColor: color,
+		TimedOut:            decoded.TimedOut,
+		SwapTargetPlayer:    decoded.SwapTargetPlayer,
+	}
+	return nil
+}
+
+func isJSONString(raw json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(raw), []byte(`"`))
+}
+
+func decodeDecisionKind(raw json.RawMessage) (PlayerDecisionKind, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+
+	if !isJSONString(raw) {
+		var kind PlayerDecisionKind
+		if err := json.Unmarshal(raw, &kind); err != nil {
+			return 0, fmt.Errorf("%w: kind %s", ErrInvalidDecisionJSON, raw)
+		}
+		return kind, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return 0, fmt.Errorf("%w: kind %s", ErrInvalidDecisionJSON, raw)
+	}
+	for kind := PlayerDecisionPullFromDeck; kind <= PlayerDecisionJumpIn; kind++ {
+		if kind.String() == name {
+			return kind, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown kind %q", ErrInvalidDecisionJSON, name)
+}
+
+func decodeDecisionColor(raw json.RawMessage) (Color, error) {
+	if len(raw) == 0 {
+		return ColorWild, nil
+	}
+
+	if !isJSONString(raw) {
+		var color Color
+		if err := json.Unmarshal(raw, &color); err != nil {
+			return ColorWild, fmt.Errorf("%w: color %s", ErrInvalidDecisionJSON, raw)
+		}
+		return color, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err != nil {
+		return ColorWild, fmt.Errorf("%w: color %s", ErrInvalidDecisionJSON, raw)
+	}
+	color, err := ParseColor(name)
+	if err != nil {
+		return ColorWild, fmt.Errorf("%w: %v", ErrInvalidDecisionJSON, err)
+	}
+	return color, nil
+}
@@ -0,0 +1,269 @@
+package uknow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+)
+
+// A Game runs a complete game in-process. It mirrors what the admin and the
+// player clients do over HTTP: the admin's table is the authority, each
+// player has their own view of the table, and the decisions of the player of
+// the current turn are evaluated on their view first and then synced to the
+// admin and every other player. Useful for simulations and tests.
+
+// Decides the next decision of a player given their own view of the table.
+// Decide is called repeatedly within a turn as long as the table needs more
+// decisions from the player.
+type PlayerStrategy interface {
+	Decide(table *Table, playerName string) PlayerDecision
+}
+
+type PlayerStrategyFunc func(table *Table, playerName string) PlayerDecision
+
+func (f PlayerStrategyFunc) Decide(table *Table, playerName string) PlayerDecision {
+	return f(table, playerName)
+}
+
+const DefaultMaxGameTurns = 1000
+
+type GameRules struct {
+	StartingHandCount int // Defaults to DefaultStartingHandCount
+	DeckCount         int // Number of standard decks combined into the draw deck. Defaults to 1.
+	MaxTurns          int // Game is stopped after these many turns without a winner. Defaults to DefaultMaxGameTurns.
+	HouseRules        HouseRules
+}
+
+type GameConfig struct {
+	Seed             int64
+	Rules            GameRules
+	PlayerNames      []string // In seating order. First player is the shuffler.
+	StrategyOfPlayer map[string]PlayerStrategy
+	Logger           *log.Logger // Defaults to discarding logs
+}
+
+type GameResult struct {
+	Table  *Table      // The admin's table at the end of the game
+	Events []GameEvent // Events emitted while evaluating decisions on the admin's table, in order
+	Turns  int
+}
+
+var ErrGameNotFinished = errors.New("game did not finish")
+
+type Game struct {
+	config        GameConfig
+	adminTable    *Table
+	tableOfPlayer map[string]*Table
+	events        GameEventRecorder
+	turns         int
+}
+
+func NewGame(config GameConfig) (*Game, error) {
+	if config.Logger == nil {
+		config.Logger = log.New(io.Discard, "", 0)
+	}
+	if config.Rules.StartingHandCount == 0 {
+		config.Rules.StartingHandCount = DefaultStartingHandCount
+	}
+	if config.Rules.MaxTurns == 0 {
+		config.Rules.MaxTurns = DefaultMaxGameTurns
+	}
+	if config.Rules.DeckCount < 0 || config.Rules.DeckCount > MaxDeckCount {
+		return nil, fmt.Errorf("deck count must be within 1 and %d, have %d", MaxDeckCount, config.Rules.DeckCount)
+	}
+	if len(config.PlayerNames) < 2 {
+		return nil, fmt.Errorf("need at least 2 players, have %d", len(config.PlayerNames))
+	}
+
+	adminTable := NewAdminTable(config.Logger)
+	adminTable.Rand = rand.New(rand.NewSource(config.Seed))
+	adminTable.DrawDeck = NewFullDeckN(config.Rules.DeckCount)
+	adminTable.HouseRules = config.Rules.HouseRules
+
+	for _, playerName := range config.PlayerNames {
+		if _, ok := config.StrategyOfPlayer[playerName]; !ok {
+			return nil, fmt.Errorf("no strategy for player %s", playerName)
+		}
+		if err := adminTable.AddPlayer(playerName); err != nil {
+			return nil, err
+		}
+	}
+
+	adminTable.ShufflerName = config.PlayerNames[0]
+	if err := adminTable.ShuffleDeckAndDistribute(config.Rules.StartingHandCount); err != nil {
+		return nil, err
+	}
+
+	game := &Game{
+		config:        config,
+		adminTable:    adminTable,
+		tableOfPlayer: make(map[string]*Table),
+		events:        GameEventRecorder{Events: []GameEvent{adminTable.OpeningCardEvent}},
+	}
+	adminTable.EventSink = &game.events
+
+	// Each player gets their own copy of the table, same as the served cards
+	// event does.
+	for _, playerName := range config.PlayerNames {
+		playerTable, err := game.copyAdminTableForPlayer(playerName)
+		if err != nil {
+			return nil, err
+		}
+		game.tableOfPlayer[playerName] = playerTable
+	}
+
+	return game, nil
+}
+
+// Plays the game until there's a winner or the max number of turns is reached.
+func (g *Game) Run() (*GameResult, error) {
+	for g.adminTable.TableState != HaveWinner {
+		if g.turns >= g.config.Rules.MaxTurns {
+			return g.result(), fmt.Errorf("%w: reached %d turns", ErrGameNotFinished, g.turns)
+		}
+
+		if err := g.RunTurn(); err != nil {
+			return g.result(), err
+		}
+	}
+	return g.result(), nil
+}
+
+// Asks the player of the next turn for their decisions and syncs them with
+// the admin and the other players.
+func (g *Game) RunTurn() error {
+	decidingPlayer := g.adminTable.PlayerOfNextTurn
+	decidingTable := g.tableOfPlayer[decidingPlayer]
+	strategy := g.config.StrategyOfPlayer[decidingPlayer]
+
+	decisions := make([]PlayerDecision, 0, 4)
+
+	for {
+		decision, err := decidingTable.EvalDecision(decidingPlayer, strategy.Decide(decidingTable, decidingPlayer))
+		if err != nil {
+			return fmt.Errorf("player %s: %w", decidingPlayer, err)
+		}
+		decisions = append(decisions, decision)
+
+		if !decidingTable.NeedMoreUserDecisionToFinishTurn() {
+			break
+		}
+	}
+
+	if err := g.adminTable.EvalDecisions(decidingPlayer, decisions); err != nil {
+		return fmt.Errorf("admin failed to eval decisions of player %s: %w", decidingPlayer, err)
+	}
+
+	for _, playerName := range g.config.PlayerNames {
+		if playerName == decidingPlayer {
+			continue
+		}
+		if err := g.tableOfPlayer[playerName].EvalDecisions(decidingPlayer, decisions); err != nil {
+			return fmt.Errorf("player %s failed to sync decisions of player %s: %w", playerName, decidingPlayer, err)
+		}
+	}
+
+	// A player's table can't know how the admin shuffled a refilled draw
+	// deck, so it is copied from the admin's again, as a client would resync.
+	for _, playerName := range g.config.PlayerNames {
+		if !g.tableOfPlayer[playerName].HasHiddenCards() {
+			continue
+		}
+		playerTable, err := g.copyAdminTableForPlayer(playerName)
+		if err != nil {
+			return err
+		}
+		g.tableOfPlayer[playerName] = playerTable
+	}
+
+	g.turns++
+	return nil
+}
+
+func (g *Game) AdminTable() *Table {
+	return g.adminTable
+}
+
+func (g *Game) TableOfPlayer(playerName string) *Table {
+	return g.tableOfPlayer[playerName]
+}
+
+func (g *Game) result() *GameResult {
+	return &GameResult{
+		Table:  g.adminTable,
+		Events: g.events.Events,
+		Turns:  g.turns,
+	}
+}
+
+func (g *Game) copyAdminTableForPlayer(playerName string) (*Table, error) {
+	b, err := json.Marshal(g.adminTable)
+	if err != nil {
+		return nil, err
+	}
+
+	playerTable := NewTable(playerName, g.config.Logger)
+	var servedTable Table
+	if err := json.Unmarshal(b, &servedTable); err != nil {
+		return nil, err
+	}
+	servedTable.LocalPlayerName = playerName
+	playerTable.Set(&servedTable)
+	return playerTable, nil
+}
+
+// A strategy that plays the first playable card in hand, choosing the color it
+// has the most cards of for wild cards. If it has no playable card, it draws
+// and plays the drawn card if possible, otherwise passes. Never challenges and
+// swaps hands with the opponent holding the fewest cards.
+var FirstPlayableCardStrategy PlayerStrategy = PlayerStrategyFunc(firstPlayableCardDecision)
+
+func firstPlayableCardDecision(table *Table, playerName string) PlayerDecision {
+	hand := table.HandOfPlayer[playerName]
+
+	switch table.TableState {
+	case AwaitingWildCardColorDecision, AwaitingWildDraw4CardColorDecision, AwaitingOpeningWildColorDecision:
+		return PlayerDecision{Kind: PlayerDecisionWildCardChooseColor, WildCardChosenColor: mostFrequentColor(hand)}
+
+	case AwaitingWildDraw4ChallengeDecision:
+		return PlayerDecision{Kind: PlayerDecisionDontChallenge}
+
+	case AwaitingSwapTargetDecision:
+		target := ""
+		for _, opponent := range table.PlayerNames {
+			if opponent != playerName && (target == "" || table.HandOfPlayer[opponent].Len() < table.HandOfPlayer[target].Len()) {
+				target = opponent
+			}
+		}
+		return PlayerDecision{Kind: PlayerDecisionChooseSwapTarget, SwapTargetPlayer: target}
+
+	case AwaitingDropOrPass:
+		if table.IsPlayable(table.LastDrawnCard) {
+			return PlayerDecision{Kind: PlayerDecisionPlayHandCard, ResultCard: table.LastDrawnCard}
+		}
+		return PlayerDecision{Kind: PlayerDecisionPass}
+	}
+
+	for _, card := range hand {
+		if table.IsPlayable(card) {
+			return PlayerDecision{Kind: PlayerDecisionPlayHandCard, ResultCard: card}
+		}
+	}
+	return PlayerDecision{Kind: PlayerDecisionPullFromDeck}
+}
+
+// Most held non-wild color of the hand, red if the hand has no colored cards.
+func mostFrequentColor(hand Deck) Color {
+	countOfColor, _ := hand.Histogram()
+
+	mostFrequent := ColorRed
+	for _, color := range RealColors() {
+		if countOfColor[color] > countOfColor[mostFrequent] {
+			mostFrequent = color
+		}
+	}
+	return mostFrequent
+}
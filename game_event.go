@@ -2,6 +2,7 @@ package uknow
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 )
 
@@ -25,6 +26,57 @@ type GameEvent interface {
 	FromLocalClient() bool
 }
 
+// Receives the game events emitted by a Table while it evaluates decisions.
+type GameEventSink interface {
+	Emit(event GameEvent)
+}
+
+// Adapts a game event channel to a GameEventSink. Emit blocks until the event
+// is received. Events are dropped if the channel is nil.
+type GameEventChan chan<- GameEvent
+
+func (c GameEventChan) Emit(event GameEvent) {
+	if c != nil {
+		c <- event
+	}
+}
+
+type discardGameEvents struct{}
+
+func (discardGameEvents) Emit(GameEvent) {}
+
+// Sink that drops every event.
+var DiscardGameEvents GameEventSink = discardGameEvents{}
+
+// Sink that keeps every event in the order they were emitted.
+type GameEventRecorder struct {
+	Events []GameEvent
+}
+
+func (r *GameEventRecorder) Emit(event GameEvent) {
+	r.Events = append(r.Events, event)
+}
+
+type stringMessager interface {
+	StringMessage(localPlayerName string) string
+}
+
+// Returns the StringMessage of the event, false if the event type has none.
+func GameEventMessage(event GameEvent, localPlayerName string) (string, bool) {
+	if messager, ok := event.(stringMessager); ok {
+		return messager.StringMessage(localPlayerName), true
+	}
+
+	// The events are passed around by value, but StringMessage has a pointer
+	// receiver.
+	eventPtr := reflect.New(reflect.TypeOf(event))
+	eventPtr.Elem().Set(reflect.ValueOf(event))
+	if messager, ok := eventPtr.Interface().(stringMessager); ok {
+		return messager.StringMessage(localPlayerName), true
+	}
+	return "", false
+}
+
 type CardTransferEvent struct {
 	Source            CardTransferNode
 	Sink              CardTransferNode
@@ -83,9 +135,11 @@ func (e SkipCardActionEvent) FromLocalClient() bool {
 }
 
 type DrawTwoCardActionEvent struct {
-	Player            string
-	SkippedPlayer     string
-	NextPlayer        string
+	Player        string
+	SkippedPlayer string
+	NextPlayer    string
+	// Number of the two penalty cards the draw deck could not supply.
+	CardsShort        int
 	IsFromLocalClient bool
 }
 
@@ -94,6 +148,9 @@ func (e *DrawTwoCardActionEvent) StringMessage(localPlayerName string) string {
 	skippedPlayerName, _ := changeIfSelf(e.SkippedPlayer, localPlayerName)
 	nextPlayerName, _ := changeIfSelf(e.NextPlayer, localPlayerName)
 
+	if e.CardsShort > 0 {
+		return fmt.Sprintf("%s played a draw-2-card, skipping %s who could only draw %d of the cards from the draw deck, making %s the next player", playerName, skippedPlayerName, 2-e.CardsShort, nextPlayerName)
+	}
 	return fmt.Sprintf("%s played a draw-2-card, skipping and adding cards to %s, making %s the next player", playerName, skippedPlayerName, nextPlayerName)
 }
 
@@ -180,7 +237,7 @@ func (e AwaitingWildCardColorDecisionEvent) GameEventName() string {
 
 type WildCardColorChosenEvent struct {
 	Player            string
-	ChosenColor       Card
+	ChosenColor       Color
 	IsFromLocalClient bool
 }
 
@@ -246,6 +303,26 @@ func (e ChallengerFailedEvent) GameEventName() string {
 	return "ChallengerFailedEvent"
 }
 
+type ChallengeTimedOutEvent struct {
+	Player              string
+	WildDraw4PlayerName string
+	IsFromLocalClient   bool
+}
+
+func (e *ChallengeTimedOutEvent) StringMessage(localPlayerName string) string {
+	playerName, _ := changeIfSelf(e.Player, localPlayerName)
+	wildPlayerName, _ := changeIfSelf(e.WildDraw4PlayerName, localPlayerName)
+	return fmt.Sprintf("%s did not decide to challenge %s in time, defaulting to no_challenge", playerName, wildPlayerName)
+}
+
+func (e ChallengeTimedOutEvent) FromLocalClient() bool {
+	return e.IsFromLocalClient
+}
+
+func (e ChallengeTimedOutEvent) GameEventName() string {
+	return "ChallengeTimedOutEvent"
+}
+
 type AwaitingPlayOrPassEvent struct {
 	Player                     string
 	AskDecisionFromLocalPlayer bool
@@ -284,9 +361,59 @@ func (e PlayerPassedTurnEvent) GameEventName() string {
 	return "PlayerPassedTurnEvent"
 }
 
+// Sent when a player undoes the draw of their turn. If ReturnedCard is set the
+// drawn card went back on top of the draw deck and the player decides again,
+// otherwise the player kept the card and passed.
+type UndoEvent struct {
+	Player            string
+	ReturnedCard      bool
+	IsFromLocalClient bool
+}
+
+func (e *UndoEvent) StringMessage(localPlayerName string) string {
+	playerName, _ := changeIfSelf(e.Player, localPlayerName)
+	if e.ReturnedCard {
+		return fmt.Sprintf("%s undid the draw and put the card back on the draw deck", playerName)
+	}
+	return fmt.Sprintf("%s undid the draw and kept the card", playerName)
+}
+
+func (e UndoEvent) FromLocalClient() bool {
+	return e.IsFromLocalClient
+}
+
+func (e UndoEvent) GameEventName() string {
+	return "UndoEvent"
+}
+
+type JumpInEvent struct {
+	Player            string
+	InterruptedPlayer string // Player whose turn it was
+	Card              Card
+	IsFromLocalClient bool
+}
+
+func (e *JumpInEvent) StringMessage(localPlayerName string) string {
+	playerName, _ := changeIfSelf(e.Player, localPlayerName)
+	interruptedPlayerName, you := changeIfSelf(e.InterruptedPlayer, localPlayerName)
+	if you {
+		return fmt.Sprintf("%s jumped in with %s, taking your turn", playerName, e.Card.String())
+	}
+	return fmt.Sprintf("%s jumped in with %s, taking %s's turn", playerName, e.Card.String(), interruptedPlayerName)
+}
+
+func (e JumpInEvent) FromLocalClient() bool {
+	return e.IsFromLocalClient
+}
+
+func (e JumpInEvent) GameEventName() string {
+	return "JumpInEvent"
+}
+
 type PlayerHasWonEvent struct {
 	Player            string
 	IsFromLocalClient bool
+	Stats             map[string]PlayerStats // Of every player at the end of the game, see Table.Stats
 }
 
 func (e *PlayerHasWonEvent) StringMessage(localPlayerName string) string {
@@ -320,3 +447,107 @@ func (e RequiredColorUpdatedEvent) FromLocalClient() bool {
 func (e RequiredColorUpdatedEvent) GameEventName() string {
 	return "RequiredColorUpdatedEvent"
 }
+
+type AwaitingSwapTargetDecisionEvent struct {
+	Player                     string
+	AskDecisionFromLocalPlayer bool
+	IsFromLocalClient          bool
+}
+
+func (e *AwaitingSwapTargetDecisionEvent) StringMessage(localPlayerName string) string {
+	playerName, _ := changeIfSelf(e.Player, localPlayerName)
+	return fmt.Sprintf("Need a player to swap hands with from %s", playerName)
+}
+
+func (e AwaitingSwapTargetDecisionEvent) FromLocalClient() bool {
+	return e.IsFromLocalClient
+}
+
+func (e AwaitingSwapTargetDecisionEvent) GameEventName() string {
+	return "AwaitingSwapTargetDecisionEvent"
+}
+
+// Emitted when a 0 is played under the Seven-Zero rule. Every hand moves to the
+// next player in the direction of play.
+type HandsRotatedEvent struct {
+	Player            string
+	HandCountOfPlayer map[string]int
+	LocalPlayerHand   Deck // New hand of the local player, empty on admin
+	IsFromLocalClient bool
+}
+
+func (e *HandsRotatedEvent) StringMessage(localPlayerName string) string {
+	playerName, _ := changeIfSelf(e.Player, localPlayerName)
+	return fmt.Sprintf("%s played a 0, all hands were passed on", playerName)
+}
+
+func (e HandsRotatedEvent) FromLocalClient() bool {
+	return e.IsFromLocalClient
+}
+
+func (e HandsRotatedEvent) GameEventName() string {
+	return "HandsRotatedEvent"
+}
+
+// Emitted when a 7 is played under the Seven-Zero rule and its player chose
+// whom to swap hands with.
+type HandsSwappedEvent struct {
+	Player            string
+	TargetPlayer      string
+	HandCountOfPlayer map[string]int
+	LocalPlayerHand   Deck // New hand of the local player, empty on admin
+	IsFromLocalClient bool
+}
+
+func (e *HandsSwappedEvent) StringMessage(localPlayerName string) string {
+	playerName, _ := changeIfSelf(e.Player, localPlayerName)
+	targetPlayerName, _ := changeIfSelf(e.TargetPlayer, localPlayerName)
+	return fmt.Sprintf("%s swapped hands with %s", playerName, targetPlayerName)
+}
+
+func (e HandsSwappedEvent) FromLocalClient() bool {
+	return e.IsFromLocalClient
+}
+
+func (e HandsSwappedEvent) GameEventName() string {
+	return "HandsSwappedEvent"
+}
+
+// Describes the opening card flipped onto the discard pile before the first
+// turn and the opening action that was applied.
+type OpeningCardEvent struct {
+	Card           Card          `json:"card"`
+	Action         OpeningAction `json:"action"`
+	ShufflerName   string        `json:"shuffler_name"`
+	AffectedPlayer string        `json:"affected_player"` // Skipped player, if any
+	NextPlayer     string        `json:"next_player"`
+
+	NextPlayerChoosesColor bool `json:"next_player_chooses_color"` // Opening card is a wild and HouseRules.ChooseOpeningWildColor is set
+}
+
+func (e *OpeningCardEvent) StringMessage(localPlayerName string) string {
+	affectedPlayerName, _ := changeIfSelf(e.AffectedPlayer, localPlayerName)
+	nextPlayerName, _ := changeIfSelf(e.NextPlayer, localPlayerName)
+
+	switch e.Action {
+	case OpeningActionSkip:
+		return fmt.Sprintf("Opening card is %s, skipping %s, making %s the first player", e.Card.String(), affectedPlayerName, nextPlayerName)
+	case OpeningActionDrawTwo:
+		return fmt.Sprintf("Opening card is %s, %s draws 2 cards and is skipped, making %s the first player", e.Card.String(), affectedPlayerName, nextPlayerName)
+	case OpeningActionReverse:
+		return fmt.Sprintf("Opening card is %s, direction is reversed, making %s the first player", e.Card.String(), nextPlayerName)
+	default:
+		if e.NextPlayerChoosesColor {
+			return fmt.Sprintf("Opening card is %s, %s is the first player and chooses the color", e.Card.String(), nextPlayerName)
+		}
+		return fmt.Sprintf("Opening card is %s, %s is the first player", e.Card.String(), nextPlayerName)
+	}
+}
+
+func (e OpeningCardEvent) FromLocalClient() bool {
+	return false
+}
+
+func (e OpeningCardEvent) GameEventName() string {
+	return "OpeningCardEvent"
+}
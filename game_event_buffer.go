@@ -0,0 +1,107 @@
+package uknow
+
+import (
+	"log"
+)
+
+// GameEventBuffer sits between a table evaluating decisions and a possibly
+// slow consumer of the game events, e.g. the client UI. Sends on PushChan never
+// block for longer than it takes the buffer's goroutine to enqueue the event.
+// Events are forwarded to the consumer in order. When the queue is full, a
+// queued event that the incoming event supersedes is removed (only the latest
+// RequiredColorUpdatedEvent matters), otherwise the oldest queued event is
+// dropped. Both cases are logged.
+type GameEventBuffer struct {
+	pushChan chan GameEvent
+	pullChan chan<- GameEvent
+	capacity int
+	logger   *log.Logger
+	done     chan struct{}
+}
+
+const DefaultGameEventBufferCapacity = 256
+
+// Creates the buffer and starts forwarding events to pullChan. Close the
+// buffer to stop forwarding.
+func NewGameEventBuffer(capacity int, pullChan chan<- GameEvent, logger *log.Logger) *GameEventBuffer {
+	if capacity <= 0 {
+		capacity = DefaultGameEventBufferCapacity
+	}
+
+	b := &GameEventBuffer{
+		pushChan: make(chan GameEvent),
+		pullChan: pullChan,
+		capacity: capacity,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+func (b *GameEventBuffer) PushChan() chan<- GameEvent {
+	return b.pushChan
+}
+
+// Stops accepting events. Events still in the queue are not forwarded.
+func (b *GameEventBuffer) Close() {
+	close(b.pushChan)
+	<-b.done
+}
+
+func (b *GameEventBuffer) run() {
+	defer close(b.done)
+
+	queue := make([]GameEvent, 0, b.capacity)
+
+	for {
+		// Sending on a nil channel blocks forever, which disables the send case
+		// while the queue is empty.
+		var pullChan chan<- GameEvent
+		var front GameEvent
+		if len(queue) > 0 {
+			pullChan = b.pullChan
+			front = queue[0]
+		}
+
+		select {
+		case event, ok := <-b.pushChan:
+			if !ok {
+				return
+			}
+			queue = b.enqueue(queue, event)
+
+		case pullChan <- front:
+			queue = queue[1:]
+		}
+	}
+}
+
+func (b *GameEventBuffer) enqueue(queue []GameEvent, event GameEvent) []GameEvent {
+	if len(queue) < b.capacity {
+		return append(queue, event)
+	}
+
+	if isCoalescableGameEvent(event) {
+		for i := len(queue) - 1; i >= 0; i-- {
+			if queue[i].GameEventName() == event.GameEventName() {
+				b.logger.Printf("GameEventBuffer: queue full, coalesced %s", event.GameEventName())
+				queue = append(queue[:i], queue[i+1:]...)
+				return append(queue, event)
+			}
+		}
+	}
+
+	b.logger.Printf("GameEventBuffer: queue full, dropped %s", queue[0].GameEventName())
+	return append(queue[1:], event)
+}
+
+// Reports whether only the latest of the events of the same kind as event is
+// of interest to a consumer.
+func isCoalescableGameEvent(event GameEvent) bool {
+	switch event.(type) {
+	case RequiredColorUpdatedEvent:
+		return true
+	}
+	return false
+}
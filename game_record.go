@@ -0,0 +1,221 @@
+package uknow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// A game record keeps every decision and game event of a single game as JSON
+// lines, so that a finished game can be shared and narrated again.
+
+type GameRecordEntryKind string
+
+const (
+	GameRecordStart     GameRecordEntryKind = "start"
+	GameRecordDecisions GameRecordEntryKind = "decisions"
+	GameRecordEvent     GameRecordEntryKind = "event"
+	GameRecordEnd       GameRecordEntryKind = "end"
+)
+
+// A line of a game record. Only the fields of the entry's kind are set.
+type GameRecordEntry struct {
+	Kind GameRecordEntryKind `json:"kind"`
+
+	// Set for GameRecordStart.
+	PlayerNames  []string    `json:"player_names,omitempty"`
+	ShufflerName string      `json:"shuffler_name,omitempty"`
+	HouseRules   *HouseRules `json:"house_rules,omitempty"`
+
+	// Set for GameRecordDecisions.
+	Player    string           `json:"player,omitempty"`
+	Decisions []PlayerDecision `json:"decisions,omitempty"`
+
+	// Set for GameRecordEvent. Event is decoded from EventJSON as the type
+	// named by EventName when reading a record.
+	EventName string          `json:"event_name,omitempty"`
+	EventJSON json.RawMessage `json:"event,omitempty"`
+	Event     GameEvent       `json:"-"`
+
+	// Set for GameRecordEnd, empty if the game ended without a winner, e.g.
+	// when the admin shut down.
+	Winner string `json:"winner,omitempty"`
+}
+
+var ErrUnknownGameEvent = errors.New("unknown game event")
+
+// Types of the recordable game events by their GameEventName.
+var gameEventTypeOfName = func() map[string]reflect.Type {
+	events := []GameEvent{
+		CardTransferEvent{},
+		SkipCardActionEvent{},
+		DrawTwoCardActionEvent{},
+		ReverseCardActionEvent{},
+		WildCardActionEvent{},
+		AwaitingWildCardColorDecisionEvent{},
+		WildCardColorChosenEvent{},
+		ChallengerSuccessEvent{},
+		ChallengerFailedEvent{},
+		ChallengeTimedOutEvent{},
+		AwaitingPlayOrPassEvent{},
+		PlayerPassedTurnEvent{},
+		UndoEvent{},
+		JumpInEvent{},
+		PlayerHasWonEvent{},
+		RequiredColorUpdatedEvent{},
+		AwaitingSwapTargetDecisionEvent{},
+		HandsRotatedEvent{},
+		HandsSwappedEvent{},
+		OpeningCardEvent{},
+	}
+
+	typeOfName := make(map[string]reflect.Type, len(events))
+	for _, event := range events {
+		typeOfName[event.GameEventName()] = reflect.TypeOf(event)
+	}
+	return typeOfName
+}()
+
+// Writes a game record, one entry per line. Also a GameEventSink, so that it
+// can be set as the EventSink of the table of the recorded game. Stops
+// writing after the first error, see Err.
+type GameRecordWriter struct {
+	encoder *json.Encoder
+	err     error
+}
+
+func NewGameRecordWriter(w io.Writer) *GameRecordWriter {
+	return &GameRecordWriter{encoder: json.NewEncoder(w)}
+}
+
+func (r *GameRecordWriter) write(entry *GameRecordEntry) {
+	if r.err != nil {
+		return
+	}
+	r.err = r.encoder.Encode(entry)
+}
+
+// Records the players and rules of the served table, and its opening card.
+func (r *GameRecordWriter) RecordStart(table *Table) {
+	houseRules := table.HouseRules
+	r.write(&GameRecordEntry{
+		Kind:         GameRecordStart,
+		PlayerNames:  table.PlayerNames,
+		ShufflerName: table.ShufflerName,
+		HouseRules:   &houseRules,
+	})
+	r.Emit(table.OpeningCardEvent)
+}
+
+func (r *GameRecordWriter) RecordDecisions(decidingPlayer string, decisions []PlayerDecision) {
+	r.write(&GameRecordEntry{
+		Kind:      GameRecordDecisions,
+		Player:    decidingPlayer,
+		Decisions: decisions,
+	})
+}
+
+func (r *GameRecordWriter) Emit(event GameEvent) {
+	if r.err != nil {
+		return
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		r.err = err
+		return
+	}
+	r.write(&GameRecordEntry{
+		Kind:      GameRecordEvent,
+		EventName: event.GameEventName(),
+		EventJSON: eventJSON,
+	})
+}
+
+// Records the end of the game, winner is empty if it has none.
+func (r *GameRecordWriter) RecordEnd(winner string) {
+	r.write(&GameRecordEntry{
+		Kind:   GameRecordEnd,
+		Winner: winner,
+	})
+}
+
+// First error while writing the record, if any.
+func (r *GameRecordWriter) Err() error {
+	return r.err
+}
+
+// Reads the entries of a game record, decoding the event of each event entry.
+func ReadGameRecord(r io.Reader) ([]GameRecordEntry, error) {
+	decoder := json.NewDecoder(r)
+
+	var entries []GameRecordEntry
+	for {
+		var entry GameRecordEntry
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, fmt.Errorf("entry %d: %w", len(entries)+1, err)
+		}
+
+		if entry.Kind == GameRecordEvent {
+			eventType, ok := gameEventTypeOfName[entry.EventName]
+			if !ok {
+				return entries, fmt.Errorf("entry %d: %w: %s", len(entries)+1, ErrUnknownGameEvent, entry.EventName)
+			}
+			eventPtr := reflect.New(eventType)
+			if err := json.Unmarshal(entry.EventJSON, eventPtr.Interface()); err != nil {
+				return entries, fmt.Errorf("entry %d: %w", len(entries)+1, err)
+			}
+			entry.Event = eventPtr.Elem().Interface().(GameEvent)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// Local player name the events are narrated for. Not a valid player name, so
+// that every player is narrated by name.
+const narratorName = "<narrator>"
+
+// Writes a turn by turn narration of a recorded game, with the StringMessage of
+// each event. Card transfers have none, they are narrated by their String.
+func NarrateGameRecord(w io.Writer, entries []GameRecordEntry) {
+	turn := 0
+	for _, entry := range entries {
+		switch entry.Kind {
+		case GameRecordStart:
+			houseRules := "none"
+			if entry.HouseRules != nil {
+				houseRules = entry.HouseRules.String()
+			}
+			fmt.Fprintf(w, "Game of %s, shuffled by %s, house rules: %s\n", strings.Join(entry.PlayerNames, ", "), entry.ShufflerName, houseRules)
+
+		case GameRecordDecisions:
+			turn++
+			decisionStrings := make([]string, len(entry.Decisions))
+			for i := range entry.Decisions {
+				decisionStrings[i] = entry.Decisions[i].String()
+			}
+			fmt.Fprintf(w, "--- Turn %d: %s decided %s\n", turn, entry.Player, strings.Join(decisionStrings, ", "))
+
+		case GameRecordEvent:
+			if transfer, ok := entry.Event.(CardTransferEvent); ok {
+				fmt.Fprintf(w, "    %s\n", transfer.String(narratorName))
+			} else if message, ok := GameEventMessage(entry.Event, narratorName); ok {
+				fmt.Fprintf(w, "    %s\n", message)
+			}
+
+		case GameRecordEnd:
+			if entry.Winner != "" {
+				fmt.Fprintf(w, "--- Game over after %d turns, %s won\n", turn, entry.Winner)
+			} else {
+				fmt.Fprintf(w, "--- Game ended after %d turns without a winner\n", turn)
+			}
+		}
+	}
+}
@@ -47,7 +47,8 @@ import (
 			["red", 9],
 			["red", "skip"],
 			["wild_draw_4"]
-		]
+		],
+		"required_color": "blue" // only with a wild on top of preset_discard_pile_top, which it must be then
 	}
 */
 
@@ -65,11 +66,13 @@ type serializedJSON struct {
 	discardedPileSize    int
 	playerToDraw         string
 	presetDiscardPileTop uknow.Deck
+	requiredColor        uknow.Color // ColorWild if not given
 }
 
 var ErrLocalPlayerNameNotDescribed = errors.New("local player name is not in hand-desc map")
 var ErrUnknownKey = errors.New("unknown key")
 var ErrUnexpectedJSONType = errors.New("unexpected JSON type")
+var ErrInvalidRequiredColor = errors.New("invalid required_color")
 
 // Reads the hand-config JSON string and modifies the given table accordingly.
 // The table should be the returned value of NewTable(...) or
@@ -80,6 +83,7 @@ func LoadConfig(j map[string]interface{}, initializedTable *uknow.Table, logger
 	discardedPileSize := 0
 	playerOfNextTurn := ""
 	presetDiscardPileTop := make(uknow.Deck, 0)
+	requiredColor := uknow.ColorWild
 
 	for key, value := range j {
 		if strings.HasPrefix(key, "player.") {
@@ -106,6 +110,13 @@ func LoadConfig(j map[string]interface{}, initializedTable *uknow.Table, logger
 			if err != nil {
 				return nil, err
 			}
+		} else if key == "required_color" {
+			colorName, _ := value.(string)
+			color, err := uknow.ParseColor(colorName)
+			if err != nil || !color.IsReal() {
+				return nil, fmt.Errorf("%w: expected one of red, green, blue or yellow", ErrInvalidRequiredColor)
+			}
+			requiredColor = color
 		} else {
 			return nil, fmt.Errorf("%w: %s", ErrUnknownKey, key)
 		}
@@ -116,6 +127,11 @@ func LoadConfig(j map[string]interface{}, initializedTable *uknow.Table, logger
 		discardedPileSize:    discardedPileSize,
 		playerToDraw:         playerOfNextTurn,
 		presetDiscardPileTop: presetDiscardPileTop,
+		requiredColor:        requiredColor,
+	}
+
+	if err := checkRequiredColor(presetDiscardPileTop, requiredColor); err != nil {
+		return nil, err
 	}
 
 	return makeTable(serializedJSON, initializedTable, logger)
@@ -141,6 +157,40 @@ func LoadConfigFromFile(filepath string, initializedTable *uknow.Table, logger *
 	return LoadConfig(j, initializedTable, logger)
 }
 
+// Loads the hand-config file on top of deckCount full decks and checks that
+// the resulting table can be played on. A report of the hands and decks is
+// written to report in either case, as far as the config could be loaded.
+func Validate(filepath string, deckCount int, report io.Writer) error {
+	table := uknow.NewAdminTable(log.New(io.Discard, "", 0))
+	table.DrawDeck = uknow.NewFullDeckN(deckCount)
+
+	table, err := LoadConfigFromFile(filepath, table, table.Logger)
+	if err != nil {
+		fmt.Fprintf(report, "%s: could not load: %v\n", filepath, err)
+		return err
+	}
+
+	writeReport(report, table)
+
+	if err := table.Validate(); err != nil {
+		fmt.Fprintf(report, "%s: invalid: %v\n", filepath, err)
+		return err
+	}
+	fmt.Fprintf(report, "%s: ok\n", filepath)
+	return nil
+}
+
+func writeReport(w io.Writer, table *uknow.Table) {
+	fmt.Fprintf(w, "players: %s\n", strings.Join(table.PlayerNames, ", "))
+	fmt.Fprintf(w, "player of next turn: %s\n", table.PlayerOfNextTurn)
+	for _, playerName := range table.PlayerNames {
+		hand := table.HandOfPlayer[playerName]
+		fmt.Fprintf(w, "hand of %s (%d cards): %s\n", playerName, hand.Len(), hand)
+	}
+	fmt.Fprintf(w, "draw deck: %d cards\n", table.DrawDeck.Len())
+	fmt.Fprintf(w, "discard pile (%d cards, bottom to top): %s\n", table.DiscardedPile.Len(), table.DiscardedPile)
+}
+
 var ErrCouldNotRemoveCard = errors.New("could not remove card")
 
 // updates countOfCard by removing each given card in cards slice
@@ -205,13 +255,19 @@ func makeTable(serializedJSON serializedJSON, table *uknow.Table, logger *log.Lo
 			c := table.DrawDeck.MustTop()
 			table.DrawDeck = table.DrawDeck.MustPop()
 			remainingDiscardPile = remainingDiscardPile.Push(c)
-			table.DrawDeck = table.DrawDeck.MustPop()
 		}
 
 		table.DiscardedPile = append(remainingDiscardPile, table.DiscardedPile...)
 	}
 
+	if table.DiscardedPile.IsEmpty() {
+		return nil, fmt.Errorf("%w: set discarded_pile_size or preset_discard_pile_top", uknow.ErrDiscardPileIsEmpty)
+	}
+
 	table.RequiredColorOfCurrentTurn = table.DiscardedPile.MustTop().Color
+	if serializedJSON.requiredColor != uknow.ColorWild {
+		table.RequiredColorOfCurrentTurn = serializedJSON.requiredColor
+	}
 	table.RequiredNumberOfCurrentTurn = table.DiscardedPile.MustTop().Number
 	table.IsShuffled = true
 	table.PlayerOfNextTurn = serializedJSON.playerToDraw
@@ -221,40 +277,6 @@ func makeTable(serializedJSON serializedJSON, table *uknow.Table, logger *log.Lo
 	return table, nil
 }
 
-func colorFromKey(colorKey string) (uknow.Color, error) {
-	switch strings.ToLower(colorKey) {
-	case "red":
-		return uknow.ColorRed, nil
-	case "blue":
-		return uknow.ColorBlue, nil
-	case "green":
-		return uknow.ColorGreen, nil
-	case "yellow":
-		return uknow.ColorYellow, nil
-	case "wild":
-		return uknow.ColorWild, nil
-	default:
-		return uknow.ColorWild, fmt.Errorf("unknown color key: '%s'", colorKey)
-	}
-}
-
-func numberFromSpecial(special string) (uknow.Number, error) {
-	switch strings.ToLower(special) {
-	case "skip":
-		return uknow.NumberSkip, nil
-	case "reverse":
-		return uknow.NumberReverse, nil
-	case "draw_2":
-		return uknow.NumberDrawTwo, nil
-	case "wild":
-		return uknow.NumberWild, nil
-	case "wild_draw_4":
-		return uknow.NumberWildDrawFour, nil
-	default:
-		return uknow.Number(0), fmt.Errorf("unknown special number key: %s", special)
-	}
-}
-
 func tryCastNumber(v interface{}) (uknow.Number, error) {
 	number, ok := v.(float64)
 	if ok {
@@ -268,7 +290,7 @@ func tryCastNumber(v interface{}) (uknow.Number, error) {
 	if !ok {
 		return uknow.Number(0), errors.New("could to cast value to uknow.Number")
 	}
-	return numberFromSpecial(specialString)
+	return uknow.ParseNumber(specialString)
 }
 
 func castHandDescMap(handDescIF interface{}) (*handDesc, error) {
@@ -282,10 +304,9 @@ func castHandDescMap(handDescIF interface{}) (*handDesc, error) {
 		drawUpto:     drawUpto{},
 	}
 
-	handDesc.cardsOfColor[uknow.ColorRed] = make([]uknow.Card, 0, len(handDescMap))
-	handDesc.cardsOfColor[uknow.ColorBlue] = make([]uknow.Card, 0, len(handDescMap))
-	handDesc.cardsOfColor[uknow.ColorGreen] = make([]uknow.Card, 0, len(handDescMap))
-	handDesc.cardsOfColor[uknow.ColorYellow] = make([]uknow.Card, 0, len(handDescMap))
+	for _, color := range uknow.RealColors() {
+		handDesc.cardsOfColor[color] = make([]uknow.Card, 0, len(handDescMap))
+	}
 
 	for key, valueIF := range handDescMap {
 		if key == "draw_upto" {
@@ -297,7 +318,7 @@ func castHandDescMap(handDescIF interface{}) (*handDesc, error) {
 			continue
 		}
 
-		color, err := colorFromKey(key)
+		color, err := uknow.ParseColor(key)
 		if err != nil {
 			return nil, err
 		}
@@ -345,6 +366,21 @@ func castDrawUpto(v interface{}) (drawUpto, error) {
 	return drawUpto, nil
 }
 
+// A wild has no color of its own, so a wild on top of the preset discard pile
+// needs the required color given, otherwise no card but another wild could be
+// played on it. The required color is not allowed with any other top card.
+func checkRequiredColor(presetDiscardPileTop uknow.Deck, requiredColor uknow.Color) error {
+	wildOnTop := !presetDiscardPileTop.IsEmpty() && presetDiscardPileTop.MustTop().IsWild()
+
+	if wildOnTop && requiredColor == uknow.ColorWild {
+		return fmt.Errorf("%w: required_color must be set with a wild on top of preset_discard_pile_top", ErrInvalidRequiredColor)
+	}
+	if !wildOnTop && requiredColor != uknow.ColorWild {
+		return fmt.Errorf("%w: required_color is only allowed with a wild on top of preset_discard_pile_top", ErrInvalidRequiredColor)
+	}
+	return nil
+}
+
 func parsePresetDiscardPileTop(value interface{}) (uknow.Deck, error) {
 	array, ok := value.([]interface{})
 	if !ok {
@@ -364,40 +400,21 @@ func parsePresetDiscardPileTop(value interface{}) (uknow.Deck, error) {
 		switch len(tupleCardDesc) {
 		case 1:
 			// Wild card
-			wildCardName, ok := tupleCardDesc[0].(string)
-			if !ok || (wildCardName != "wild" && wildCardName != "wild_draw_4") {
+			wildCardName, _ := tupleCardDesc[0].(string)
+			number, err := uknow.ParseNumber(wildCardName)
+			card = uknow.Card{Number: number, Color: uknow.ColorWild}
+			if err != nil || !card.IsWild() {
 				return nil, fmt.Errorf("%w: expected either wild or wild_draw_4 string for preset_discard_pile_top array item at index: %d", ErrUnexpectedJSONType, i)
 			}
-			switch wildCardName {
-			case "wild":
-				card = uknow.Card{
-					Number: uknow.NumberWild,
-					Color:  uknow.ColorWild,
-				}
-			case "wild_draw_4":
-				card = uknow.Card{
-					Number: uknow.NumberWildDrawFour,
-					Color:  uknow.ColorWild,
-				}
-			}
 
 		case 2:
 			// Non wild card
-			colorName, ok := tupleCardDesc[0].(string)
-			if !ok || (colorName != "red" && colorName != "blue" && colorName != "yellow" && colorName != "green") {
+			colorName, _ := tupleCardDesc[0].(string)
+			color, err := uknow.ParseColor(colorName)
+			if err != nil || color == uknow.ColorWild {
 				return nil, fmt.Errorf("%w: expected a color name as first array element for preset_discard_pile_top array item at index: %d", ErrUnexpectedJSONType, i)
 			}
-
-			switch colorName {
-			case "red":
-				card.Color = uknow.ColorRed
-			case "blue":
-				card.Color = uknow.ColorBlue
-			case "yellow":
-				card.Color = uknow.ColorYellow
-			case "green":
-				card.Color = uknow.ColorGreen
-			}
+			card.Color = color
 
 			switch number := tupleCardDesc[1].(type) {
 			case float64:
@@ -407,14 +424,8 @@ func parsePresetDiscardPileTop(value interface{}) (uknow.Deck, error) {
 					return nil, fmt.Errorf("invalid number for card description for preset_discard_pile_top array item at index: %d", i)
 				}
 			case string:
-				switch number {
-				case "skip":
-					card.Number = uknow.NumberSkip
-				case "reverse":
-					card.Number = uknow.NumberReverse
-				case "draw_2":
-					card.Number = uknow.NumberDrawTwo
-				default:
+				card.Number, err = uknow.ParseNumber(number)
+				if err != nil || card.IsWild() {
 					return nil, fmt.Errorf("invalid number for card description for preset_discard_pile_top array item at index: %d", i)
 				}
 			default:
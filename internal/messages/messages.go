@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/nrawrx3/uknow"
 	"github.com/nrawrx3/uknow/internal/utils"
@@ -16,6 +17,14 @@ import (
 type AddNewPlayersMessage struct {
 	PlayerNames       []string                 `json:"player_names"`
 	ClientListenAddrs []utils.HostPortProtocol `json:"client_listen_addrs"`
+
+	// Room of the game to join when the admin hosts several, empty otherwise.
+	// Must match the room code in the URL.
+	RoomCode string `json:"room_code"`
+
+	// Token issued by the admin when the player first joined. Only set when
+	// reconnecting, the admin rejects a reconnect with a wrong token.
+	SessionToken string `json:"session_token,omitempty"`
 }
 
 func (msg *AddNewPlayersMessage) Add(playerName string, clientHost string, clientPort int, protocol string) *AddNewPlayersMessage {
@@ -65,6 +74,11 @@ const (
 	EventTypeServedCards         EventType = "served_cards"
 	EventTypeChosenPlayer        EventType = "chosen_player"
 	EventTypePlayerDecisionsSync EventType = "player_decisions_sync"
+	EventTypeServerShuttingDown  EventType = "server_shutting_down"
+	EventTypeRematch             EventType = "rematch"
+	EventTypeHouseRulesChanged   EventType = "house_rules_changed"
+	EventTypeHeartbeat           EventType = "heartbeat"
+	EventTypeResponse            EventType = "response"
 )
 
 type ServerEventMessage struct {
@@ -104,6 +118,16 @@ func ParseServerEventMessage(b []byte) (ServerEvent, error) {
 		return DecodeEvent[ChosenPlayerEvent](b)
 	case EventTypePlayerDecisionsSync:
 		return DecodeEvent[PlayerDecisionsSyncEvent](b)
+	case EventTypeServerShuttingDown:
+		return DecodeEvent[ServerShuttingDownEvent](b)
+	case EventTypeRematch:
+		return DecodeEvent[RematchEvent](b)
+	case EventTypeHouseRulesChanged:
+		return DecodeEvent[HouseRulesChangedEvent](b)
+	case EventTypeHeartbeat:
+		return DecodeEvent[HeartbeatEvent](b)
+	case EventTypeResponse:
+		return DecodeEvent[ResponseEvent](b)
 	}
 	return nil, fmt.Errorf("unknown event, could not parse type: %v", onlyType.Type)
 }
@@ -115,27 +139,109 @@ type PlayerJoinedEvent struct {
 }
 
 type ExistingPlayersListEvent struct {
-	PlayerNames []string `json:"player_names"`
+	PlayerNames []string         `json:"player_names"`
+	HouseRules  uknow.HouseRules `json:"house_rules"` // Rules of the game being joined, they can still change until set_ready
+
+	// Token to present when reconnecting as the player. Empty for spectators.
+	SessionToken string `json:"session_token,omitempty"`
 }
 
 type ServedCardsEvent struct {
 	Table uknow.Table `json:"table"`
+
+	// Set when the admin deals new hands to the players in the middle of a
+	// game, replacing the table that was served before.
+	Redeal bool `json:"redeal"`
 }
 
+// Sent at the start of each turn. Also carries what can be played on the turn,
+// so that a client knows it without a full table sync.
 type ChosenPlayerEvent struct {
-	PlayerName           string `json:"player_name"`
-	DecisionEventCounter int    `json:"decision_event_counter"`
+	PlayerName           string       `json:"player_name"`
+	DecisionEventCounter int          `json:"decision_event_counter"`
+	DiscardTop           uknow.Card   `json:"discard_top"`
+	RequiredColor        uknow.Color  `json:"required_color"`
+	RequiredNumber       uknow.Number `json:"required_number"`
+}
+
+func NewChosenPlayerEvent(table *uknow.Table, decisionEventCounter int) ChosenPlayerEvent {
+	discardTop, _ := table.DiscardedPile.Top()
+	return ChosenPlayerEvent{
+		PlayerName:           table.PlayerOfNextTurn,
+		DecisionEventCounter: decisionEventCounter,
+		DiscardTop:           discardTop,
+		RequiredColor:        table.RequiredColorOfCurrentTurn,
+		RequiredNumber:       table.RequiredNumberOfCurrentTurn,
+	}
+}
+
+// Reports whether the table has the same discard top and required color and
+// number as the event.
+func (e *ChosenPlayerEvent) MatchesTable(table *uknow.Table) bool {
+	discardTop, _ := table.DiscardedPile.Top()
+	return discardTop.IsEqual(e.DiscardTop) && table.RequiredColorOfCurrentTurn == e.RequiredColor && table.RequiredNumberOfCurrentTurn == e.RequiredNumber
+}
+
+func (e *ChosenPlayerEvent) TurnContextString() string {
+	return fmt.Sprintf("top of pile: %s, play %s or %s", e.DiscardTop.String(), e.RequiredColor.String(), e.RequiredNumber.String())
 }
 
 type PlayerDecisionsSyncEvent struct {
 	PlayerDecisionsRequest
 }
 
+// Last event sent by the admin on each SSE stream before it shuts down.
+type ServerShuttingDownEvent struct{}
+
+// Sent after a game has a winner and the admin starts a new game with the same
+// players. Table contains the freshly served cards.
+type RematchEvent struct {
+	Table            uknow.Table    `json:"table"`
+	WinCountOfPlayer map[string]int `json:"win_count_of_player"`
+}
+
+// Sent to every player when the admin changes the house rules before the game
+// starts.
+type HouseRulesChangedEvent struct {
+	HouseRules uknow.HouseRules `json:"house_rules"`
+}
+
+// Sent periodically on every SSE stream so that clients can tell a quiet game
+// from a broken link to the admin.
+type HeartbeatEvent struct{}
+
+// Request of a client to the admin over a WebSocket connection, which the
+// admin serves as if it was made over HTTP to the endpoint. Answered with a
+// ResponseEvent of the same ID, except a request to join as a player, whose
+// events stream over the connection from then on.
+type WebSocketRequest struct {
+	ID     int    `json:"id"`
+	Method string `json:"method"`
+
+	// Path of the endpoint relative to the admin, with the query if any, e.g.
+	// "player_decisions" or "game_state?player=alice".
+	Endpoint string `json:"endpoint"`
+	Body     []byte `json:"body,omitempty"`
+}
+
+// Response of the admin to a WebSocketRequest. Only sent over WebSocket
+// connections.
+type ResponseEvent struct {
+	ID         int    `json:"id"`
+	StatusCode int    `json:"status_code"`
+	Body       []byte `json:"body,omitempty"`
+}
+
 func (PlayerJoinedEvent) EventType() EventType        { return EventTypePlayerJoined }
 func (ExistingPlayersListEvent) EventType() EventType { return EventTypeExistingPlayersList }
 func (ServedCardsEvent) EventType() EventType         { return EventTypeServedCards }
 func (ChosenPlayerEvent) EventType() EventType        { return EventTypeChosenPlayer }
 func (PlayerDecisionsSyncEvent) EventType() EventType { return EventTypePlayerDecisionsSync }
+func (ServerShuttingDownEvent) EventType() EventType  { return EventTypeServerShuttingDown }
+func (RematchEvent) EventType() EventType             { return EventTypeRematch }
+func (HouseRulesChangedEvent) EventType() EventType   { return EventTypeHouseRulesChanged }
+func (HeartbeatEvent) EventType() EventType           { return EventTypeHeartbeat }
+func (ResponseEvent) EventType() EventType            { return EventTypeResponse }
 
 // Wraps a sequence of player decisions from a single player in an event.
 type PlayerDecisionsRequest struct {
@@ -148,6 +254,88 @@ func (*PlayerDecisionsRequest) RestPath() string {
 	return "player_decisions"
 }
 
+// Response to GET /counts. Meant to be a cheap way to check a client's view of
+// the table is not stale without sending the full table.
+type TableCountsMessage struct {
+	DrawDeck    int            `json:"draw_deck"`
+	DiscardPile int            `json:"discard_pile"`
+	HandCounts  map[string]int `json:"hand_counts"`
+}
+
+func (*TableCountsMessage) RestPath() string {
+	return "counts"
+}
+
+// Response to GET /turn_context. What can be played on the current turn as
+// seen by the admin, cheaper to fetch than the whole table.
+type TurnContextMessage struct {
+	RequiredColor  uknow.Color  `json:"required_color"`
+	RequiredNumber uknow.Number `json:"required_number"`
+	DiscardTop     uknow.Card   `json:"discard_top"`
+	CurrentPlayer  string       `json:"current_player"`
+	Direction      int          `json:"direction"`
+}
+
+func (*TurnContextMessage) RestPath() string {
+	return "turn_context"
+}
+
+// Top card of the admin's draw deck. The draw deck sent to players is hidden,
+// so the player of the turn fetches the card right before drawing it.
+type DrawDeckTopMessage struct {
+	Card uknow.Card `json:"card"`
+}
+
+func (*DrawDeckTopMessage) RestPath() string {
+	return "draw_deck_top"
+}
+
+// Snapshot of the admin's table. A client fetches it to replace its local copy
+// of the table when it fails to apply synced decisions.
+type GameStateMessage struct {
+	Table uknow.Table `json:"table"`
+}
+
+func (*GameStateMessage) RestPath() string {
+	return "game_state"
+}
+
+// Response to GET /seed_commitment. The commitment is published once the deck
+// is shuffled and the seed is revealed once the game has a winner, so that
+// players can check the deal with uknow.VerifySeedCommitment.
+type SeedCommitmentMessage struct {
+	Commitment string `json:"commitment"`
+	Revealed   bool   `json:"revealed"`
+	Seed       int64  `json:"seed,omitempty"`
+}
+
+func (*SeedCommitmentMessage) RestPath() string {
+	return "seed_commitment"
+}
+
+// Request and response of POST /rules, response of GET /rules.
+type HouseRulesMessage struct {
+	HouseRules uknow.HouseRules `json:"house_rules"`
+}
+
+func (*HouseRulesMessage) RestPath() string {
+	return "rules"
+}
+
+// First error in the payload of a 409 response to a PlayerDecisionsRequest
+// whose DecisionEventCounter belongs to a turn that is already over.
+const StaleTurnError = "stale turn"
+
+// First error in the payload of a 409 response to a PlayerDecisionsRequest
+// that reached the admin while it was not waiting for decisions, e.g. while
+// the previous decisions were being synced.
+const NotWaitingForDecisionsError = "admin not waiting for decisions"
+
+// First error in the payload of a 400 response to a PlayerDecisionsRequest
+// from a player other than the one of the turn that is not a jump-in. The
+// message of uknow.ErrNotYourTurn.
+const NotYourTurnError = "not your turn"
+
 // TODO: Don't really need this. Simple error codes and/or error messages should
 // be fine.
 type UnwrappedErrorPayload struct {
@@ -168,6 +356,11 @@ func (payload *UnwrappedErrorPayload) Add(err error) {
 	}
 }
 
+// Reports whether the payload's first error is the given one.
+func (payload *UnwrappedErrorPayload) FirstErrorIs(errorString string) bool {
+	return len(payload.Errors) != 0 && strings.HasPrefix(payload.Errors[0], errorString)
+}
+
 func WriteErrorPayload(w io.Writer, err error) {
 	UnwrappedErrorPayload := UnwrappedErrorPayload{}
 	UnwrappedErrorPayload.Add(err)
@@ -191,7 +384,11 @@ func DecryptAndDecodeJSON(structPointer interface{}, input io.Reader, aesCipher
 		return json.NewDecoder(input).Decode(structPointer)
 	}
 
-	return aesCipher.MustDecryptJSON(input).Decode(structPointer)
+	decoder, err := aesCipher.DecryptJSON(input)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt message: %w", err)
+	}
+	return decoder.Decode(structPointer)
 }
 
 // Encode given struct as JSON and encrypt if given aesCipher is non-nil
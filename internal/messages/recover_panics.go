@@ -0,0 +1,42 @@
+package messages
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gorilla/mux"
+)
+
+// First error in the payload of a 500 response to a request whose handler
+// panicked.
+const HandlerPanickedError = "internal error while handling request"
+
+// Middleware that recovers from a panic in a handler of the router, so that a
+// single malformed request doesn't take down a game in progress. Logs the
+// panic along with the request, and responds with InternalServerError and an
+// error payload.
+func RecoverPanics(logger *log.Logger) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				// Panicking with ErrAbortHandler is how a handler aborts
+				// its response on purpose.
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				logger.Printf("PANIC while handling %s %s from %s: %v\n%s", r.Method, r.URL.Path, r.RemoteAddr, recovered, debug.Stack())
+				w.WriteHeader(http.StatusInternalServerError)
+				WriteErrorPayload(w, errors.New(HandlerPanickedError))
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -2,11 +2,14 @@ package utils
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 )
@@ -31,12 +34,18 @@ func (t *HostPortProtocol) SetHostPort(ip string, port int) {
 	t.Port = port
 }
 
-// Ignore the Protocol, return the http address. If port is 0, doesn't prepend it.
+// Returns the https address if the Protocol is "https", otherwise the http
+// address. If port is 0, doesn't prepend it.
 func (t *HostPortProtocol) HTTPAddressString() string {
+	scheme := "http"
+	if t.Protocol == "https" {
+		scheme = "https"
+	}
+
 	if t.Port != 0 {
-		return fmt.Sprintf("http://%s:%d", t.IP, t.Port)
+		return fmt.Sprintf("%s://%s:%d", scheme, t.IP, t.Port)
 	} else {
-		return fmt.Sprintf("http://%s", t.IP)
+		return fmt.Sprintf("%s://%s", scheme, t.IP)
 	}
 }
 
@@ -83,7 +92,16 @@ func ResolveTCPAddress(addr string) (HostPortProtocol, error) {
 }
 
 func CreateHTTPClient(timeout time.Duration) *http.Client {
+	return CreateHTTPClientWithRootCAs(timeout, nil)
+}
+
+// Same as CreateHTTPClient, but https servers are verified against the given
+// roots instead of the system's, e.g. to trust a self-signed admin. Nil means
+// the system roots.
+func CreateHTTPClientWithRootCAs(timeout time.Duration, rootCAs *x509.CertPool) *http.Client {
 	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+
 		MaxIdleConns: 20,
 
 		// We rarely, if at all, make many parallel requests to any
@@ -101,6 +119,21 @@ func CreateHTTPClient(timeout time.Duration) *http.Client {
 	}
 }
 
+// Reads the PEM encoded certificates in the file into a pool, for
+// CreateHTTPClientWithRootCAs.
+func LoadCertPool(certFile string) (*x509.CertPool, error) {
+	pemBytes, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificates: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("no PEM encoded certificates in %s", certFile)
+	}
+	return pool, nil
+}
+
 type RequestSender struct {
 	Client     *http.Client
 	Method     string
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// A WebSocket connection carrying JSON messages both ways between the admin
+// and a client. Messages are sent as single text frames. Pings are answered
+// while reading.
+type WebSocketConn struct {
+	ws        *websocket.Conn
+	closeOnce sync.Once
+}
+
+var (
+	ErrBadWebSocketHandshake    = errors.New("bad websocket handshake")
+	ErrWebSocketMessageTooLarge = errors.New("websocket message too large")
+)
+
+// Served tables fit well within this.
+const maxWebSocketMessageSize = 16 << 20
+
+func newWebSocketConn(ws *websocket.Conn) *WebSocketConn {
+	ws.MaxPayloadBytes = maxWebSocketMessageSize
+	ws.PayloadType = websocket.TextFrame
+	return &WebSocketConn{ws: ws}
+}
+
+// Completes the handshake of a WebSocket request and serves its connection
+// with handle, closing the connection once handle returns. Responds with
+// BadRequest if the request is not a WebSocket handshake.
+func ServeWebSocket(w http.ResponseWriter, r *http.Request, handle func(conn *WebSocketConn)) {
+	server := websocket.Server{
+		// Clients are not browsers and send no meaningful origin.
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			// The server's read and write timeouts would end the
+			// connection, which lasts as long as the game.
+			ws.SetDeadline(time.Time{})
+			handle(newWebSocketConn(ws))
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// Opens a WebSocket connection to the http, https, ws or wss URL. The TLS
+// config is used for https and wss, nil means the default one.
+func DialWebSocket(ctx context.Context, rawURL string, tlsConfig *tls.Config) (*WebSocketConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	useTLS := false
+	origin := "http://" + u.Host
+	switch u.Scheme {
+	case "ws", "http":
+		u.Scheme = "ws"
+	case "wss", "https":
+		u.Scheme = "wss"
+		origin = "https://" + u.Host
+		useTLS = true
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrBadWebSocketHandshake, u.Scheme)
+	}
+
+	config, err := websocket.NewConfig(u.String(), origin)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadWebSocketHandshake, err)
+	}
+
+	hostPort := u.Host
+	if u.Port() == "" {
+		if useTLS {
+			hostPort = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			hostPort = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", hostPort)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if useTLS {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		} else {
+			tlsConfig = tlsConfig.Clone()
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = u.Hostname()
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	ws, err := websocket.NewClient(config, conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %v", ErrBadWebSocketHandshake, err)
+	}
+
+	conn.SetDeadline(time.Time{})
+	return newWebSocketConn(ws), nil
+}
+
+// Reads the next data message, answering pings on the way. Returns io.EOF
+// once the peer closes the connection.
+func (c *WebSocketConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	if err := websocket.Message.Receive(c.ws, &message); err != nil {
+		if errors.Is(err, websocket.ErrFrameTooLarge) {
+			return nil, ErrWebSocketMessageTooLarge
+		}
+		return nil, err
+	}
+	return message, nil
+}
+
+// Sends the message as a single text frame. Safe to call concurrently.
+func (c *WebSocketConn) WriteMessage(message []byte) error {
+	return websocket.Message.Send(c.ws, string(message))
+}
+
+// Sends a close frame and closes the connection. A blocked ReadMessage returns
+// with an error.
+func (c *WebSocketConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.ws.Close()
+	})
+	return err
+}
@@ -0,0 +1,88 @@
+package uknow
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// Size based rotation of a log file. The zero value disables rotation.
+type LogRotation struct {
+	// The log file is rotated before a write that would take it past MaxBytes.
+	MaxBytes int64
+
+	// Number of rotated files to keep, named <log file>.1 (newest) to
+	// <log file>.KeepFiles (oldest).
+	KeepFiles int
+}
+
+// Same as CreateFileLogger, but the log file is rotated as given by rotation.
+func CreateRotatingFileLogger(logDir, name string, rotation LogRotation) (*log.Logger, error) {
+	if rotation.MaxBytes <= 0 {
+		return CreateFileLogger(false, logDir, name)
+	}
+
+	fileName := uniqueLogFileName(logDir, name)
+
+	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open/create log file %s: %w", fileName, err)
+	}
+
+	w := &rotatingFile{
+		fileName: fileName,
+		rotation: rotation,
+		f:        f,
+	}
+	return log.New(w, name+"|", log.Ltime|log.Lshortfile), nil
+}
+
+type rotatingFile struct {
+	mu       sync.Mutex
+	fileName string
+	rotation LogRotation
+	f        *os.File
+	size     int64
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.rotation.MaxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Shifts <log file>.i to <log file>.i+1, dropping the oldest, and starts a new
+// log file.
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+
+	if r.rotation.KeepFiles > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", r.fileName, r.rotation.KeepFiles))
+		for i := r.rotation.KeepFiles - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.fileName, i), fmt.Sprintf("%s.%d", r.fileName, i+1))
+		}
+		if err := os.Rename(r.fileName, r.fileName+".1"); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(r.fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	r.f = f
+	r.size = 0
+	return nil
+}
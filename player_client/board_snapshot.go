@@ -0,0 +1,79 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Number of the latest event log lines written to a board snapshot.
+const snapshotEventLogLines = 20
+
+// **DOES NOT LOCK** uiActionMutex. Writes what the board widgets currently
+// show as plain text. The own hand is written even if it is hidden.
+func (clientUI *ClientUI) writeBoardSnapshotNoLock(w io.Writer) {
+	fmt.Fprintln(w, "== Hand ==")
+	for _, card := range clientUI.playerHand {
+		fmt.Fprintf(w, "%s ", card.SymbolString())
+	}
+	fmt.Fprintf(w, "\n(%d cards)\n\n", clientUI.playerHand.Len())
+
+	// Same cards as the discard pile cells, top of the pile first.
+	fmt.Fprintln(w, "== Discard pile ==")
+	shownCount := len(clientUI.discardPileCells)
+	if shownCount > clientUI.discardPile.Len() {
+		shownCount = clientUI.discardPile.Len()
+	}
+	for i := clientUI.discardPile.Len() - 1; i >= clientUI.discardPile.Len()-shownCount; i-- {
+		card := clientUI.discardPile[i]
+		fmt.Fprintf(w, "%s ", card.SymbolString())
+	}
+	fmt.Fprintf(w, "\n(%d cards)\n\n", clientUI.discardPile.Len())
+
+	fmt.Fprintln(w, "== Hand counts ==")
+	for i, playerName := range clientUI.handCountChart.Labels {
+		fmt.Fprintf(w, "%s: %d\n", playerName, int(clientUI.handCountChart.Data[i]))
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "== Required ==")
+	fmt.Fprintf(w, "color: %s\n", clientUI.requiredColor.String())
+	if topCard, err := clientUI.discardPile.Top(); err == nil {
+		fmt.Fprintf(w, "number: %s\n", topCard.Number.String())
+	} else {
+		fmt.Fprintln(w, "number: none")
+	}
+	fmt.Fprintf(w, "draw deck: %d cards\n\n", clientUI.drawDeckGauge.Percent)
+
+	fmt.Fprintln(w, "== Event log ==")
+	low := len(clientUI.eventLogLines) - snapshotEventLogLines
+	if low < 0 {
+		low = 0
+	}
+	for _, line := range clientUI.eventLogLines[low:] {
+		fmt.Fprintln(w, line.Text)
+	}
+}
+
+// Saves a board snapshot to fileName in the snapshot dir, returning the path
+// of the file.
+func (clientUI *ClientUI) saveBoardSnapshot(fileName string) (string, error) {
+	dir := clientUI.snapshotDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	snapshotPath := filepath.Join(dir, fileName)
+
+	f, err := os.Create(snapshotPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	clientUI.uiActionMutex.Lock()
+	clientUI.writeBoardSnapshotNoLock(f)
+	clientUI.uiActionMutex.Unlock()
+
+	return snapshotPath, nil
+}
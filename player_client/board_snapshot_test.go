@@ -0,0 +1,63 @@
+package client
+
+import (
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestBoardSnapshotHasAllSections(t *testing.T) {
+	clientUI := ClientUI{Logger: log.Default()}
+	clientUI.InitWidgetObjects(3)
+	clientUI.snapshotDir = t.TempDir()
+
+	redFive := uknow.Card{Number: 5, Color: uknow.ColorRed}
+	blueOne := uknow.Card{Number: 1, Color: uknow.ColorBlue}
+	clientUI.setHandsAfterExchange(uknow.Deck{redFive}, map[string]int{})
+	clientUI.discardPile = uknow.Deck{redFive, blueOne}
+	clientUI.requiredColor = uknow.ColorBlue
+	clientUI.handCountChart.Labels = []string{"alice", "bob"}
+	clientUI.handCountChart.Data = []float64{1, 7}
+	clientUI.appendEventLogNoLock(EventLogInfo, "bob played a card")
+
+	snapshotPath, err := clientUI.saveBoardSnapshot("board.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := string(contents)
+	blue := uknow.ColorBlue
+	for _, want := range []string{
+		"== Hand ==", redFive.SymbolString(),
+		"== Discard pile ==", blueOne.SymbolString(),
+		"== Hand counts ==", "bob: 7",
+		"== Required ==", "color: " + blue.String(),
+		"== Event log ==", "bob played a card",
+	} {
+		if !strings.Contains(snapshot, want) {
+			t.Logf("expected the snapshot to contain '%s', got:\n%s", want, snapshot)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseSnapshotCommandRejectsDirectories(t *testing.T) {
+	cmd, err := ParseCommandFromInput("snapshot board.txt", "alice")
+	if err != nil || cmd.Kind != CmdSnapshot || cmd.ExtraData.(string) != "board.txt" {
+		t.Fatalf("expected a snapshot command for board.txt, got %+v, %v", cmd, err)
+	}
+
+	for _, input := range []string{"snapshot", "snapshot ../board.txt", "snapshot /tmp/board.txt", "snapshot .."} {
+		if _, err := ParseCommandFromInput(input, "alice"); err == nil {
+			t.Logf("expected '%s' to be rejected", input)
+			t.Fail()
+		}
+	}
+}
@@ -1,5 +1,13 @@
 package client
 
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 type HistoryRing struct {
 	buffer []string
 	head   int
@@ -31,6 +39,52 @@ func (h *HistoryRing) Len() int {
 	return h.size
 }
 
+// Returns the items from oldest to newest.
+func (h *HistoryRing) Items() []string {
+	items := make([]string, 0, h.size)
+	for i := 0; i < h.size; i++ {
+		items = append(items, h.buffer[(h.head+i)%len(h.buffer)])
+	}
+	return items
+}
+
+// Pushes each line read from r, oldest first. Older lines are overwritten if
+// there are more lines than the ring's capacity.
+func (h *HistoryRing) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			h.Push(line)
+		}
+	}
+	return scanner.Err()
+}
+
+// Writes the items, one per line, oldest first. Since the ring is bounded, so
+// is the written size.
+func (h *HistoryRing) Save(w io.Writer) error {
+	for _, item := range h.Items() {
+		if _, err := io.WriteString(w, item+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const commandHistoryCapacity = 256
+
+// Returns the path of the file where command history of the given player is
+// persisted, under the user's config dir. Returns empty string if there is no
+// config dir.
+func DefaultCommandHistoryFile(playerName string) string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "uknow", playerName+"_command_history.txt")
+}
+
 // Represents the currently being shown index + 1.
 type HistoryPos int
 
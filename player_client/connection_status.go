@@ -0,0 +1,96 @@
+package client
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Health of the event stream from the admin, as told by how long ago the last
+// event arrived. The admin sends a heartbeat event every couple of seconds, so
+// a long gap means the link is broken even if the stream was not closed.
+type ConnectionStatus string
+
+const (
+	ConnectionOK           ConnectionStatus = "ok"
+	ConnectionLaggy        ConnectionStatus = "laggy"
+	ConnectionDisconnected ConnectionStatus = "disconnected"
+)
+
+const (
+	// Gaps between events after which the connection is shown as laggy and
+	// as disconnected. Heartbeats can be late while the admin pauses after
+	// serving the cards, so these are a few heartbeat intervals long.
+	connectionLaggyAfter        = 8 * time.Second
+	connectionDisconnectedAfter = 20 * time.Second
+
+	connectionCheckInterval = 1 * time.Second
+)
+
+// Tracks when the last event arrived from the admin.
+type connectionMonitor struct {
+	mu          sync.Mutex
+	lastEventAt time.Time
+	status      ConnectionStatus
+}
+
+// Notes that an event, heartbeat or otherwise, arrived at the given time.
+func (m *connectionMonitor) noteEvent(at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastEventAt = at
+}
+
+// Updates the status for the gap since the last event. Returns the new status
+// and whether it changed.
+func (m *connectionMonitor) check(now time.Time) (ConnectionStatus, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := ConnectionOK
+	if gap := now.Sub(m.lastEventAt); gap >= connectionDisconnectedAfter {
+		status = ConnectionDisconnected
+	} else if gap >= connectionLaggyAfter {
+		status = ConnectionLaggy
+	}
+
+	changed := m.status != status
+	m.status = status
+	return status, changed
+}
+
+// Checks the connection every connectionCheckInterval and shows changes of its
+// status. Once it's disconnected, closes the event stream so that sseController
+// returns, and closes stalled so that it asks for a reconnect. Runs until stop
+// is closed.
+func (c *PlayerClient) watchConnection(eventStream io.Closer, stop <-chan struct{}, stalled chan<- struct{}) {
+	ticker := time.NewTicker(connectionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			status, changed := c.connection.check(now)
+			if !changed {
+				continue
+			}
+			c.showConnectionStatus(status)
+
+			if status == ConnectionDisconnected {
+				c.logToWindow("no event from admin for %s, dropping the event stream", connectionDisconnectedAfter)
+				close(stalled)
+				eventStream.Close()
+				return
+			}
+		}
+	}
+}
+
+func (c *PlayerClient) showConnectionStatus(status ConnectionStatus) {
+	c.Logger.Printf("Connection to admin is %s", status)
+	if err := c.sendCommandToUI(&UICommandSetConnectionStatus{status: status}, 1*time.Second); err != nil {
+		c.Logger.Printf("%v: %T", err, &UICommandSetConnectionStatus{})
+	}
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectionStatusFollowsHeartbeatGaps(t *testing.T) {
+	var m connectionMonitor
+	start := time.Now()
+	m.noteEvent(start)
+
+	steps := []struct {
+		name        string
+		heartbeatAt time.Duration // Zero if no heartbeat arrives before the check
+		checkAt     time.Duration
+		wantStatus  ConnectionStatus
+		wantChanged bool
+	}{
+		{"first check after connecting", 0, time.Second, ConnectionOK, true},
+		{"heartbeat on time", 2 * time.Second, 3 * time.Second, ConnectionOK, false},
+		{"heartbeats stop", 0, 2*time.Second + connectionLaggyAfter, ConnectionLaggy, true},
+		{"still no heartbeat", 0, 3*time.Second + connectionLaggyAfter, ConnectionLaggy, false},
+		{"heartbeat arrives late", 4*time.Second + connectionLaggyAfter, 5*time.Second + connectionLaggyAfter, ConnectionOK, true},
+		{"heartbeats stop for good", 0, 4*time.Second + connectionLaggyAfter + connectionDisconnectedAfter, ConnectionDisconnected, true},
+	}
+
+	for _, step := range steps {
+		if step.heartbeatAt != 0 {
+			m.noteEvent(start.Add(step.heartbeatAt))
+		}
+		status, changed := m.check(start.Add(step.checkAt))
+		if status != step.wantStatus || changed != step.wantChanged {
+			t.Logf("%s: expected status %s (changed: %v), got %s (changed: %v)", step.name, step.wantStatus, step.wantChanged, status, changed)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Category of a line in the event log, used to filter what is shown.
+type EventLogCategory int
+
+const (
+	EventLogInfo         EventLogCategory = iota // Messages from the client and anything uncategorized
+	EventLogGame                                 // Actions, turns and the winner
+	EventLogCardTransfer                         // Card transfer animations
+	EventLogDebug                                // UI internals
+)
+
+type EventLogLine struct {
+	Category EventLogCategory
+	Text     string
+}
+
+// Set of categories that are hidden from the event log.
+type EventLogFilter map[EventLogCategory]bool
+
+func (f EventLogFilter) Shows(category EventLogCategory) bool {
+	return !f[category]
+}
+
+// Filters accepted by the log_filter command.
+var eventLogFilterOfName = map[string]EventLogFilter{
+	"all":         {},
+	"no_transfer": {EventLogCardTransfer: true},
+	"no_debug":    {EventLogDebug: true},
+	"game":        {EventLogCardTransfer: true, EventLogDebug: true},
+}
+
+func ParseEventLogFilter(name string) (EventLogFilter, error) {
+	filter, ok := eventLogFilterOfName[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown log filter '%s', expected one of all|no_transfer|no_debug|game", name)
+	}
+	return filter, nil
+}
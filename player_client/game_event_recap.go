@@ -0,0 +1,73 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+)
+
+const (
+	gameEventRecapCapacity = 64
+	defaultRecapCount      = 10
+)
+
+type recapEntry struct {
+	at    time.Time
+	event uknow.GameEvent
+}
+
+// Ring of the latest game events that have a StringMessage, shown again by the
+// recap command. Safe for concurrent use.
+type GameEventRecap struct {
+	mu      sync.Mutex
+	entries []recapEntry
+	head    int
+	size    int
+}
+
+func NewGameEventRecap(capacity int) *GameEventRecap {
+	return &GameEventRecap{
+		entries: make([]recapEntry, capacity),
+	}
+}
+
+// Keeps the event if it has a StringMessage, overwriting the oldest one if the
+// ring is full.
+func (r *GameEventRecap) Push(at time.Time, event uknow.GameEvent) {
+	if _, ok := uknow.GameEventMessage(event, ""); !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := recapEntry{at: at, event: event}
+	if r.size == len(r.entries) {
+		r.entries[r.head] = entry
+		r.head = (r.head + 1) % len(r.entries)
+	} else {
+		r.entries[(r.head+r.size)%len(r.entries)] = entry
+		r.size++
+	}
+}
+
+// Returns the messages of the latest count events, oldest first, each prefixed
+// with the time the event was processed.
+func (r *GameEventRecap) Lines(count int, localPlayerName string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if count > r.size {
+		count = r.size
+	}
+
+	lines := make([]string, 0, count)
+	for i := r.size - count; i < r.size; i++ {
+		entry := r.entries[(r.head+i)%len(r.entries)]
+		message, _ := uknow.GameEventMessage(entry.event, localPlayerName)
+		lines = append(lines, fmt.Sprintf("%s %s", entry.at.Format("15:04:05"), message))
+	}
+	return lines
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestHandCountChartScalesWithLargestHand(t *testing.T) {
+	clientUI := ClientUI{Logger: log.Default()}
+	clientUI.InitWidgetObjects(3)
+	clientUI.handCountChart.Labels = []string{"alice", "bob"}
+	clientUI.handCountChart.Data = []float64{7, 7}
+
+	clientUI.addToHandCountChart("bob", 4)
+	if maxVal := clientUI.handCountChart.MaxVal; maxVal != minHandCountChartMaxVal {
+		t.Fatalf("expected the minimum scale %d for small hands, got %v", minHandCountChartMaxVal, maxVal)
+	}
+
+	// Draw penalties inflate bob's hand past the minimum scale.
+	clientUI.addToHandCountChart("bob", 14)
+	if maxVal := clientUI.handCountChart.MaxVal; maxVal != 25+handCountChartMargin {
+		t.Fatalf("expected the chart to scale to bob's 25 cards, got MaxVal %v", maxVal)
+	}
+
+	clientUI.setHandsAfterExchange(uknow.Deck{}, map[string]int{"alice": 25, "bob": 7})
+	if maxVal := clientUI.handCountChart.MaxVal; maxVal != 25+handCountChartMargin {
+		t.Fatalf("expected the chart to scale to alice's 25 cards after the exchange, got MaxVal %v", maxVal)
+	}
+
+	clientUI.setHandsAfterExchange(uknow.Deck{}, map[string]int{"alice": 3, "bob": 7})
+	if maxVal := clientUI.handCountChart.MaxVal; maxVal != minHandCountChartMaxVal {
+		t.Fatalf("expected the chart to return to the minimum scale, got MaxVal %v", maxVal)
+	}
+
+	clientUI.addToHandCountChart("alice", -5)
+	if count := clientUI.handCountChart.Data[0]; count != 0 {
+		t.Fatalf("expected alice's hand count to be clamped at 0, got %v", count)
+	}
+}
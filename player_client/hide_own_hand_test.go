@@ -0,0 +1,44 @@
+package client
+
+import (
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestHiddenHandKeepsTrackingTransfers(t *testing.T) {
+	clientUI := ClientUI{Logger: log.Default()}
+	clientUI.InitWidgetObjects(0)
+
+	redFive := uknow.Card{Number: 5, Color: uknow.ColorRed}
+	blueOne := uknow.Card{Number: 1, Color: uknow.ColorBlue}
+	clientUI.setHandsAfterExchange(uknow.Deck{redFive}, map[string]int{})
+
+	clientUI.toggleOwnHandHidden()
+	if strings.Contains(clientUI.selfHandWidget.Text, redFive.SymbolString()) {
+		t.Fatalf("expected the hidden hand not to show its cards, got '%s'", clientUI.selfHandWidget.Text)
+	}
+
+	clientUI.addCardToTransferSink(uknow.CardTransferEvent{
+		Source:     uknow.CardTransferNodeDeck,
+		Sink:       uknow.CardTransferNodePlayerHand,
+		SinkPlayer: "alice",
+		Card:       blueOne,
+	}, "alice")
+
+	if !clientUI.playerHand.EqualAsMultiset(uknow.Deck{redFive, blueOne}) {
+		t.Fatalf("expected the hidden hand to still hold both cards, got %s", clientUI.playerHand)
+	}
+	if strings.Contains(clientUI.selfHandWidget.Text, blueOne.SymbolString()) || !strings.Contains(clientUI.selfHandWidget.Text, "2 cards") {
+		t.Logf("expected only the count of the hidden hand to be shown, got '%s'", clientUI.selfHandWidget.Text)
+		t.Fail()
+	}
+
+	clientUI.toggleOwnHandHidden()
+	if !strings.Contains(clientUI.selfHandWidget.Text, redFive.SymbolString()) || !strings.Contains(clientUI.selfHandWidget.Text, blueOne.SymbolString()) {
+		t.Logf("expected the revealed hand to show both cards, got '%s'", clientUI.selfHandWidget.Text)
+		t.Fail()
+	}
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestLegalPlayHintMatchesLegalPlays(t *testing.T) {
+	redFive := uknow.Card{Number: 5, Color: uknow.ColorRed}
+	blueFive := uknow.Card{Number: 5, Color: uknow.ColorBlue}
+	blueOne := uknow.Card{Number: 1, Color: uknow.ColorBlue}
+	greenTwo := uknow.Card{Number: 2, Color: uknow.ColorGreen}
+	wild := uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}
+
+	table := uknow.NewTable("alice", log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.HandOfPlayer["alice"] = uknow.Deck{greenTwo, blueFive, blueOne, wild}
+	table.DiscardedPile = uknow.Deck{redFive}
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+	table.TableState = uknow.StartOfTurn
+
+	clientUI := ClientUI{Logger: log.Default()}
+	clientUI.uiActionCond = sync.NewCond(&clientUI.uiActionMutex)
+	clientUI.InitWidgetObjects(0)
+	clientUI.setHandsAfterExchange(table.HandOfPlayer["alice"].Clone(), map[string]int{})
+
+	clientUI.showLegalPlayHint()
+	if lastLine := clientUI.eventLogLines[len(clientUI.eventLogLines)-1].Text; !strings.Contains(lastLine, "only shown on your turn") {
+		t.Fatalf("expected no hint outside of the local player's turn, got '%s'", lastLine)
+	}
+
+	clientUI.uiState = ClientUIAllowPlayerDecisionReplCommands
+	clientUI.legalPlays = table.LegalPlays("alice")
+	clientUI.showLegalPlayHint()
+
+	// blue 5 matches the number and the wild is always playable.
+	if legalPlays := clientUI.legalPlays; !legalPlays.EqualAsMultiset(uknow.Deck{blueFive, wild}) {
+		t.Fatalf("expected the legal plays to be the blue 5 and the wild, got %s", legalPlays)
+	}
+
+	// The hand is shown sorted, the hint uses the indices shown.
+	blueFiveIndex, _ := clientUI.playerHand.FindCard(blueFive)
+	wildIndex, _ := clientUI.playerHand.FindCard(wild)
+	wantHint := fmt.Sprintf("Hint: playable %d:%s %d:%s", blueFiveIndex, blueFive.SymbolString(), wildIndex, wild.SymbolString())
+	if wildIndex < blueFiveIndex {
+		wantHint = fmt.Sprintf("Hint: playable %d:%s %d:%s", wildIndex, wild.SymbolString(), blueFiveIndex, blueFive.SymbolString())
+	}
+	if hint := clientUI.eventLogLines[len(clientUI.eventLogLines)-1].Text; hint != wantHint {
+		t.Fatalf("expected hint '%s', got '%s'", wantHint, hint)
+	}
+}
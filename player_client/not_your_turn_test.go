@@ -0,0 +1,51 @@
+package client
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestDecisionOutOfTurnAsksToWait(t *testing.T) {
+	blueFive := uknow.Card{Number: 5, Color: uknow.ColorBlue}
+
+	table := uknow.NewTable("alice", log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.HandOfPlayer["alice"] = uknow.Deck{blueFive}
+	table.HandOfPlayer["bob"] = uknow.Deck{uknow.HiddenCard}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+	table.TableState = uknow.StartOfTurn
+	table.PlayerOfNextTurn = "bob"
+
+	c := &PlayerClient{table: table, Logger: log.Default()}
+	command := NewReplCommand(CmdDropCard, "alice")
+	command.Cards = append(command.Cards, blueFive)
+
+	_, err := c.evalReplCommandOnTable(command)
+	if !errors.Is(err, uknow.ErrNotYourTurn) {
+		t.Fatalf("expected %v, got %v", uknow.ErrNotYourTurn, err)
+	}
+	if reason := decisionRejectionReason(err, table.TableState); reason != waitForYourTurnMessage {
+		t.Errorf("expected the rejection reason '%s', got '%s'", waitForYourTurnMessage, reason)
+	}
+	if table.HandOfPlayer["alice"].Len() != 1 || table.DiscardedPile.Len() != 1 {
+		t.Errorf("expected the table to be unchanged, got alice's hand %s and pile %s", table.HandOfPlayer["alice"], table.DiscardedPile)
+	}
+
+	// The UI doesn't pass on decisions while the user is not being asked.
+	clientUI := ClientUI{Logger: log.Default()}
+	clientUI.uiActionCond = sync.NewCond(&clientUI.uiActionMutex)
+	clientUI.InitWidgetObjects(0)
+	clientUI.commandStringBeingTyped = "drop 5 blue"
+	clientUI.handleCommandInput("alice")
+
+	if lastLine := clientUI.eventLogLines[len(clientUI.eventLogLines)-1].Text; lastLine != waitForYourTurnMessage {
+		t.Errorf("expected the UI to ask to wait, got '%s'", lastLine)
+	}
+}
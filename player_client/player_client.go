@@ -3,11 +3,15 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"path"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -29,6 +33,7 @@ const (
 	WaitingForAdminToChoosePlayer PlayerClientState = "waiting_for_admin_to_choose_player"
 	AskingUserForDecision         PlayerClientState = "asking_user_for_decision"
 	WaitingForDecisionSync        PlayerClientState = "waiting_for_decision_sync"
+	Spectating                    PlayerClientState = "spectating"
 )
 
 var ErrorFailedToConnectToNewPlayer = errors.New("failed to connect to new player")
@@ -68,6 +73,46 @@ type PlayerClient struct {
 	adminAddr          utils.HostPortProtocol
 	advertiseIP        string
 
+	// Number of players known to be in the game, including the local player.
+	// Counted from the SSE handshake and the player joined events after it.
+	playersInGame       int
+	autoReady           bool
+	autoReadyMinPlayers int
+	autoReadyOnce       sync.Once
+
+	// Connects as a spectator instead of a player, see ConfigNewPlayerClient.Observe.
+	observe bool
+
+	// See ConfigNewPlayerClient.RoomCode.
+	roomCode string
+
+	// Issued by the admin in the SSE handshake, presented when reconnecting.
+	// Guarded by stateMutex.
+	sessionToken string
+
+	// Counter of the decision the admin is waiting on, from the last chosen
+	// player event. Used for jump-ins.
+	decisionEventCounterOfTurn int
+
+	// Guards askCancelChan, the cancel channel of the decision prompt shown
+	// to the user, if any. Not guarded by stateMutex since the prompt holds it.
+	askCancelMutex sync.Mutex
+	askCancelChan  chan struct{}
+
+	// Set when the local player's decisions could not be evaluated faithfully
+	// on the redacted local table. See Table.NeedsResyncAfter.
+	resyncBeforeNextTurn bool
+
+	// When the last event arrived from the admin, see ConnectionStatus.
+	connection connectionMonitor
+
+	// See ConfigNewPlayerClient.WebSocket. The connection is set while the
+	// event stream over it lasts.
+	webSocket           bool
+	adminRootCAs        *x509.CertPool
+	adminWebSocketMutex sync.Mutex
+	adminWebSocket      *adminWebSocket
+
 	// Exposes the player API to the game admin.
 	router *mux.Router
 
@@ -85,20 +130,67 @@ type ConfigNewPlayerClient struct {
 	DefaultAdminAddr utils.HostPortProtocol
 	AdvertiseIP      string
 	AESCipher        *uknow.AESCipher
+
+	// Directory to write the client's log file in. Defaults to os.TempDir().
+	LogDir string
+
+	// Declare ready once, with the local player as the shuffler, as soon as
+	// AutoReadyMinPlayers players have joined. Only the client meant to be the
+	// shuffler should set this.
+	AutoReady bool
+
+	// Defaults to DefaultAutoReadyMinPlayers if zero
+	AutoReadyMinPlayers int
+
+	// Watch the game without being dealt in. The client connects with POST
+	// /spectate, follows the public events and never sends decisions.
+	Observe bool
+
+	// Roots to verify an admin served over https against. Nil means the
+	// system roots.
+	AdminRootCAs *x509.CertPool
+
+	// Room of the game to join when the admin hosts several, see
+	// admin.Server. Empty for a single-game admin.
+	RoomCode string
+
+	// Join as a player over the admin's /ws endpoint instead of POST
+	// /player. The events and the client's requests to the admin, e.g. its
+	// decisions and acks, then go over the one WebSocket connection. The
+	// admin must serve it, see admin.AdminUserConfig.WebSocket.
+	WebSocket bool
 }
 
+const DefaultAutoReadyMinPlayers = 2
+
 func NewPlayerClient(config *ConfigNewPlayerClient) *PlayerClient {
 	c := &PlayerClient{
 		table:              config.Table,
 		clientState:        WaitingToConnectToAdmin,
-		httpClient:         utils.CreateHTTPClient(10 * time.Minute),
-		httpClientQuick:    utils.CreateHTTPClient(1 * time.Minute),
+		httpClient:         utils.CreateHTTPClientWithRootCAs(10*time.Minute, config.AdminRootCAs),
+		httpClientQuick:    utils.CreateHTTPClientWithRootCAs(1*time.Minute, config.AdminRootCAs),
 		neighborListenAddr: make(map[string]utils.HostPortProtocol),
 		ClientChannels:     config.ClientChannels,
-		Logger:             uknow.CreateFileLogger(false, config.Table.LocalPlayerName),
+		Logger:             uknow.CreateFileLoggerOrDefault(config.LogDir, config.Table.LocalPlayerName),
 		adminAddr:          config.DefaultAdminAddr,
 		aesCipher:          config.AESCipher,
 		advertiseIP:        config.AdvertiseIP,
+
+		autoReady:           config.AutoReady,
+		autoReadyMinPlayers: config.AutoReadyMinPlayers,
+		observe:             config.Observe,
+		roomCode:            config.RoomCode,
+		webSocket:           config.WebSocket,
+		adminRootCAs:        config.AdminRootCAs,
+	}
+
+	if c.webSocket {
+		c.httpClient.Transport = &adminWebSocketTransport{base: c.httpClient.Transport, client: c}
+		c.httpClientQuick.Transport = &adminWebSocketTransport{base: c.httpClientQuick.Transport, client: c}
+	}
+
+	if c.autoReadyMinPlayers == 0 {
+		c.autoReadyMinPlayers = DefaultAutoReadyMinPlayers
 	}
 
 	c.router = mux.NewRouter()
@@ -128,6 +220,34 @@ func NewPlayerClient(config *ConfigNewPlayerClient) *PlayerClient {
 // 	}
 // }
 
+func (c *PlayerClient) State() PlayerClientState {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	return c.clientState
+}
+
+// Number of cards in each player's hand as seen by the client.
+func (c *PlayerClient) HandCounts() map[string]int {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	handCounts := make(map[string]int, len(c.table.HandOfPlayer))
+	for playerName, hand := range c.table.HandOfPlayer {
+		handCounts[playerName] = hand.Len()
+	}
+	return handCounts
+}
+
+// House rules of the client's table, as sent by the admin.
+func (c *PlayerClient) HouseRules() uknow.HouseRules {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+	return c.table.HouseRules
+}
+
+// Number of cards in each line of the pile_history output.
+const pileHistoryCardsPerLine = 8
+
 // Meant to be running in its goroutine. Handles non-play or inspect related commands.
 func (c *PlayerClient) RunGeneralCommandHandler() {
 	c.Logger.Printf("%s - running default command handler", c.table.LocalPlayerName)
@@ -164,6 +284,7 @@ func (c *PlayerClient) RunGeneralCommandHandler() {
 			// }
 
 			msg.Add(c.table.LocalPlayerName, c.advertiseIP, 0, "http")
+			msg.RoomCode = c.roomCode
 
 			// Lock and check if we have the correct state. Connect to admin if yes.
 			c.stateMutex.Lock()
@@ -174,45 +295,39 @@ func (c *PlayerClient) RunGeneralCommandHandler() {
 			}
 			// c.Logger.Printf("Will be sending listenAddr %+v to admin", listenAddr)
 			// c.connectToAdmin(ctx, msg, adminAddr)
-			go c.connectToAdminAndStartSSEController(ctx, msg, adminAddr)
+			if c.observe {
+				go c.connectToAdminAsSpectator(ctx, msg, adminAddr)
+			} else {
+				go c.connectToAdminAndStartSSEController(ctx, msg, adminAddr)
+			}
 			c.stateMutex.Unlock()
 
 		case CmdDeclareReady:
 			c.Logger.Printf("Received a declare ready command from UI...")
 
-			url := fmt.Sprintf("%s/set_ready", c.adminAddr.HTTPAddressString())
-
-			setReadyMessage := messages.SetReadyMessage{
-				ShufflerName:          c.table.LocalPlayerName,
-				ShufflerIsFirstPlayer: false,
-			}
-
-			var b bytes.Buffer
-			messages.EncodeJSONAndEncrypt(&setReadyMessage, &b, c.aesCipher)
-
-			requestSender := utils.RequestSender{
-				Client:     c.httpClient,
-				Method:     "POST",
-				URL:        url,
-				BodyReader: &b,
+			if c.observe {
+				c.logToWindow("spectators cannot declare ready")
+				break
 			}
 
-			resp, err := requestSender.Send(context.TODO())
-
+			statusCode, err := c.sendSetReady(ctx)
 			if err != nil {
 				c.Logger.Print(err)
-				break
-			}
-
-			if resp.StatusCode != http.StatusOK {
-				c.LogWindowPushChan <- "Failed to send declare ready message"
-				c.Logger.Printf("Failed to send declare ready message, resp status code: %s", resp.Status)
+				if statusCode != 0 {
+					c.LogWindowPushChan <- "Failed to send declare ready message"
+					c.LogWindowPushChan <- err.Error()
+				}
 			}
 
 		case CmdShowHand:
 			c.Logger.Printf("Received showhand command from UI...")
 			// Just printing to event log window
-			c.logToWindow(c.table.HandOfPlayer[c.table.LocalPlayerName].String())
+			hand, err := c.table.Hand(c.table.LocalPlayerName)
+			if err != nil {
+				c.logToWindow("no hand to show: %v", err)
+				break
+			}
+			c.logToWindow(hand.String())
 
 		case CmdTableSummary:
 			c.logToWindow("--- table_info:")
@@ -226,6 +341,104 @@ func (c *PlayerClient) RunGeneralCommandHandler() {
 			c.logToWindow("--- Draw Deck:")
 			c.logToWindow(sb.String())
 
+		case CmdJumpIn:
+			c.jumpIn(ctx, cmd.Cards[0])
+
+		case CmdResync:
+			c.stateMutex.Lock()
+			if c.clientState == WaitingToConnectToAdmin {
+				c.logToWindow("resync: not connected to admin")
+				c.stateMutex.Unlock()
+				break
+			}
+
+			if err := c.resyncTableWithAdmin(ctx); err != nil {
+				c.logToWindow("resync: %v", err)
+			} else if c.clientState == WaitingForAdminToServeCards {
+				// The served cards event was missed, the snapshot takes its place.
+				c.GameEventPushChan <- c.table.OpeningCardEvent
+				c.clientState = WaitingForAdminToChoosePlayer
+			}
+			c.stateMutex.Unlock()
+
+		case CmdPileHistory:
+			var sb strings.Builder
+			c.stateMutex.Lock()
+			c.table.PrintDiscardPileHistory(&sb, pileHistoryCardsPerLine)
+			pileLen := c.table.DiscardedPile.Len()
+			c.stateMutex.Unlock()
+
+			c.logToWindow("--- Discard Pile (%d cards, top first):", pileLen)
+			for _, line := range strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n") {
+				c.logToWindow(line)
+			}
+			c.logToWindow("---")
+
+		case CmdTurnOrder:
+			c.stateMutex.Lock()
+			turnOrder := c.table.TurnOrderString()
+			direction := "↻ clockwise"
+			if c.table.Direction < 0 {
+				direction = "↺ counter-clockwise"
+			}
+			c.stateMutex.Unlock()
+
+			c.logToWindow("turn order (%s): %s", direction, turnOrder)
+
+		case CmdHouseRules:
+			c.stateMutex.Lock()
+			houseRules := c.table.HouseRules
+			c.stateMutex.Unlock()
+
+			c.logToWindow("--- house rules:")
+			for _, name := range uknow.HouseRuleNames {
+				enabled, _ := houseRules.Enabled(name)
+				onOrOff := "off"
+				if enabled {
+					onOrOff = "on"
+				}
+				c.logToWindow("%s: %s", name, onOrOff)
+			}
+			c.logToWindow("---")
+
+		case CmdShowCounts:
+			counts, err := c.fetchTableCounts(ctx)
+			if err != nil {
+				c.logToWindow("failed to fetch counts from admin: %v", err)
+				break
+			}
+
+			playerNames := make([]string, 0, len(counts.HandCounts))
+			for playerName := range counts.HandCounts {
+				playerNames = append(playerNames, playerName)
+			}
+			sort.Strings(playerNames)
+
+			c.logToWindow("--- counts (admin):")
+			c.logToWindow("draw_deck: %d, discard_pile: %d", counts.DrawDeck, counts.DiscardPile)
+			for _, playerName := range playerNames {
+				c.logToWindow("%s: %d", playerName, counts.HandCounts[playerName])
+			}
+			c.logToWindow("---")
+
+		case CmdTurnContext:
+			turnContext, err := c.fetchTurnContext(ctx)
+			if err != nil {
+				c.logToWindow("failed to fetch turn context from admin: %v", err)
+				break
+			}
+
+			direction := "↻ clockwise"
+			if turnContext.Direction < 0 {
+				direction = "↺ counter-clockwise"
+			}
+
+			c.logToWindow("--- turn (admin):")
+			c.logToWindow("player: %s, direction: %s", turnContext.CurrentPlayer, direction)
+			c.logToWindow("pile top: %s", turnContext.DiscardTop.String())
+			c.logToWindow("required: %s, %s", turnContext.RequiredColor.String(), turnContext.RequiredNumber.String())
+			c.logToWindow("---")
+
 		default:
 			c.Logger.Printf("RunDefaultCommandHandler: Unhandled command %s", cmd.Kind)
 		}
@@ -243,7 +456,7 @@ func (c *PlayerClient) connectToAdmin(ctx context.Context, msg messages.AddNewPl
 	}
 
 	// CONSIDER: use ctx along with httpClient.Do
-	url := fmt.Sprintf("%s/player", adminAddr.HTTPAddressString())
+	url := c.adminURL(adminAddr, "player")
 	resp, err := c.httpClient.Post(url, "application/json", &body)
 	if err != nil {
 		c.Logger.Printf("connectToAdmin error: %s", err.Error())
@@ -272,6 +485,180 @@ func (c *PlayerClient) connectToAdmin(ctx context.Context, msg messages.AddNewPl
 	}
 }
 
+// Fetches the draw deck, discard pile and hand counts as seen by the admin.
+func (c *PlayerClient) fetchTableCounts(ctx context.Context) (messages.TableCountsMessage, error) {
+	var counts messages.TableCountsMessage
+
+	requestSender := utils.RequestSender{
+		Client: c.httpClientQuick,
+		Method: "GET",
+		URL:    c.adminURL(c.adminAddr, counts.RestPath()),
+	}
+
+	resp, err := requestSender.Send(ctx)
+	if err != nil {
+		return counts, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return counts, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	err = messages.DecryptAndDecodeJSON(&counts, resp.Body, c.aesCipher)
+	return counts, err
+}
+
+// Fetches what can be played on the current turn as seen by the admin.
+func (c *PlayerClient) fetchTurnContext(ctx context.Context) (messages.TurnContextMessage, error) {
+	var turnContext messages.TurnContextMessage
+
+	requestSender := utils.RequestSender{
+		Client: c.httpClientQuick,
+		Method: "GET",
+		URL:    c.adminURL(c.adminAddr, turnContext.RestPath()),
+	}
+
+	resp, err := requestSender.Send(ctx)
+	if err != nil {
+		return turnContext, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return turnContext, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	err = messages.DecryptAndDecodeJSON(&turnContext, resp.Body, c.aesCipher)
+	return turnContext, err
+}
+
+func (c *PlayerClient) fetchGameState(ctx context.Context) (messages.GameStateMessage, error) {
+	var gameState messages.GameStateMessage
+
+	requestSender := utils.RequestSender{
+		Client: c.httpClientQuick,
+		Method: "GET",
+		URL:    c.adminURL(c.adminAddr, gameState.RestPath()+"?player="+url.QueryEscape(c.table.LocalPlayerName)),
+	}
+
+	resp, err := requestSender.Send(ctx)
+	if err != nil {
+		return gameState, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return gameState, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	err = messages.DecryptAndDecodeJSON(&gameState, resp.Body, c.aesCipher)
+	return gameState, err
+}
+
+// Evaluates the synced decisions of another player on the local table. If the
+// local table has diverged from the admin's, e.g. the played card is not in the
+// local copy of the player's hand, the local table is replaced with a snapshot
+// fetched from the admin. Caller must hold the stateMutex.
+func (c *PlayerClient) evalSyncedDecisions(decidingPlayer string, decisions []uknow.PlayerDecision) {
+	err := c.table.EvalPlayerDecisions(decidingPlayer, decisions, c.GameEventPushChan)
+	if err == nil {
+		// The admin has already evaluated the decisions, so its table has the
+		// outcome the redacted local table could not work out.
+		if c.table.NeedsResyncAfter(decisions) {
+			if err := c.resyncTableWithAdmin(context.Background()); err != nil {
+				c.Logger.Printf("Failed to resync table with admin: %v", err)
+				c.logToWindow("failed to resync table with admin: %v", err)
+			}
+		}
+		return
+	}
+
+	c.Logger.Printf("Failed to evaluate synced decisions of player %s, local table diverged from admin's: %v", decidingPlayer, err)
+	c.logToWindow("out of sync with admin (%v), fetching game state", err)
+
+	if err := c.resyncTableWithAdmin(context.Background()); err != nil {
+		c.Logger.Printf("Failed to resync table with admin: %v", err)
+		c.logToWindow("failed to resync table with admin: %v", err)
+	}
+}
+
+var errCardsNotServedYet = errors.New("admin has not served the cards yet")
+
+// The draw deck of the local table is hidden, so the card the local player is
+// about to draw is fetched from the admin and put on top of it. Caller must
+// hold the stateMutex.
+func (c *PlayerClient) revealDrawDeckTop(ctx context.Context) error {
+	drawDeckTop, err := c.table.DrawDeck.Top()
+	if err != nil || !drawDeckTop.IsHidden() {
+		// Nothing to draw, or the card was put back by an undo and is known.
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var drawDeckTopMessage messages.DrawDeckTopMessage
+
+	requestSender := utils.RequestSender{
+		Client: c.httpClientQuick,
+		Method: "GET",
+		URL:    c.adminURL(c.adminAddr, drawDeckTopMessage.RestPath()+"?player="+url.QueryEscape(c.table.LocalPlayerName)),
+	}
+
+	resp, err := requestSender.Send(ctx)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	if err := messages.DecryptAndDecodeJSON(&drawDeckTopMessage, resp.Body, c.aesCipher); err != nil {
+		return err
+	}
+
+	c.table.DrawDeck = c.table.DrawDeck.MustPop().Push(drawDeckTopMessage.Card)
+	return nil
+}
+
+// Replaces the local table with a snapshot fetched from the admin. Caller must
+// hold the stateMutex.
+func (c *PlayerClient) resyncTableWithAdmin(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	gameState, err := c.fetchGameState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch game state from admin: %w", err)
+	}
+
+	if !gameState.Table.TableState.IsKnown() {
+		return fmt.Errorf("fetched game state has unknown table state %q", gameState.Table.TableState)
+	}
+
+	if !gameState.Table.IsShuffled {
+		return errCardsNotServedYet
+	}
+
+	gameState.Table.LocalPlayerName = c.table.LocalPlayerName
+	c.table.Set(&gameState.Table)
+
+	c.sendTableCommandToUI(&UICommandSetServedCards{table: &gameState.Table}, 1*time.Second)
+	c.logToWindow("resynced table with admin")
+	return nil
+}
+
+// Returns the URL of an admin API path, under the client's room if it has one.
+func (c *PlayerClient) adminURL(adminAddr utils.HostPortProtocol, path string) string {
+	if c.roomCode == "" {
+		return fmt.Sprintf("%s/%s", adminAddr.HTTPAddressString(), path)
+	}
+	return fmt.Sprintf("%s/room/%s/%s", adminAddr.HTTPAddressString(), c.roomCode, path)
+}
+
 func (c *PlayerClient) logToWindow(format string, args ...interface{}) {
 	_, file, line, _ := runtime.Caller(1)
 	format = c.table.LocalPlayerName + ":" + path.Base(file) + ":" + strconv.FormatInt(int64(line), 10) + " " + format
@@ -300,6 +687,7 @@ func (c *PlayerClient) initRouterHandlers() {
 		w.Write([]byte(c.clientState))
 	})
 
+	c.router.Use(messages.RecoverPanics(c.Logger))
 	utils.RoutesSummary(c.router, c.Logger)
 }
 
@@ -368,6 +756,12 @@ func (c *PlayerClient) handleServedCardsEvent(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if !servedCardsEvent.Table.TableState.IsKnown() {
+		c.Logger.Printf("Served cards event has unknown table state %q", servedCardsEvent.Table.TableState)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	servedCardsEvent.Table.LocalPlayerName = c.table.LocalPlayerName
 	c.Logger.Printf("Client: served cards has handOfPlayer: %+v", servedCardsEvent.Table.HandOfPlayer)
 
@@ -377,10 +771,8 @@ func (c *PlayerClient) handleServedCardsEvent(w http.ResponseWriter, r *http.Req
 		table: &servedCardsEvent.Table,
 	}
 
-	err = c.sendCommandToUI(uiCommand, 2*time.Second)
-	if err != nil {
-		c.Logger.Print(err)
-	}
+	c.sendTableCommandToUI(uiCommand, 2*time.Second)
+	c.GameEventPushChan <- c.table.OpeningCardEvent
 
 	c.clientState = WaitingForAdminToChoosePlayer
 }
@@ -416,13 +808,32 @@ func (c *PlayerClient) handleChosenPlayerEvent(w http.ResponseWriter, r *http.Re
 	}
 
 	c.clientState = AskingUserForDecision
-	go c.askAndRunUserDecisions(chosenPlayerEvent.DecisionEventCounter)
+	go c.askAndRunUserDecisions(chosenPlayerEvent.DecisionEventCounter, c.newAskCancelChan())
+}
+
+// Cards the local player can play for the next decision, none if the next
+// decision is not about playing a card, e.g. a challenge.
+func (c *PlayerClient) legalPlaysOfLocalPlayer() uknow.Deck {
+	if c.table.TableState != uknow.StartOfTurn && c.table.TableState != uknow.AwaitingDropOrPass {
+		return uknow.NewEmptyDeck()
+	}
+	return c.table.LegalPlays(c.table.LocalPlayerName)
 }
 
-func (c *PlayerClient) askAndRunUserDecisions(decisionEventCounter int) {
+// Asks the user for the decisions of the local player's turn and sends them to
+// the admin, unless the turn is taken by a jump-in or a redeal, which closes
+// cancelAsk.
+func (c *PlayerClient) askAndRunUserDecisions(decisionEventCounter int, cancelAsk <-chan struct{}) {
 	c.stateMutex.Lock()
 	defer c.stateMutex.Unlock()
 
+	select {
+	case <-cancelAsk:
+		c.logToWindow("turn was taken by a jump-in or a redeal before it started")
+		return
+	default:
+	}
+
 	c.logToWindow("Asking for user decision")
 	// c.logToWindow(c.table.Summary())
 
@@ -432,14 +843,23 @@ func (c *PlayerClient) askAndRunUserDecisions(decisionEventCounter int) {
 	askCommand := &UICommandAskUserForDecision{
 		receive:            receiveReplCommandsChan,
 		decisionResultChan: askUserForDecisionResultChan,
-		timeout:            10 * time.Second, // TODO(@rk): Unused and arbitrary. Think later.
-		sender:             "PlayerClient",   // TODO(@rk): Unused and arbitrary. Just delete.
+		sender:             "PlayerClient", // TODO(@rk): Unused and arbitrary. Just delete.
+		cancel:             cancelAsk,
 	}
 
 	if c.table.TableState == uknow.AwaitingWildDraw4ChallengeDecision {
 		askCommand.SetChallengeablePlayer(c.table.PlayerOfLastTurn)
 	}
 
+	askCommand.legalPlays = c.legalPlaysOfLocalPlayer()
+	if c.table.TableState == uknow.StartOfTurn && askCommand.legalPlays.IsEmpty() {
+		askCommand.noLegalPlays = true
+	}
+
+	if c.table.TableState == uknow.AwaitingOpeningWildColorDecision {
+		c.logToWindow("choose the color of the opening wild card first: %s", uknow.EligibleCommandsAtState(c.table.TableState))
+	}
+
 	c.AskUserForDecisionPushChan <- askCommand
 
 	// Now consume the PlayerDecisionEvent(s) and send these to admin
@@ -447,36 +867,78 @@ func (c *PlayerClient) askAndRunUserDecisions(decisionEventCounter int) {
 	decisions := make([]uknow.PlayerDecision, 0, 4)
 
 	for replCommand := range receiveReplCommandsChan {
-		decision, err := c.evalReplCommandOnTable(replCommand)
+		var newDecisions []uknow.PlayerDecision
+		var err error
+
+		// A forced draw with a legal play in hand is rejected without drawing.
+		drawsNow := c.table.TableState == uknow.StartOfTurn && (replCommand.Kind != CmdForcedDraw || c.legalPlaysOfLocalPlayer().IsEmpty())
+		if replCommand.Kind.DrawsFromDeck() && drawsNow {
+			if err := c.revealDrawDeckTop(context.Background()); err != nil {
+				c.Logger.Printf("revealDrawDeckTop failed: %s", err.Error())
+
+				askUserForDecisionResultChan <- AskUserForDecisionResult{
+					Error:                 err,
+					RejectionReason:       fmt.Sprintf("could not draw: %v", err),
+					AskForOneMoreDecision: true,
+					LegalPlays:            c.legalPlaysOfLocalPlayer(),
+				}
+				continue
+			}
+		}
 
-		if err != nil {
-			var errEvalDecision *uknow.EvalDecisionError
-			if errors.As(err, &errEvalDecision) {
-				msg := fmt.Sprintf("invalid decision, eligible decisions are: %s", uknow.EligibleCommandsAtState(c.table.TableState))
-				c.Logger.Printf(msg)
-				c.logToWindow(msg)
-			} else {
-				c.Logger.Printf("evalReplCommandOnTable failed: %s", err.Error())
+		if replCommand.Kind == CmdDrawAndPlayCard {
+			newDecisions, err = c.table.EvalDrawAndPlayDecision(c.table.LocalPlayerName, c.GameEventPushChan)
+		} else if replCommand.Kind == CmdForcedDraw {
+			newDecisions, err = c.table.EvalForcedDrawDecision(c.table.LocalPlayerName, c.GameEventPushChan)
+		} else if replCommand.Kind == CmdDropCard && len(replCommand.Cards) > 1 {
+			newDecisions, err = c.table.EvalDropCardsDecision(c.table.LocalPlayerName, replCommand.Cards, c.GameEventPushChan)
+		} else {
+			var decision uknow.PlayerDecision
+			decision, err = c.evalReplCommandOnTable(replCommand)
+			if err == nil {
+				newDecisions = []uknow.PlayerDecision{decision}
 			}
+		}
+
+		// Even on error, the draw part of a draw_play or auto could have been
+		// evaluated, in which case it must be sent to admin too.
+		decisions = append(decisions, newDecisions...)
+
+		if err != nil {
+			c.Logger.Printf("evalReplCommandOnTable failed: %s", err.Error())
 
 			askUserForDecisionResultChan <- AskUserForDecisionResult{
 				Error:                 err,
+				RejectionReason:       decisionRejectionReason(err, c.table.TableState),
 				AskForOneMoreDecision: true, // CONSIDER(@rk): Perhaps we only allow a certain number of retries?
+				LegalPlays:            c.legalPlaysOfLocalPlayer(),
 			}
 			continue
 		}
 
-		c.Logger.Printf("Received replCommand: %s, decisionEvent: %s", replCommand.Kind.String(), &decision)
-
-		decisions = append(decisions, decision)
+		c.Logger.Printf("Received replCommand: %s, decisionEvents: %+v", replCommand.Kind.String(), newDecisions)
 
 		askUserForDecisionResultChan <- AskUserForDecisionResult{
 			AskForOneMoreDecision: c.table.NeedMoreUserDecisionToFinishTurn(),
+			LegalPlays:            c.legalPlaysOfLocalPlayer(),
 		}
 	}
 
 	c.Logger.Printf("Done receiving player decision events from ClientUI")
 
+	if c.askWasCancelled(cancelAsk) {
+		// The local table has the decisions applied, the jump-in sync event
+		// or the redealt cards replace it with the admin's.
+		c.logToWindow("turn was taken by a jump-in or a redeal, decisions not sent")
+		return
+	}
+
+	// The admin evaluates the decisions after responding, so resync once it
+	// chooses the player of the next turn.
+	if c.table.NeedsResyncAfter(decisions) {
+		c.resyncBeforeNextTurn = true
+	}
+
 	// Send decisions to admin
 	requestBody := messages.PlayerDecisionsRequest{
 		Decisions:            decisions,
@@ -488,7 +950,7 @@ func (c *PlayerClient) askAndRunUserDecisions(decisionEventCounter int) {
 	messages.EncodeJSONAndEncrypt(&requestBody, &b, c.aesCipher)
 
 	requester := utils.RequestSender{
-		URL:        fmt.Sprintf("%s/%s", c.adminAddr.HTTPAddressString(), requestBody.RestPath()),
+		URL:        c.adminURL(c.adminAddr, requestBody.RestPath()),
 		Method:     "POST",
 		Client:     c.httpClient,
 		BodyReader: &b,
@@ -503,8 +965,30 @@ func (c *PlayerClient) askAndRunUserDecisions(decisionEventCounter int) {
 		c.logToWindow(err.Error())
 		return
 	}
+	if resp.StatusCode == http.StatusConflict || resp.StatusCode == http.StatusBadRequest {
+		// The turn was over, or the admin was not waiting for decisions,
+		// before the decisions reached the admin, or the local table took
+		// the turn to be the local player's when the admin's doesn't. Either
+		// way the local table has the decisions applied but the admin's
+		// doesn't.
+		var errorPayload messages.UnwrappedErrorPayload
+		if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, c.aesCipher); err == nil && (errorPayload.FirstErrorIs(messages.StaleTurnError) || errorPayload.FirstErrorIs(messages.NotWaitingForDecisionsError) || errorPayload.FirstErrorIs(messages.NotYourTurnError)) {
+			if errorPayload.FirstErrorIs(messages.NotYourTurnError) {
+				c.logToWindow("decisions rejected: %s", waitForYourTurnMessage)
+			} else {
+				c.logToWindow("decisions rejected: %s", errorPayload.Errors[0])
+			}
+			c.resyncBeforeNextTurn = false
+			if err := c.resyncTableWithAdmin(context.Background()); err != nil {
+				c.Logger.Printf("Failed to resync table with admin: %v", err)
+				c.logToWindow("failed to resync table with admin: %v", err)
+			}
+			return
+		}
+	}
 	if resp.StatusCode != http.StatusOK {
 		c.Logger.Printf("askAndRunUserDecisions: Received status code %d from admin", resp.StatusCode)
+		c.logToWindow("decisions rejected by admin with status %d", resp.StatusCode)
 		return
 	}
 
@@ -542,13 +1026,49 @@ func (c *PlayerClient) handlePlayerDecisionsSyncEvent(w http.ResponseWriter, r *
 
 	// Eval decisions of other player
 	c.Logger.Printf("Evaluating player %s's %d decisions: %+v", decisionsEvent.DecidingPlayer, len(decisionsEvent.Decisions), decisionsEvent)
-	c.table.EvalPlayerDecisions(decisionsEvent.DecidingPlayer, decisionsEvent.Decisions, c.GameEventPushChan)
+	c.evalSyncedDecisions(decisionsEvent.DecidingPlayer, decisionsEvent.Decisions)
 
 	c.Logger.Printf("Done evaluating player %s's %d decisions", decisionsEvent.DecidingPlayer, len(decisionsEvent.Decisions))
 
 	c.clientState = WaitingForAdminToChoosePlayer
 }
 
+// Shown when the user decides out of turn.
+const waitForYourTurnMessage = "Wait for your turn"
+
+// Describes why the engine rejected a decision, in terms the user can act on.
+func decisionRejectionReason(err error, tableState uknow.TableState) string {
+	var errIllegalPlay *uknow.IllegalPlayError
+	var errEvalDecision *uknow.EvalDecisionError
+
+	switch {
+	case errors.Is(err, uknow.ErrNotYourTurn):
+		return waitForYourTurnMessage
+	case errors.Is(err, uknow.ErrCardNotInHand):
+		return "card not in hand"
+	case errors.As(err, &errIllegalPlay):
+		return fmt.Sprintf("cannot play %s, need color %s or number %s", errIllegalPlay.Card.String(), errIllegalPlay.ExpectedColor.String(), errIllegalPlay.ExpectedNumber.String())
+	case errors.Is(err, uknow.ErrDrawDeckIsEmpty):
+		return "draw deck is empty"
+	case errors.Is(err, uknow.ErrNothingToUndo):
+		return "nothing to undo, draw a card first"
+	case errors.Is(err, uknow.ErrNoWildColorToChoose):
+		return fmt.Sprintf("no wild card color to choose now, eligible decisions are: %s", uknow.EligibleCommandsAtState(tableState))
+	case errors.Is(err, uknow.ErrInvalidWildColor):
+		return "choose red, green, blue or yellow as the wild card color"
+	case errors.Is(err, uknow.ErrHasLegalPlay):
+		return "you have a playable card, play it or draw"
+	case errors.Is(err, uknow.ErrStackingNotAllowed):
+		return "only one card can be dropped, the stack_same_number rule is off"
+	case errors.Is(err, uknow.ErrIllegalStackedPlay):
+		return "cards dropped together must all have the same number, and not be wild cards"
+	case errors.As(err, &errEvalDecision):
+		return fmt.Sprintf("not allowed now, eligible decisions are: %s", uknow.EligibleCommandsAtState(tableState))
+	default:
+		return err.Error()
+	}
+}
+
 // Maps the repl command to a PlayerDecision and evaluates it on the table with
 // local player as the deciding player.
 func (c *PlayerClient) evalReplCommandOnTable(replCommand *ReplCommand) (uknow.PlayerDecision, error) {
@@ -575,18 +1095,31 @@ func (c *PlayerClient) evalReplCommandOnTable(replCommand *ReplCommand) (uknow.P
 		return c.table.EvalPlayerDecision(c.table.LocalPlayerName, decision, c.GameEventPushChan)
 
 	case CmdSetWildCardColor:
+		decision := uknow.PlayerDecision{
+			Kind: uknow.PlayerDecisionWildCardChooseColor,
+		}
+
+		// Checked here too so that a wild_color at the wrong time or with a
+		// bad color gets a clear rejection reason.
 		chosenColor, ok := replCommand.ExtraData.(uknow.Color)
 		if !ok {
-			return uknow.PlayerDecision{}, uknow.ErrShouldNotHappen
+			return decision, uknow.ErrInvalidWildColor
 		}
+		decision.WildCardChosenColor = chosenColor
 
-		decision := uknow.PlayerDecision{
-			Kind:                uknow.PlayerDecisionWildCardChooseColor,
-			WildCardChosenColor: chosenColor,
+		if err := uknow.ValidateWildColorChoice(c.table.TableState, chosenColor); err != nil {
+			return decision, err
 		}
 
 		return c.table.EvalPlayerDecision(c.table.LocalPlayerName, decision, c.GameEventPushChan)
 
+	case CmdChooseSwapTarget:
+		decision := uknow.PlayerDecision{
+			Kind:             uknow.PlayerDecisionChooseSwapTarget,
+			SwapTargetPlayer: replCommand.TargetPlayerName,
+		}
+		return c.table.EvalPlayerDecision(c.table.LocalPlayerName, decision, c.GameEventPushChan)
+
 	case CmdChallenge:
 		decision := uknow.PlayerDecision{
 			Kind:                uknow.PlayerDecisionDoChallenge,
@@ -608,18 +1141,43 @@ func (c *PlayerClient) evalReplCommandOnTable(replCommand *ReplCommand) (uknow.P
 
 		return c.table.EvalPlayerDecision(c.table.LocalPlayerName, decision, c.GameEventPushChan)
 
+	case CmdUndoDraw:
+		decision := uknow.PlayerDecision{
+			Kind: uknow.PlayerDecisionUndoDraw,
+		}
+
+		return c.table.EvalPlayerDecision(c.table.LocalPlayerName, decision, c.GameEventPushChan)
+
 	default:
 		c.Logger.Printf("Unknown repl command kind: %s", replCommand.Kind.String())
 		return uknow.PlayerDecision{}, ErrorUnimplementedReplCommand
 	}
 }
 
+// Number of times sendCommandToUI waits for the UI before giving up.
+const sendCommandToUIAttempts = 3
+
+// Sends the command to the UI, waiting up to timeout for each of a few
+// attempts in case the UI is briefly busy.
 func (c *PlayerClient) sendCommandToUI(uiCommand UICommand, timeout time.Duration) error {
-	select {
-	case c.GeneralUICommandPushChan <- uiCommand:
-		return nil
-	case <-time.After(timeout):
-		return ErrorUIFailedToConsumeCommand
+	for attempt := 1; attempt <= sendCommandToUIAttempts; attempt++ {
+		select {
+		case c.GeneralUICommandPushChan <- uiCommand:
+			return nil
+		case <-time.After(timeout):
+			c.Logger.Printf("UI did not take %T within %s, attempt %d of %d", uiCommand, timeout, attempt, sendCommandToUIAttempts)
+		}
+	}
+	return ErrorUIFailedToConsumeCommand
+}
+
+// Sends a command that replaces the table shown by the UI. If the UI doesn't
+// take it, the table is resynced before the next turn, which sends it to the
+// UI again. Caller must hold the stateMutex.
+func (c *PlayerClient) sendTableCommandToUI(uiCommand UICommand, timeout time.Duration) {
+	if err := c.sendCommandToUI(uiCommand, timeout); err != nil {
+		c.Logger.Printf("%v: %T, resyncing before the next turn", err, uiCommand)
+		c.resyncBeforeNextTurn = true
 	}
 }
 
@@ -627,9 +1185,10 @@ func (c *PlayerClient) sendCommandToUI(uiCommand UICommand, timeout time.Duratio
 func (c *PlayerClient) noteEachPlayer(ctx context.Context, playerNames []string, playerListenAddrs []utils.HostPortProtocol) {
 	c.logToWindow("noting each player and sending ack: %+v", playerNames)
 
-	adminURL := fmt.Sprintf("%s/ack_player_added", c.adminAddr.HTTPAddressString())
+	adminURL := c.adminURL(c.adminAddr, "ack_player_added")
 
-	ctxWithTimeout, _ := context.WithTimeout(ctx, 10*time.Second)
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
 	g, _ := errgroup.WithContext(ctxWithTimeout)
 
 	for _, playerName := range playerNames {
@@ -671,30 +1230,144 @@ func (c *PlayerClient) noteEachPlayer(ctx context.Context, playerNames []string,
 	}
 }
 
+var errSetReadyRejected = errors.New("admin rejected set_ready")
+
+// Posts a set_ready message with the local player as the shuffler. Returns the
+// response status code, which is zero if the request could not be sent, and an
+// error carrying the admin's reason if it did not accept the message.
+func (c *PlayerClient) sendSetReady(ctx context.Context) (int, error) {
+	url := c.adminURL(c.adminAddr, "set_ready")
+
+	setReadyMessage := messages.SetReadyMessage{
+		ShufflerName:          c.table.LocalPlayerName,
+		ShufflerIsFirstPlayer: false,
+	}
+
+	var b bytes.Buffer
+	messages.EncodeJSONAndEncrypt(&setReadyMessage, &b, c.aesCipher)
+
+	requestSender := utils.RequestSender{
+		Client:     c.httpClient,
+		Method:     "POST",
+		URL:        url,
+		BodyReader: &b,
+	}
+
+	resp, err := requestSender.Send(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorPayload messages.UnwrappedErrorPayload
+		if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, c.aesCipher); err == nil && len(errorPayload.Errors) != 0 {
+			return resp.StatusCode, fmt.Errorf("%w: %s", errSetReadyRejected, errorPayload.Errors[0])
+		}
+		return resp.StatusCode, fmt.Errorf("%w: %s", errSetReadyRejected, resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+const (
+	autoReadyAttempts      = 5
+	autoReadyRetryInterval = 500 * time.Millisecond
+)
+
+// Declares ready on behalf of the user if auto-ready is enabled and enough
+// players have joined. Fires at most once per client.
+func (c *PlayerClient) maybeAutoReady() {
+	c.stateMutex.Lock()
+	ready := c.autoReady && c.clientState == WaitingForAdminToServeCards && c.playersInGame >= c.autoReadyMinPlayers
+	c.stateMutex.Unlock()
+
+	if !ready {
+		return
+	}
+
+	c.autoReadyOnce.Do(func() {
+		go c.autoDeclareReady()
+	})
+}
+
+func (c *PlayerClient) autoDeclareReady() {
+	// The admin refuses set_ready while acks for a newly joined player are
+	// pending, so retry a few times before giving up.
+	for attempt := 1; ; attempt++ {
+		statusCode, err := c.sendSetReady(context.Background())
+		if err == nil {
+			c.logToWindow("declared ready automatically")
+			return
+		}
+
+		if statusCode != http.StatusSeeOther || attempt == autoReadyAttempts {
+			c.logToWindow("failed to declare ready automatically, use the ready command: %v", err)
+			return
+		}
+		time.Sleep(autoReadyRetryInterval)
+	}
+}
+
+const (
+	sseReconnectAttempts = 3
+	sseReconnectInterval = 1 * time.Second
+)
+
+// Connects to admin and runs the SSE controller. Reconnects a bounded number of
+// times if the event stream turns out to be corrupt or stalls.
 func (c *PlayerClient) connectToAdminAndStartSSEController(ctx context.Context, msg messages.AddNewPlayersMessage, adminAddr utils.HostPortProtocol) {
+	for attempt := 0; attempt <= sseReconnectAttempts; attempt++ {
+		if attempt > 0 {
+			c.logToWindow("reconnecting to admin, attempt %d of %d", attempt, sseReconnectAttempts)
+			time.Sleep(sseReconnectInterval)
+		}
+
+		err := c.connectAndRunSSEController(ctx, msg, adminAddr)
+		if !errors.Is(err, errSSEStreamCorrupt) && !errors.Is(err, errSSEStreamStalled) {
+			return
+		}
+	}
+	c.logToWindow("giving up on the event stream from admin after %d reconnects", sseReconnectAttempts)
+}
+
+func (c *PlayerClient) connectAndRunSSEController(ctx context.Context, msg messages.AddNewPlayersMessage, adminAddr utils.HostPortProtocol) error {
+	c.stateMutex.Lock()
+	msg.SessionToken = c.sessionToken
+	c.stateMutex.Unlock()
+
 	var requestBody bytes.Buffer
 	if err := messages.EncodeJSONAndEncrypt(&msg, &requestBody, c.aesCipher); err != nil {
 		c.Logger.Fatal(err)
 	}
 
-	url := fmt.Sprintf("%s/player", adminAddr.HTTPAddressString())
+	url := c.adminURL(adminAddr, "player")
 
-	c.logToWindow("Calling %s", url)
+	var resp *http.Response
+	if c.webSocket {
+		var err error
+		resp, err = c.joinOverWebSocket(ctx, adminAddr, requestBody.Bytes())
+		if err != nil {
+			c.logToWindow("failed to connect to admin over websocket: %v", err)
+			return err
+		}
+	} else {
+		c.logToWindow("Calling %s", url)
 
-	req, err := http.NewRequest("POST", url, &requestBody)
-	if err != nil {
-		c.logToWindow("failed to create request toi %s: %v", url, err)
-		return
-	}
+		req, err := http.NewRequest("POST", url, &requestBody)
+		if err != nil {
+			c.logToWindow("failed to create request toi %s: %v", url, err)
+			return err
+		}
 
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Connection", "keep-alive")
+		req.Header.Set("Cache-Control", "no-cache")
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("Connection", "keep-alive")
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		c.logToWindow("failed to connect to admin: %v", err)
-		return
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			c.logToWindow("failed to connect to admin: %v", err)
+			return err
+		}
 	}
 
 	c.logToWindow("POST %s %+v response code: %s", url, msg, resp.Status)
@@ -703,12 +1376,17 @@ func (c *PlayerClient) connectToAdminAndStartSSEController(ctx context.Context,
 	case http.StatusSeeOther:
 		c.logToWindow("connectToAdmin: Local player is already present in admin's table")
 	case http.StatusOK:
-		c.sseController(resp)
+		return c.sseController(resp)
+	case http.StatusForbidden:
+		reason, _ := io.ReadAll(resp.Body)
+		c.logToWindow("admin refused the connection: %s", strings.TrimSpace(string(reason)))
 	}
+	resp.Body.Close()
+	return nil
 }
 
 func (client *PlayerClient) ackPlayerSyncToAdmin(ctx context.Context, decisionCounter int) error {
-	url := fmt.Sprintf("%s/ack-decision-sync", client.adminAddr.HTTPAddressString())
+	url := client.adminURL(client.adminAddr, "ack-decision-sync")
 
 	ackMessage := messages.AckSyncedPlayerDecisionsMesasge{
 		AckerPlayer:     client.table.LocalPlayerName,
@@ -18,6 +18,48 @@ type ClientUserConfig struct {
 	PlayerName      string `json:"player_name"`
 	AESKeyString    string `json:"aes_key"`
 	EncryptMessages bool   `json:"encrypt_messages"`
+
+	// Derive the key from a passphrase and salt instead of aes_key, the same
+	// as the admin's.
+	AESPassphrase string `json:"aes_passphrase"`
+	AESSalt       string `json:"aes_salt"`
+
+	// File to persist the command history in. Defaults to a file under the
+	// user's config dir.
+	CommandHistoryFile string `json:"command_history_file"`
+
+	// Directory to write the log files in. Defaults to os.TempDir().
+	LogDir string `json:"log_dir"`
+
+	// Milliseconds between a card leaving its source and arriving at its sink
+	// in the UI. Zero disables the animation, unset means use the default.
+	CardTransferDelayMsecs *int `json:"card_transfer_delay_msecs"`
+
+	// Number of top discarded cards shown in the UI, clamped to [4, 32].
+	// Zero means the default of 12.
+	DiscardPileCells int `json:"discard_pile_cells"`
+
+	// Declare ready automatically once auto_ready_min_players have joined.
+	// Only the player meant to be the shuffler should enable it.
+	AutoReady           bool `json:"auto_ready"`
+	AutoReadyMinPlayers int  `json:"auto_ready_min_players"`
+
+	// Watch the game as a spectator without being dealt in. Same as passing
+	// --observe.
+	Observe bool `json:"observe"`
+
+	// Connect to the default admin address with https. The admin's
+	// certificate is verified against admin_ca_cert_file, a PEM file, or
+	// against the system roots if it is empty.
+	AdminTLS        bool   `json:"admin_tls"`
+	AdminCACertFile string `json:"admin_ca_cert_file"`
+
+	// Room of the game to join when the admin hosts several games.
+	RoomCode string `json:"room_code"`
+
+	// Join over the admin's WebSocket endpoint instead of SSE, which the
+	// admin must serve with its "websocket" option.
+	WebSocket bool `json:"websocket"`
 }
 
 // Channels used for communication between the client components - PlayerClientUI and PlayerClient
@@ -34,12 +76,13 @@ type CommChannels struct {
 	LogWindowChan chan string
 
 	// The PlayerClient sends card transfer events on this channel after executing game logic. The PlayerClientUI receives these and updates the UI.
+	// The PlayerClient should send via a uknow.GameEventBuffer so that a busy UI doesn't block table evaluation.
 	GameEventChan chan uknow.GameEvent
 }
 
 func MakeCommChannels() CommChannels {
 	var chans CommChannels
-	chans.GeneralUICommandChan = make(chan UICommand)
+	chans.GeneralUICommandChan = make(chan UICommand, 8) // Lets the client move on while the UI is briefly busy
 	chans.AskUIForUserTurnChan = make(chan *UICommandAskUserForDecision)
 	chans.NonDecisionReplCommandsChan = make(chan *ReplCommand)
 	chans.LogWindowChan = make(chan string, 64) // Logging to ui window doesn't have to be synchronous
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	messages "github.com/nrawrx3/uknow/internal/messages"
+	utils "github.com/nrawrx3/uknow/internal/utils"
+)
+
+// Creates the cancel channel for a new decision prompt. Caller must hold the
+// stateMutex.
+func (c *PlayerClient) newAskCancelChan() <-chan struct{} {
+	c.askCancelMutex.Lock()
+	defer c.askCancelMutex.Unlock()
+
+	c.askCancelChan = make(chan struct{})
+	return c.askCancelChan
+}
+
+// Stops asking the user for decisions, if they are being asked.
+func (c *PlayerClient) cancelAskingUser() {
+	c.askCancelMutex.Lock()
+	defer c.askCancelMutex.Unlock()
+
+	if c.askCancelChan != nil {
+		close(c.askCancelChan)
+		c.askCancelChan = nil
+	}
+}
+
+// Called once the user is done deciding. Reports whether the prompt was
+// cancelled by a jump-in or a redeal.
+func (c *PlayerClient) askWasCancelled(cancelAsk <-chan struct{}) bool {
+	c.askCancelMutex.Lock()
+	defer c.askCancelMutex.Unlock()
+
+	select {
+	case <-cancelAsk:
+		return true
+	default:
+		c.askCancelChan = nil
+		return false
+	}
+}
+
+// Another player jumped in during the local player's turn. Whatever the local
+// player decided so far was never accepted by the admin, so the local table is
+// replaced with the admin's, which has the jump-in applied. Caller must hold
+// the stateMutex.
+func (c *PlayerClient) handleJumpInDuringLocalTurn(ev messages.PlayerDecisionsSyncEvent) {
+	card, _ := uknow.JumpInCard(ev.Decisions)
+
+	c.GameEventPushChan <- uknow.JumpInEvent{
+		Player:            ev.DecidingPlayer,
+		InterruptedPlayer: c.table.LocalPlayerName,
+		Card:              card,
+	}
+
+	c.resyncBeforeNextTurn = false
+	if err := c.resyncTableWithAdmin(context.Background()); err != nil {
+		c.Logger.Printf("Failed to resync table with admin: %v", err)
+		c.logToWindow("failed to resync table with admin: %v", err)
+	}
+
+	c.ackPlayerSyncToAdmin(context.Background(), ev.DecisionEventCounter)
+	c.clientState = WaitingForAdminToChoosePlayer
+}
+
+// The local player did not decide in time and the admin decided for them.
+// Decisions the local player made meanwhile were not accepted, in which case
+// the local table can't take the admin's decisions and is resynced instead.
+// Caller must hold the stateMutex.
+func (c *PlayerClient) handleTimedOutLocalTurn(ev messages.PlayerDecisionsSyncEvent) {
+	c.Logger.Printf("Admin decided for the local player on timeout, decisions: %+v, COUNTER: %d", ev.Decisions, ev.DecisionEventCounter)
+
+	c.resyncBeforeNextTurn = false
+	c.evalSyncedDecisions(ev.DecidingPlayer, ev.Decisions)
+
+	c.ackPlayerSyncToAdmin(context.Background(), ev.DecisionEventCounter)
+	c.clientState = WaitingForAdminToChoosePlayer
+}
+
+// Plays the card out of turn. The admin decides whether the jump-in made it
+// before the player of the turn decided, only then is it applied locally.
+func (c *PlayerClient) jumpIn(ctx context.Context, card uknow.Card) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if c.observe {
+		c.logToWindow("spectators cannot jump in")
+		return
+	}
+
+	if c.clientState != WaitingForDecisionSync {
+		c.logToWindow("jump_in: can only jump in during another player's turn")
+		return
+	}
+
+	if err := c.table.CanJumpIn(c.table.LocalPlayerName, card); err != nil {
+		c.logToWindow("jump_in: %v", err)
+		return
+	}
+
+	decision := uknow.PlayerDecision{Kind: uknow.PlayerDecisionJumpIn, ResultCard: card}
+
+	requestBody := messages.PlayerDecisionsRequest{
+		Decisions:            []uknow.PlayerDecision{decision},
+		DecidingPlayer:       c.table.LocalPlayerName,
+		DecisionEventCounter: c.decisionEventCounterOfTurn,
+	}
+
+	var b bytes.Buffer
+	if err := messages.EncodeJSONAndEncrypt(&requestBody, &b, c.aesCipher); err != nil {
+		c.logToWindow("jump_in: %v", err)
+		return
+	}
+
+	requester := utils.RequestSender{
+		URL:        c.adminURL(c.adminAddr, requestBody.RestPath()),
+		Method:     "POST",
+		Client:     c.httpClient,
+		BodyReader: &b,
+	}
+
+	resp, err := requester.Send(ctx)
+	if err != nil {
+		c.logToWindow("jump_in: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errorPayload messages.UnwrappedErrorPayload
+		if err := messages.DecryptAndDecodeJSON(&errorPayload, resp.Body, c.aesCipher); err != nil || len(errorPayload.Errors) == 0 {
+			c.logToWindow("jump_in rejected by admin: %s", resp.Status)
+			return
+		}
+		c.logToWindow("jump_in rejected by admin: %s", errorPayload.Errors[0])
+		return
+	}
+
+	if _, err := c.table.EvalPlayerDecision(c.table.LocalPlayerName, decision, c.GameEventPushChan); err != nil {
+		c.logToWindow("jump_in accepted by admin but failed locally (%v), fetching game state", err)
+		if err := c.resyncTableWithAdmin(ctx); err != nil {
+			c.logToWindow("failed to resync table with admin: %v", err)
+		}
+	}
+
+	c.clientState = WaitingForAdminToChoosePlayer
+}
+
+// The admin dealt new hands in the middle of the game. The served table
+// replaces the local one whatever the local player was doing, and the game
+// starts over from the opening card. Caller must hold the stateMutex.
+func (c *PlayerClient) handleRedeal(ev messages.ServedCardsEvent) {
+	if c.clientState == WaitingToConnectToAdmin || c.clientState == WaitingForAdminToServeCards {
+		c.Logger.Printf("Received redealt cards, but no cards were served yet, client state: %s", c.clientState)
+		return
+	}
+
+	if !ev.Table.TableState.IsKnown() {
+		c.Logger.Printf("Received redealt cards with unknown table state %q, ignoring them", ev.Table.TableState)
+		c.logToWindow("admin dealt cards with unknown table state %q", ev.Table.TableState)
+		return
+	}
+
+	ev.Table.LocalPlayerName = c.table.LocalPlayerName
+	c.table.Set(&ev.Table)
+	c.resyncBeforeNextTurn = false
+
+	uiCommand := &UICommandSetServedCards{table: &ev.Table}
+	c.sendTableCommandToUI(uiCommand, 1*time.Second)
+	c.logToWindow("admin dealt the cards again")
+	c.GameEventPushChan <- c.table.OpeningCardEvent
+	c.clientState = WaitingForAdminToChoosePlayer
+}
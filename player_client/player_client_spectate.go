@@ -0,0 +1,163 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	messages "github.com/nrawrx3/uknow/internal/messages"
+	utils "github.com/nrawrx3/uknow/internal/utils"
+)
+
+// Connects to the admin as a spectator named by msg and follows the game.
+func (c *PlayerClient) connectToAdminAsSpectator(ctx context.Context, msg messages.AddNewPlayersMessage, adminAddr utils.HostPortProtocol) {
+	var requestBody bytes.Buffer
+	if err := messages.EncodeJSONAndEncrypt(&msg, &requestBody, c.aesCipher); err != nil {
+		c.Logger.Fatal(err)
+	}
+
+	url := c.adminURL(adminAddr, "spectate")
+
+	c.logToWindow("Calling %s", url)
+
+	req, err := http.NewRequest("POST", url, &requestBody)
+	if err != nil {
+		c.logToWindow("failed to create request to %s: %v", url, err)
+		return
+	}
+
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Connection", "keep-alive")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logToWindow("failed to connect to admin: %v", err)
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		c.logToWindow("admin did not accept spectator: %s %s", resp.Status, strings.TrimSpace(string(body)))
+		return
+	}
+
+	c.stateMutex.Lock()
+	c.adminAddr = adminAddr
+	c.stateMutex.Unlock()
+
+	c.spectatorSSEController(resp)
+}
+
+// Read-only variant of sseController. Only the public events are processed,
+// and no acks or decisions are ever sent to the admin.
+func (c *PlayerClient) spectatorSSEController(response *http.Response) {
+	defer response.Body.Close()
+
+	lineReader := utils.NewLineReader(response.Body, c.Logger)
+
+	lineBytes, err := io.ReadAll(lineReader)
+	if err != nil {
+		c.logToWindow("Unexpected error while reading first event message from admin: %v", err)
+		return
+	}
+
+	firstMessage, err := messages.DecodeEvent[messages.ExistingPlayersListEvent](lineBytes)
+	if err != nil {
+		c.logToWindow("Failed to unmarshal messages.ExistingPlayersListEvent: %v", err)
+		return
+	}
+
+	c.stateMutex.Lock()
+	c.clientState = Spectating
+	c.setLobbyHouseRules(firstMessage.HouseRules)
+	c.stateMutex.Unlock()
+
+	c.logToWindow("SPECTATING, players: %s", strings.Join(firstMessage.PlayerNames, ", "))
+
+	for {
+		lineBytes, err := io.ReadAll(lineReader)
+		if err != nil {
+			if errors.Is(err, utils.ErrDoneReadingLines) {
+				c.logToWindow("done reading all lines from admin")
+				return
+			}
+			c.logToWindow("unexpected error while reading next line: %v", err)
+			return
+		}
+
+		serverEvent, err := messages.ParseServerEventMessage(lineBytes)
+		if err != nil {
+			c.logToWindow("Failed to parse server event message: %v", err)
+			continue
+		}
+
+		if _, ok := serverEvent.(messages.HeartbeatEvent); ok {
+			continue
+		}
+
+		c.Logger.Printf("spectator received server event: %T %+v", serverEvent, serverEvent)
+
+		switch ev := serverEvent.(type) {
+		case messages.ServerShuttingDownEvent:
+			c.logToWindow("admin is shutting down, stopped spectating")
+			return
+
+		case messages.PlayerJoinedEvent:
+			c.logToWindow("player %s joined", ev.PlayerName)
+
+		case messages.HouseRulesChangedEvent:
+			c.stateMutex.Lock()
+			c.setLobbyHouseRules(ev.HouseRules)
+			c.stateMutex.Unlock()
+
+		case messages.ServedCardsEvent:
+			c.setSpectatedTable(&ev.Table, &UICommandSetServedCards{table: &ev.Table})
+
+		case messages.RematchEvent:
+			c.setSpectatedTable(&ev.Table, &UICommandRematch{table: &ev.Table, winCountOfPlayer: ev.WinCountOfPlayer})
+
+		case messages.ChosenPlayerEvent:
+			c.stateMutex.Lock()
+			c.table.PlayerOfNextTurn = ev.PlayerName
+			if !ev.MatchesTable(c.table) {
+				c.Logger.Printf("Spectated table does not match the turn context sent by admin: %s", ev.TurnContextString())
+				c.table.RequiredColorOfCurrentTurn = ev.RequiredColor
+				c.table.RequiredNumberOfCurrentTurn = ev.RequiredNumber
+			}
+			c.stateMutex.Unlock()
+			c.logToWindow("PLAYER %s's TURN, %s", ev.PlayerName, ev.TurnContextString())
+
+		case messages.PlayerDecisionsSyncEvent:
+			c.stateMutex.Lock()
+			c.evalSyncedDecisions(ev.DecidingPlayer, ev.Decisions)
+			c.stateMutex.Unlock()
+		}
+	}
+}
+
+func (c *PlayerClient) setSpectatedTable(table *uknow.Table, uiCommand UICommand) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if !table.TableState.IsKnown() {
+		c.logToWindow("admin sent a table with unknown table state %q", table.TableState)
+		return
+	}
+
+	table.LocalPlayerName = c.table.LocalPlayerName
+	c.table.Set(table)
+
+	c.sendTableCommandToUI(uiCommand, 1*time.Second)
+
+	// Only a fresh deal has an opening card worth announcing
+	if c.table.TurnsCompleted == 0 {
+		c.GameEventPushChan <- c.table.OpeningCardEvent
+	}
+}
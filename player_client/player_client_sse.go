@@ -3,29 +3,53 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"time"
 
+	"github.com/nrawrx3/uknow"
 	messages "github.com/nrawrx3/uknow/internal/messages"
 	utils "github.com/nrawrx3/uknow/internal/utils"
 )
 
-func (c *PlayerClient) sseController(response *http.Response) {
+// Consecutive lines that fail to parse before the event stream is considered
+// corrupt and the client reconnects.
+const maxConsecutiveSSEParseFailures = 3
+
+// Returned by sseController when the event stream from the admin cannot be
+// parsed anymore, as opposed to the stream ending cleanly.
+var errSSEStreamCorrupt = errors.New("event stream from admin is corrupt")
+
+// Returned by sseController when no event, not even a heartbeat, arrived from
+// the admin for too long and the stream was dropped.
+var errSSEStreamStalled = errors.New("event stream from admin stalled")
+
+// Reads and handles server events until the stream ends. Returns nil if the
+// stream ended cleanly, errSSEStreamCorrupt if it ended with or kept sending
+// lines that could not be parsed, errSSEStreamStalled if it went quiet.
+func (c *PlayerClient) sseController(response *http.Response) error {
 	c.logToWindow("connected to admin, starting SSE controller")
+	defer response.Body.Close()
+
+	c.connection.noteEvent(time.Now())
+	stopWatching := make(chan struct{})
+	defer close(stopWatching)
+	stalled := make(chan struct{})
+	go c.watchConnection(response.Body, stopWatching, stalled)
 
 	lineReader := utils.NewLineReader(response.Body, c.Logger)
 
 	lineBytes, err := io.ReadAll(lineReader)
 	if err != nil {
 		c.logToWindow("Unexpected error while reading first event message from admin: %v", err)
-		return
+		return err
 	}
 
 	firstMessage, err := messages.DecodeEvent[messages.ExistingPlayersListEvent](lineBytes)
 	if err != nil {
 		c.logToWindow("Failed to unmarshal messages.PlayerJoinedEvent: %v", err)
-		return
+		return fmt.Errorf("%w: %v", errSSEStreamCorrupt, err)
 	}
 
 	// Send an ack to admin
@@ -33,27 +57,63 @@ func (c *PlayerClient) sseController(response *http.Response) {
 	c.logToWindow("done sending ack to admin after receiving first existing players list event message")
 
 	c.stateMutex.Lock()
-	c.clientState = WaitingForAdminToServeCards
+	if firstMessage.SessionToken != "" {
+		c.sessionToken = firstMessage.SessionToken
+	}
+	if c.clientState == WaitingToConnectToAdmin || c.clientState == WaitingForAdminToServeCards {
+		c.clientState = WaitingForAdminToServeCards
+		c.playersInGame = len(firstMessage.PlayerNames) + 1
+		c.setLobbyHouseRules(firstMessage.HouseRules)
+	} else {
+		// Reconnected mid-game, events may have been missed.
+		c.resyncBeforeNextTurn = true
+	}
 	c.stateMutex.Unlock()
 
+	c.maybeAutoReady()
+
 	// Now start the loop
 
+	parseFailures := 0
+
 	for {
 		lineBytes, err := io.ReadAll(lineReader)
 		if err != nil {
+			select {
+			case <-stalled:
+				return errSSEStreamStalled
+			default:
+			}
+
 			if errors.Is(err, utils.ErrDoneReadingLines) {
+				if parseFailures > 0 {
+					c.logToWindow("event stream from admin ended with a malformed event")
+					return errSSEStreamCorrupt
+				}
 				c.logToWindow("done reading all lines from admin")
-				return
+				return nil
 			}
 			c.logToWindow("unexpected error while reading next line: %v", err)
-			return
+			return err
 		}
 
 		c.Logger.Printf("lineReader received: %s", lineBytes)
 
 		serverEvent, err := messages.ParseServerEventMessage(lineBytes)
 		if err != nil {
+			parseFailures++
 			c.logToWindow("Failed to parse server event message: %v", err)
+			if parseFailures >= maxConsecutiveSSEParseFailures {
+				c.logToWindow("%d consecutive malformed events from admin, dropping the event stream", parseFailures)
+				return errSSEStreamCorrupt
+			}
+			continue
+		}
+		parseFailures = 0
+		c.connection.noteEvent(time.Now())
+
+		// Heartbeats only keep the connection status up to date.
+		if _, ok := serverEvent.(messages.HeartbeatEvent); ok {
 			continue
 		}
 
@@ -62,19 +122,43 @@ func (c *PlayerClient) sseController(response *http.Response) {
 		// switch on event, check if current state can transition and do that
 
 		switch ev := serverEvent.(type) {
+		case messages.ServerShuttingDownEvent:
+			c.logToWindow("admin is shutting down, stopped listening for events from admin")
+			return nil
+
 		case messages.PlayerJoinedEvent:
 			func() {
 				c.stateMutex.Lock()
 				defer c.stateMutex.Unlock()
 				c.neighborListenAddr[ev.PlayerName] = utils.HostPortProtocol{} // Ignore, just keep the name
 				c.noteEachPlayer(context.Background(), []string{ev.PlayerName}, []utils.HostPortProtocol{{}})
+				c.playersInGame++
 			}()
 
+			c.maybeAutoReady()
+
+		case messages.HouseRulesChangedEvent:
+			c.stateMutex.Lock()
+			c.setLobbyHouseRules(ev.HouseRules)
+			c.stateMutex.Unlock()
+
 		case messages.ServedCardsEvent:
+			// Like a jump-in, a redeal can come during the local player's
+			// turn, who must stop being asked before the stateMutex can be
+			// taken.
+			if ev.Redeal {
+				c.cancelAskingUser()
+			}
+
 			func() {
 				c.stateMutex.Lock()
 				defer c.stateMutex.Unlock()
 
+				if ev.Redeal {
+					c.handleRedeal(ev)
+					return
+				}
+
 				if c.clientState != WaitingForAdminToServeCards {
 					// TODO: Implement an admin error handler and send that error to the
 					// admin. This way the admin can decide to send the client a
@@ -83,14 +167,37 @@ func (c *PlayerClient) sseController(response *http.Response) {
 					return
 				}
 
+				if !ev.Table.TableState.IsKnown() {
+					c.Logger.Printf("Received ServedCardsEvent with unknown table state %q, ignoring it", ev.Table.TableState)
+					c.logToWindow("admin served cards with unknown table state %q", ev.Table.TableState)
+					return
+				}
+
 				ev.Table.LocalPlayerName = c.table.LocalPlayerName
 				c.table.Set(&ev.Table)
 
 				uiCommand := &UICommandSetServedCards{table: &ev.Table}
-				err = c.sendCommandToUI(uiCommand, 1*time.Second)
-				if err != nil {
-					c.Logger.Print(err)
+				c.sendTableCommandToUI(uiCommand, 1*time.Second)
+				c.GameEventPushChan <- c.table.OpeningCardEvent
+				c.clientState = WaitingForAdminToChoosePlayer
+			}()
+
+		case messages.RematchEvent:
+			func() {
+				c.stateMutex.Lock()
+				defer c.stateMutex.Unlock()
+
+				if c.table.TableState != uknow.HaveWinner {
+					c.Logger.Printf("Received RematchEvent, but local table has no winner, table state: %s", c.table.TableState)
+					return
 				}
+
+				ev.Table.LocalPlayerName = c.table.LocalPlayerName
+				c.table.Set(&ev.Table)
+
+				uiCommand := &UICommandRematch{table: &ev.Table, winCountOfPlayer: ev.WinCountOfPlayer}
+				c.sendTableCommandToUI(uiCommand, 1*time.Second)
+				c.GameEventPushChan <- c.table.OpeningCardEvent
 				c.clientState = WaitingForAdminToChoosePlayer
 			}()
 
@@ -104,32 +211,79 @@ func (c *PlayerClient) sseController(response *http.Response) {
 					return
 				}
 
+				if c.resyncBeforeNextTurn {
+					c.resyncBeforeNextTurn = false
+					if err := c.resyncTableWithAdmin(context.Background()); err != nil {
+						c.Logger.Printf("Failed to resync table with admin: %v", err)
+						c.logToWindow("failed to resync table with admin: %v", err)
+					}
+				}
+
+				if !ev.MatchesTable(c.table) {
+					c.Logger.Printf("Local table does not match the turn context sent by admin: %s", ev.TurnContextString())
+					c.logToWindow("local table is out of sync with admin, resyncing")
+					if err := c.resyncTableWithAdmin(context.Background()); err != nil {
+						c.Logger.Printf("Failed to resync table with admin: %v", err)
+						c.logToWindow("failed to resync table with admin: %v", err)
+					}
+				}
+
+				// Differs when the admin overrides the turn order with set_next
+				if c.table.PlayerOfNextTurn != ev.PlayerName {
+					c.Logger.Printf("Admin chose %s for the turn, but local table has %s as player of next turn", ev.PlayerName, c.table.PlayerOfNextTurn)
+					c.table.PlayerOfNextTurn = ev.PlayerName
+				}
+
+				c.decisionEventCounterOfTurn = ev.DecisionEventCounter
+
 				if c.table.LocalPlayerName == ev.PlayerName {
-					c.logToWindow("↑ YOUR TURN ↑ ")
-					go c.askAndRunUserDecisions(ev.DecisionEventCounter)
+					c.logToWindow("↑ YOUR TURN ↑ %s", ev.TurnContextString())
+					go c.askAndRunUserDecisions(ev.DecisionEventCounter, c.newAskCancelChan())
 				} else {
 					c.clientState = WaitingForDecisionSync
-					c.logToWindow("PLAYER %s's TURN", ev.PlayerName)
+					c.logToWindow("PLAYER %s's TURN, %s", ev.PlayerName, ev.TurnContextString())
 				}
 			}()
 
 		case messages.PlayerDecisionsSyncEvent:
 			func() {
 				c.Logger.Printf("Received player_decisions_sync_event")
-				if ev.DecidingPlayer == c.table.LocalPlayerName {
+
+				// The local player's own decisions are synced back only when
+				// the admin made them.
+				decidedByAdmin := ev.DecidingPlayer == c.table.LocalPlayerName
+				if decidedByAdmin && !uknow.DecidedOnTimeout(ev.Decisions) {
 					return
 				}
 
+				// A jump-in or the admin can take the turn of the local
+				// player, who must stop being asked before the stateMutex can
+				// be taken.
+				_, isJumpIn := uknow.JumpInCard(ev.Decisions)
+				if isJumpIn || decidedByAdmin {
+					c.cancelAskingUser()
+				}
+
 				c.stateMutex.Lock()
 				defer c.stateMutex.Unlock()
 
+				if decidedByAdmin {
+					c.handleTimedOutLocalTurn(ev)
+					return
+				}
+
+				if isJumpIn && c.clientState != WaitingForDecisionSync {
+					c.handleJumpInDuringLocalTurn(ev)
+					return
+				}
+
 				if c.clientState != WaitingForDecisionSync {
 					c.Logger.Printf("Received PlayerDecisionsEvent, but client state is not %s, instead it is %s", WaitingForDecisionSync, c.clientState)
 					return
 				}
 
 				c.Logger.Printf("Evaluating player_decisions_sync, deciding player: %s, decisions count: %d, decisions: %+v, COUNTER: %d", ev.DecidingPlayer, len(ev.Decisions), ev.Decisions, ev.DecisionEventCounter)
-				c.table.EvalPlayerDecisions(ev.DecidingPlayer, ev.Decisions, c.GameEventPushChan)
+				c.evalSyncedDecisions(ev.DecidingPlayer, ev.Decisions)
 
 				c.ackPlayerSyncToAdmin(context.Background(), ev.DecisionEventCounter)
 
@@ -139,3 +293,10 @@ func (c *PlayerClient) sseController(response *http.Response) {
 		}
 	}
 }
+
+// Takes the house rules announced by the admin before the cards are served.
+// The served table carries the final rules. Does not lock stateMutex.
+func (c *PlayerClient) setLobbyHouseRules(houseRules uknow.HouseRules) {
+	c.table.HouseRules = houseRules
+	c.logToWindow("house rules: %s", houseRules.String())
+}
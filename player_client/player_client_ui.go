@@ -1,8 +1,11 @@
 package client
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
@@ -33,6 +36,14 @@ const (
 
 const maxLinesInEventLog = 50
 
+// More lines than shown are kept so that a filtered log still has enough to
+// show, and so that changing the filter can bring back hidden lines.
+const maxStoredEventLogLines = 4 * maxLinesInEventLog
+
+const DefaultCardTransferDelay = 500 * time.Millisecond
+
+const cardTransferQueueSize = 64
+
 type ClientUIChannels struct {
 	GeneralUICommandPullChan   <-chan UICommand                    // For one-off commands and events
 	AskUserForDecisionPullChan <-chan *UICommandAskUserForDecision // Tells the UI component that it should expect decision commands from player
@@ -43,10 +54,37 @@ type ClientUIChannels struct {
 	GeneralReplCommandPushChan chan<- *ReplCommand
 }
 
-const numCardsToShowInPile = 12
+// Number of discard pile cells shown when the config leaves it unset, and the
+// range it is clamped to otherwise.
+const (
+	DefaultDiscardPileCells = 12
+	minDiscardPileCells     = 4
+	maxDiscardPileCells     = 32
+)
+
+// Clamps the configured number of discard pile cells to a sane range. Zero
+// means the default.
+func clampDiscardPileCells(count int) int {
+	switch {
+	case count == 0:
+		return DefaultDiscardPileCells
+	case count < minDiscardPileCells:
+		return minDiscardPileCells
+	case count > maxDiscardPileCells:
+		return maxDiscardPileCells
+	}
+	return count
+}
 
 const defaultCommandPromptCellTitle = "Not your turn (only info commands allowed)"
 
+const spectatingCommandPromptCellTitle = "SPECTATING (only info commands allowed)"
+
+const noLegalPlaysCommandPromptCellTitle = "Your turn now, no playable cards: draw (or auto)"
+
+// Lists the playable cards of the local player's turn in the event log.
+const hintKey = "<Tab>"
+
 type ClientUI struct {
 	// stateMutex protects the uiState field. We must take care to always
 	// lock the mutexes in the order as they appear in this struct to
@@ -55,6 +93,10 @@ type ClientUI struct {
 	// appear here.
 	stateMutex sync.Mutex
 	uiState    ClientUIState
+	spectating bool // uiState stays at ClientUIOnlyAllowInspectReplCommands
+
+	// Playable cards of the local player's turn, shown by the hint key.
+	legalPlays uknow.Deck
 
 	// Signalling the UI process that we have updated UI data is done by the actionCond and concurrent
 	// rw is protected by the uiActionMutex
@@ -71,20 +113,32 @@ type ClientUI struct {
 	selfHandWidget    *widgets.Paragraph
 	discardPile       uknow.Deck    // Not a widget itself, but the pileCell gets its data from here
 	playerHand        uknow.Deck    // Not widget itself, but the playerHandCell gets its data from here
+	hideOwnHand       bool          // Only show the card count in selfHandWidget, playerHand is still kept up to date
 	discardPileCells  []interface{} // Stores *widgets.Paragraph(s)
 	eventLogCell      *widgets.Paragraph
-	eventLogLines     []string
+	eventLogLines     []EventLogLine
+	eventLogFilter    EventLogFilter
+	gameEventRecap    *GameEventRecap
+	requiredColor     uknow.Color // Not a widget itself, only used for snapshots
+	snapshotDir       string      // Snapshots are saved here, defaults to os.TempDir()
 
 	commandPromptMutex      sync.Mutex
 	commandStringBeingTyped string
 	commandHistory          *HistoryRing
 	commandHistoryPos       HistoryPos
+	commandHistoryFile      string // Empty if history is not persisted
 
 	ClientUIChannels
 
 	// Used for internal comm when the we receive a UICommandAskUserForDecision ui command
 	decisionReplCommandConsumerChan chan *ReplCommand
 
+	// Card transfers are animated in order by their own goroutine so that the
+	// delay between removing a card from the source and adding it to the sink
+	// doesn't stall the processing of other game events.
+	cardTransferQueue chan uknow.CardTransferEvent
+	cardTransferDelay time.Duration
+
 	Logger *log.Logger
 }
 
@@ -111,11 +165,37 @@ func (clientUI *ClientUI) handleCommandInput(playerName string) {
 		return
 	}
 
-	clientUI.appendEventLog(fmt.Sprintf("ClientUI received command: %s", command.Kind.String()))
-
-	if command.Kind.IsUserDecisionCommand() {
+	clientUI.appendEventLogOfCategory(EventLogDebug, fmt.Sprintf("ClientUI received command: %s", command.Kind.String()))
+
+	if command.Kind == CmdLogFilter {
+		filter := command.ExtraData.(EventLogFilter)
+		clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+			clientUI.eventLogFilter = filter
+			clientUI.renderEventLogNoLock()
+		})
+	} else if command.Kind == CmdToggleHand {
+		clientUI.notifyRedrawUI(uiRedrawGrid, clientUI.toggleOwnHandHidden)
+	} else if command.Kind == CmdSnapshot {
+		snapshotFile, err := clientUI.saveBoardSnapshot(command.ExtraData.(string))
+		if err != nil {
+			clientUI.appendEventLog(fmt.Sprintf("snapshot: %s", err))
+		} else {
+			clientUI.appendEventLog(fmt.Sprintf("snapshot saved to %s", snapshotFile))
+		}
+	} else if command.Kind == CmdRecap {
+		lines := clientUI.gameEventRecap.Lines(command.Count, playerName)
+		clientUI.appendEventLogOfCategory(EventLogGame, fmt.Sprintf("--- recap of last %d events:", len(lines)))
+		for _, line := range lines {
+			clientUI.appendEventLogOfCategory(EventLogGame, line)
+		}
+		clientUI.appendEventLogOfCategory(EventLogGame, "---")
+	} else if command.Kind.IsUserDecisionCommand() {
+		if clientUI.spectating {
+			clientUI.appendEventLog("Spectators cannot make decisions")
+			return
+		}
 		if clientUI.uiState != ClientUIAllowPlayerDecisionReplCommands {
-			clientUI.appendEventLog("User decision commands not allowed currently!")
+			clientUI.appendEventLog(waitForYourTurnMessage)
 			return
 		}
 		clientUI.Logger.Printf("Before sending command to clientUI.decisionReplCommandConsumerChan")
@@ -129,35 +209,51 @@ func (clientUI *ClientUI) handleCommandInput(playerName string) {
 	}
 	clientUI.commandHistoryPos.Push(clientUI.commandHistory, clientUI.commandStringBeingTyped)
 	clientUI.resetCommandPrompt("")
+
+	if command.Kind == CmdQuit {
+		clientUI.saveCommandHistory()
+	}
 }
 
 func (clientUI *ClientUI) appendEventLog(s string) {
+	clientUI.appendEventLogOfCategory(EventLogInfo, s)
+}
+
+func (clientUI *ClientUI) appendEventLogOfCategory(category EventLogCategory, s string) {
 	clientUI.notifyRedrawUI(uiRedrawGrid, func() {
-		clientUI.appendEventLogNoLock(s)
+		clientUI.appendEventLogNoLock(category, s)
 	})
 }
 
-func (clientUI *ClientUI) appendEventLogNoLock(s string) {
-	clientUI.eventLogLines = append(clientUI.eventLogLines, strings.Split(s, "\n")...)
-
-	var sb strings.Builder
+func (clientUI *ClientUI) appendEventLogNoLock(category EventLogCategory, s string) {
+	for _, line := range strings.Split(s, "\n") {
+		clientUI.eventLogLines = append(clientUI.eventLogLines, EventLogLine{Category: category, Text: line})
+	}
 
-	low := len(clientUI.eventLogLines) - maxLinesInEventLog
-	if low < 0 {
-		low = 0
+	l := len(clientUI.eventLogLines)
+	if l > maxStoredEventLogLines {
+		clientUI.eventLogLines = clientUI.eventLogLines[l-maxStoredEventLogLines : l]
 	}
 
-	for i := len(clientUI.eventLogLines) - 1; i >= low; i-- {
-		sb.WriteString(clientUI.eventLogLines[i])
+	clientUI.renderEventLogNoLock()
+}
+
+// Shows the latest lines allowed by the event log filter, newest first.
+func (clientUI *ClientUI) renderEventLogNoLock() {
+	var sb strings.Builder
+
+	shownCount := 0
+	for i := len(clientUI.eventLogLines) - 1; i >= 0 && shownCount < maxLinesInEventLog; i-- {
+		line := clientUI.eventLogLines[i]
+		if !clientUI.eventLogFilter.Shows(line.Category) {
+			continue
+		}
+		sb.WriteString(line.Text)
 		sb.WriteRune('\n')
+		shownCount++
 	}
 
 	clientUI.eventLogCell.Text = sb.String()
-
-	l := len(clientUI.eventLogLines)
-	if l > maxLinesInEventLog {
-		clientUI.eventLogLines = clientUI.eventLogLines[l-maxLinesInEventLog : l]
-	}
 }
 
 func (clientUI *ClientUI) appendCommandPrompt(s string) {
@@ -209,6 +305,7 @@ func (clientUI *ClientUI) initTableElements(table *uknow.Table, localPlayerName
 
 	// Initialize the draw deck
 	clientUI.drawDeckGauge.Percent = table.DrawDeck.Len()
+	clientUI.requiredColor = table.RequiredColorOfCurrentTurn
 
 	// Update the pile cells
 	clientUI.initDiscardPileCells(table)
@@ -236,11 +333,41 @@ func (clientUI *ClientUI) sortHandCountChartByTurn(table *uknow.Table) {
 		}
 	}
 
-	clientUI.appendEventLogNoLock(fmt.Sprintf("Handcount chart labels set to: %v", clientUI.handCountChart.Labels))
+	clientUI.updateHandCountChartMaxVal()
+
+	clientUI.appendEventLogNoLock(EventLogDebug, fmt.Sprintf("Handcount chart labels set to: %v", clientUI.handCountChart.Labels))
+}
+
+const (
+	// Scale of the hand count chart while every hand is small, so that the
+	// bars don't rescale with every card early in the game.
+	minHandCountChartMaxVal = 20
+
+	// Room left above the tallest bar once a hand outgrows the minimum scale.
+	handCountChartMargin = 4
+)
+
+// Scales the hand count chart to its largest hand, so that the bar of a hand
+// grown by draw penalties isn't clipped.
+func (clientUI *ClientUI) updateHandCountChartMaxVal() {
+	chart := clientUI.handCountChart
+
+	maxVal := float64(minHandCountChartMaxVal)
+	for _, count := range chart.Data {
+		if count+handCountChartMargin > maxVal {
+			maxVal = count + handCountChartMargin
+		}
+	}
+	chart.MaxVal = maxVal
 }
 
 // **DOES NOT LOCK** uiActionMutex
 func (clientUI *ClientUI) updatePlayerHandWidget() {
+	if clientUI.hideOwnHand {
+		clientUI.selfHandWidget.Text = fmt.Sprintf("%s (%d cards hidden, toggle_hand to show)", strings.Repeat("## ", clientUI.playerHand.Len()), clientUI.playerHand.Len())
+		return
+	}
+
 	var sb strings.Builder
 	for _, card := range clientUI.playerHand {
 		// sb.WriteString(fmt.Sprintf("(%s|%s) ", card.Color.String(), card.Number.String()))
@@ -250,10 +377,45 @@ func (clientUI *ClientUI) updatePlayerHandWidget() {
 	clientUI.selfHandWidget.Text = sb.String()
 }
 
+// **DOES NOT LOCK** uiActionMutex. Only changes what selfHandWidget shows.
+func (clientUI *ClientUI) toggleOwnHandHidden() {
+	clientUI.hideOwnHand = !clientUI.hideOwnHand
+	clientUI.updatePlayerHandWidget()
+}
+
+// Writes the cards of the local player's hand that can be played, with their
+// hand indices, to the event log. Only on the local player's turn.
+func (clientUI *ClientUI) showLegalPlayHint() {
+	clientUI.stateMutex.Lock()
+	defer clientUI.stateMutex.Unlock()
+
+	if clientUI.uiState != ClientUIAllowPlayerDecisionReplCommands {
+		clientUI.appendEventLog("Hints are only shown on your turn")
+		return
+	}
+
+	clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+		clientUI.appendEventLogNoLock(EventLogInfo, legalPlayHint(clientUI.playerHand, clientUI.legalPlays))
+	})
+}
+
+func legalPlayHint(hand uknow.Deck, legalPlays uknow.Deck) string {
+	var sb strings.Builder
+	for i, card := range hand {
+		if _, err := legalPlays.FindCard(card); err == nil {
+			sb.WriteString(fmt.Sprintf(" %d:%s", i, card.SymbolString()))
+		}
+	}
+	if sb.Len() == 0 {
+		return "Hint: no playable cards, draw"
+	}
+	return "Hint: playable" + sb.String()
+}
+
 // Creates and initializes the widget structs. All updates to the UI happens via modifying data in these
 // structs. So even if we don't have a ui goro running, these structs can be modified anyway - no need to
-// check first if ui is disabled or not
-func (clientUI *ClientUI) initWidgetObjects() {
+// check first if ui is disabled or not. Init calls this after initializing the terminal.
+func (clientUI *ClientUI) InitWidgetObjects(discardPileCells int) {
 	clientUI.pileList = widgets.NewList()
 	clientUI.pileList.Title = "Discard Pile"
 	clientUI.pileList.Border = true
@@ -265,7 +427,7 @@ func (clientUI *ClientUI) initWidgetObjects() {
 	clientUI.handCountChart.Labels = make([]string, 0, 16)
 	clientUI.handCountChart.Data = make([]float64, 0, 16)
 	clientUI.handCountChart.Title = "Hand count"
-	clientUI.handCountChart.MaxVal = 20
+	clientUI.handCountChart.MaxVal = minHandCountChartMaxVal
 
 	clientUI.drawDeckGauge = widgets.NewGauge()
 	clientUI.drawDeckGauge.Percent = 100
@@ -275,14 +437,16 @@ func (clientUI *ClientUI) initWidgetObjects() {
 
 	clientUI.eventLogCell = widgets.NewParagraph()
 	clientUI.eventLogCell.Title = "Event Log"
-	clientUI.eventLogLines = make([]string, 0, 5120)
+	clientUI.eventLogLines = make([]EventLogLine, 0, maxStoredEventLogLines)
 
 	clientUI.commandPromptCell = widgets.NewParagraph()
-	clientUI.commandPromptCell.Title = defaultCommandPromptCellTitle
+	clientUI.commandPromptCell.Title = clientUI.idleCommandPromptTitle()
 	clientUI.commandPromptCell.Block.BorderStyle.Fg = ui.ColorRed
-	clientUI.resetCommandPrompt("")
+	clientUI.commandStringBeingTyped = ""
+	clientUI.commandPromptCell.Text = " _"
 
-	clientUI.commandHistory = NewHistoryRing(4)
+	clientUI.commandHistory = NewHistoryRing(commandHistoryCapacity)
+	clientUI.gameEventRecap = NewGameEventRecap(gameEventRecapCapacity)
 
 	clientUI.selfHandWidget = widgets.NewParagraph()
 	clientUI.selfHandWidget.Title = "Hand"
@@ -290,8 +454,9 @@ func (clientUI *ClientUI) initWidgetObjects() {
 	clientUI.discardPile = uknow.NewEmptyDeck()
 	clientUI.playerHand = uknow.NewEmptyDeck()
 
-	clientUI.discardPileCells = make([]interface{}, 0, numCardsToShowInPile)
-	for i := 0; i < numCardsToShowInPile; i++ {
+	discardPileCells = clampDiscardPileCells(discardPileCells)
+	clientUI.discardPileCells = make([]interface{}, 0, discardPileCells)
+	for i := 0; i < discardPileCells; i++ {
 		// TODO: Perhaps we can use widgets.List instead of all this
 		// manual management with widgets.Paragraph and ui.Rows??
 		p := widgets.NewParagraph()
@@ -317,13 +482,29 @@ func uiColorOfCard(color uknow.Color) ui.Color {
 	panic(fmt.Sprintf("Unexpected Color value: %d", color))
 }
 
+func uiColorOfConnectionStatus(status ConnectionStatus) ui.Color {
+	switch status {
+	case ConnectionLaggy:
+		return ui.ColorYellow
+	case ConnectionDisconnected:
+		return ui.ColorRed
+	}
+	return ui.ColorGreen
+}
+
+// Number of discard pile cells, i.e. how many of the top discarded cards are
+// shown.
+func (clientUI *ClientUI) DiscardPileCellCount() int {
+	return len(clientUI.discardPileCells)
+}
+
 func (clientUI *ClientUI) initDiscardPileCells(table *uknow.Table) {
 	clientUI.discardPile = table.DiscardedPile.Clone()
 	clientUI.refreshDiscardPileCells()
 }
 
 func (clientUI *ClientUI) refreshDiscardPileCells() {
-	low := len(clientUI.discardPile) - numCardsToShowInPile
+	low := len(clientUI.discardPile) - len(clientUI.discardPileCells)
 	if low < 0 {
 		low = 0
 	}
@@ -341,12 +522,74 @@ func (clientUI *ClientUI) refreshDiscardPileCells() {
 	}
 }
 
+// **DOES NOT LOCK** uiActionMutex. Returns the cell showing the top of the
+// discard pile, nil if the pile is empty.
+func (clientUI *ClientUI) topDiscardPileCell() *widgets.Paragraph {
+	shownCount := len(clientUI.discardPile)
+	if shownCount == 0 {
+		return nil
+	}
+	if shownCount > len(clientUI.discardPileCells) {
+		shownCount = len(clientUI.discardPileCells)
+	}
+	return clientUI.discardPileCells[len(clientUI.discardPileCells)-shownCount].(*widgets.Paragraph)
+}
+
+// Briefly sets the border color of the top discard pile cell. Meant to be run
+// in its own goroutine.
+func (clientUI *ClientUI) flashTopDiscardPileCell(color ui.Color, duration time.Duration) {
+	var cell *widgets.Paragraph
+	clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+		cell = clientUI.topDiscardPileCell()
+		if cell != nil {
+			cell.BorderStyle.Fg = color
+		}
+	})
+
+	<-time.After(duration)
+
+	clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+		if cell != nil {
+			cell.BorderStyle.Fg = ui.ColorWhite
+		}
+	})
+}
+
+// Shows the reason a decision was rejected in the command prompt title with a
+// red border, then restores the prompt. Meant to be run in its own goroutine.
+func (clientUI *ClientUI) flashDecisionRejected(reason string, duration time.Duration) {
+	clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+		clientUI.commandPromptCell.Block.BorderStyle.Fg = ui.ColorRed
+		clientUI.commandPromptCell.Title = "Rejected: " + reason
+	})
+
+	<-time.After(duration)
+
+	clientUI.stateMutex.Lock()
+	defer clientUI.stateMutex.Unlock()
+
+	// Border stays red if the turn ended in the meantime, same as when the
+	// turn is over.
+	if clientUI.uiState != ClientUIAllowPlayerDecisionReplCommands {
+		return
+	}
+
+	clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+		clientUI.commandPromptCell.Block.BorderStyle.Fg = ui.ColorBlue
+		clientUI.commandPromptCell.Title = "Your turn now"
+	})
+}
+
 func (clientUI *ClientUI) Init(logger *log.Logger,
 	generalUICommandChan <-chan UICommand,
 	askUserForDecisionChan <-chan *UICommandAskUserForDecision,
 	generalReplCommandPushChan chan<- *ReplCommand,
 	gameEventPullChan <-chan uknow.GameEvent,
-	logWindowChan <-chan string) {
+	logWindowChan <-chan string,
+	commandHistoryFile string,
+	cardTransferDelay time.Duration,
+	discardPileCells int,
+	snapshotDir string) {
 	if err := ui.Init(); err != nil {
 		log.Fatalf("Failed to initialized termui: %v", err)
 	}
@@ -356,7 +599,15 @@ func (clientUI *ClientUI) Init(logger *log.Logger,
 	clientUI.uiActionCond = sync.NewCond(&clientUI.uiActionMutex)
 	clientUI.action = uiRedrawGrid
 
-	clientUI.initWidgetObjects()
+	clientUI.InitWidgetObjects(discardPileCells)
+
+	clientUI.commandHistoryFile = commandHistoryFile
+	clientUI.loadCommandHistory()
+
+	clientUI.snapshotDir = snapshotDir
+
+	clientUI.cardTransferQueue = make(chan uknow.CardTransferEvent, cardTransferQueueSize)
+	clientUI.cardTransferDelay = cardTransferDelay
 
 	clientUI.GeneralUICommandPullChan = generalUICommandChan
 	clientUI.AskUserForDecisionPullChan = askUserForDecisionChan
@@ -374,9 +625,51 @@ func (clientUI *ClientUI) Init(logger *log.Logger,
 	clientUI.decisionReplCommandConsumerChan = make(chan *ReplCommand)
 }
 
+func (clientUI *ClientUI) loadCommandHistory() {
+	if clientUI.commandHistoryFile == "" {
+		return
+	}
+
+	f, err := os.Open(clientUI.commandHistoryFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			clientUI.Logger.Printf("failed to open command history file: %v", err)
+		}
+		return
+	}
+	defer f.Close()
+
+	if err := clientUI.commandHistory.Load(f); err != nil {
+		clientUI.Logger.Printf("failed to load command history: %v", err)
+	}
+}
+
+// Does not lock commandPromptMutex
+func (clientUI *ClientUI) saveCommandHistory() {
+	if clientUI.commandHistoryFile == "" {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(clientUI.commandHistoryFile), 0755); err != nil {
+		clientUI.Logger.Printf("failed to create command history dir: %v", err)
+		return
+	}
+
+	f, err := os.OpenFile(clientUI.commandHistoryFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		clientUI.Logger.Printf("failed to open command history file: %v", err)
+		return
+	}
+	defer f.Close()
+
+	if err := clientUI.commandHistory.Save(f); err != nil {
+		clientUI.Logger.Printf("failed to save command history: %v", err)
+	}
+}
+
 func (clientUI *ClientUI) embedWidgetsInGrid() {
-	pileCellRows := make([]interface{}, 0, numCardsToShowInPile)
-	sizePerPileCell := 1.0 / numCardsToShowInPile
+	pileCellRows := make([]interface{}, 0, len(clientUI.discardPileCells))
+	sizePerPileCell := 1.0 / float64(len(clientUI.discardPileCells))
 	for _, pileCell := range clientUI.discardPileCells {
 		pileCellRows = append(pileCellRows, ui.NewRow(sizePerPileCell, pileCell))
 	}
@@ -396,13 +689,42 @@ func (clientUI *ClientUI) RunGeneralUICommandConsumer(localPlayerName string) {
 	for uiCommand := range clientUI.GeneralUICommandPullChan {
 		switch cmd := uiCommand.(type) {
 		case *UICommandSetServedCards:
-			clientUI.appendEventLog("Received UICommandSetServedCards")
+			clientUI.appendEventLogOfCategory(EventLogDebug, "Received UICommandSetServedCards")
 
 			clientUI.notifyRedrawUI(uiRedrawGrid, func() {
 				clientUI.drawDeckGauge.Percent = cmd.table.DrawDeck.Len()
 				clientUI.initTableElements(cmd.table, localPlayerName)
 			})
 
+		case *UICommandRematch:
+			playerNames := make([]string, 0, len(cmd.winCountOfPlayer))
+			for playerName := range cmd.winCountOfPlayer {
+				playerNames = append(playerNames, playerName)
+			}
+			sort.Strings(playerNames)
+
+			var sb strings.Builder
+			sb.WriteString("Rematch! Wins:")
+			for _, playerName := range playerNames {
+				sb.WriteString(fmt.Sprintf(" %s: %d", playerName, cmd.winCountOfPlayer[playerName]))
+			}
+			clientUI.appendEventLog(sb.String())
+
+			clientUI.stateMutex.Lock()
+			clientUI.uiState = ClientUIOnlyAllowInspectReplCommands
+			clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+				clientUI.drawDeckGauge.Percent = cmd.table.DrawDeck.Len()
+				clientUI.initTableElements(cmd.table, localPlayerName)
+				clientUI.commandPromptCell.Title = clientUI.idleCommandPromptTitle()
+			})
+			clientUI.stateMutex.Unlock()
+
+		case *UICommandSetConnectionStatus:
+			clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+				clientUI.eventLogCell.Title = fmt.Sprintf("Event Log | admin: %s", cmd.status)
+				clientUI.eventLogCell.TitleStyle.Fg = uiColorOfConnectionStatus(cmd.status)
+			})
+
 		default:
 			clientUI.appendEventLog("Unknown UI command")
 		}
@@ -424,6 +746,10 @@ func (clientUI *ClientUI) RunPollInputEvents(playerName string) {
 
 			switch e.ID {
 			case "<C-c>":
+				clientUI.commandPromptMutex.Lock()
+				clientUI.saveCommandHistory()
+				clientUI.commandPromptMutex.Unlock()
+
 				clientUI.GeneralReplCommandPushChan <- NewReplCommand(CmdQuit, playerName)
 				clientUI.notifyRedrawUI(uiStop, func() {})
 			case "<Resize>":
@@ -435,6 +761,8 @@ func (clientUI *ClientUI) RunPollInputEvents(playerName string) {
 				})
 			case "<Enter>":
 				clientUI.handleCommandInput(playerName)
+			case hintKey:
+				clientUI.showLegalPlayHint()
 			case "<Space>":
 				clientUI.appendCommandPrompt(" ")
 			case "<Backspace>":
@@ -470,6 +798,7 @@ func (clientUI *ClientUI) RunPollInputEvents(playerName string) {
 			}
 
 			clientUI.uiState = ClientUIAllowPlayerDecisionReplCommands
+			clientUI.legalPlays = askUserForDecisionCommand.legalPlays
 			clientUI.stateMutex.Unlock()
 
 			// Change the UI style a bit to make it obvious it's the local player's turn
@@ -478,6 +807,9 @@ func (clientUI *ClientUI) RunPollInputEvents(playerName string) {
 				clientUI.commandPromptCell.TextStyle.Fg = ui.ColorBlue
 				// clientUI.drawDeckGauge.BarColor = ui.ColorBlue
 				clientUI.commandPromptCell.Title = "Your turn now"
+				if askUserForDecisionCommand.noLegalPlays {
+					clientUI.commandPromptCell.Title = noLegalPlaysCommandPromptCellTitle
+				}
 			})
 
 			go func() {
@@ -487,37 +819,40 @@ func (clientUI *ClientUI) RunPollInputEvents(playerName string) {
 					})
 
 					defer func() {
-						clientUI.commandPromptCell.Title = defaultCommandPromptCellTitle
+						clientUI.commandPromptCell.Title = clientUI.idleCommandPromptTitle()
 					}()
 				}
 
-				for decisionReplCommand := range clientUI.decisionReplCommandConsumerChan {
+				for {
+					var decisionReplCommand *ReplCommand
+
+					select {
+					case decisionReplCommand = <-clientUI.decisionReplCommandConsumerChan:
+					case <-askUserForDecisionCommand.cancel:
+					}
+
+					if decisionReplCommand == nil {
+						clientUI.appendEventLog("Turn was taken by a jump-in, a redeal or the admin")
+						break
+					}
+
 					// Convert to PlayerDecisionEvent
 					askUserForDecisionCommand.receive <- decisionReplCommand
 					decisionResult := <-askUserForDecisionCommand.decisionResultChan
 
 					if decisionResult.Error != nil {
-						// CONSIDER(@rk): Does it make sense to show a bit more fancy signal in case he makes an illegal decision?
-
-						go func() {
-							clientUI.notifyRedrawUI(uiRedrawGrid, func() {
-								// clientUI.drawDeckGauge.BarColor = ui.ColorRed
-								clientUI.eventLogCell.BorderStyle.Fg = ui.ColorRed
-
-							})
-							<-time.After(2 * time.Second)
-							clientUI.notifyRedrawUI(uiRedrawGrid, func() {
-								// clientUI.drawDeckGauge.BarColor = ui.ColorBlue
-								clientUI.eventLogCell.BorderStyle.Fg = ui.ColorWhite
-							})
-						}()
-						clientUI.appendEventLog(decisionResult.Error.Error())
+						clientUI.appendEventLog("Decision rejected: " + decisionResult.RejectionReason)
+						go clientUI.flashDecisionRejected(decisionResult.RejectionReason, 2*time.Second)
 					}
 
 					if !decisionResult.AskForOneMoreDecision {
 						break
 					}
 
+					clientUI.stateMutex.Lock()
+					clientUI.legalPlays = decisionResult.LegalPlays
+					clientUI.stateMutex.Unlock()
+
 					clientUI.Logger.Printf("need more decision from user")
 				}
 
@@ -533,7 +868,7 @@ func (clientUI *ClientUI) RunPollInputEvents(playerName string) {
 						clientUI.commandPromptCell.Block.BorderStyle.Fg = ui.ColorRed
 						clientUI.commandPromptCell.TextStyle.Fg = ui.ColorWhite
 						// clientUI.drawDeckGauge.BarColor = ui.ColorWhite
-						clientUI.commandPromptCell.Title = defaultCommandPromptCellTitle
+						clientUI.commandPromptCell.Title = clientUI.idleCommandPromptTitle()
 					})
 				}
 				clientUI.stateMutex.Unlock()
@@ -546,25 +881,51 @@ func (clientUI *ClientUI) RunPollInputEvents(playerName string) {
 }
 
 func (clientUI *ClientUI) RunGameEventProcessor(localPlayerName string) {
+	go clientUI.runCardTransferAnimator(localPlayerName)
+	defer close(clientUI.cardTransferQueue)
+
 	for event := range clientUI.GameEventPullChan {
+		clientUI.gameEventRecap.Push(time.Now(), event)
+
 		switch event := event.(type) {
 		case uknow.RequiredColorUpdatedEvent:
 			clientUI.notifyRedrawUI(uiRedrawGrid, func() {
 				clientUI.drawDeckGauge.BarColor = uiColorOfCard(event.NewColor)
+				clientUI.requiredColor = event.NewColor
 			})
 
 		case uknow.CardTransferEvent:
-			// clientUI.Logger.Printf("received")
-
-			clientUI.notifyRedrawUI(uiRedrawGrid, func() {
-				clientUI.handleCardTransferEvent(event, localPlayerName)
-			})
+			clientUI.cardTransferQueue <- event
 
 		case uknow.AwaitingWildCardColorDecisionEvent:
 			if event.AskDecisionFromLocalPlayer {
-				clientUI.appendEventLog(event.StringMessage(localPlayerName))
+				clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
 			}
 
+		case uknow.AwaitingSwapTargetDecisionEvent:
+			if event.AskDecisionFromLocalPlayer {
+				clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
+				clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+					clientUI.commandPromptCell.Title = "Choose a player to swap hands with: swap <player>"
+				})
+			}
+
+		case uknow.HandsRotatedEvent:
+			clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
+			clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+				clientUI.setHandsAfterExchange(event.LocalPlayerHand, event.HandCountOfPlayer)
+			})
+
+		case uknow.HandsSwappedEvent:
+			clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
+			clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+				clientUI.setHandsAfterExchange(event.LocalPlayerHand, event.HandCountOfPlayer)
+			})
+
+		case uknow.WildCardColorChosenEvent:
+			clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
+			go clientUI.flashTopDiscardPileCell(uiColorOfCard(event.ChosenColor), 2*time.Second)
+
 		case uknow.ChallengerSuccessEvent:
 			if event.FromLocalClient() { // Set challenge result info as command prompt
 				clientUI.notifyRedrawUI(uiRedrawGrid, func() {
@@ -583,10 +944,27 @@ func (clientUI *ClientUI) RunGameEventProcessor(localPlayerName string) {
 				go clientUI.runResetCommandPromptTitle(3 * time.Second)
 			}
 
+		case uknow.ChallengeTimedOutEvent:
+			clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
+
+		case uknow.OpeningCardEvent:
+			clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
+
 		case uknow.AwaitingPlayOrPassEvent:
 			if event.FromLocalClient() {
 				clientUI.notifyRedrawUI(uiRedrawGrid, func() {
-					clientUI.commandPromptCell.Title = "Please play a card or pass"
+					clientUI.commandPromptCell.Title = "Please play a card, pass or undo"
+				})
+			}
+
+		case uknow.JumpInEvent:
+			clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
+
+		case uknow.UndoEvent:
+			clientUI.appendEventLogOfCategory(EventLogGame, event.StringMessage(localPlayerName))
+			if event.FromLocalClient() && event.ReturnedCard {
+				clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+					clientUI.commandPromptCell.Title = "Your turn now"
 				})
 			}
 
@@ -595,7 +973,7 @@ func (clientUI *ClientUI) RunGameEventProcessor(localPlayerName string) {
 				clientUI.stateMutex.Lock()
 
 				if clientUI.uiState != ClientUIWeHaveAWinner {
-					clientUI.notifyRedrawUI(uiRedrawGrid, func() { clientUI.commandPromptCell.Title = defaultCommandPromptCellTitle })
+					clientUI.notifyRedrawUI(uiRedrawGrid, func() { clientUI.commandPromptCell.Title = clientUI.idleCommandPromptTitle() })
 				}
 
 				clientUI.stateMutex.Unlock()
@@ -609,6 +987,14 @@ func (clientUI *ClientUI) RunGameEventProcessor(localPlayerName string) {
 			}, event.Player, "won the game")
 			clientUI.stateMutex.Unlock()
 
+			if len(event.Stats) != 0 {
+				clientUI.appendEventLogOfCategory(EventLogGame, "--- scoreboard:")
+				for _, line := range uknow.PlayerStatsLines(event.Stats) {
+					clientUI.appendEventLogOfCategory(EventLogGame, line)
+				}
+				clientUI.appendEventLogOfCategory(EventLogGame, "---")
+			}
+
 		default:
 			clientUI.Logger.Printf("UNKNOWN GAME EVENT: %s", event.GameEventName())
 			clientUI.appendEventLog("<Unknown game event received>")
@@ -623,10 +1009,31 @@ func (clientUI *ClientUI) runResetCommandPromptTitle(timeout time.Duration) {
 	defer clientUI.stateMutex.Unlock()
 
 	clientUI.notifyRedrawUI(uiRedrawGrid, func() {
-		clientUI.setTurnBasedCommandPromptTitle(defaultCommandPromptCellTitle)
+		clientUI.setTurnBasedCommandPromptTitle(clientUI.idleCommandPromptTitle())
+	})
+}
+
+// Marks the UI as spectating. Only inspect commands are allowed from then on.
+func (clientUI *ClientUI) SetSpectating() {
+	clientUI.stateMutex.Lock()
+	defer clientUI.stateMutex.Unlock()
+
+	clientUI.spectating = true
+	clientUI.uiState = ClientUIOnlyAllowInspectReplCommands
+	clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+		clientUI.commandPromptCell.Title = spectatingCommandPromptCellTitle
 	})
 }
 
+// Title of the command prompt when it's not the local player's turn. Does not
+// lock stateMutex.
+func (clientUI *ClientUI) idleCommandPromptTitle() string {
+	if clientUI.spectating {
+		return spectatingCommandPromptCellTitle
+	}
+	return defaultCommandPromptCellTitle
+}
+
 // Does not lock stateMutex
 func (clientUI *ClientUI) setTurnBasedCommandPromptTitle(title string) {
 	if clientUI.uiState != ClientUIWeHaveAWinner {
@@ -634,10 +1041,34 @@ func (clientUI *ClientUI) setTurnBasedCommandPromptTitle(title string) {
 	}
 }
 
-func (clientUI *ClientUI) handleCardTransferEvent(event uknow.CardTransferEvent, localPlayerName string) {
+// Runs in own thread. Consumes the card transfers queued by
+// RunGameEventProcessor.
+func (clientUI *ClientUI) runCardTransferAnimator(localPlayerName string) {
+	for event := range clientUI.cardTransferQueue {
+		ok := true
+		clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+			ok = clientUI.removeCardFromTransferSource(event, localPlayerName)
+		})
+		if !ok {
+			continue
+		}
+
+		if clientUI.cardTransferDelay > 0 {
+			<-time.After(clientUI.cardTransferDelay)
+		}
+
+		clientUI.notifyRedrawUI(uiRedrawGrid, func() {
+			clientUI.addCardToTransferSink(event, localPlayerName)
+		})
+	}
+}
+
+// Must be called with uiActionMutex held. Returns false if the transfer can't
+// proceed.
+func (clientUI *ClientUI) removeCardFromTransferSource(event uknow.CardTransferEvent, localPlayerName string) bool {
 	// TODO(@rk): Don't show the card info if the card transfer is happening
 	// to hand of non local player
-	clientUI.appendEventLogNoLock(fmt.Sprintf("handleCardTransferEvent: %s, localPlayerName: %s", event.String(localPlayerName), localPlayerName))
+	clientUI.appendEventLogNoLock(EventLogCardTransfer, fmt.Sprintf("card transfer: %s, localPlayerName: %s", event.String(localPlayerName), localPlayerName))
 
 	switch event.Source {
 	case uknow.CardTransferNodeDeck:
@@ -646,8 +1077,8 @@ func (clientUI *ClientUI) handleCardTransferEvent(event uknow.CardTransferEvent,
 		var err error
 		clientUI.discardPile, err = clientUI.discardPile.Pop()
 		if err != nil {
-			clientUI.appendEventLogNoLock("handleCardTransferEvent failed: Transfer from empty pile")
-			return
+			clientUI.appendEventLogNoLock(EventLogInfo, "card transfer failed: Transfer from empty pile")
+			return false
 		}
 
 	case uknow.CardTransferNodePlayerHand:
@@ -656,14 +1087,16 @@ func (clientUI *ClientUI) handleCardTransferEvent(event uknow.CardTransferEvent,
 			var err error
 			clientUI.playerHand, err = clientUI.playerHand.FindAndRemoveCard(event.Card)
 			if err != nil {
-				clientUI.appendEventLog(fmt.Sprintf("handleCardTransferEvent failed: %s", err))
+				clientUI.appendEventLogNoLock(EventLogInfo, fmt.Sprintf("card transfer failed: %s", err))
 			}
 			clientUI.updatePlayerHandWidget()
 		}
 	}
+	return true
+}
 
-	<-time.After(500 * time.Millisecond)
-
+// Must be called with uiActionMutex held.
+func (clientUI *ClientUI) addCardToTransferSink(event uknow.CardTransferEvent, localPlayerName string) {
 	switch event.Sink {
 	case uknow.CardTransferNodeDeck:
 		clientUI.drawDeckGauge.Percent += 1
@@ -680,15 +1113,34 @@ func (clientUI *ClientUI) handleCardTransferEvent(event uknow.CardTransferEvent,
 	}
 }
 
+// Must be called with uiActionMutex held. Used when hands change owners as a
+// whole instead of card by card.
+func (clientUI *ClientUI) setHandsAfterExchange(localPlayerHand uknow.Deck, handCountOfPlayer map[string]int) {
+	clientUI.playerHand = localPlayerHand.Clone()
+	sort.Sort(clientUI.playerHand)
+	clientUI.updatePlayerHandWidget()
+
+	chart := clientUI.handCountChart
+	for i, chartPlayerName := range chart.Labels {
+		chart.Data[i] = float64(handCountOfPlayer[chartPlayerName])
+	}
+	clientUI.updateHandCountChartMaxVal()
+}
+
 func (clientUI *ClientUI) addToHandCountChart(playerName string, cardCount int) {
 	chart := clientUI.handCountChart
 	for i, chartPlayerName := range chart.Labels {
 		if chartPlayerName == playerName {
 			chart.Data[i] += float64(cardCount)
+			if chart.Data[i] < 0 {
+				clientUI.appendEventLogNoLock(EventLogDebug, fmt.Sprintf("addToHandCountChart: hand count of %s went below 0, clamped", playerName))
+				chart.Data[i] = 0
+			}
+			clientUI.updateHandCountChartMaxVal()
 			return
 		}
 	}
-	clientUI.appendEventLogNoLock(fmt.Sprintf("addToHandCountChart failed: did not find playerName %s", playerName))
+	clientUI.appendEventLogNoLock(EventLogInfo, fmt.Sprintf("addToHandCountChart failed: did not find playerName %s", playerName))
 }
 
 // Runs in own thread.
@@ -740,3 +1192,8 @@ func (dummy *DummyClientUI) RunWindowLogger() {
 	for range dummy.LogWindowPullChan {
 	}
 }
+
+func (dummy *DummyClientUI) RunUICommandDumper() {
+	for range dummy.GeneralUICommandPullChan {
+	}
+}
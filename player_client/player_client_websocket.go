@@ -0,0 +1,278 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	messages "github.com/nrawrx3/uknow/internal/messages"
+	utils "github.com/nrawrx3/uknow/internal/utils"
+)
+
+var errAdminWebSocketClosed = errors.New("websocket to admin is closed")
+
+// Connection to the admin's /ws endpoint, carrying the event stream of the
+// local player and the requests of the client to the admin. See
+// ConfigNewPlayerClient.WebSocket.
+type adminWebSocket struct {
+	conn *utils.WebSocketConn
+
+	// URL of the admin the endpoints of the requests are relative to, with a
+	// trailing slash.
+	adminURL string
+
+	// Events from the admin, written in the same lines as on an SSE stream
+	// so that sseController reads them the same way.
+	events      *io.PipeReader
+	eventWriter *io.PipeWriter
+
+	// Closed before the first event is passed on to sseController.
+	streamStarted chan struct{}
+
+	// Closed once the connection is done.
+	done chan struct{}
+
+	mutex   sync.Mutex
+	nextID  int
+	pending map[int]chan messages.ResponseEvent
+	closed  bool
+}
+
+func newAdminWebSocket(conn *utils.WebSocketConn, adminURL string) *adminWebSocket {
+	events, eventWriter := io.Pipe()
+	ws := &adminWebSocket{
+		conn:          conn,
+		adminURL:      adminURL,
+		events:        events,
+		eventWriter:   eventWriter,
+		streamStarted: make(chan struct{}),
+		done:          make(chan struct{}),
+		pending:       make(map[int]chan messages.ResponseEvent),
+	}
+	go ws.readMessages()
+	return ws
+}
+
+// Passes on events to the event stream and responses to the requests waiting
+// for them, until the connection closes.
+func (ws *adminWebSocket) readMessages() {
+	var err error
+	defer func() {
+		ws.mutex.Lock()
+		ws.closed = true
+		ws.pending = nil
+		ws.mutex.Unlock()
+
+		close(ws.done)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+		ws.eventWriter.CloseWithError(err)
+	}()
+
+	streamStarted := false
+	for {
+		var message []byte
+		message, err = ws.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var onlyType struct{ Type messages.EventType }
+		if json.Unmarshal(message, &onlyType) == nil && onlyType.Type == messages.EventTypeResponse {
+			response, decodeErr := messages.DecodeEvent[messages.ResponseEvent](message)
+			if decodeErr == nil {
+				ws.deliver(response)
+				continue
+			}
+		}
+
+		if !streamStarted {
+			streamStarted = true
+			close(ws.streamStarted)
+		}
+		// Malformed messages are passed on too, sseController counts them.
+		if _, err = fmt.Fprintf(ws.eventWriter, "%s\n\n", message); err != nil {
+			return
+		}
+	}
+}
+
+func (ws *adminWebSocket) deliver(response messages.ResponseEvent) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+
+	if responseChan, ok := ws.pending[response.ID]; ok {
+		responseChan <- response
+		delete(ws.pending, response.ID)
+	}
+}
+
+// Sends a request to the endpoint of the admin. The response arrives on the
+// returned channel, which is buffered.
+func (ws *adminWebSocket) send(method, endpoint string, body []byte) (<-chan messages.ResponseEvent, error) {
+	ws.mutex.Lock()
+	if ws.closed {
+		ws.mutex.Unlock()
+		return nil, errAdminWebSocketClosed
+	}
+	ws.nextID++
+	request := messages.WebSocketRequest{
+		ID:       ws.nextID,
+		Method:   method,
+		Endpoint: endpoint,
+		Body:     body,
+	}
+	responseChan := make(chan messages.ResponseEvent, 1)
+	ws.pending[request.ID] = responseChan
+	ws.mutex.Unlock()
+
+	message, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := ws.conn.WriteMessage(message); err != nil {
+		return nil, err
+	}
+	return responseChan, nil
+}
+
+// Serves an HTTP request to the admin over the connection.
+func (ws *adminWebSocket) roundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	responseChan, err := ws.send(req.Method, strings.TrimPrefix(req.URL.String(), ws.adminURL), body)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case response := <-responseChan:
+		return newResponseOfEvent(req, response), nil
+	case <-ws.done:
+		return nil, errAdminWebSocketClosed
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+}
+
+func newResponseOfEvent(req *http.Request, response messages.ResponseEvent) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}
+}
+
+// Reads the event stream, closing it closes the connection.
+func (ws *adminWebSocket) Read(p []byte) (int, error) {
+	return ws.events.Read(p)
+}
+
+func (ws *adminWebSocket) Close() error {
+	ws.events.Close()
+	return ws.conn.Close()
+}
+
+// Sends the requests of the client to the admin over the admin websocket
+// while one is open, and over HTTP otherwise.
+type adminWebSocketTransport struct {
+	base   http.RoundTripper
+	client *PlayerClient
+}
+
+func (t *adminWebSocketTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ws := t.client.currentAdminWebSocket()
+	if ws == nil || !strings.HasPrefix(req.URL.String(), ws.adminURL) {
+		return t.base.RoundTrip(req)
+	}
+	return ws.roundTrip(req)
+}
+
+func (c *PlayerClient) currentAdminWebSocket() *adminWebSocket {
+	c.adminWebSocketMutex.Lock()
+	defer c.adminWebSocketMutex.Unlock()
+	return c.adminWebSocket
+}
+
+// Joins the game over the admin's /ws endpoint with the encoded join request.
+// Returns the same response as POST /player would, whose body is the event
+// stream if the admin accepted the player.
+func (c *PlayerClient) joinOverWebSocket(ctx context.Context, adminAddr utils.HostPortProtocol, joinRequestBody []byte) (*http.Response, error) {
+	url := c.adminURL(adminAddr, "ws")
+	c.logToWindow("Connecting to %s", url)
+
+	conn, err := utils.DialWebSocket(ctx, url, &tls.Config{RootCAs: c.adminRootCAs})
+	if err != nil {
+		return nil, err
+	}
+
+	ws := newAdminWebSocket(conn, c.adminURL(adminAddr, ""))
+	joinResponseChan, err := ws.send(http.MethodPost, "player", joinRequestBody)
+	if err != nil {
+		ws.Close()
+		return nil, err
+	}
+
+	select {
+	case joinResponse := <-joinResponseChan:
+		ws.Close()
+		return newResponseOfEvent(nil, joinResponse), nil
+
+	case <-ws.streamStarted:
+		c.adminWebSocketMutex.Lock()
+		c.adminWebSocket = ws
+		c.adminWebSocketMutex.Unlock()
+
+		go func() {
+			<-ws.done
+			c.adminWebSocketMutex.Lock()
+			if c.adminWebSocket == ws {
+				c.adminWebSocket = nil
+			}
+			c.adminWebSocketMutex.Unlock()
+		}()
+
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       ws,
+		}, nil
+
+	case <-ws.done:
+		ws.Close()
+		// A refusal is followed by the admin closing the connection.
+		select {
+		case joinResponse := <-joinResponseChan:
+			return newResponseOfEvent(nil, joinResponse), nil
+		default:
+			return nil, errAdminWebSocketClosed
+		}
+
+	case <-ctx.Done():
+		ws.Close()
+		return nil, ctx.Err()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,14 +13,6 @@ import (
 	"github.com/nrawrx3/uknow"
 )
 
-const (
-	ReservedNameDeck          = "<deck>"
-	ReservedNamePile          = "<pile>"
-	ReservedNameAdmin         = "<admin>"
-	ReservedNameClient        = "<player_client>"
-	ReservedNameCommandPrompt = "<command_prompt>"
-)
-
 // `ReplCommandKind` represents a client command. Both UI and Admin can send these commands to the PlayerClient to do an action.
 //
 //go:generate stringer -type=ReplCommandKind
@@ -36,13 +29,28 @@ const (
 	CmdTableSummary
 	CmdDumpDrawDeck
 	CmdShowHand // Might delete since we want to show hand at all times in the UI in the MVP
+	CmdShowCounts
+	CmdLogFilter
+	CmdPileHistory
+	CmdResync
+	CmdTurnOrder
+	CmdRecap
+	CmdToggleHand
+	CmdSnapshot
+	CmdJumpIn // Not a decision command since it's played out of turn
+	CmdHouseRules
+	CmdTurnContext
 
 	// Player decision commands. Add new decision commands to the _middle_ of the list, or update IsUserDecisionCommand function.
 	CmdDropCard
 	CmdDrawCard
+	CmdDrawAndPlayCard
+	CmdForcedDraw // Draw, then play the drawn card or pass, when no card in hand is playable
 	CmdPass
+	CmdUndoDraw
 	CmdDrawCardFromPile // TODO(@rk): Delete this? Not needed.
 	CmdSetWildCardColor
+	CmdChooseSwapTarget
 	CmdNoChallenge
 	CmdChallenge
 )
@@ -51,6 +59,11 @@ func (k ReplCommandKind) IsUserDecisionCommand() bool {
 	return CmdDropCard <= k && k <= CmdChallenge
 }
 
+// Reports whether the command starts by drawing the top card of the draw deck.
+func (k ReplCommandKind) DrawsFromDeck() bool {
+	return k == CmdDrawCard || k == CmdDrawAndPlayCard || k == CmdForcedDraw || k == CmdDrawCardFromPile
+}
+
 // Represents a single command. Not all fields are used for all commands. TODO(@rk): _maybe_ use a sum type instead
 // of clubbing all possible payload in a single struct?
 type ReplCommand struct {
@@ -73,11 +86,27 @@ func NewReplCommand(kind ReplCommandKind, playerName string) *ReplCommand {
 // Syntax:
 //	connect REMOTE_ADDRESS
 //	draw NUMBER              (where NUMBER denotes the count of cards to pull)
+//	draw_play                (draw a card and play it right away if it's playable)
+//	auto                     (when no card in hand is playable, draw and play the drawn card if possible, pass otherwise)
 //	drawpile
 //	drop NUMBER COLOR (NUMBER COLOR)*        (where NUMBER can denote or action name or action name)
+//	                                         (more than one card, all of the same number, with the stack_same_number rule)
+//	undo                     (after drawing, pass with the drawn card, or put it back on the draw deck with the undo_draw_returns_card rule)
+//	swap NAME                (swap hands with player NAME after playing a 7, with the Seven-Zero rule)
 //	quit                     (quit the game??)
 //	challenge NAME           (where NAME is name of player whom to challenge)
 //	table_info
+//	counts                   (fetch the draw deck, discard pile and hand counts from admin)
+//	log_filter FILTER        (where FILTER is all|no_transfer|no_debug|game, game hides card transfers and UI internals)
+//	pile_history             (show the whole discard pile, top card first)
+//	resync                   (replace the local table with the admin's, e.g. after missing the served cards)
+//	turn_order               (show the players in order of play, starting with the player of the current turn)
+//	recap [COUNT]            (show the last COUNT game events again, 10 if COUNT is not given)
+//	toggle_hand              (hide the cards of your hand, or show them again, e.g. while sharing the screen)
+//	snapshot FILE            (save the board as text to FILE in the log dir, e.g. for a bug report)
+//	jump_in NUMBER COLOR     (play a card identical to the top of the pile out of turn, with the jump_in rule)
+//	rules                    (show which house rules are on in the game)
+//	turn                     (fetch the required color and number, top of the pile and player of the turn from admin)
 
 func ParseCommandFromInput(input string, playerName string) (*ReplCommand, error) {
 	input = strings.TrimSpace(input)
@@ -93,6 +122,10 @@ func ParseCommandFromInput(input string, playerName string) (*ReplCommand, error
 		return parseConnectCommand(input, playerName)
 	}
 
+	if tok == scanner.Ident && s.TokenText() == "snapshot" {
+		return parseSnapshotCommand(input, playerName)
+	}
+
 	tok, command, err := parseCommand(&s, tok, playerName)
 	if err != nil {
 		return command, err
@@ -137,6 +170,14 @@ func parseCommand(s *scanner.Scanner, tok rune, playerName string) (rune, *ReplC
 		command.Count = number
 		return tok, command, nil
 
+	case "draw_play":
+		command.Kind = CmdDrawAndPlayCard
+		return s.Scan(), command, nil
+
+	case "auto":
+		command.Kind = CmdForcedDraw
+		return s.Scan(), command, nil
+
 	case "drawpile":
 		command.Kind = CmdDrawCardFromPile
 		return s.Scan(), command, nil
@@ -163,13 +204,22 @@ func parseCommand(s *scanner.Scanner, tok rune, playerName string) (rune, *ReplC
 			return tok, command, fmt.Errorf("expected a color (red|blue|yellow|green) as argument of wild_color command")
 		}
 
-		color, ok := getColorFromString(s.TokenText())
-		if !ok {
+		color, err := uknow.ParseColor(s.TokenText())
+		if err != nil || color == uknow.ColorWild {
 			return tok, command, fmt.Errorf("invalid color: %s", s.TokenText())
 		}
 		command.ExtraData = color
 		return s.Scan(), command, nil
 
+	case "swap":
+		command.Kind = CmdChooseSwapTarget
+		tok := s.Scan()
+		if tok != scanner.Ident {
+			return tok, command, fmt.Errorf("expected a player name as argument of swap command")
+		}
+		command.TargetPlayerName = s.TokenText()
+		return s.Scan(), command, nil
+
 	case "quit":
 		command.Kind = CmdQuit
 		return s.Scan(), command, nil
@@ -186,6 +236,10 @@ func parseCommand(s *scanner.Scanner, tok rune, playerName string) (rune, *ReplC
 		command.Kind = CmdPass
 		return s.Scan(), command, nil
 
+	case "undo":
+		command.Kind = CmdUndoDraw
+		return s.Scan(), command, nil
+
 	case "connect_default":
 		command.Kind = CmdConnect
 		command.ExtraData = nil
@@ -218,53 +272,91 @@ func parseCommand(s *scanner.Scanner, tok rune, playerName string) (rune, *ReplC
 		command.Kind = CmdShowHand
 		return s.Scan(), command, nil
 
-	default:
-		return tok, command, fmt.Errorf("expected a main-command (draw|drop|quit|challenge), found '%s'", s.TokenText())
-	}
-}
+	case "counts":
+		command.Kind = CmdShowCounts
+		return s.Scan(), command, nil
+
+	case "turn":
+		command.Kind = CmdTurnContext
+		return s.Scan(), command, nil
+
+	case "log_filter":
+		command.Kind = CmdLogFilter
+		tok := s.Scan()
+		if tok != scanner.Ident {
+			return tok, command, fmt.Errorf("expected a filter (all|no_transfer|no_debug|game) as argument of log_filter command")
+		}
+		filter, err := ParseEventLogFilter(s.TokenText())
+		if err != nil {
+			return tok, command, err
+		}
+		command.ExtraData = filter
+		return s.Scan(), command, nil
+
+	case "pile_history":
+		command.Kind = CmdPileHistory
+		return s.Scan(), command, nil
+
+	case "resync":
+		command.Kind = CmdResync
+		return s.Scan(), command, nil
+
+	case "turn_order":
+		command.Kind = CmdTurnOrder
+		return s.Scan(), command, nil
+
+	case "recap":
+		command.Kind = CmdRecap
+		command.Count = defaultRecapCount
+		tok := s.Scan()
+		if tok == scanner.EOF {
+			return tok, command, nil
+		}
+		count, err := strconv.Atoi(s.TokenText())
+		if tok != scanner.Int || err != nil || count < 1 || count > gameEventRecapCapacity {
+			return tok, command, fmt.Errorf("expected empty or a number of events (1-%d) to recap. Got token '%s'", gameEventRecapCapacity, s.TokenText())
+		}
+		command.Count = count
+		return s.Scan(), command, nil
+
+	case "toggle_hand":
+		command.Kind = CmdToggleHand
+		return s.Scan(), command, nil
+
+	case "rules":
+		command.Kind = CmdHouseRules
+		return s.Scan(), command, nil
+
+	case "jump_in":
+		command.Kind = CmdJumpIn
+		tok := s.Scan()
+		if !(tok == scanner.Int || tok == scanner.Ident) {
+			return tok, command, fmt.Errorf("expected a number (0-9) or action name (skip|rev|draw2) to jump in with, found: '%s'", s.TokenText())
+		}
+
+		tok, cards, err := parseCardSequence(s, command.Cards)
+		if err != nil {
+			return tok, command, err
+		}
+		if len(cards) != 1 {
+			return tok, command, fmt.Errorf("expected exactly 1 card to jump in with, got %d", len(cards))
+		}
+		command.Cards = cards
+		return tok, command, nil
 
-func getColorFromString(s string) (uknow.Color, bool) {
-	switch strings.ToLower(s) {
-	case "red":
-		return uknow.ColorRed, true
-	case "blue":
-		return uknow.ColorBlue, true
-	case "green":
-		return uknow.ColorGreen, true
-	case "yellow":
-		return uknow.ColorYellow, true
 	default:
-		return uknow.ColorWild, false
+		return tok, command, fmt.Errorf("expected a main-command (draw|drop|quit|challenge), found '%s'", s.TokenText())
 	}
 }
 
 func parseCardSequence(s *scanner.Scanner, cards []uknow.Card) (rune, []uknow.Card, error) {
 	card := uknow.Card{}
 
-	switch text := strings.ToLower(s.TokenText()); text {
-	case "skip":
-		card.Number = uknow.NumberSkip
-	case "rev":
-		card.Number = uknow.NumberReverse
-	case "draw2":
-		card.Number = uknow.NumberDrawTwo
-	case "wild":
-		card.Number = uknow.NumberWild
-	case "wild4":
-		card.Number = uknow.NumberWildDrawFour
-	default:
-		number, err := strconv.Atoi(text)
-		if err != nil {
-			return 0, cards, err
-		}
-		if number < 0 || number > 9 {
-			return 0, cards, fmt.Errorf("invalid card number: %d", number)
-		}
-		card.Number, err = uknow.IntToNumber(number)
-		if err != nil {
-			return 0, cards, err
-		}
+	number, err := uknow.ParseNumber(s.TokenText())
+	if err != nil {
+		return 0, cards, err
 	}
+	card.Number = number
 
 	if card.Number == uknow.NumberWild || card.Number == uknow.NumberWildDrawFour {
 		cards = append(cards, card)
@@ -280,18 +372,11 @@ func parseCardSequence(s *scanner.Scanner, cards []uknow.Card) (rune, []uknow.Ca
 	if tok != scanner.Ident {
 		return tok, cards, fmt.Errorf("expected a card color (red|green|blue|yellow). Got '%s'", s.TokenText())
 	}
-	switch strings.ToLower(s.TokenText()) {
-	case "red":
-		card.Color = uknow.ColorRed
-	case "green":
-		card.Color = uknow.ColorGreen
-	case "blue":
-		card.Color = uknow.ColorBlue
-	case "yellow":
-		card.Color = uknow.ColorYellow
-	default:
+	color, err := uknow.ParseColor(s.TokenText())
+	if err != nil || color == uknow.ColorWild {
 		return tok, cards, fmt.Errorf("expected a card color (red|green|blue|yellow). Got '%s'", s.TokenText())
 	}
+	card.Color = color
 	cards = append(cards, card)
 
 	tok = s.Scan()
@@ -301,7 +386,8 @@ func parseCardSequence(s *scanner.Scanner, cards []uknow.Card) (rune, []uknow.Ca
 	return tok, cards, nil
 }
 
-// Connect command is of the form: connect adminAddr
+// Connect command is of the form: connect adminAddr. The address is taken as
+// http unless it starts with https://.
 func parseConnectCommand(input string, playerName string) (*ReplCommand, error) {
 	re := regexp.MustCompile(`^connect\\s+(?P<adminAddr>.+)$`)
 	input = strings.TrimSpace(input)
@@ -313,7 +399,7 @@ func parseConnectCommand(input string, playerName string) (*ReplCommand, error)
 	adminAddrIndex := re.SubexpIndex("adminAddr")
 	adminAddr := matches[adminAddrIndex]
 
-	if !strings.HasPrefix(adminAddr, "http://") {
+	if !strings.HasPrefix(adminAddr, "http://") && !strings.HasPrefix(adminAddr, "https://") {
 		adminAddr = "http://" + adminAddr
 	}
 
@@ -322,7 +408,16 @@ func parseConnectCommand(input string, playerName string) (*ReplCommand, error)
 	return cmd, nil
 }
 
-func IsUserNameAllowed(name string) bool {
-	re := regexp.MustCompile(`^([[:alpha:]]|_)+$`)
-	return re.MatchString(name)
+// Snapshot command is of the form: snapshot fileName. The file name must not
+// contain a directory, snapshots are always saved in the log dir.
+func parseSnapshotCommand(input string, playerName string) (*ReplCommand, error) {
+	fileName := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(input), "snapshot"))
+
+	if fileName == "" || fileName == "." || fileName == ".." || filepath.Base(fileName) != fileName {
+		return &ReplCommand{}, errors.New("expected a `snapshot <file>` command with a file name without directories")
+	}
+
+	cmd := NewReplCommand(CmdSnapshot, playerName)
+	cmd.ExtraData = fileName
+	return cmd, nil
 }
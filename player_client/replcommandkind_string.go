@@ -16,18 +16,33 @@ func _() {
 	_ = x[CmdTableSummary-5]
 	_ = x[CmdDumpDrawDeck-6]
 	_ = x[CmdShowHand-7]
-	_ = x[CmdDropCard-8]
-	_ = x[CmdDrawCard-9]
-	_ = x[CmdPass-10]
-	_ = x[CmdDrawCardFromPile-11]
-	_ = x[CmdSetWildCardColor-12]
-	_ = x[CmdNoChallenge-13]
-	_ = x[CmdChallenge-14]
+	_ = x[CmdShowCounts-8]
+	_ = x[CmdLogFilter-9]
+	_ = x[CmdPileHistory-10]
+	_ = x[CmdResync-11]
+	_ = x[CmdTurnOrder-12]
+	_ = x[CmdRecap-13]
+	_ = x[CmdToggleHand-14]
+	_ = x[CmdSnapshot-15]
+	_ = x[CmdJumpIn-16]
+	_ = x[CmdHouseRules-17]
+	_ = x[CmdTurnContext-18]
+	_ = x[CmdDropCard-19]
+	_ = x[CmdDrawCard-20]
+	_ = x[CmdDrawAndPlayCard-21]
+	_ = x[CmdForcedDraw-22]
+	_ = x[CmdPass-23]
+	_ = x[CmdUndoDraw-24]
+	_ = x[CmdDrawCardFromPile-25]
+	_ = x[CmdSetWildCardColor-26]
+	_ = x[CmdChooseSwapTarget-27]
+	_ = x[CmdNoChallenge-28]
+	_ = x[CmdChallenge-29]
 }
 
-const _ReplCommandKind_name = "CmdNoneCmdAskUserToPlayCmdDeclareReadyCmdQuitCmdConnectCmdTableSummaryCmdDumpDrawDeckCmdShowHandCmdDropCardCmdDrawCardCmdPassCmdDrawCardFromPileCmdSetWildCardColorCmdNoChallengeCmdChallenge"
+const _ReplCommandKind_name = "CmdNoneCmdAskUserToPlayCmdDeclareReadyCmdQuitCmdConnectCmdTableSummaryCmdDumpDrawDeckCmdShowHandCmdShowCountsCmdLogFilterCmdPileHistoryCmdResyncCmdTurnOrderCmdRecapCmdToggleHandCmdSnapshotCmdJumpInCmdHouseRulesCmdTurnContextCmdDropCardCmdDrawCardCmdDrawAndPlayCardCmdForcedDrawCmdPassCmdUndoDrawCmdDrawCardFromPileCmdSetWildCardColorCmdChooseSwapTargetCmdNoChallengeCmdChallenge"
 
-var _ReplCommandKind_index = [...]uint8{0, 7, 23, 38, 45, 55, 70, 85, 96, 107, 118, 125, 144, 163, 177, 189}
+var _ReplCommandKind_index = [...]uint16{0, 7, 23, 38, 45, 55, 70, 85, 96, 109, 121, 135, 144, 156, 164, 177, 188, 197, 210, 224, 235, 246, 264, 277, 284, 295, 314, 333, 352, 366, 378}
 
 func (i ReplCommandKind) String() string {
 	if i < 0 || i >= ReplCommandKind(len(_ReplCommandKind_index)-1) {
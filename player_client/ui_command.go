@@ -1,8 +1,6 @@
 package client
 
 import (
-	"time"
-
 	"github.com/nrawrx3/uknow"
 )
 
@@ -19,15 +17,28 @@ type UICommand interface {
 type AskUserForDecisionResult struct {
 	AskForOneMoreDecision bool
 	Error                 error
+	RejectionReason       string     // Shown to the user when Error is not nil
+	LegalPlays            uknow.Deck // Playable cards for the next decision, if one is asked for
 }
 
 type UICommandAskUserForDecision struct {
 	// The PlayerClient itself will wait on this channel to receive the command input from user
 	receive             chan<- *ReplCommand
 	decisionResultChan  <-chan AskUserForDecisionResult
-	timeout             time.Duration
 	sender              string
 	challengeablePlayer string
+
+	// Set when no card in the local player's hand can be played, so the UI
+	// can hint that a draw is due.
+	noLegalPlays bool
+
+	// Cards of the local player's hand that can be played, shown by the hint
+	// key.
+	legalPlays uknow.Deck
+
+	// Closed when the turn is taken from the local player by a jump-in or a
+	// redeal. The UI stops asking for decisions then.
+	cancel <-chan struct{}
 }
 
 func (d *UICommandAskUserForDecision) LocalPlayerCanChallenge() bool {
@@ -45,3 +56,18 @@ type UICommandSetServedCards struct {
 }
 
 func (*UICommandSetServedCards) uiCommandDummy() {}
+
+// Tells the UI that a new game has started after the previous one had a winner.
+type UICommandRematch struct {
+	table            *uknow.Table
+	winCountOfPlayer map[string]int
+}
+
+func (*UICommandRematch) uiCommandDummy() {}
+
+// Tells the UI how the connection to the admin is doing.
+type UICommandSetConnectionStatus struct {
+	status ConnectionStatus
+}
+
+func (*UICommandSetConnectionStatus) uiCommandDummy() {}
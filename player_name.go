@@ -0,0 +1,43 @@
+package uknow
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	ReservedNameDeck          = "<deck>"
+	ReservedNamePile          = "<pile>"
+	ReservedNameAdmin         = "<admin>"
+	ReservedNameClient        = "<player_client>"
+	ReservedNameCommandPrompt = "<command_prompt>"
+)
+
+const MaxPlayerNameLength = 16
+
+var reservedNames = []string{
+	ReservedNameDeck,
+	ReservedNamePile,
+	ReservedNameAdmin,
+	ReservedNameClient,
+	ReservedNameCommandPrompt,
+}
+
+// Only names with alphabet and underscore characters are allowed. The name
+// must not be longer than MaxPlayerNameLength and must not match any of the
+// reserved names (ignoring the angle brackets and case). Checked by the client
+// before joining and by the admin when a player joins.
+func IsUserNameAllowed(name string) bool {
+	if len(name) > MaxPlayerNameLength {
+		return false
+	}
+
+	for _, reservedName := range reservedNames {
+		if strings.EqualFold(name, strings.Trim(reservedName, "<>")) {
+			return false
+		}
+	}
+
+	re := regexp.MustCompile(`^([[:alpha:]]|_)+$`)
+	return re.MatchString(name)
+}
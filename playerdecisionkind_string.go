@@ -14,11 +14,14 @@ func _() {
 	_ = x[PlayerDecisionWildCardChooseColor-4]
 	_ = x[PlayerDecisionDoChallenge-5]
 	_ = x[PlayerDecisionDontChallenge-6]
+	_ = x[PlayerDecisionChooseSwapTarget-7]
+	_ = x[PlayerDecisionUndoDraw-8]
+	_ = x[PlayerDecisionJumpIn-9]
 }
 
-const _PlayerDecisionKind_name = "PlayerDecisionPullFromDeckPlayerDecisionPlayHandCardPlayerDecisionPassPlayerDecisionWildCardChooseColorPlayerDecisionDoChallengePlayerDecisionDontChallenge"
+const _PlayerDecisionKind_name = "PlayerDecisionPullFromDeckPlayerDecisionPlayHandCardPlayerDecisionPassPlayerDecisionWildCardChooseColorPlayerDecisionDoChallengePlayerDecisionDontChallengePlayerDecisionChooseSwapTargetPlayerDecisionUndoDrawPlayerDecisionJumpIn"
 
-var _PlayerDecisionKind_index = [...]uint8{0, 26, 52, 70, 103, 128, 155}
+var _PlayerDecisionKind_index = [...]uint8{0, 26, 52, 70, 103, 128, 155, 185, 207, 227}
 
 func (i PlayerDecisionKind) String() string {
 	i -= 1
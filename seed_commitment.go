@@ -0,0 +1,39 @@
+package uknow
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	mathrand "math/rand"
+)
+
+// Returns a random seed for the shuffle, read from crypto/rand.
+func NewShuffleSeed() (int64, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(b[:])), nil
+}
+
+// Hex encoded SHA-256 of the seed as 8 big-endian bytes. Publishing it before
+// shuffling commits to the seed without revealing it.
+func SeedCommitment(seed int64) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(seed))
+	sum := sha256.Sum256(b[:])
+	return hex.EncodeToString(sum[:])
+}
+
+// Reports whether the revealed seed is the one committed to.
+func VerifySeedCommitment(seed int64, commitment string) bool {
+	return SeedCommitment(seed) == commitment
+}
+
+// Random source of a table shuffled with the given seed. A revealed seed deals
+// the same hands again when the same players are seated in the same order on
+// a table with the same draw deck.
+func NewSeededRand(seed int64) *mathrand.Rand {
+	return mathrand.New(mathrand.NewSource(seed))
+}
@@ -0,0 +1,82 @@
+package uknow
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Counters of what a player did over a game, for post-game analysis. Skips
+// are dealt with skip and draw two cards. A wild draw 4 challenge is won by
+// the challenger if it succeeds, otherwise by the wild draw 4 player, and lost
+// by the other one.
+type PlayerStats struct {
+	CardsPlayed    int `json:"cards_played"`
+	CardsDrawn     int `json:"cards_drawn"` // Including penalties, but not the served hand or draws taken back with undo
+	WildsPlayed    int `json:"wilds_played"`
+	SkipsDealt     int `json:"skips_dealt"`
+	SkipsReceived  int `json:"skips_received"`
+	ChallengesWon  int `json:"challenges_won"`
+	ChallengesLost int `json:"challenges_lost"`
+}
+
+func (s *PlayerStats) String() string {
+	return fmt.Sprintf("played %d (%d wild), drew %d, skips dealt %d, skips received %d, challenges won %d, challenges lost %d",
+		s.CardsPlayed, s.WildsPlayed, s.CardsDrawn, s.SkipsDealt, s.SkipsReceived, s.ChallengesWon, s.ChallengesLost)
+}
+
+// Returns a copy of the stats of every player who has done anything counted
+// by PlayerStats this game.
+func (t *Table) Stats() map[string]PlayerStats {
+	stats := make(map[string]PlayerStats, len(t.StatsOfPlayer))
+	for playerName, playerStats := range t.StatsOfPlayer {
+		stats[playerName] = playerStats
+	}
+	return stats
+}
+
+func (t *Table) updateStats(playerName string, update func(stats *PlayerStats)) {
+	if t.StatsOfPlayer == nil {
+		t.StatsOfPlayer = make(map[string]PlayerStats)
+	}
+	stats := t.StatsOfPlayer[playerName]
+	update(&stats)
+	t.StatsOfPlayer[playerName] = stats
+}
+
+func (t *Table) countSkip(skippingPlayer, skippedPlayer string) {
+	t.updateStats(skippingPlayer, func(stats *PlayerStats) { stats.SkipsDealt++ })
+	t.updateStats(skippedPlayer, func(stats *PlayerStats) { stats.SkipsReceived++ })
+}
+
+func (t *Table) countChallenge(winner, loser string) {
+	t.updateStats(winner, func(stats *PlayerStats) { stats.ChallengesWon++ })
+	t.updateStats(loser, func(stats *PlayerStats) { stats.ChallengesLost++ })
+}
+
+func statsEqual(a, b map[string]PlayerStats) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for playerName, stats := range a {
+		if otherStats, ok := b[playerName]; !ok || stats != otherStats {
+			return false
+		}
+	}
+	return true
+}
+
+// One line per player of the stats, sorted by player name.
+func PlayerStatsLines(stats map[string]PlayerStats) []string {
+	playerNames := make([]string, 0, len(stats))
+	for playerName := range stats {
+		playerNames = append(playerNames, playerName)
+	}
+	sort.Strings(playerNames)
+
+	lines := make([]string, 0, len(playerNames))
+	for _, playerName := range playerNames {
+		playerStats := stats[playerName]
+		lines = append(lines, fmt.Sprintf("%s: %s", playerName, playerStats.String()))
+	}
+	return lines
+}
@@ -0,0 +1,256 @@
+package test
+
+import (
+	"context"
+	"crypto/x509"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/admin"
+	"github.com/nrawrx3/uknow/internal/utils"
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+// Admin clock whose After only fires once released, so that the game can be
+// held right after the cards are served. Timers are real.
+type heldClock struct {
+	released chan time.Time
+}
+
+func newHeldClock() *heldClock {
+	return &heldClock{released: make(chan time.Time)}
+}
+
+func (c *heldClock) Now() time.Time                         { return time.Now() }
+func (c *heldClock) NewTimer(d time.Duration) admin.Timer   { return realTimer{time.NewTimer(d)} }
+func (c *heldClock) After(d time.Duration) <-chan time.Time { return c.released }
+
+func (c *heldClock) release() {
+	close(c.released)
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) Chan() <-chan time.Time { return t.C }
+
+type testGamePlayer struct {
+	client          *client.PlayerClient
+	replCommandChan chan *client.ReplCommand
+}
+
+// An admin served with httptest and players connected to it through the SSE
+// API, each with a DummyClientUI.
+type testGame struct {
+	t       *testing.T
+	admin   *admin.Admin
+	server  *httptest.Server
+	clock   *heldClock
+	players map[string]*testGamePlayer
+
+	// Room of the game if the admin is hosted by an admin.Server.
+	roomCode string
+
+	// Connect the players over the admin's WebSocket endpoint instead of SSE.
+	webSocket bool
+}
+
+func newTestGame(t *testing.T) *testGame {
+	return newTestGameServedWith(t, httptest.NewServer)
+}
+
+// Same as newTestGame, but the admin is served with https using httptest's
+// self-signed certificate, which the players trust.
+func newTLSTestGame(t *testing.T) *testGame {
+	return newTestGameServedWith(t, httptest.NewTLSServer)
+}
+
+func newTestGameServedWith(t *testing.T, newServer func(http.Handler) *httptest.Server) *testGame {
+	clock := newHeldClock()
+	a := newTestAdmin(t, clock, "")
+
+	return &testGame{
+		t:       t,
+		admin:   a,
+		server:  newServer(a.RunWithoutServer()),
+		clock:   clock,
+		players: make(map[string]*testGamePlayer),
+	}
+}
+
+func newTestAdmin(t *testing.T, clock *heldClock, roomCode string) *admin.Admin {
+	noPause := 0
+	return admin.NewAdmin(&admin.ConfigNewAdmin{
+		Table:    uknow.NewAdminTable(log.Default()),
+		Clock:    clock,
+		RoomCode: roomCode,
+	}, &admin.AdminUserConfig{
+		StartingHandCount:    uknow.DefaultStartingHandCount,
+		DeckCount:            1,
+		LogDir:               t.TempDir(),
+		PauseMsecsAfterServe: &noPause,
+		WebSocket:            true,
+	})
+}
+
+// Lets the game go on if it's held, then shuts down the admin, ending the
+// event streams of the players.
+func (g *testGame) close() {
+	select {
+	case <-g.clock.released:
+	default:
+		g.clock.release()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := g.admin.Shutdown(ctx); err != nil {
+		g.t.Logf("failed to shut down admin: %v", err)
+	}
+	g.server.Close()
+}
+
+// Creates a player client and connects it to the admin.
+func (g *testGame) connect(playerName string, autoReady bool, autoReadyMinPlayers int) {
+	adminAddr, err := utils.ResolveTCPAddress(g.server.URL)
+	if err != nil {
+		g.t.Fatal(err)
+	}
+
+	var adminRootCAs *x509.CertPool
+	if g.server.TLS != nil {
+		adminRootCAs = x509.NewCertPool()
+		adminRootCAs.AddCert(g.server.Certificate())
+	}
+
+	commChannels := client.MakeCommChannels()
+	var dummyUI client.DummyClientUI
+	dummyUI.Init(commChannels.GeneralUICommandChan, commChannels.AskUIForUserTurnChan, commChannels.LogWindowChan)
+	go dummyUI.RunUICommandDumper()
+	go dummyUI.RunAskUIDumper()
+	go dummyUI.RunWindowLogger()
+
+	c := client.NewPlayerClient(&client.ConfigNewPlayerClient{
+		ClientChannels: client.ClientChannels{
+			GeneralUICommandPushChan:       commChannels.GeneralUICommandChan,
+			AskUserForDecisionPushChan:     commChannels.AskUIForUserTurnChan,
+			NonDecisionReplCommandPullChan: commChannels.NonDecisionReplCommandsChan,
+			LogWindowPushChan:              commChannels.LogWindowChan,
+			GameEventPushChan:              drainGameEvents(),
+		},
+		Table:               uknow.NewTable(playerName, log.Default()),
+		DefaultAdminAddr:    adminAddr,
+		LogDir:              g.t.TempDir(),
+		AutoReady:           autoReady,
+		AutoReadyMinPlayers: autoReadyMinPlayers,
+		AdminRootCAs:        adminRootCAs,
+		RoomCode:            g.roomCode,
+		WebSocket:           g.webSocket,
+	})
+	go c.RunGeneralCommandHandler()
+
+	g.players[playerName] = &testGamePlayer{client: c, replCommandChan: commChannels.NonDecisionReplCommandsChan}
+	commChannels.NonDecisionReplCommandsChan <- &client.ReplCommand{Kind: client.CmdConnect}
+}
+
+// Waits until every connected player's client is in the given state.
+func (g *testGame) waitForAllPlayers(state client.PlayerClientState, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for playerName, player := range g.players {
+		for player.client.State() != state {
+			if time.Now().After(deadline) {
+				g.t.Fatalf("expected %s to reach state %s, still in %s", playerName, state, player.client.State())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestAdminAndClientsServeCards(t *testing.T) {
+	g := newTestGame(t)
+	defer g.close()
+
+	g.connect("alice", false, 0)
+	g.waitForAllPlayers(client.WaitingForAdminToServeCards, 5*time.Second)
+	g.connect("bob", false, 0)
+	g.waitForAllPlayers(client.WaitingForAdminToServeCards, 5*time.Second)
+
+	// carol declares ready once she sees all three players.
+	g.connect("carol", true, 3)
+	g.waitForAllPlayers(client.WaitingForAdminToChoosePlayer, 10*time.Second)
+
+	wantHandCounts := map[string]int{
+		"alice": uknow.DefaultStartingHandCount,
+		"bob":   uknow.DefaultStartingHandCount,
+		"carol": uknow.DefaultStartingHandCount,
+	}
+	for playerName, player := range g.players {
+		handCounts := player.client.HandCounts()
+		if len(handCounts) != len(wantHandCounts) {
+			t.Fatalf("%s: expected hand counts %v, got %v", playerName, wantHandCounts, handCounts)
+		}
+		for handOwner, count := range handCounts {
+			// An opening Draw Two adds to the first player's hand.
+			if count != wantHandCounts[handOwner] && count != wantHandCounts[handOwner]+2 {
+				t.Logf("%s: expected %s to hold %d cards, got %d", playerName, handOwner, wantHandCounts[handOwner], count)
+				t.Fail()
+			}
+		}
+	}
+
+	aliceHandCounts := g.players["alice"].client.HandCounts()
+	for playerName, player := range g.players {
+		for handOwner, count := range player.client.HandCounts() {
+			if count != aliceHandCounts[handOwner] {
+				t.Logf("%s: expected the same hand counts as alice %v, got %v", playerName, aliceHandCounts, player.client.HandCounts())
+				t.Fail()
+				break
+			}
+		}
+	}
+}
+
+func TestHouseRulesPropagateToClients(t *testing.T) {
+	g := newTestGame(t)
+	defer g.close()
+
+	wantRules := uknow.HouseRules{SevenZero: true, DrawnActionCardHasNoEffect: true}
+	if err := g.admin.SetHouseRules(wantRules); err != nil {
+		t.Fatal(err)
+	}
+
+	g.connect("alice", false, 0)
+	g.waitForAllPlayers(client.WaitingForAdminToServeCards, 5*time.Second)
+	g.connect("bob", true, 2)
+	g.waitForAllPlayers(client.WaitingForAdminToChoosePlayer, 10*time.Second)
+
+	for playerName, player := range g.players {
+		if houseRules := player.client.HouseRules(); houseRules != wantRules {
+			t.Logf("%s: expected house rules %s, got %s", playerName, wantRules.String(), houseRules.String())
+			t.Fail()
+		}
+	}
+}
+
+func TestAdminServesCardsOverTLS(t *testing.T) {
+	g := newTLSTestGame(t)
+	defer g.close()
+
+	g.connect("alice", false, 0)
+	g.waitForAllPlayers(client.WaitingForAdminToServeCards, 5*time.Second)
+	g.connect("bob", true, 2)
+	g.waitForAllPlayers(client.WaitingForAdminToChoosePlayer, 10*time.Second)
+
+	for playerName, player := range g.players {
+		handCounts := player.client.HandCounts()
+		if handCounts[playerName] < uknow.DefaultStartingHandCount {
+			t.Logf("%s: expected the served cards over https, got hand counts %v", playerName, handCounts)
+			t.Fail()
+		}
+	}
+}
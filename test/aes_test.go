@@ -1,17 +1,21 @@
 package test
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"testing"
 
 	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
 )
 
-func TestEncryptWithAES(t *testing.T) {
-	hexKey := "55cfb2bd7e7803532bfcc3ca9f08c3e601e68b26d98fd4119dacace2ab668ce3"
+const testAESHexKey = "55cfb2bd7e7803532bfcc3ca9f08c3e601e68b26d98fd4119dacace2ab668ce3"
 
+func TestEncryptWithAES(t *testing.T) {
 	plaintext := []byte("Scar tissue that I wished you saw")
 
-	aesCipher, err := uknow.NewAESCipher(hexKey)
+	aesCipher, err := uknow.NewAESCipher(testAESHexKey)
 	if err != nil {
 		t.Log(err)
 		t.Fail()
@@ -35,3 +39,118 @@ func TestEncryptWithAES(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestTamperedMessageFailsDecryption(t *testing.T) {
+	aesCipher, err := uknow.NewAESCipher(testAESHexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptedBytes, err := io.ReadAll(aesCipher.MustEncryptJSON(&messages.SetReadyMessage{ShufflerName: "alice"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Flip a byte of the nonce, of the ciphertext and of the tag in turn.
+	for _, i := range []int{0, len(encryptedBytes) / 2, len(encryptedBytes) - 1} {
+		tampered := append([]byte(nil), encryptedBytes...)
+		tampered[i] ^= 0x01
+
+		if _, err := aesCipher.Decrypt(tampered); !errors.Is(err, uknow.ErrMessageAuthFailed) {
+			t.Logf("byte %d: expected ErrMessageAuthFailed, got %v", i, err)
+			t.Fail()
+		}
+
+		var setReadyMessage messages.SetReadyMessage
+		err := messages.DecryptAndDecodeJSON(&setReadyMessage, bytes.NewReader(tampered), aesCipher)
+		if !errors.Is(err, uknow.ErrMessageAuthFailed) || setReadyMessage.ShufflerName != "" {
+			t.Logf("byte %d: expected decoding to fail without touching the message, got %v, %+v", i, err, setReadyMessage)
+			t.Fail()
+		}
+	}
+
+	if _, err := aesCipher.Decrypt(encryptedBytes[:4]); !errors.Is(err, uknow.ErrCiphertextTooShort) {
+		t.Logf("expected ErrCiphertextTooShort for a truncated message, got %v", err)
+		t.Fail()
+	}
+}
+
+const (
+	testAESPassphrase = "correct horse battery staple"
+	testAESSalt       = "uknow-game-night"
+
+	// scrypt of the passphrase and salt above, with N=32768, r=8, p=1.
+	testAESPassphraseHexKey = "1984df6c42c7ba477b56068fb89749c659ec4c28f77013d6d990d59dbc61fc1e"
+)
+
+func TestCiphersFromPassphraseMatch(t *testing.T) {
+	// The admin reads the passphrase from its config, the client is given it
+	// directly.
+	adminCipher, err := uknow.NewAESCipherFromConfig("", testAESPassphrase, testAESSalt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCipher, err := uknow.NewAESCipherFromPassphrase(testAESPassphrase, []byte(testAESSalt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hexKeyCipher, err := uknow.NewAESCipher(testAESPassphraseHexKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs := []struct {
+		name      string
+		encrypter *uknow.AESCipher
+		decrypter *uknow.AESCipher
+	}{
+		{"admin to client", adminCipher, clientCipher},
+		{"client to admin", clientCipher, adminCipher},
+		{"client to known key", clientCipher, hexKeyCipher},
+	}
+
+	for _, pair := range pairs {
+		var b bytes.Buffer
+		if err := messages.EncodeJSONAndEncrypt(&messages.SetReadyMessage{ShufflerName: "alice"}, &b, pair.encrypter); err != nil {
+			t.Fatalf("%s: %v", pair.name, err)
+		}
+
+		var setReadyMessage messages.SetReadyMessage
+		if err := messages.DecryptAndDecodeJSON(&setReadyMessage, &b, pair.decrypter); err != nil || setReadyMessage.ShufflerName != "alice" {
+			t.Errorf("%s: expected the message to round-trip, got %v, %+v", pair.name, err, setReadyMessage)
+		}
+	}
+
+	otherSaltCipher, err := uknow.NewAESCipherFromPassphrase(testAESPassphrase, []byte(testAESSalt+"!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedBytes, err := clientCipher.Encrypt([]byte("Scar tissue that I wished you saw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := otherSaltCipher.Decrypt(encryptedBytes); !errors.Is(err, uknow.ErrMessageAuthFailed) {
+		t.Errorf("expected a cipher of another salt to fail with ErrMessageAuthFailed, got %v", err)
+	}
+}
+
+func TestInvalidAESConfigs(t *testing.T) {
+	cases := []struct {
+		hexKey, passphrase, salt string
+		wantErr                  error
+	}{
+		{"", "", "", uknow.ErrAESKeyOrPassphrase},
+		{testAESHexKey, testAESPassphrase, testAESSalt, uknow.ErrAESKeyOrPassphrase},
+		{"", testAESPassphrase, "short", uknow.ErrAESSaltTooShort},
+	}
+
+	for _, c := range cases {
+		if _, err := uknow.NewAESCipherFromConfig(c.hexKey, c.passphrase, c.salt); !errors.Is(err, c.wantErr) {
+			t.Errorf("key %q, passphrase %q, salt %q: expected %v, got %v", c.hexKey, c.passphrase, c.salt, c.wantErr, err)
+		}
+	}
+
+	if _, err := uknow.NewAESCipherFromPassphrase("", []byte(testAESSalt)); !errors.Is(err, uknow.ErrEmptyAESPassphrase) {
+		t.Errorf("expected ErrEmptyAESPassphrase, got %v", err)
+	}
+}
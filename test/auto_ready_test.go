@@ -0,0 +1,101 @@
+package test
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+func writeServerEvent(t *testing.T, w http.ResponseWriter, event messages.ServerEvent) {
+	if err := utils.WriteJsonWithNewline(w, messages.ServerEventMessage{Event: event, Type: event.EventType()}); err != nil {
+		t.Errorf("failed to write %T: %v", event, err)
+	}
+	w.(http.Flusher).Flush()
+}
+
+func TestAutoReadySendsSetReadyOnce(t *testing.T) {
+	var setReadyCount int32
+	setReadyReceived := make(chan struct{}, 4)
+	carolAcked := make(chan struct{}, 4)
+	done := make(chan struct{})
+
+	// Fake admin that lists alice as already joined, then announces carol
+	// after bob declares ready.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/player", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		writeServerEvent(t, w, messages.ExistingPlayersListEvent{PlayerNames: []string{"alice"}})
+
+		select {
+		case <-setReadyReceived:
+			writeServerEvent(t, w, messages.PlayerJoinedEvent{PlayerName: "carol"})
+		case <-done:
+			return
+		}
+		<-done
+	})
+	mux.HandleFunc("/ack_player_added", func(w http.ResponseWriter, r *http.Request) {
+		var ackMsg messages.AckNewPlayerAddedMessage
+		if err := messages.DecryptAndDecodeJSON(&ackMsg, r.Body, nil); err == nil && ackMsg.NewPlayer == "carol" {
+			carolAcked <- struct{}{}
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/set_ready", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&setReadyCount, 1)
+		setReadyReceived <- struct{}{}
+		w.WriteHeader(http.StatusOK)
+	})
+	fakeAdmin := httptest.NewServer(mux)
+	defer fakeAdmin.Close()
+	defer close(done)
+
+	adminAddr, err := utils.ResolveTCPAddress(strings.TrimPrefix(fakeAdmin.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logWindowChan := make(chan string, 64)
+	go func() {
+		for range logWindowChan {
+		}
+	}()
+	replCommandChan := make(chan *client.ReplCommand)
+
+	c := client.NewPlayerClient(&client.ConfigNewPlayerClient{
+		ClientChannels: client.ClientChannels{
+			NonDecisionReplCommandPullChan: replCommandChan,
+			LogWindowPushChan:              logWindowChan,
+		},
+		Table:            uknow.NewTable("bob", log.Default()),
+		DefaultAdminAddr: adminAddr,
+		LogDir:           t.TempDir(),
+		AutoReady:        true,
+	})
+	go c.RunGeneralCommandHandler()
+
+	replCommandChan <- &client.ReplCommand{Kind: client.CmdConnect}
+
+	select {
+	case <-carolAcked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected bob to declare ready and then ack carol, set_ready count: %d", atomic.LoadInt32(&setReadyCount))
+	}
+
+	// Give a second auto-ready, if any, time to arrive.
+	time.Sleep(200 * time.Millisecond)
+
+	if count := atomic.LoadInt32(&setReadyCount); count != 1 {
+		t.Logf("expected exactly one set_ready, got %d", count)
+		t.Fail()
+	}
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+var (
+	redSkip  = uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed}
+	blueSkip = uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorBlue}
+	blueFive = uknow.Card{Number: 5, Color: uknow.ColorBlue}
+)
+
+// Table on which alice has just played a red skip on a red 5, skipping bob.
+// carol, who plays next, holds a blue 5 and a blue skip.
+func newAfterSkipTable(t *testing.T, houseRules uknow.HouseRules) *uknow.Table {
+	table := uknow.NewAdminTable(log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.AddPlayer("carol")
+	table.HouseRules = houseRules
+
+	table.HandOfPlayer["alice"] = uknow.Deck{redSkip, {Number: 1, Color: uknow.ColorGreen}}
+	table.HandOfPlayer["bob"] = uknow.Deck{{Number: 2, Color: uknow.ColorGreen}}
+	table.HandOfPlayer["carol"] = uknow.Deck{blueFive, blueSkip}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+	table.DrawDeck = uknow.Deck{{Number: 8, Color: uknow.ColorGreen}}
+
+	table.PlayerOfNextTurn = "alice"
+	table.TableState = uknow.StartOfTurn
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	_, err := table.EvalPlayerDecision("alice", uknow.PlayerDecision{
+		Kind:       uknow.PlayerDecisionPlayHandCard,
+		ResultCard: redSkip,
+	}, gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.PlayerOfNextTurn != "carol" {
+		t.Fatalf("expected carol to play after the skip, got %s", table.PlayerOfNextTurn)
+	}
+	return table
+}
+
+func TestCardMatchAgreesAfterPlayedActionCard(t *testing.T) {
+	for _, houseRules := range []uknow.HouseRules{{}, {MatchDiscardTopNumber: true}} {
+		table := newAfterSkipTable(t, houseRules)
+
+		if number := table.NumberToMatch(); number != uknow.NumberSkip {
+			t.Errorf("%+v: expected skip as the number to match, got %s", houseRules, number.String())
+		}
+		if legalPlays := table.LegalPlays("carol"); !legalPlays.Equal(uknow.Deck{blueSkip}) {
+			t.Errorf("%+v: expected only the blue skip to be legal, got %s", houseRules, legalPlays)
+		}
+	}
+}
+
+// A table whose required number is still the one of the turn before the skip,
+// as on a table read from a hand file, plays differently with the two rules.
+func TestCardMatchDivergesAfterPlayedActionCard(t *testing.T) {
+	cases := []struct {
+		houseRules   uknow.HouseRules
+		legalCard    uknow.Card
+		illegalCard  uknow.Card
+		wantExpected uknow.Number
+	}{
+		{uknow.HouseRules{}, blueFive, blueSkip, 5},
+		{uknow.HouseRules{MatchDiscardTopNumber: true}, blueSkip, blueFive, uknow.NumberSkip},
+	}
+
+	for _, c := range cases {
+		table := newAfterSkipTable(t, c.houseRules)
+		table.RequiredNumberOfCurrentTurn = 5
+
+		if legalPlays := table.LegalPlays("carol"); !legalPlays.Equal(uknow.Deck{c.legalCard}) {
+			t.Errorf("%+v: expected only %s to be legal, got %s", c.houseRules, c.legalCard.String(), legalPlays)
+		}
+
+		gameEventChan := drainGameEvents()
+		_, err := table.EvalPlayerDecision("carol", uknow.PlayerDecision{
+			Kind:       uknow.PlayerDecisionPlayHandCard,
+			ResultCard: c.illegalCard,
+		}, gameEventChan)
+		close(gameEventChan)
+
+		var illegalPlay *uknow.IllegalPlayError
+		if !errors.As(err, &illegalPlay) {
+			t.Errorf("%+v: expected playing %s to be illegal, got %v", c.houseRules, c.illegalCard.String(), err)
+			continue
+		}
+		if illegalPlay.ExpectedNumber != c.wantExpected {
+			t.Errorf("%+v: expected the error to name %s as the number to match, got %s", c.houseRules, c.wantExpected.String(), illegalPlay.ExpectedNumber.String())
+		}
+	}
+}
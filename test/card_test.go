@@ -0,0 +1,475 @@
+package test
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func allColors() []uknow.Color {
+	return []uknow.Color{uknow.ColorWild, uknow.ColorRed, uknow.ColorGreen, uknow.ColorBlue, uknow.ColorYellow}
+}
+
+func allNumbers() []uknow.Number {
+	numbers := make([]uknow.Number, 0, int(uknow.NumberWildDrawFour)+1)
+	for n := 0; n <= int(uknow.NumberWildDrawFour); n++ {
+		numbers = append(numbers, uknow.Number(n))
+	}
+	return numbers
+}
+
+func TestCardIsPlayableOn(t *testing.T) {
+	red7 := uknow.Card{Number: 7, Color: uknow.ColorRed}
+	blueSkip := uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorBlue}
+	wild := uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}
+	wild4 := uknow.Card{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild}
+
+	cases := []struct {
+		card           uknow.Card
+		requiredColor  uknow.Color
+		requiredNumber uknow.Number
+		want           bool
+	}{
+		{red7, uknow.ColorRed, 3, true},
+		{red7, uknow.ColorBlue, 7, true},
+		{red7, uknow.ColorBlue, 3, false},
+		{red7, uknow.ColorGreen, uknow.NumberSkip, false},
+		{blueSkip, uknow.ColorRed, uknow.NumberSkip, true},
+		{blueSkip, uknow.ColorBlue, 0, true},
+		{blueSkip, uknow.ColorYellow, uknow.NumberReverse, false},
+		{wild, uknow.ColorRed, 3, true},
+		{wild4, uknow.ColorGreen, uknow.NumberDrawTwo, true},
+	}
+
+	for _, c := range cases {
+		if got := c.card.IsPlayableOn(c.requiredColor, c.requiredNumber); got != c.want {
+			t.Logf("%s.IsPlayableOn(%s, %s) = %v, want %v", c.card.String(), c.requiredColor.String(), c.requiredNumber.String(), got, c.want)
+			t.Fail()
+		}
+	}
+}
+
+func TestCardIsPlayableOnAllCombinations(t *testing.T) {
+	for _, card := range uknow.NewFullDeck() {
+		for _, requiredColor := range allColors() {
+			for _, requiredNumber := range allNumbers() {
+				want := card.IsWild() || card.Color == requiredColor || card.Number == requiredNumber
+				if got := card.IsPlayableOn(requiredColor, requiredNumber); got != want {
+					t.Logf("%s.IsPlayableOn(%s, %s) = %v, want %v", card.String(), requiredColor.String(), requiredNumber.String(), got, want)
+					t.Fail()
+				}
+			}
+		}
+	}
+}
+
+func TestCardPoints(t *testing.T) {
+	for number := uknow.Number(0); number <= 9; number++ {
+		card := uknow.Card{Number: number, Color: uknow.ColorGreen}
+		if card.Points() != int(number) {
+			t.Logf("expected %s to be worth %d, got %d", card.String(), number, card.Points())
+			t.Fail()
+		}
+	}
+
+	pointsOfCard := map[uknow.Card]int{
+		{Number: uknow.NumberSkip, Color: uknow.ColorRed}:          20,
+		{Number: uknow.NumberReverse, Color: uknow.ColorBlue}:      20,
+		{Number: uknow.NumberDrawTwo, Color: uknow.ColorYellow}:    20,
+		{Number: uknow.NumberWild, Color: uknow.ColorWild}:         50,
+		{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild}: 50,
+	}
+
+	for card, points := range pointsOfCard {
+		if card.Points() != points {
+			t.Logf("expected %s to be worth %d, got %d", card.String(), points, card.Points())
+			t.Fail()
+		}
+	}
+}
+
+func TestTableHandPoints(t *testing.T) {
+	table := uknow.NewAdminTable(log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.AddPlayer("carol")
+
+	table.HandOfPlayer["alice"] = uknow.Deck{}
+	table.HandOfPlayer["bob"] = uknow.Deck{
+		{Number: 7, Color: uknow.ColorRed},
+		{Number: uknow.NumberSkip, Color: uknow.ColorGreen},
+	}
+	table.HandOfPlayer["carol"] = uknow.Deck{
+		{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild},
+		{Number: 0, Color: uknow.ColorBlue},
+	}
+
+	if table.HandPoints("bob") != 27 || table.HandPoints("carol") != 50 || table.HandPoints("alice") != 0 {
+		t.Logf("unexpected hand points, bob: %d, carol: %d, alice: %d", table.HandPoints("bob"), table.HandPoints("carol"), table.HandPoints("alice"))
+		t.Fail()
+	}
+
+	if table.TotalOpponentPoints("alice") != 77 {
+		t.Logf("expected alice to score 77, got %d", table.TotalOpponentPoints("alice"))
+		t.Fail()
+	}
+
+	// Pure: computing points doesn't change the hands.
+	if table.HandOfPlayer["bob"].Len() != 2 || table.HandOfPlayer["carol"].Len() != 2 {
+		t.Log("computing points changed the hands")
+		t.Fail()
+	}
+}
+
+func TestNewFullDeckN(t *testing.T) {
+	countOfCard := func(deck uknow.Deck) map[uknow.Card]int {
+		count := make(map[uknow.Card]int)
+		for _, card := range deck {
+			count[card]++
+		}
+		return count
+	}
+
+	single := countOfCard(uknow.NewFullDeck())
+	if uknow.NewFullDeck().Len() != 108 {
+		t.Fatalf("expected 108 cards in a full deck, got %d", uknow.NewFullDeck().Len())
+	}
+
+	for _, n := range []int{1, 2, uknow.MaxDeckCount} {
+		deck := uknow.NewFullDeckN(n)
+		if deck.Len() != 108*n {
+			t.Logf("n = %d: expected %d cards, got %d", n, 108*n, deck.Len())
+			t.Fail()
+		}
+
+		count := countOfCard(deck)
+		if len(count) != len(single) {
+			t.Logf("n = %d: expected %d distinct cards, got %d", n, len(single), len(count))
+			t.Fail()
+		}
+		for card, singleCount := range single {
+			if count[card] != n*singleCount {
+				t.Logf("n = %d: expected %d of %s, got %d", n, n*singleCount, card.String(), count[card])
+				t.Fail()
+			}
+		}
+	}
+
+	if uknow.NewFullDeckN(0).Len() != 108 {
+		t.Log("expected n = 0 to give a single deck")
+		t.Fail()
+	}
+}
+
+func TestDeckHistogram(t *testing.T) {
+	hand := uknow.Deck{
+		{Number: 1, Color: uknow.ColorRed},
+		{Number: 1, Color: uknow.ColorGreen},
+		{Number: 5, Color: uknow.ColorGreen},
+		{Number: uknow.NumberSkip, Color: uknow.ColorGreen},
+		{Number: uknow.NumberWild, Color: uknow.ColorWild},
+	}
+
+	countOfColor, countOfNumber := hand.Histogram()
+
+	wantCountOfColor := map[uknow.Color]int{uknow.ColorRed: 1, uknow.ColorGreen: 3, uknow.ColorWild: 1}
+	if len(countOfColor) != len(wantCountOfColor) {
+		t.Fatalf("expected color counts %v, got %v", wantCountOfColor, countOfColor)
+	}
+	for color, count := range wantCountOfColor {
+		if countOfColor[color] != count {
+			t.Logf("expected %d cards of color %s, got %d", count, color.String(), countOfColor[color])
+			t.Fail()
+		}
+	}
+
+	wantCountOfNumber := map[uknow.Number]int{1: 2, 5: 1, uknow.NumberSkip: 1, uknow.NumberWild: 1}
+	if len(countOfNumber) != len(wantCountOfNumber) {
+		t.Fatalf("expected number counts %v, got %v", wantCountOfNumber, countOfNumber)
+	}
+	for number, count := range wantCountOfNumber {
+		if countOfNumber[number] != count {
+			t.Logf("expected %d cards of number %s, got %d", count, number.String(), countOfNumber[number])
+			t.Fail()
+		}
+	}
+}
+
+func TestDeckRemoveCard(t *testing.T) {
+	deck := uknow.Deck{
+		{Number: 1, Color: uknow.ColorRed},
+		{Number: 2, Color: uknow.ColorGreen},
+		{Number: 3, Color: uknow.ColorBlue},
+	}
+	deckBefore := deck.String()
+
+	testCases := []struct {
+		index int
+		want  string
+	}{
+		{0, "[2 of green|3 of blue]"},
+		{1, "[1 of red|3 of blue]"},
+		{2, "[1 of red|2 of green]"},
+	}
+	for _, tc := range testCases {
+		remaining, err := deck.RemoveCard(tc.index)
+		if err != nil {
+			t.Fatalf("index %d: %v", tc.index, err)
+		}
+		if remaining.String() != tc.want {
+			t.Logf("index %d: expected %s, got %s", tc.index, tc.want, remaining)
+			t.Fail()
+		}
+	}
+
+	if deck.String() != deckBefore {
+		t.Logf("expected the deck to be left as is, got %s", deck)
+		t.Fail()
+	}
+
+	for _, index := range []int{-1, 3} {
+		if _, err := deck.RemoveCard(index); !errors.Is(err, uknow.ErrCardIndexOutOfRange) {
+			t.Logf("index %d: expected ErrCardIndexOutOfRange, got %v", index, err)
+			t.Fail()
+		}
+	}
+
+	if _, err := uknow.NewEmptyDeck().RemoveCard(0); !errors.Is(err, uknow.ErrCardIndexOutOfRange) {
+		t.Logf("expected removing from an empty deck to fail, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestDeckPeek(t *testing.T) {
+	deck := uknow.Deck{
+		{Number: 1, Color: uknow.ColorRed},
+		{Number: 2, Color: uknow.ColorGreen},
+		{Number: 3, Color: uknow.ColorBlue},
+	}
+	deckBefore := deck.String()
+
+	testCases := []struct {
+		n    int
+		want string
+	}{
+		{0, "[]"},
+		{1, "[3 of blue]"},
+		{2, "[3 of blue|2 of green]"},
+		{3, "[3 of blue|2 of green|1 of red]"},
+		{5, "[3 of blue|2 of green|1 of red]"},
+		{-1, "[]"},
+	}
+	for _, tc := range testCases {
+		if topCards := deck.Peek(tc.n); topCards.String() != tc.want {
+			t.Logf("n %d: expected %s, got %s", tc.n, tc.want, topCards)
+			t.Fail()
+		}
+	}
+
+	// Changing the peeked cards doesn't change the deck.
+	topCards := deck.Peek(1)
+	topCards[0] = uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}
+	if deck.String() != deckBefore {
+		t.Logf("expected the deck to be left as is, got %s", deck)
+		t.Fail()
+	}
+}
+
+func TestPrintDiscardPileHistory(t *testing.T) {
+	table := uknow.NewTable("alice", log.Default())
+	table.DiscardedPile = uknow.Deck{
+		{Number: 1, Color: uknow.ColorRed},
+		{Number: 2, Color: uknow.ColorRed},
+		{Number: 2, Color: uknow.ColorBlue},
+		{Number: uknow.NumberSkip, Color: uknow.ColorBlue},
+		{Number: uknow.NumberWild, Color: uknow.ColorWild},
+	}
+
+	var sb strings.Builder
+	table.PrintDiscardPileHistory(&sb, 2)
+
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	wantPrefixes := []string{"01-02:", "03-04:", "05-05:"}
+	if len(lines) != len(wantPrefixes) {
+		t.Fatalf("expected %d lines, got %q", len(wantPrefixes), sb.String())
+	}
+
+	for i, line := range lines {
+		if !strings.HasPrefix(line, wantPrefixes[i]) {
+			t.Logf("expected line %d to start with %s, got %q", i, wantPrefixes[i], line)
+			t.Fail()
+		}
+	}
+
+	// Top card first.
+	wildCard := uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}
+	bottomCard := uknow.Card{Number: 1, Color: uknow.ColorRed}
+	if !strings.Contains(lines[0], wildCard.SymbolString()) || !strings.Contains(lines[2], bottomCard.SymbolString()) {
+		t.Logf("expected the pile from top to bottom, got %q", sb.String())
+		t.Fail()
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	wantNumberOf := map[string]uknow.Number{
+		"0":            0,
+		"7":            7,
+		"9":            9,
+		"skip":         uknow.NumberSkip,
+		"Skip":         uknow.NumberSkip,
+		"reverse":      uknow.NumberReverse,
+		"REV":          uknow.NumberReverse,
+		"DrawTwo":      uknow.NumberDrawTwo,
+		"draw2":        uknow.NumberDrawTwo,
+		"Draw_2":       uknow.NumberDrawTwo,
+		"wild":         uknow.NumberWild,
+		"WildDrawFour": uknow.NumberWildDrawFour,
+		"wild4":        uknow.NumberWildDrawFour,
+		"WILD_DRAW_4":  uknow.NumberWildDrawFour,
+	}
+
+	for s, wantNumber := range wantNumberOf {
+		number, err := uknow.ParseNumber(s)
+		if err != nil || number != wantNumber {
+			t.Logf("%s: expected %s, got %s, err: %v", s, wantNumber.String(), number.String(), err)
+			t.Fail()
+		}
+	}
+
+	for _, number := range allNumbers() {
+		if parsed, err := uknow.ParseNumber(number.String()); err != nil || parsed != number {
+			t.Logf("expected ParseNumber to invert String() for %s, got %s, err: %v", number.String(), parsed.String(), err)
+			t.Fail()
+		}
+	}
+
+	for _, s := range []string{"", "10", "-1", "draw", "07", "wild 4"} {
+		if _, err := uknow.ParseNumber(s); err == nil {
+			t.Logf("expected '%s' to be rejected", s)
+			t.Fail()
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	wantColorOf := map[string]uknow.Color{
+		"red":    uknow.ColorRed,
+		"Green":  uknow.ColorGreen,
+		"BLUE":   uknow.ColorBlue,
+		"yeLLow": uknow.ColorYellow,
+		"Wild":   uknow.ColorWild,
+	}
+
+	for s, wantColor := range wantColorOf {
+		color, err := uknow.ParseColor(s)
+		if err != nil || color != wantColor {
+			t.Logf("%s: expected %s, got %s, err: %v", s, wantColor.String(), color.String(), err)
+			t.Fail()
+		}
+	}
+
+	for _, color := range allColors() {
+		if parsed, err := uknow.ParseColor(color.String()); err != nil || parsed != color {
+			t.Logf("expected ParseColor to invert String() for %s, got %s, err: %v", color.String(), parsed.String(), err)
+			t.Fail()
+		}
+	}
+
+	for _, s := range []string{"", "purple", "r", "wilds"} {
+		if _, err := uknow.ParseColor(s); err == nil {
+			t.Logf("expected '%s' to be rejected", s)
+			t.Fail()
+		}
+	}
+}
+
+func TestRealColorsExcludeWild(t *testing.T) {
+	realColors := uknow.RealColors()
+	if len(realColors) != 4 {
+		t.Fatalf("expected 4 real colors, got %v", realColors)
+	}
+
+	for _, color := range allColors() {
+		isListed := false
+		for _, realColor := range realColors {
+			isListed = isListed || realColor == color
+		}
+		if color.IsReal() != isListed || color.IsReal() == (color == uknow.ColorWild) {
+			t.Logf("%s: IsReal %v, listed in RealColors %v", color.String(), color.IsReal(), isListed)
+			t.Fail()
+		}
+	}
+
+	if uknow.Color(9).IsReal() {
+		t.Logf("expected an invalid color to not be real")
+		t.Fail()
+	}
+}
+
+func TestTableDebugDumpListsWholeDecks(t *testing.T) {
+	table := newTableWithPlayers(2)
+	table.DrawDeck = uknow.NewFullDeck()
+	handCard := uknow.Card{Number: 3, Color: uknow.ColorGreen}
+	pileCard := uknow.Card{Number: 7, Color: uknow.ColorYellow}
+	table.HandOfPlayer["player_a"] = uknow.Deck{handCard}
+	table.DiscardedPile = uknow.Deck{pileCard}
+
+	var first, second strings.Builder
+	table.DebugDump(&first)
+	table.DebugDump(&second)
+
+	if first.String() != second.String() {
+		t.Fatalf("expected the same dump for the same table")
+	}
+
+	dump := first.String()
+	wantLines := []string{
+		"---- Hand of player_a (index 0, 1 cards) ----\n001: " + handCard.String() + "\n",
+		"---- DiscardedPile (1 cards) ----\n001: " + pileCard.String() + "\n",
+		"108: " + table.DrawDeck[0].String() + "\n",
+		"TableState: " + string(table.TableState) + "\n",
+	}
+	for _, want := range wantLines {
+		if !strings.Contains(dump, want) {
+			t.Logf("expected dump to contain %q, got:\n%s", want, dump)
+			t.Fail()
+		}
+	}
+}
+
+func TestDeckEqual(t *testing.T) {
+	redOne := uknow.Card{Number: 1, Color: uknow.ColorRed}
+	blueTwo := uknow.Card{Number: 2, Color: uknow.ColorBlue}
+	wild := uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}
+
+	testCases := []struct {
+		a, b                uknow.Deck
+		wantEqual           bool
+		wantEqualAsMultiset bool
+	}{
+		{uknow.Deck{redOne, blueTwo}, uknow.Deck{redOne, blueTwo}, true, true},
+		{uknow.Deck{redOne, blueTwo}, uknow.Deck{blueTwo, redOne}, false, true},
+		{uknow.Deck{redOne, redOne, blueTwo}, uknow.Deck{redOne, blueTwo, blueTwo}, false, false},
+		{uknow.Deck{redOne, wild}, uknow.Deck{redOne}, false, false},
+		{uknow.Deck{redOne}, uknow.Deck{blueTwo}, false, false},
+		{nil, uknow.NewEmptyDeck(), true, true},
+	}
+
+	for _, tc := range testCases {
+		if equal := tc.a.Equal(tc.b); equal != tc.wantEqual {
+			t.Logf("%s and %s: expected Equal %v, got %v", tc.a, tc.b, tc.wantEqual, equal)
+			t.Fail()
+		}
+		if equal := tc.a.EqualAsMultiset(tc.b); equal != tc.wantEqualAsMultiset {
+			t.Logf("%s and %s: expected EqualAsMultiset %v, got %v", tc.a, tc.b, tc.wantEqualAsMultiset, equal)
+			t.Fail()
+		}
+		if tc.a.EqualAsMultiset(tc.b) != tc.b.EqualAsMultiset(tc.a) {
+			t.Logf("%s and %s: expected EqualAsMultiset to be symmetric", tc.a, tc.b)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+// alice plays a wild draw 4 on a red 5, holding otherCard, chooses blue and bob
+// challenges her.
+func challengeWildDraw4(t *testing.T, otherCard uknow.Card) *uknow.Table {
+	table := newDrawPlayTable(uknow.Card{Number: 9, Color: uknow.ColorYellow})
+	wildDraw4 := uknow.Card{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild}
+	table.HandOfPlayer["alice"] = uknow.Deck{wildDraw4, otherCard}
+	for i := 0; i < 8; i++ {
+		table.DrawDeck = table.DrawDeck.Push(uknow.Card{Number: 3, Color: uknow.ColorGreen})
+	}
+
+	gameEventChan := drainGameEvents()
+	decisions := []uknow.PlayerDecision{
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: wildDraw4},
+		{Kind: uknow.PlayerDecisionWildCardChooseColor, WildCardChosenColor: uknow.ColorBlue},
+	}
+	for _, decision := range decisions {
+		if _, err := table.EvalPlayerDecision("alice", decision, gameEventChan); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if table.TableState != uknow.AwaitingWildDraw4ChallengeDecision {
+		t.Fatalf("expected bob to be asked to challenge, got state %s", table.TableState)
+	}
+
+	if _, err := table.EvalPlayerDecision("bob", uknow.PlayerDecision{Kind: uknow.PlayerDecisionDoChallenge}, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestChallengeSucceedsOnlyOnRequiredColor(t *testing.T) {
+	testCases := []struct {
+		otherCard   uknow.Card
+		wantSuccess bool
+	}{
+		{uknow.Card{Number: 2, Color: uknow.ColorRed}, true},
+		{uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed}, true},
+		{uknow.Card{Number: 5, Color: uknow.ColorBlue}, false}, // Matches only the required number
+		{uknow.Card{Number: 8, Color: uknow.ColorGreen}, false},
+		{uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}, false},
+	}
+
+	for _, tc := range testCases {
+		table := challengeWildDraw4(t, tc.otherCard)
+
+		aliceHandCount, bobHandCount := table.HandOfPlayer["alice"].Len(), table.HandOfPlayer["bob"].Len()
+		succeeded := aliceHandCount == 5 && bobHandCount == 1
+		failed := aliceHandCount == 1 && bobHandCount > 1
+		if succeeded != tc.wantSuccess || failed == tc.wantSuccess {
+			t.Logf("%s: expected challenge success %v, alice holds %d cards, bob holds %d", tc.otherCard.String(), tc.wantSuccess, aliceHandCount, bobHandCount)
+			t.Fail()
+		}
+	}
+}
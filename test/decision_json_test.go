@@ -0,0 +1,70 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestPlayerDecisionJSONRoundTrip(t *testing.T) {
+	decisions := []uknow.PlayerDecision{
+		{Kind: uknow.PlayerDecisionPullFromDeck},
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed}},
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}},
+		{Kind: uknow.PlayerDecisionWildCardChooseColor, WildCardChosenColor: uknow.ColorYellow, TimedOut: true},
+		{Kind: uknow.PlayerDecisionChooseSwapTarget, SwapTargetPlayer: "bob"},
+	}
+
+	b, err := json.Marshal(decisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{`"PlayerDecisionPlayHandCard"`, `"r-skip"`, `"yellow"`} {
+		if !strings.Contains(string(b), want) {
+			t.Logf("expected the encoded decisions to contain %s, got %s", want, b)
+			t.Fail()
+		}
+	}
+
+	var decoded []uknow.PlayerDecision
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(decisions) {
+		t.Fatalf("expected %d decisions, got %d", len(decisions), len(decoded))
+	}
+	for i := range decisions {
+		if decoded[i] != decisions[i] {
+			t.Logf("expected %+v, got %+v", decisions[i], decoded[i])
+			t.Fail()
+		}
+	}
+}
+
+func TestPlayerDecisionJSONAcceptsIntegers(t *testing.T) {
+	var decision uknow.PlayerDecision
+	err := json.Unmarshal([]byte(`{"Kind":4,"ResultCard":"wild","WildCardChosenColor":3,"TimedOut":false,"SwapTargetPlayer":""}`), &decision)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decision.Kind != uknow.PlayerDecisionWildCardChooseColor || decision.WildCardChosenColor != uknow.ColorBlue {
+		t.Fatalf("expected a choose color decision for blue, got %+v", decision)
+	}
+}
+
+func TestPlayerDecisionJSONRejectsUnknownNames(t *testing.T) {
+	for _, input := range []string{
+		`{"Kind":"PlayerDecisionFlipTable"}`,
+		`{"Kind":"PlayerDecisionWildCardChooseColor","WildCardChosenColor":"purple"}`,
+	} {
+		var decision uknow.PlayerDecision
+		if err := json.Unmarshal([]byte(input), &decision); err == nil {
+			t.Logf("expected %s to be rejected, got %+v", input, decision)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestParseDeck(t *testing.T) {
+	deck, err := uknow.ParseDeck("r7, g-skip,wild4,y0,b-draw2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := uknow.Deck{
+		{Number: 7, Color: uknow.ColorRed},
+		{Number: uknow.NumberSkip, Color: uknow.ColorGreen},
+		{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild},
+		{Number: 0, Color: uknow.ColorYellow},
+		{Number: uknow.NumberDrawTwo, Color: uknow.ColorBlue},
+	}
+
+	if deck.Len() != want.Len() {
+		t.Fatalf("expected %d cards, got %d", want.Len(), deck.Len())
+	}
+
+	for i := range want {
+		if !deck[i].IsEqual(want[i]) {
+			t.Logf("card %d: expected %s, got %s", i, want[i].String(), deck[i].String())
+			t.Fail()
+		}
+	}
+
+	for _, invalid := range []string{"r", "x7", "r10", "g-jump", "wild5", "r7,,g1"} {
+		if _, err := uknow.ParseDeck(invalid); err == nil {
+			t.Logf("expected error parsing %q", invalid)
+			t.Fail()
+		}
+	}
+}
+
+func TestDeckEncodeRoundTrip(t *testing.T) {
+	fullDeck := uknow.NewFullDeck()
+	rng := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 200; i++ {
+		subset := uknow.NewEmptyDeck()
+		for _, card := range fullDeck {
+			if rng.Intn(2) == 0 {
+				subset = subset.Push(card)
+			}
+		}
+		rng.Shuffle(subset.Len(), subset.Swap)
+
+		encoded := subset.Encode()
+		decoded, err := uknow.ParseDeck(encoded)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", encoded, err)
+		}
+
+		if decoded.Len() != subset.Len() {
+			t.Fatalf("expected %d cards after round trip of %q, got %d", subset.Len(), encoded, decoded.Len())
+		}
+
+		for j := range subset {
+			if !decoded[j].IsEqual(subset[j]) {
+				t.Fatalf("card %d of %q did not round trip", j, encoded)
+			}
+		}
+	}
+}
+
+func TestCardJSONRoundTrip(t *testing.T) {
+	// Zero card is used as placeholder in decisions and hidden cards fill the
+	// opponent hands of redacted tables, so they must survive too.
+	cards := append(uknow.NewFullDeck(), uknow.Card{}, uknow.HiddenCard)
+
+	b, err := json.Marshal(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded uknow.Deck
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range cards {
+		if !decoded[i].IsEqual(cards[i]) {
+			t.Logf("card %d: expected %s, got %s", i, cards[i].String(), decoded[i].String())
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package test
+
+import (
+	"testing"
+
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+func TestDiscardPileCellCount(t *testing.T) {
+	testCases := []struct {
+		configured int
+		want       int
+	}{
+		{0, client.DefaultDiscardPileCells},
+		{6, 6},
+		{20, 20},
+		{1, 4},
+		{-3, 4},
+		{100, 32},
+	}
+
+	for _, tc := range testCases {
+		var clientUI client.ClientUI
+		clientUI.InitWidgetObjects(tc.configured)
+
+		if count := clientUI.DiscardPileCellCount(); count != tc.want {
+			t.Logf("configured %d: expected %d discard pile cells, got %d", tc.configured, tc.want, count)
+			t.Fail()
+		}
+	}
+}
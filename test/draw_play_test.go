@@ -0,0 +1,116 @@
+package test
+
+import (
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+// Creates a table with alice to play on top of a red 5, with the given card
+// on top of the draw deck.
+func newDrawPlayTable(topOfDrawDeck uknow.Card) *uknow.Table {
+	table := uknow.NewAdminTable(log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+
+	table.HandOfPlayer["alice"] = uknow.Deck{{Number: 1, Color: uknow.ColorBlue}}
+	table.HandOfPlayer["bob"] = uknow.Deck{{Number: 2, Color: uknow.ColorBlue}}
+	table.DrawDeck = uknow.Deck{{Number: 9, Color: uknow.ColorGreen}, topOfDrawDeck}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+
+	table.PlayerOfNextTurn = "alice"
+	table.TableState = uknow.StartOfTurn
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+	return table
+}
+
+func drainGameEvents() chan uknow.GameEvent {
+	gameEventChan := make(chan uknow.GameEvent)
+	go func() {
+		for range gameEventChan {
+		}
+	}()
+	return gameEventChan
+}
+
+func TestDrawAndPlayPlayableCard(t *testing.T) {
+	drawnCard := uknow.Card{Number: 7, Color: uknow.ColorRed}
+	table := newDrawPlayTable(drawnCard)
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	decisions, err := table.EvalDrawAndPlayDecision("alice", gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decisions) != 2 || decisions[0].Kind != uknow.PlayerDecisionPullFromDeck || decisions[1].Kind != uknow.PlayerDecisionPlayHandCard {
+		t.Fatalf("expected a draw and a play decision, got %+v", decisions)
+	}
+
+	if !table.DiscardedPile.MustTop().IsEqual(drawnCard) {
+		t.Logf("expected %s on top of discard pile", drawnCard.String())
+		t.Fail()
+	}
+
+	if table.HandOfPlayer["alice"].Len() != 1 {
+		t.Logf("expected alice's hand to be unchanged, got %s", table.HandOfPlayer["alice"])
+		t.Fail()
+	}
+
+	if table.PlayerOfNextTurn != "bob" || table.TableState != uknow.StartOfTurn {
+		t.Logf("expected bob's turn to start, got player %s, state %s", table.PlayerOfNextTurn, table.TableState)
+		t.Fail()
+	}
+}
+
+func TestDrawAndPlayUnplayableCard(t *testing.T) {
+	drawnCard := uknow.Card{Number: 3, Color: uknow.ColorYellow}
+	table := newDrawPlayTable(drawnCard)
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	decisions, err := table.EvalDrawAndPlayDecision("alice", gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decisions) != 1 || decisions[0].Kind != uknow.PlayerDecisionPullFromDeck {
+		t.Fatalf("expected only a draw decision, got %+v", decisions)
+	}
+
+	if table.TableState != uknow.AwaitingDropOrPass || table.PlayerOfNextTurn != "alice" {
+		t.Logf("expected alice to be awaiting drop or pass, got player %s, state %s", table.PlayerOfNextTurn, table.TableState)
+		t.Fail()
+	}
+
+	if table.HandOfPlayer["alice"].Len() != 2 {
+		t.Logf("expected drawn card in alice's hand, got %s", table.HandOfPlayer["alice"])
+		t.Fail()
+	}
+}
+
+func TestDrawAndPlayWildCardAwaitsColor(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild})
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	decisions, err := table.EvalDrawAndPlayDecision("alice", gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decisions) != 2 {
+		t.Fatalf("expected a draw and a play decision, got %+v", decisions)
+	}
+
+	if table.TableState != uknow.AwaitingWildCardColorDecision || !table.NeedMoreUserDecisionToFinishTurn() {
+		t.Logf("expected to await wild card color decision, got state %s", table.TableState)
+		t.Fail()
+	}
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+var redDrawTwo = uknow.Card{Number: uknow.NumberDrawTwo, Color: uknow.ColorRed}
+
+// Table on which alice is about to play a red draw-2 on bob, with the given
+// draw deck left.
+func newDrawTwoTable(drawDeck uknow.Deck) *uknow.Table {
+	table := uknow.NewAdminTable(log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.AddPlayer("carol")
+
+	table.HandOfPlayer["alice"] = uknow.Deck{redDrawTwo, {Number: 1, Color: uknow.ColorGreen}}
+	table.HandOfPlayer["bob"] = uknow.Deck{{Number: 2, Color: uknow.ColorGreen}}
+	table.HandOfPlayer["carol"] = uknow.Deck{{Number: 3, Color: uknow.ColorGreen}}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+	table.DrawDeck = drawDeck
+
+	table.PlayerOfNextTurn = "alice"
+	table.TableState = uknow.StartOfTurn
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+	return table
+}
+
+func TestDrawTwoOnShortDrawDeck(t *testing.T) {
+	// The red 5 under the draw-2 refills a short draw deck.
+	cases := []struct {
+		drawDeck        uknow.Deck
+		wantBobHandLen  int
+		wantCardsShort  int
+		wantDrawDeckLen int
+	}{
+		{uknow.Deck{{Number: 8, Color: uknow.ColorBlue}, {Number: 9, Color: uknow.ColorBlue}, {Number: 4, Color: uknow.ColorBlue}}, 3, 0, 1},
+		{uknow.Deck{{Number: 8, Color: uknow.ColorBlue}}, 3, 0, 0},
+		{uknow.Deck{}, 2, 1, 0},
+	}
+
+	for _, c := range cases {
+		table := newDrawTwoTable(c.drawDeck)
+		drawDeckLen := c.drawDeck.Len()
+		recorder := &uknow.GameEventRecorder{}
+		table.EventSink = recorder
+
+		decisions := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redDrawTwo}}
+		if err := table.EvalDecisions("alice", decisions); err != nil {
+			t.Fatalf("draw deck of %d: %v", drawDeckLen, err)
+		}
+
+		if got := table.HandOfPlayer["bob"].Len(); got != c.wantBobHandLen {
+			t.Errorf("draw deck of %d: expected bob to hold %d cards, got %d", drawDeckLen, c.wantBobHandLen, got)
+		}
+		if table.DrawDeck.Len() != c.wantDrawDeckLen {
+			t.Errorf("draw deck of %d: expected %d cards left in the draw deck, got %d", drawDeckLen, c.wantDrawDeckLen, table.DrawDeck.Len())
+		}
+		if !table.DiscardedPile.MustTop().IsEqual(redDrawTwo) {
+			t.Errorf("draw deck of %d: expected the draw-2 to stay on the discard pile, got %s", drawDeckLen, table.DiscardedPile)
+		}
+		if table.PlayerOfNextTurn != "carol" || table.TableState != uknow.StartOfTurn {
+			t.Errorf("draw deck of %d: expected carol's turn to start, got %s in state %s", drawDeckLen, table.PlayerOfNextTurn, table.TableState)
+		}
+		if table.RequiredColorOfCurrentTurn != uknow.ColorRed || table.RequiredNumberOfCurrentTurn != uknow.NumberDrawTwo {
+			t.Errorf("draw deck of %d: expected a red draw-2 to be required, got %s %s", drawDeckLen, table.RequiredColorOfCurrentTurn.String(), table.RequiredNumberOfCurrentTurn.String())
+		}
+
+		var drawTwoEvent *uknow.DrawTwoCardActionEvent
+		passedTurn := false
+		for _, event := range recorder.Events {
+			switch event := event.(type) {
+			case uknow.DrawTwoCardActionEvent:
+				drawTwoEvent = &event
+			case uknow.PlayerPassedTurnEvent:
+				passedTurn = event.PlayerOfNextTurn == "carol"
+			}
+		}
+		if drawTwoEvent == nil || drawTwoEvent.SkippedPlayer != "bob" || drawTwoEvent.CardsShort != c.wantCardsShort {
+			t.Errorf("draw deck of %d: expected a draw-2 event skipping bob %d cards short, got %+v", drawDeckLen, c.wantCardsShort, drawTwoEvent)
+		}
+		if !passedTurn {
+			t.Errorf("draw deck of %d: expected the turn to be passed to carol, got %v", drawDeckLen, gameEventNames(recorder.Events))
+		}
+	}
+}
+
+func TestDrawTwoRefillsDrawDeckFromPile(t *testing.T) {
+	pile := uknow.Deck{
+		{Number: 6, Color: uknow.ColorYellow},
+		{Number: 7, Color: uknow.ColorYellow},
+		{Number: 5, Color: uknow.ColorRed},
+	}
+	drawDecks := map[string]uknow.Deck{}
+
+	for _, localPlayerName := range []string{"", "carol"} {
+		table := newDrawTwoTable(uknow.Deck{{Number: 8, Color: uknow.ColorBlue}})
+		table.LocalPlayerName = localPlayerName
+		table.DiscardedPile = pile.Clone()
+
+		decisions := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redDrawTwo}}
+		if err := table.EvalDecisions("alice", decisions); err != nil {
+			t.Fatalf("%q: %v", localPlayerName, err)
+		}
+
+		if !table.DiscardedPile.Equal(uknow.Deck{redDrawTwo}) {
+			t.Errorf("%q: expected only the draw-2 to stay on the discard pile, got %s", localPlayerName, table.DiscardedPile)
+		}
+		if table.HandOfPlayer["bob"].Len() != 3 || table.DrawDeck.Len() != 2 {
+			t.Errorf("%q: expected bob to draw 2 cards with 2 left in the draw deck, got bob's hand %s and draw deck %s", localPlayerName, table.HandOfPlayer["bob"], table.DrawDeck)
+		}
+		drawDecks[localPlayerName] = table.DrawDeck
+	}
+
+	// The admin knows the refilled cards, a client doesn't.
+	if drawDecks[""].HasHiddenCards() {
+		t.Errorf("expected the admin's draw deck to hold the refilled cards, got %s", drawDecks[""])
+	}
+	if !drawDecks["carol"].Equal(uknow.NewHiddenDeck(2)) {
+		t.Errorf("expected carol's draw deck to be hidden, got %s", drawDecks["carol"])
+	}
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+var drawThenPlayRedSkip = []uknow.PlayerDecision{
+	{Kind: uknow.PlayerDecisionPullFromDeck},
+	{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed}},
+}
+
+func TestDrawnSkipAppliesWhenPlayed(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed})
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	if err := table.EvalPlayerDecisions("alice", drawThenPlayRedSkip, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	// With two players, skipping bob gives alice another turn.
+	if table.PlayerOfNextTurn != "alice" || table.TableState != uknow.StartOfTurn {
+		t.Fatalf("expected bob to be skipped, got %s's turn in state %s", table.PlayerOfNextTurn, table.TableState)
+	}
+}
+
+func TestDrawnSkipHasNoEffectWithRule(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed})
+	table.HouseRules.DrawnActionCardHasNoEffect = true
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	if err := table.EvalPlayerDecisions("alice", drawThenPlayRedSkip, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	if table.PlayerOfNextTurn != "bob" || table.TableState != uknow.StartOfTurn {
+		t.Fatalf("expected bob's turn, got %s's in state %s", table.PlayerOfNextTurn, table.TableState)
+	}
+
+	if !table.DiscardedPile.MustTop().IsEqual(drawThenPlayRedSkip[1].ResultCard) || table.RequiredNumberOfCurrentTurn != uknow.NumberSkip || table.RequiredColorOfCurrentTurn != uknow.ColorRed {
+		t.Logf("expected the skip on the discard pile to set the required color and number, got pile %s", table.DiscardedPile)
+		t.Fail()
+	}
+
+	if table.HandOfPlayer["alice"].Len() != 1 {
+		t.Logf("expected alice to only hold her starting card, got %s", table.HandOfPlayer["alice"])
+		t.Fail()
+	}
+}
+
+func TestSkipFromHandAppliesWithRule(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: 3, Color: uknow.ColorYellow})
+	table.HouseRules.DrawnActionCardHasNoEffect = true
+	table.HandOfPlayer["alice"] = uknow.Deck{{Number: uknow.NumberSkip, Color: uknow.ColorRed}, {Number: 1, Color: uknow.ColorBlue}}
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	if err := table.EvalPlayerDecisions("alice", drawThenPlayRedSkip, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	if table.PlayerOfNextTurn != "alice" {
+		t.Fatalf("expected a skip played from hand after drawing to still skip bob, got %s's turn", table.PlayerOfNextTurn)
+	}
+}
@@ -0,0 +1,43 @@
+package test
+
+import (
+	"testing"
+
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+func TestParseLogFilterCommand(t *testing.T) {
+	command, err := client.ParseCommandFromInput("log_filter game", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter, ok := command.ExtraData.(client.EventLogFilter)
+	if command.Kind != client.CmdLogFilter || !ok {
+		t.Fatalf("expected a log_filter command with a filter, got %s, %+v", command.Kind, command.ExtraData)
+	}
+
+	if filter.Shows(client.EventLogCardTransfer) || filter.Shows(client.EventLogDebug) || !filter.Shows(client.EventLogGame) || !filter.Shows(client.EventLogInfo) {
+		t.Logf("expected game filter to hide only card transfers and debug lines, got %+v", filter)
+		t.Fail()
+	}
+
+	if _, err := client.ParseCommandFromInput("log_filter nothing", "alice"); err == nil {
+		t.Log("expected an unknown filter to be rejected")
+		t.Fail()
+	}
+}
+
+func TestEventLogFilterAllShowsEverything(t *testing.T) {
+	filter, err := client.ParseEventLogFilter("ALL")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, category := range []client.EventLogCategory{client.EventLogInfo, client.EventLogGame, client.EventLogCardTransfer, client.EventLogDebug} {
+		if !filter.Shows(category) {
+			t.Logf("expected all filter to show category %d", category)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func gameEventNames(events []uknow.GameEvent) []string {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event.GameEventName()
+	}
+	return names
+}
+
+func TestRecordedEventsOfTurn(t *testing.T) {
+	redSeven := uknow.Card{Number: 7, Color: uknow.ColorRed}
+	decisions := []uknow.PlayerDecision{
+		{Kind: uknow.PlayerDecisionPullFromDeck},
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redSeven},
+	}
+
+	table := newDrawPlayTable(redSeven)
+	recorder := &uknow.GameEventRecorder{}
+	table.EventSink = recorder
+
+	if err := table.EvalDecisions("alice", decisions); err != nil {
+		t.Fatal(err)
+	}
+
+	wantNames := []string{
+		"CardTransferEvent",
+		"AwaitingPlayOrPassEvent",
+		"CardTransferEvent",
+		"RequiredColorUpdatedEvent",
+		"PlayerPassedTurnEvent",
+	}
+	if names := gameEventNames(recorder.Events); !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("expected events %v, got %v", wantNames, names)
+	}
+
+	drawEvent := recorder.Events[0].(uknow.CardTransferEvent)
+	playEvent := recorder.Events[2].(uknow.CardTransferEvent)
+	if drawEvent.Source != uknow.CardTransferNodeDeck || drawEvent.SinkPlayer != "alice" || !drawEvent.Card.IsEqual(redSeven) {
+		t.Logf("expected alice to draw %s from the deck, got %+v", redSeven.String(), drawEvent)
+		t.Fail()
+	}
+	if playEvent.SourcePlayer != "alice" || playEvent.Sink != uknow.CardTransferNodePile || !playEvent.Card.IsEqual(redSeven) {
+		t.Logf("expected alice to play %s on the pile, got %+v", redSeven.String(), playEvent)
+		t.Fail()
+	}
+
+	// The channel API must emit the same events.
+	table = newDrawPlayTable(redSeven)
+	gameEventChan := make(chan uknow.GameEvent)
+	chanEvents := make(chan []uknow.GameEvent)
+	go func() {
+		var events []uknow.GameEvent
+		for event := range gameEventChan {
+			events = append(events, event)
+		}
+		chanEvents <- events
+	}()
+
+	err := table.EvalPlayerDecisions("alice", decisions, gameEventChan)
+	close(gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if events := <-chanEvents; !reflect.DeepEqual(events, recorder.Events) {
+		t.Logf("expected the channel to receive %v, got %v", gameEventNames(recorder.Events), gameEventNames(events))
+		t.Fail()
+	}
+}
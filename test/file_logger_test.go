@@ -0,0 +1,99 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestFileLoggersSharingNameWriteToSeparateFiles(t *testing.T) {
+	logDir := t.TempDir()
+
+	for _, message := range []string{"first", "second"} {
+		logger, err := uknow.CreateFileLogger(false, logDir, "alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger.Print(message)
+	}
+
+	for fileName, message := range map[string]string{"alice_log.txt": "first", "alice_2_log.txt": "second"} {
+		b, err := os.ReadFile(filepath.Join(logDir, fileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), message) || strings.Count(string(b), "\n") != 1 {
+			t.Logf("expected %s to contain only the %s message, got %q", fileName, message, string(b))
+			t.Fail()
+		}
+	}
+}
+
+func TestFileLoggerMissingDir(t *testing.T) {
+	logDir := filepath.Join(t.TempDir(), "missing")
+
+	if _, err := uknow.CreateFileLogger(false, logDir, "bob"); err == nil {
+		t.Fatal("expected an error for a missing log dir")
+	}
+}
+
+func TestRotatingFileLoggerKeepsLastFiles(t *testing.T) {
+	logDir := t.TempDir()
+
+	logger, err := uknow.CreateRotatingFileLogger(logDir, "admin", uknow.LogRotation{MaxBytes: 200, KeepFiles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 50; i++ {
+		logger.Printf("line %d", i)
+	}
+
+	fileName := filepath.Join(logDir, "admin_log.txt")
+	for _, name := range []string{fileName, fileName + ".1", fileName + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 200 {
+			t.Logf("expected %s to be at most 200 bytes, got %d", name, info.Size())
+			t.Fail()
+		}
+	}
+
+	if _, err := os.Stat(fileName + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("expected only 2 rotated files to be kept, got err: %v", err)
+	}
+
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "line 49") {
+		t.Fatalf("expected the newest line in the current log file, got %q", string(b))
+	}
+}
+
+func TestRotatingFileLoggerDisabled(t *testing.T) {
+	logDir := t.TempDir()
+
+	logger, err := uknow.CreateRotatingFileLogger(logDir, "admin", uknow.LogRotation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 50; i++ {
+		logger.Printf("line %d", i)
+	}
+
+	b, err := os.ReadFile(filepath.Join(logDir, "admin_log.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(b), "\n") != 50 {
+		t.Fatalf("expected all 50 lines in a single log file, got %q", string(b))
+	}
+}
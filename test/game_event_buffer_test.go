@@ -0,0 +1,62 @@
+package test
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestGameEventBufferSlowConsumerDoesNotBlockEvaluation(t *testing.T) {
+	consumerChan := make(chan uknow.GameEvent)
+	buffer := uknow.NewGameEventBuffer(16, consumerChan, log.Default())
+	defer buffer.Close()
+
+	table := newDrawPlayTable(uknow.Card{Number: 7, Color: uknow.ColorRed})
+
+	// Nobody reads consumerChan while evaluating.
+	done := make(chan error)
+	go func() {
+		_, err := table.EvalDrawAndPlayDecision("alice", buffer.PushChan())
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("evaluation blocked on a consumer that is not reading")
+	}
+
+	// The consumer gets the queued events in order once it reads.
+	event := <-consumerChan
+	if transfer, ok := event.(uknow.CardTransferEvent); !ok || transfer.Source != uknow.CardTransferNodeDeck {
+		t.Logf("expected the draw transfer first, got %s", event.GameEventName())
+		t.Fail()
+	}
+}
+
+func TestGameEventBufferCoalescesWhenFull(t *testing.T) {
+	consumerChan := make(chan uknow.GameEvent)
+	buffer := uknow.NewGameEventBuffer(2, consumerChan, log.Default())
+	defer buffer.Close()
+
+	buffer.PushChan() <- uknow.RequiredColorUpdatedEvent{NewColor: uknow.ColorRed}
+	buffer.PushChan() <- uknow.PlayerPassedTurnEvent{Player: "alice"}
+	buffer.PushChan() <- uknow.RequiredColorUpdatedEvent{NewColor: uknow.ColorBlue}
+
+	first := <-consumerChan
+	if _, ok := first.(uknow.PlayerPassedTurnEvent); !ok {
+		t.Logf("expected PlayerPassedTurnEvent to be kept, got %s", first.GameEventName())
+		t.Fail()
+	}
+
+	second := <-consumerChan
+	if colorEvent, ok := second.(uknow.RequiredColorUpdatedEvent); !ok || colorEvent.NewColor != uknow.ColorBlue {
+		t.Logf("expected only the latest color update, got %+v", second)
+		t.Fail()
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+func TestGameEventRecapReproducesMessagesInOrder(t *testing.T) {
+	events := []uknow.GameEvent{
+		uknow.SkipCardActionEvent{Player: "alice", SkippedPlayer: "bob"},
+		uknow.CardTransferEvent{Source: uknow.CardTransferNodeDeck, Sink: uknow.CardTransferNodePlayerHand, SinkPlayer: "carol"},
+		uknow.PlayerPassedTurnEvent{Player: "carol", PlayerOfNextTurn: "alice"},
+		uknow.WildCardColorChosenEvent{Player: "alice", ChosenColor: uknow.ColorBlue},
+	}
+
+	recap := client.NewGameEventRecap(2)
+	start := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	for i, event := range events {
+		recap.Push(start.Add(time.Duration(i)*time.Second), event)
+	}
+
+	// The card transfer has no message and is not kept, the skip event is
+	// overwritten by the wild color event.
+	var wantLines []string
+	for _, i := range []int{2, 3} {
+		message, _ := uknow.GameEventMessage(events[i], "bob")
+		wantLines = append(wantLines, start.Add(time.Duration(i)*time.Second).Format("15:04:05")+" "+message)
+	}
+
+	lines := recap.Lines(10, "bob")
+	if strings.Join(lines, "\n") != strings.Join(wantLines, "\n") {
+		t.Logf("expected recap lines %q, got %q", wantLines, lines)
+		t.Fail()
+	}
+
+	lastLine := recap.Lines(1, "bob")
+	if len(lastLine) != 1 || lastLine[0] != wantLines[1] {
+		t.Logf("expected only the latest line %q, got %q", wantLines[1], lastLine)
+		t.Fail()
+	}
+}
+
+func TestParseRecapCommand(t *testing.T) {
+	command, err := client.ParseCommandFromInput("recap 5", "alice")
+	if err != nil || command.Kind != client.CmdRecap || command.Count != 5 {
+		t.Fatalf("expected recap of 5 events, got %+v, err: %v", command, err)
+	}
+
+	if _, err := client.ParseCommandFromInput("recap 0", "alice"); err == nil {
+		t.Log("expected a zero count to be rejected")
+		t.Fail()
+	}
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestRecordAndNarrateGame(t *testing.T) {
+	redThree := uknow.Card{Number: 3, Color: uknow.ColorRed}
+	blueThree := uknow.Card{Number: 3, Color: uknow.ColorBlue}
+
+	table := newLastCardTable(redThree)
+	table.HandOfPlayer["alice"] = uknow.Deck{redThree, blueThree}
+	table.HandOfPlayer["bob"] = uknow.Deck{{Number: 2, Color: uknow.ColorBlue}, blueThree}
+	table.HandOfPlayer["carol"] = uknow.Deck{{Number: 8, Color: uknow.ColorYellow}, blueThree}
+	table.ShufflerName = "carol"
+	table.OpeningCardEvent = uknow.OpeningCardEvent{
+		Card:         uknow.Card{Number: 5, Color: uknow.ColorRed},
+		ShufflerName: "carol",
+		NextPlayer:   "alice",
+	}
+
+	var record bytes.Buffer
+	recordWriter := uknow.NewGameRecordWriter(&record)
+	recordWriter.RecordStart(table)
+	table.EventSink = recordWriter
+
+	turns := []struct {
+		player string
+		card   uknow.Card
+	}{
+		{"alice", redThree},
+		{"bob", blueThree},
+		{"carol", blueThree},
+		{"alice", blueThree},
+	}
+	for _, turn := range turns {
+		decisions := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: turn.card}}
+		recordWriter.RecordDecisions(turn.player, decisions)
+		if err := table.EvalDecisions(turn.player, decisions); err != nil {
+			t.Fatalf("%s playing %s: %v", turn.player, turn.card.String(), err)
+		}
+	}
+	if table.WinnerPlayerName != "alice" {
+		t.Fatalf("expected alice to win, got %q", table.WinnerPlayerName)
+	}
+	recordWriter.RecordEnd(table.WinnerPlayerName)
+	if err := recordWriter.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := uknow.ReadGameRecord(&record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lastEvent uknow.GameEvent
+	for _, entry := range entries {
+		if entry.Kind == uknow.GameRecordEvent {
+			lastEvent = entry.Event
+		}
+	}
+	if wonEvent, ok := lastEvent.(uknow.PlayerHasWonEvent); !ok || wonEvent.Player != "alice" {
+		t.Fatalf("expected the last recorded event to be alice winning, got %+v", lastEvent)
+	}
+
+	var narration strings.Builder
+	uknow.NarrateGameRecord(&narration, entries)
+	lines := strings.Split(strings.TrimSuffix(narration.String(), "\n"), "\n")
+
+	wantLines := []string{
+		"Game of alice, bob, carol, shuffled by carol, house rules: none",
+		"    Opening card is 5 of red, alice is the first player",
+		"--- Turn 1: alice decided PlayerDecisionPlayHandCard: 3 of red",
+		"    Card transfer from player alice to pile, card: 3 of red",
+	}
+	for i, wantLine := range wantLines {
+		if i >= len(lines) || lines[i] != wantLine {
+			t.Fatalf("expected line %d to be %q, got narration:\n%s", i, wantLine, narration.String())
+		}
+	}
+
+	turnCount := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "--- Turn ") {
+			turnCount++
+		}
+	}
+	if turnCount != len(turns) {
+		t.Logf("expected %d turns, got narration:\n%s", len(turns), narration.String())
+		t.Fail()
+	}
+	if !strings.Contains(narration.String(), "    alice is the winner\n") {
+		t.Logf("expected alice's win to be narrated, got:\n%s", narration.String())
+		t.Fail()
+	}
+	if lastLine := lines[len(lines)-1]; lastLine != "--- Game over after 4 turns, alice won" {
+		t.Logf("expected the narration to end with alice winning, got %q", lastLine)
+		t.Fail()
+	}
+}
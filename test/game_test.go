@@ -0,0 +1,68 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func runSeededGame(t *testing.T, seed int64) *uknow.GameResult {
+	playerNames := []string{"alice", "bob", "carol"}
+	strategyOfPlayer := make(map[string]uknow.PlayerStrategy)
+	for _, playerName := range playerNames {
+		strategyOfPlayer[playerName] = uknow.FirstPlayableCardStrategy
+	}
+
+	game, err := uknow.NewGame(uknow.GameConfig{
+		Seed:             seed,
+		PlayerNames:      playerNames,
+		StrategyOfPlayer: strategyOfPlayer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := game.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every player's view must agree with the admin's table.
+	for _, playerName := range playerNames {
+		playerTable := game.TableOfPlayer(playerName)
+		for _, handOwner := range playerNames {
+			if playerTable.HandOfPlayer[handOwner].Encode() != result.Table.HandOfPlayer[handOwner].Encode() {
+				t.Fatalf("%s's view of %s's hand differs from admin's", playerName, handOwner)
+			}
+		}
+	}
+	return result
+}
+
+func TestSeededGameRunsToCompletion(t *testing.T) {
+	result := runSeededGame(t, 42)
+
+	if result.Table.TableState != uknow.HaveWinner {
+		t.Fatalf("expected a winner, got table state %s", result.Table.TableState)
+	}
+
+	winner := result.Table.WinnerPlayerName
+	if result.Table.HandOfPlayer[winner].Len() != 0 {
+		t.Logf("expected winner %s to have an empty hand, got %s", winner, result.Table.HandOfPlayer[winner])
+		t.Fail()
+	}
+
+	if _, ok := result.Events[len(result.Events)-1].(uknow.PlayerHasWonEvent); !ok {
+		t.Logf("expected last event to be PlayerHasWonEvent, got %s", result.Events[len(result.Events)-1].GameEventName())
+		t.Fail()
+	}
+
+	// Same seed, same game.
+	again := runSeededGame(t, 42)
+	if again.Turns != result.Turns || again.Table.WinnerPlayerName != winner || again.Table.DiscardedPile.Encode() != result.Table.DiscardedPile.Encode() {
+		t.Logf("expected seeded games to be identical, got %d turns won by %s and %d turns won by %s", result.Turns, winner, again.Turns, again.Table.WinnerPlayerName)
+		t.Fail()
+	}
+
+	t.Logf("%s won after %d turns", winner, result.Turns)
+}
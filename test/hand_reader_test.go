@@ -0,0 +1,146 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/hand_reader"
+)
+
+const handConfigWithLargeDiscardPile = `{
+	"player.alice": {
+		"red": [1, 2, "skip"],
+		"wild": ["wild"]
+	},
+	"player.john": {
+		"blue": [7, "reverse"]
+	},
+	"discarded_pile_size": 10,
+	"player_of_next_turn": "alice",
+	"preset_discard_pile_top": [
+		["green", 9],
+		["yellow", "draw_2"]
+	]
+}`
+
+func TestHandReaderDiscardPileTopUp(t *testing.T) {
+	var j map[string]interface{}
+	if err := json.Unmarshal([]byte(handConfigWithLargeDiscardPile), &j); err != nil {
+		t.Fatal(err)
+	}
+
+	fullDeckSize := uknow.NewFullDeck().Len()
+
+	table, err := hand_reader.LoadConfig(j, uknow.NewAdminTable(log.Default()), log.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.DiscardedPile.Len() != 10 {
+		t.Logf("expected discard pile size 10, got %d", table.DiscardedPile.Len())
+		t.Fail()
+	}
+
+	handCardsCount := 0
+	for _, hand := range table.HandOfPlayer {
+		handCardsCount += hand.Len()
+	}
+
+	if handCardsCount != 6 {
+		t.Logf("expected 6 cards in hands, got %d", handCardsCount)
+		t.Fail()
+	}
+
+	wantDrawDeckSize := fullDeckSize - 10 - handCardsCount
+	if table.DrawDeck.Len() != wantDrawDeckSize {
+		t.Logf("expected draw deck size %d, got %d", wantDrawDeckSize, table.DrawDeck.Len())
+		t.Fail()
+	}
+
+	topCard := table.DiscardedPile.MustTop()
+	if !topCard.IsEqual(uknow.Card{Number: 9, Color: uknow.ColorGreen}) {
+		t.Logf("expected preset top card to stay on top, got %s", topCard.String())
+		t.Fail()
+	}
+}
+
+func TestValidateHandConfigFile(t *testing.T) {
+	var report bytes.Buffer
+	if err := hand_reader.Validate("../test_configs/hand.json", 1, &report); err != nil {
+		t.Fatalf("expected hand.json to be valid, got %v, report:\n%s", err, report.String())
+	}
+
+	for _, want := range []string{"hand of alice (7 cards)", "hand of john (10 cards)", "draw deck: 86 cards", "discard pile (5 cards"} {
+		if !strings.Contains(report.String(), want) {
+			t.Logf("expected report to contain '%s', got:\n%s", want, report.String())
+			t.Fail()
+		}
+	}
+
+	report.Reset()
+	err := hand_reader.Validate("../test_configs/hand_with_unknown_next_player.json", 1, &report)
+	if !errors.Is(err, uknow.ErrInvalidTable) {
+		t.Fatalf("expected an unknown player of next turn to be invalid, got %v", err)
+	}
+	if !strings.Contains(report.String(), "'bob' is not at the table") {
+		t.Logf("expected report to name the unknown player, got:\n%s", report.String())
+		t.Fail()
+	}
+}
+
+func loadHandConfigWithPileTop(t *testing.T, pileTopAndColor string) (*uknow.Table, error) {
+	config := `{
+		"player.alice": {"red": [1], "blue": [2]},
+		"player.john": {"green": [3]},
+		"player_of_next_turn": "alice",
+		` + pileTopAndColor + `
+	}`
+
+	var j map[string]interface{}
+	if err := json.Unmarshal([]byte(config), &j); err != nil {
+		t.Fatal(err)
+	}
+	return hand_reader.LoadConfig(j, uknow.NewAdminTable(log.Default()), log.Default())
+}
+
+func TestHandReaderWildPileTopNeedsRequiredColor(t *testing.T) {
+	_, err := loadHandConfigWithPileTop(t, `"preset_discard_pile_top": [["wild_draw_4"], ["red", 9]]`)
+	if !errors.Is(err, hand_reader.ErrInvalidRequiredColor) {
+		t.Fatalf("expected a wild draw 4 on top without a required color to be rejected, got %v", err)
+	}
+
+	table, err := loadHandConfigWithPileTop(t, `"preset_discard_pile_top": [["wild_draw_4"], ["red", 9]], "required_color": "blue"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.RequiredColorOfCurrentTurn != uknow.ColorBlue || !table.DiscardedPile.MustTop().IsWild() {
+		t.Fatalf("expected blue to be required on the wild draw 4, got %s on %s", table.RequiredColorOfCurrentTurn.String(), table.DiscardedPile)
+	}
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	blueTwo := uknow.Card{Number: 2, Color: uknow.ColorBlue}
+	decision := uknow.PlayerDecision{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: blueTwo}
+	if _, err := table.EvalPlayerDecision("alice", decision, gameEventChan); err != nil {
+		t.Fatalf("expected alice to be able to play %s, got %v", blueTwo.String(), err)
+	}
+}
+
+func TestHandReaderRequiredColorOnlyWithWildPileTop(t *testing.T) {
+	for _, pileTopAndColor := range []string{
+		`"preset_discard_pile_top": [["red", 9]], "required_color": "blue"`,
+		`"preset_discard_pile_top": [["wild"]], "required_color": "wild"`,
+		`"preset_discard_pile_top": [["wild"]], "required_color": "purple"`,
+	} {
+		if _, err := loadHandConfigWithPileTop(t, pileTopAndColor); !errors.Is(err, hand_reader.ErrInvalidRequiredColor) {
+			t.Logf("expected %s to be rejected, got %v", pileTopAndColor, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/utils"
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+func TestRulesCommandShowsAdminHouseRules(t *testing.T) {
+	g := newTestGame(t)
+	defer g.close()
+
+	houseRules := uknow.HouseRules{SevenZero: true, JumpIn: true}
+	if err := g.admin.SetHouseRules(houseRules); err != nil {
+		t.Fatal(err)
+	}
+
+	adminAddr, err := utils.ResolveTCPAddress(g.server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Collects the lines printed by the rules command, without the caller
+	// prefix of each log line.
+	logWindowChan := make(chan string)
+	ruleLinesChan := make(chan []string, 1)
+	go func() {
+		var ruleLines []string
+		inRules := false
+		for line := range logWindowChan {
+			line = line[strings.Index(line, " ")+1:]
+			switch {
+			case line == "--- house rules:":
+				inRules = true
+			case inRules && line == "---":
+				ruleLinesChan <- ruleLines
+				inRules = false
+			case inRules:
+				ruleLines = append(ruleLines, line)
+			}
+		}
+	}()
+	replCommandChan := make(chan *client.ReplCommand)
+	uiCommandChan := make(chan client.UICommand)
+	go func() {
+		for range uiCommandChan {
+		}
+	}()
+
+	c := client.NewPlayerClient(&client.ConfigNewPlayerClient{
+		ClientChannels: client.ClientChannels{
+			GeneralUICommandPushChan:       uiCommandChan,
+			NonDecisionReplCommandPullChan: replCommandChan,
+			LogWindowPushChan:              logWindowChan,
+			GameEventPushChan:              drainGameEvents(),
+		},
+		Table:            uknow.NewTable("alice", log.Default()),
+		DefaultAdminAddr: adminAddr,
+		LogDir:           t.TempDir(),
+	})
+	go c.RunGeneralCommandHandler()
+
+	replCommandChan <- &client.ReplCommand{Kind: client.CmdConnect}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for c.State() != client.WaitingForAdminToServeCards || c.HouseRules() != houseRules {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected alice to join with the admin's house rules, got state %s and rules %+v", c.State(), c.HouseRules())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	replCommandChan <- &client.ReplCommand{Kind: client.CmdHouseRules}
+
+	var ruleLines []string
+	select {
+	case ruleLines = <-ruleLinesChan:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the house rules in the event log")
+	}
+
+	wantLines := []string{
+		"seven_zero: on",
+		"ignore_opening_action: off",
+		"undo_draw_returns_card: off",
+		"choose_opening_wild_color: off",
+		"jump_in: on",
+		"drawn_action_card_has_no_effect: off",
+		"match_discard_top_number: off",
+		"stack_same_number: off",
+	}
+	if len(ruleLines) != len(wantLines) {
+		t.Fatalf("expected rules %q, got %q", wantLines, ruleLines)
+	}
+	for i, line := range ruleLines {
+		if line != wantLines[i] {
+			t.Logf("expected %q, got %q", wantLines[i], line)
+			t.Fail()
+		}
+	}
+}
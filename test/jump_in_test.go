@@ -0,0 +1,121 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+// Table of player_a, player_b and player_c with the jump_in rule, where it's
+// player_a's turn on top of a red 5 and player_c holds another red 5.
+func newJumpInTable() *uknow.Table {
+	table := newTableWithPlayers(3)
+	table.HouseRules.JumpIn = true
+	table.DrawDeck = uknow.Deck{{Number: 9, Color: uknow.ColorGreen}}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+	table.HandOfPlayer["player_a"] = uknow.Deck{{Number: 1, Color: uknow.ColorBlue}}
+	table.HandOfPlayer["player_b"] = uknow.Deck{{Number: 2, Color: uknow.ColorBlue}}
+	table.HandOfPlayer["player_c"] = uknow.Deck{
+		{Number: 5, Color: uknow.ColorRed},
+		{Number: 5, Color: uknow.ColorBlue},
+		{Number: 3, Color: uknow.ColorGreen},
+	}
+
+	table.PlayerOfNextTurn = "player_a"
+	table.TableState = uknow.StartOfTurn
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+	return table
+}
+
+func TestJumpInWithIdenticalCard(t *testing.T) {
+	table := newJumpInTable()
+	redFive := uknow.Card{Number: 5, Color: uknow.ColorRed}
+
+	gameEventChan := make(chan uknow.GameEvent)
+	done := make(chan []uknow.GameEvent)
+	go func() {
+		var events []uknow.GameEvent
+		for event := range gameEventChan {
+			events = append(events, event)
+		}
+		done <- events
+	}()
+
+	_, err := table.EvalPlayerDecision("player_c", uknow.PlayerDecision{Kind: uknow.PlayerDecisionJumpIn, ResultCard: redFive}, gameEventChan)
+	close(gameEventChan)
+	events := <-done
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Play continues from player_c, so player_a is next again.
+	if table.PlayerOfNextTurn != "player_a" || table.PlayerOfLastTurn != "player_c" || table.TableState != uknow.StartOfTurn {
+		t.Logf("expected player_a to be next after player_c, got next %s, last %s, state %s", table.PlayerOfNextTurn, table.PlayerOfLastTurn, table.TableState)
+		t.Fail()
+	}
+
+	if table.HandOfPlayer["player_c"].Len() != 2 || table.DiscardedPile.Len() != 2 || !table.DiscardedPile.MustTop().IsEqual(redFive) {
+		t.Logf("expected player_c's red 5 on the discard pile, got hand %s, pile %s", table.HandOfPlayer["player_c"], table.DiscardedPile)
+		t.Fail()
+	}
+
+	var jumpInEvent *uknow.JumpInEvent
+	for _, event := range events {
+		if e, ok := event.(uknow.JumpInEvent); ok {
+			jumpInEvent = &e
+		}
+	}
+	if jumpInEvent == nil || jumpInEvent.Player != "player_c" || jumpInEvent.InterruptedPlayer != "player_a" {
+		t.Logf("expected a jump-in event of player_c interrupting player_a, got %+v", events)
+		t.Fail()
+	}
+}
+
+func TestIllegalJumpIns(t *testing.T) {
+	redFive := uknow.Card{Number: 5, Color: uknow.ColorRed}
+
+	testCases := []struct {
+		name      string
+		player    string
+		card      uknow.Card
+		setup     func(table *uknow.Table)
+		wantError error
+	}{
+		{"rule off", "player_c", redFive, func(table *uknow.Table) { table.HouseRules.JumpIn = false }, uknow.ErrJumpInNotAllowed},
+		{"same number, other color", "player_c", uknow.Card{Number: 5, Color: uknow.ColorBlue}, nil, uknow.ErrJumpInNotAllowed},
+		{"card not in hand", "player_b", redFive, nil, uknow.ErrCardNotInHand},
+		{"own turn", "player_c", redFive, func(table *uknow.Table) { table.PlayerOfNextTurn = "player_c" }, uknow.ErrJumpInNotAllowed},
+		{"turn underway", "player_c", redFive, func(table *uknow.Table) { table.TableState = uknow.AwaitingDropOrPass }, uknow.ErrJumpInNotAllowed},
+		{"wild card", "player_c", uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}, func(table *uknow.Table) {
+			wild := uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}
+			table.DiscardedPile = table.DiscardedPile.Push(wild)
+			table.HandOfPlayer["player_c"] = table.HandOfPlayer["player_c"].Push(wild)
+		}, uknow.ErrJumpInNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		table := newJumpInTable()
+		if tc.setup != nil {
+			tc.setup(table)
+		}
+		handBefore := table.HandOfPlayer[tc.player].String()
+		playerOfNextTurn := table.PlayerOfNextTurn
+
+		gameEventChan := drainGameEvents()
+		_, err := table.EvalPlayerDecision(tc.player, uknow.PlayerDecision{Kind: uknow.PlayerDecisionJumpIn, ResultCard: tc.card}, gameEventChan)
+		close(gameEventChan)
+
+		if !errors.Is(err, tc.wantError) {
+			t.Logf("%s: expected error %v, got %v", tc.name, tc.wantError, err)
+			t.Fail()
+		}
+
+		if table.HandOfPlayer[tc.player].String() != handBefore || table.PlayerOfNextTurn != playerOfNextTurn {
+			t.Logf("%s: expected the table to be unchanged, got hand %s, next player %s", tc.name, table.HandOfPlayer[tc.player], table.PlayerOfNextTurn)
+			t.Fail()
+		}
+	}
+}
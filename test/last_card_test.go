@@ -0,0 +1,132 @@
+package test
+
+import (
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+// alice, bob and carol, alice to play her last card on a red 5.
+func newLastCardTable(lastCard uknow.Card) *uknow.Table {
+	table := uknow.NewAdminTable(log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.AddPlayer("carol")
+
+	table.HandOfPlayer["alice"] = uknow.Deck{lastCard}
+	table.HandOfPlayer["bob"] = uknow.Deck{{Number: 2, Color: uknow.ColorBlue}}
+	table.HandOfPlayer["carol"] = uknow.Deck{{Number: 3, Color: uknow.ColorBlue}}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+	table.DrawDeck = uknow.Deck{
+		{Number: 8, Color: uknow.ColorGreen},
+		{Number: 9, Color: uknow.ColorGreen},
+	}
+
+	table.PlayerOfNextTurn = "alice"
+	table.TableState = uknow.StartOfTurn
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+	return table
+}
+
+// Plays alice's last card and returns the events emitted.
+func playLastCard(t *testing.T, table *uknow.Table, lastCard uknow.Card) []uknow.GameEvent {
+	gameEventChan := make(chan uknow.GameEvent)
+	done := make(chan []uknow.GameEvent)
+	go func() {
+		var events []uknow.GameEvent
+		for event := range gameEventChan {
+			events = append(events, event)
+		}
+		done <- events
+	}()
+
+	_, err := table.EvalPlayerDecision("alice", uknow.PlayerDecision{
+		Kind:       uknow.PlayerDecisionPlayHandCard,
+		ResultCard: lastCard,
+	}, gameEventChan)
+	close(gameEventChan)
+	events := <-done
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestPlayingLastCardWins(t *testing.T) {
+	for _, lastCard := range []uknow.Card{
+		{Number: uknow.NumberWild, Color: uknow.ColorWild},
+		{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild},
+		{Number: uknow.NumberDrawTwo, Color: uknow.ColorRed},
+		{Number: uknow.NumberSkip, Color: uknow.ColorRed},
+	} {
+		table := newLastCardTable(lastCard)
+		events := playLastCard(t, table, lastCard)
+
+		if table.TableState != uknow.HaveWinner || table.WinnerPlayerName != "alice" || table.NeedMoreUserDecisionToFinishTurn() {
+			t.Logf("%s: expected alice to win, got state %s, winner %q", lastCard.String(), table.TableState, table.WinnerPlayerName)
+			t.Fail()
+		}
+
+		if table.HandOfPlayer["bob"].Len() != 1 || table.DrawDeck.Len() != 2 {
+			t.Logf("%s: expected no cards to be drawn, bob's hand: %s, draw deck: %s", lastCard.String(), table.HandOfPlayer["bob"], table.DrawDeck)
+			t.Fail()
+		}
+
+		if _, ok := events[len(events)-1].(uknow.PlayerHasWonEvent); !ok {
+			t.Logf("%s: expected last event to be PlayerHasWonEvent, got %s", lastCard.String(), events[len(events)-1].GameEventName())
+			t.Fail()
+		}
+
+		for _, event := range events {
+			switch event.(type) {
+			case uknow.AwaitingWildCardColorDecisionEvent, uknow.DrawTwoCardActionEvent, uknow.SkipCardActionEvent, uknow.PlayerPassedTurnEvent:
+				t.Logf("%s: unexpected event %s after the winning card", lastCard.String(), event.GameEventName())
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestNoDecisionsAfterWin(t *testing.T) {
+	lastCard := uknow.Card{Number: 7, Color: uknow.ColorRed}
+	table := newLastCardTable(lastCard)
+	// carol holding no cards must not make her a second winner.
+	table.HandOfPlayer["carol"] = uknow.NewEmptyDeck()
+	playLastCard(t, table, lastCard)
+
+	if table.WinnerPlayerName != "alice" {
+		t.Fatalf("expected alice to win, got %q", table.WinnerPlayerName)
+	}
+
+	gameEventChan := drainGameEvents()
+
+	for _, attempt := range []struct {
+		player   string
+		decision uknow.PlayerDecision
+	}{
+		{"bob", uknow.PlayerDecision{Kind: uknow.PlayerDecisionPullFromDeck}},
+		{"bob", uknow.PlayerDecision{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: 2, Color: uknow.ColorBlue}}},
+		{"carol", uknow.PlayerDecision{Kind: uknow.PlayerDecisionPass}},
+		{"alice", uknow.PlayerDecision{Kind: uknow.PlayerDecisionWildCardChooseColor, WildCardChosenColor: uknow.ColorBlue}},
+	} {
+		_, err := table.EvalPlayerDecision(attempt.player, attempt.decision, gameEventChan)
+		if !errors.Is(err, uknow.ErrUnexpectedDecision) {
+			t.Logf("%s by %s: expected ErrUnexpectedDecision, got %v", attempt.decision.Kind, attempt.player, err)
+			t.Fail()
+		}
+	}
+
+	if table.WinnerPlayerName != "alice" || table.TableState != uknow.HaveWinner {
+		t.Logf("expected alice to stay the winner, got %q, state %s", table.WinnerPlayerName, table.TableState)
+		t.Fail()
+	}
+
+	if table.HandOfPlayer["bob"].Len() != 1 || table.DrawDeck.Len() != 2 || table.DiscardedPile.Len() != 2 {
+		t.Logf("expected the table to be unchanged after the win, bob's hand: %s, draw deck: %s, discard pile: %s", table.HandOfPlayer["bob"], table.DrawDeck, table.DiscardedPile)
+		t.Fail()
+	}
+}
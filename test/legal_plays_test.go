@@ -0,0 +1,71 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestForcedDrawWithoutLegalPlays(t *testing.T) {
+	// alice holds only a blue 1 against a red 5.
+	table := newDrawPlayTable(uknow.Card{Number: 8, Color: uknow.ColorYellow})
+
+	if legalPlays := table.LegalPlays("alice"); !legalPlays.IsEmpty() {
+		t.Fatalf("expected no legal plays, got %s", legalPlays)
+	}
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	decisions, err := table.EvalForcedDrawDecision("alice", gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decisions) != 2 || decisions[0].Kind != uknow.PlayerDecisionPullFromDeck || decisions[1].Kind != uknow.PlayerDecisionPass {
+		t.Fatalf("expected a draw and a pass decision, got %+v", decisions)
+	}
+
+	if table.HandOfPlayer["alice"].Len() != 2 || table.PlayerOfNextTurn != "bob" || table.TableState != uknow.StartOfTurn {
+		t.Logf("expected alice to keep the drawn card and bob to play next, got hand %s, next player %s, state %s", table.HandOfPlayer["alice"], table.PlayerOfNextTurn, table.TableState)
+		t.Fail()
+	}
+}
+
+func TestForcedDrawPlaysPlayableCard(t *testing.T) {
+	drawnCard := uknow.Card{Number: 5, Color: uknow.ColorGreen}
+	table := newDrawPlayTable(drawnCard)
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	decisions, err := table.EvalForcedDrawDecision("alice", gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decisions) != 2 || decisions[1].Kind != uknow.PlayerDecisionPlayHandCard || !table.DiscardedPile.MustTop().IsEqual(drawnCard) {
+		t.Logf("expected the drawn %s to be played, got %+v", drawnCard.String(), decisions)
+		t.Fail()
+	}
+}
+
+func TestForcedDrawRejectedWithLegalPlay(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: 8, Color: uknow.ColorYellow})
+	table.HandOfPlayer["alice"] = table.HandOfPlayer["alice"].Push(uknow.Card{Number: 2, Color: uknow.ColorRed})
+
+	if legalPlays := table.LegalPlays("alice"); legalPlays.Len() != 1 {
+		t.Fatalf("expected the red 2 to be the only legal play, got %s", legalPlays)
+	}
+
+	_, err := table.EvalForcedDrawDecision("alice", nil)
+	if !errors.Is(err, uknow.ErrHasLegalPlay) {
+		t.Fatalf("expected ErrHasLegalPlay, got %v", err)
+	}
+
+	if table.HandOfPlayer["alice"].Len() != 2 || table.TableState != uknow.StartOfTurn {
+		t.Logf("expected nothing to be drawn, got hand %s, state %s", table.HandOfPlayer["alice"], table.TableState)
+		t.Fail()
+	}
+}
@@ -0,0 +1,30 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestDecisionsOutOfTurnAreRejected(t *testing.T) {
+	offTurnDecisions := []uknow.PlayerDecision{
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: 2, Color: uknow.ColorGreen}},
+		{Kind: uknow.PlayerDecisionPullFromDeck},
+		{Kind: uknow.PlayerDecisionPass},
+	}
+
+	for _, decision := range offTurnDecisions {
+		table := newStackTable(uknow.HouseRules{})
+		tableBefore := table.Clone()
+
+		_, err := table.EvalDecision("bob", decision)
+		var errEvalDecision *uknow.EvalDecisionError
+		if !errors.Is(err, uknow.ErrNotYourTurn) || !errors.As(err, &errEvalDecision) {
+			t.Errorf("%s: expected %v, got %v", decision.String(), uknow.ErrNotYourTurn, err)
+		}
+		if !table.Equal(tableBefore) {
+			t.Errorf("%s: expected the table to be unchanged", decision.String())
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+// Table of player_a, player_b and player_c, shuffled by player_a, with the given
+// card on top of the draw deck.
+func newOpeningCardTable(openingCard uknow.Card) *uknow.Table {
+	table := newTableWithPlayers(3)
+	table.DrawDeck = uknow.Deck{
+		{Number: 1, Color: uknow.ColorBlue},
+		{Number: 2, Color: uknow.ColorBlue},
+		openingCard,
+	}
+	return table
+}
+
+func TestOpeningCardActions(t *testing.T) {
+	testCases := []struct {
+		openingCard        uknow.Card
+		wantAction         uknow.OpeningAction
+		wantNextPlayer     string
+		wantAffectedPlayer string
+		wantDirection      int
+	}{
+		{uknow.Card{Number: 5, Color: uknow.ColorRed}, uknow.OpeningActionNone, "player_b", "", 1},
+		{uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed}, uknow.OpeningActionSkip, "player_c", "player_b", 1},
+		{uknow.Card{Number: uknow.NumberDrawTwo, Color: uknow.ColorRed}, uknow.OpeningActionDrawTwo, "player_c", "player_b", 1},
+		{uknow.Card{Number: uknow.NumberReverse, Color: uknow.ColorRed}, uknow.OpeningActionReverse, "player_c", "", -1},
+		{uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild}, uknow.OpeningActionNone, "player_b", "", 1},
+	}
+
+	for _, tc := range testCases {
+		table := newOpeningCardTable(tc.openingCard)
+
+		if err := table.FlipOpeningCard(); err != nil {
+			t.Fatalf("%s: %v", tc.openingCard.String(), err)
+		}
+
+		event := table.OpeningCardEvent
+		if event.Action != tc.wantAction || event.AffectedPlayer != tc.wantAffectedPlayer || event.NextPlayer != tc.wantNextPlayer {
+			t.Logf("%s: expected action %s affecting '%s' with next player %s, got %+v", tc.openingCard.String(), tc.wantAction, tc.wantAffectedPlayer, tc.wantNextPlayer, event)
+			t.Fail()
+		}
+
+		if table.PlayerOfNextTurn != tc.wantNextPlayer || table.Direction != tc.wantDirection {
+			t.Logf("%s: expected next player %s and direction %d, got %s and %d", tc.openingCard.String(), tc.wantNextPlayer, tc.wantDirection, table.PlayerOfNextTurn, table.Direction)
+			t.Fail()
+		}
+
+		if !table.DiscardedPile.MustTop().IsEqual(tc.openingCard) || table.RequiredNumberOfCurrentTurn != tc.openingCard.Number {
+			t.Logf("%s: expected opening card on the discard pile, got %s", tc.openingCard.String(), table.DiscardedPile)
+			t.Fail()
+		}
+
+		wantDrawnCards := 0
+		if tc.wantAction == uknow.OpeningActionDrawTwo {
+			wantDrawnCards = 2
+		}
+		if table.HandOfPlayer["player_b"].Len() != wantDrawnCards || table.DrawDeck.Len() != 2-wantDrawnCards {
+			t.Logf("%s: expected player_b to draw %d cards, hand: %s, draw deck: %s", tc.openingCard.String(), wantDrawnCards, table.HandOfPlayer["player_b"], table.DrawDeck)
+			t.Fail()
+		}
+	}
+}
+
+func TestOpeningCardWildDefaultsToRed(t *testing.T) {
+	table := newOpeningCardTable(uknow.Card{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild})
+
+	if err := table.FlipOpeningCard(); err != nil {
+		t.Fatal(err)
+	}
+
+	if table.RequiredColorOfCurrentTurn != uknow.ColorRed {
+		t.Logf("expected required color red, got %s", table.RequiredColorOfCurrentTurn.String())
+		t.Fail()
+	}
+}
+
+func TestOpeningCardWildTakesFirstPlayersMostHeldColor(t *testing.T) {
+	table := newOpeningCardTable(uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild})
+	table.HandOfPlayer["player_b"] = uknow.Deck{
+		{Number: 3, Color: uknow.ColorRed},
+		{Number: 3, Color: uknow.ColorYellow},
+		{Number: 8, Color: uknow.ColorYellow},
+		{Number: uknow.NumberWild, Color: uknow.ColorWild},
+	}
+
+	if err := table.FlipOpeningCard(); err != nil {
+		t.Fatal(err)
+	}
+
+	if table.RequiredColorOfCurrentTurn != uknow.ColorYellow {
+		t.Logf("expected required color yellow, got %s", table.RequiredColorOfCurrentTurn.String())
+		t.Fail()
+	}
+}
+
+func TestOpeningCardActionIgnored(t *testing.T) {
+	for _, number := range []uknow.Number{uknow.NumberSkip, uknow.NumberDrawTwo, uknow.NumberReverse} {
+		openingCard := uknow.Card{Number: number, Color: uknow.ColorGreen}
+		table := newOpeningCardTable(openingCard)
+		table.HouseRules.IgnoreOpeningAction = true
+
+		if err := table.FlipOpeningCard(); err != nil {
+			t.Fatal(err)
+		}
+
+		if table.OpeningCardEvent.Action != uknow.OpeningActionNone || table.PlayerOfNextTurn != "player_b" || table.Direction != 1 {
+			t.Logf("%s: expected no opening action, got %+v, direction %d", openingCard.String(), table.OpeningCardEvent, table.Direction)
+			t.Fail()
+		}
+
+		if table.HandOfPlayer["player_b"].Len() != 0 {
+			t.Logf("%s: expected player_b to draw no cards, got %s", openingCard.String(), table.HandOfPlayer["player_b"])
+			t.Fail()
+		}
+	}
+}
+
+func TestTurnOrderAfterReverse(t *testing.T) {
+	table := newOpeningCardTable(uknow.Card{Number: uknow.NumberReverse, Color: uknow.ColorBlue})
+	table.LocalPlayerName = "player_a"
+
+	if err := table.FlipOpeningCard(); err != nil {
+		t.Fatal(err)
+	}
+
+	if order := table.TurnOrderString(); order != "player_c → player_b → (you)" {
+		t.Logf("expected turn order to follow the reversed direction, got %s", order)
+		t.Fail()
+	}
+}
+
+func TestOpeningWildColorChosenBeforeFirstPlay(t *testing.T) {
+	table := newOpeningCardTable(uknow.Card{Number: uknow.NumberWild, Color: uknow.ColorWild})
+	table.HouseRules.ChooseOpeningWildColor = true
+	table.HandOfPlayer["player_b"] = uknow.Deck{
+		{Number: 3, Color: uknow.ColorRed},
+		{Number: 3, Color: uknow.ColorRed},
+		{Number: 4, Color: uknow.ColorBlue},
+	}
+
+	if err := table.FlipOpeningCard(); err != nil {
+		t.Fatal(err)
+	}
+
+	if table.TableState != uknow.AwaitingOpeningWildColorDecision || !table.OpeningCardEvent.NextPlayerChoosesColor {
+		t.Fatalf("expected player_b to be asked for the color, got state %s, event %+v", table.TableState, table.OpeningCardEvent)
+	}
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	redThree := uknow.Card{Number: 3, Color: uknow.ColorRed}
+	if _, err := table.EvalPlayerDecision("player_b", uknow.PlayerDecision{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redThree}, gameEventChan); err == nil {
+		t.Fatalf("expected playing before choosing the color to be rejected")
+	}
+
+	chooseBlue := uknow.PlayerDecision{Kind: uknow.PlayerDecisionWildCardChooseColor, WildCardChosenColor: uknow.ColorBlue}
+	if _, err := table.EvalPlayerDecision("player_b", chooseBlue, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	if table.TableState != uknow.StartOfTurn || table.PlayerOfNextTurn != "player_b" || table.RequiredColorOfCurrentTurn != uknow.ColorBlue {
+		t.Fatalf("expected player_b to keep the first turn with blue required, got state %s, player %s, color %s", table.TableState, table.PlayerOfNextTurn, table.RequiredColorOfCurrentTurn.String())
+	}
+
+	var errIllegalPlay *uknow.IllegalPlayError
+	_, err := table.EvalPlayerDecision("player_b", uknow.PlayerDecision{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: redThree}, gameEventChan)
+	if !errors.As(err, &errIllegalPlay) {
+		t.Fatalf("expected red 3 to be an illegal play on blue, got %v", err)
+	}
+
+	blueFour := uknow.Card{Number: 4, Color: uknow.ColorBlue}
+	if _, err := table.EvalPlayerDecision("player_b", uknow.PlayerDecision{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: blueFour}, gameEventChan); err != nil {
+		t.Logf("expected blue 4 to be playable, got %v", err)
+		t.Fail()
+	}
+}
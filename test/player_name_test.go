@@ -0,0 +1,48 @@
+package test
+
+import (
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestIsUserNameAllowed(t *testing.T) {
+	allowedOfName := map[string]bool{
+		"alice":             true,
+		"john_doe":          true,
+		"sixteen_chars_ok":  true,
+		"seventeen_chars_x": false,
+		"":                  false,
+		"bob1":              false,
+		"<deck>":            false,
+		"deck":              false,
+		"Pile":              false,
+		"ADMIN":             false,
+		"player_client":     false,
+		"command_prompt":    false,
+	}
+
+	for name, wantAllowed := range allowedOfName {
+		if uknow.IsUserNameAllowed(name) != wantAllowed {
+			t.Logf("IsUserNameAllowed(%q) != %v", name, wantAllowed)
+			t.Fail()
+		}
+	}
+}
+
+func TestFindPlayerNameFold(t *testing.T) {
+	table := uknow.NewAdminTable(log.Default())
+	table.AddPlayer("bob")
+
+	existingName, ok := table.FindPlayerNameFold("Bob")
+	if !ok || existingName != "bob" {
+		t.Logf("expected Bob to collide with bob, got %q, %v", existingName, ok)
+		t.Fail()
+	}
+
+	if _, ok := table.FindPlayerNameFold("alice"); ok {
+		t.Log("did not expect alice to collide with any player")
+		t.Fail()
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestRedactedTableHidesOpponentHands(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: 5, Color: uknow.ColorRed})
+	table.HandOfPlayer["bob"] = uknow.Deck{
+		{Number: 1, Color: uknow.ColorRed},
+		{Number: 2, Color: uknow.ColorBlue},
+	}
+
+	redacted := table.RedactedFor("bob")
+
+	for _, playerName := range table.PlayerNames {
+		hand := redacted.HandOfPlayer[playerName]
+		if hand.Len() != table.HandOfPlayer[playerName].Len() {
+			t.Fatalf("expected %s to hold %d cards, got %s", playerName, table.HandOfPlayer[playerName].Len(), hand)
+		}
+		if wantHidden := playerName != "bob" && hand.Len() != 0; hand.HasHiddenCards() != wantHidden {
+			t.Logf("%s: expected hidden cards: %v, got %s", playerName, wantHidden, hand)
+			t.Fail()
+		}
+	}
+
+	if redacted.DrawDeck.Len() != table.DrawDeck.Len() || redacted.DrawDeck.String() != uknow.NewHiddenDeck(table.DrawDeck.Len()).String() {
+		t.Logf("expected a hidden draw deck of %d cards, got %s", table.DrawDeck.Len(), redacted.DrawDeck)
+		t.Fail()
+	}
+
+	if table.HasHiddenCards() {
+		t.Fatalf("expected the original table to keep every hand and the draw deck, got %+v, draw deck %s", table.HandOfPlayer, table.DrawDeck)
+	}
+}
+
+func TestRedactedTableShowsDrawnCardOnlyToDrawer(t *testing.T) {
+	greenSeven := uknow.Card{Number: 7, Color: uknow.ColorGreen}
+	table := newDrawPlayTable(greenSeven)
+
+	pull := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}}
+	if err := table.EvalPlayerDecisionsNoTransferChan("alice", pull); err != nil {
+		t.Fatal(err)
+	}
+
+	if drawnCard := table.RedactedFor("alice").LastDrawnCard; drawnCard != greenSeven {
+		t.Logf("expected alice to see her drawn card %s, got %s", greenSeven.String(), drawnCard.String())
+		t.Fail()
+	}
+	if drawnCard := table.RedactedFor("bob").LastDrawnCard; drawnCard != (uknow.Card{}) {
+		t.Logf("expected bob to not see alice's drawn card, got %s", drawnCard.String())
+		t.Fail()
+	}
+
+	// Once alice passes, the drawn card is of no use to her either.
+	pass := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPass}}
+	if err := table.EvalPlayerDecisionsNoTransferChan("alice", pass); err != nil {
+		t.Fatal(err)
+	}
+	if drawnCard := table.RedactedFor("alice").LastDrawnCard; drawnCard != (uknow.Card{}) {
+		t.Logf("expected the drawn card to be cleared after alice's turn, got %s", drawnCard.String())
+		t.Fail()
+	}
+}
+
+func TestOpponentPlaysFromRedactedHand(t *testing.T) {
+	adminTable := newDrawPlayTable(uknow.Card{Number: 5, Color: uknow.ColorRed})
+	adminTable.HandOfPlayer["alice"] = uknow.Deck{
+		{Number: 5, Color: uknow.ColorBlue},
+		{Number: 8, Color: uknow.ColorGreen},
+	}
+
+	localTable := uknow.NewTable("bob", adminTable.Logger)
+	redacted := adminTable.RedactedFor("bob")
+	redacted.LocalPlayerName = "bob"
+	localTable.Set(redacted)
+
+	decisions := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: 5, Color: uknow.ColorBlue}}}
+	if err := localTable.EvalPlayerDecisionsNoTransferChan("alice", decisions); err != nil {
+		t.Fatal(err)
+	}
+
+	if localTable.HandOfPlayer["alice"].Len() != 1 || !localTable.DiscardedPile.MustTop().IsEqual(decisions[0].ResultCard) {
+		t.Logf("expected alice's play to leave one hidden card, hand: %s, pile: %s", localTable.HandOfPlayer["alice"], localTable.DiscardedPile)
+		t.Fail()
+	}
+
+	if localTable.NeedsResyncAfter(decisions) {
+		t.Logf("expected a plain play not to need a resync")
+		t.Fail()
+	}
+	challenge := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionDoChallenge}}
+	if !localTable.NeedsResyncAfter(challenge) || adminTable.NeedsResyncAfter(challenge) {
+		t.Logf("expected a challenge to need a resync only on the redacted table")
+		t.Fail()
+	}
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/admin"
+	"github.com/nrawrx3/uknow/internal/utils"
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+// Opens a room on the server and returns its game, served by the shared
+// httpServer.
+func openTestRoom(t *testing.T, server *admin.Server, httpServer *httptest.Server, roomCode string, clockOfRoom map[string]*heldClock) *testGame {
+	room, err := server.OpenRoom(roomCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testGame{
+		t:        t,
+		admin:    room.Admin,
+		server:   httpServer,
+		clock:    clockOfRoom[roomCode],
+		players:  make(map[string]*testGamePlayer),
+		roomCode: roomCode,
+	}
+}
+
+func TestRoomsAreIsolated(t *testing.T) {
+	clockOfRoom := map[string]*heldClock{"red": newHeldClock(), "blue": newHeldClock()}
+	server := admin.NewServer(utils.HostPortProtocol{}, func(roomCode string) *admin.Admin {
+		return newTestAdmin(t, clockOfRoom[roomCode], roomCode)
+	})
+	httpServer := httptest.NewServer(server.Handler())
+
+	red := openTestRoom(t, server, httpServer, "red", clockOfRoom)
+	blue := openTestRoom(t, server, httpServer, "blue", clockOfRoom)
+	defer func() {
+		red.clock.release()
+		blue.clock.release()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			t.Logf("failed to shut down server: %v", err)
+		}
+		httpServer.Close()
+	}()
+
+	if _, err := server.OpenRoom("red"); err == nil {
+		t.Fatal("expected opening the red room again to fail")
+	}
+
+	red.connect("alice", false, 0)
+	red.waitForAllPlayers(client.WaitingForAdminToServeCards, 5*time.Second)
+	blue.connect("carol", false, 0)
+	blue.waitForAllPlayers(client.WaitingForAdminToServeCards, 5*time.Second)
+	blue.connect("dave", false, 0)
+	blue.waitForAllPlayers(client.WaitingForAdminToServeCards, 5*time.Second)
+
+	// Only the red room is served, the blue one keeps waiting for players.
+	red.connect("bob", true, 2)
+	red.waitForAllPlayers(client.WaitingForAdminToChoosePlayer, 10*time.Second)
+
+	for roomCode, game := range map[string]*testGame{"red": red, "blue": blue} {
+		for playerName, player := range game.players {
+			handCounts := player.client.HandCounts()
+			for handOwner := range handCounts {
+				if _, ok := game.players[handOwner]; !ok {
+					t.Logf("%s in room %s: expected only the hands of the room's players, got %v", playerName, roomCode, handCounts)
+					t.Fail()
+				}
+			}
+		}
+	}
+
+	for playerName, player := range red.players {
+		if count := player.client.HandCounts()[playerName]; count < uknow.DefaultStartingHandCount {
+			t.Logf("%s: expected the served cards of the red room, got %d cards", playerName, count)
+			t.Fail()
+		}
+	}
+	for playerName, player := range blue.players {
+		if state := player.client.State(); state != client.WaitingForAdminToServeCards {
+			t.Logf("%s: expected the blue room to still wait for players, client state is %s", playerName, state)
+			t.Fail()
+		}
+	}
+
+	// A room that isn't open has no admin API.
+	resp, err := http.Get(httpServer.URL + "/room/green/counts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Logf("expected status 404 for a room that isn't open, got %d", resp.StatusCode)
+		t.Fail()
+	}
+}
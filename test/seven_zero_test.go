@@ -0,0 +1,158 @@
+package test
+
+import (
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+// alice, bob and carol with the Seven-Zero rule, alice to play on a red 5.
+func newSevenZeroTable() *uknow.Table {
+	table := uknow.NewAdminTable(log.Default())
+	table.HouseRules.SevenZero = true
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.AddPlayer("carol")
+
+	table.HandOfPlayer["alice"] = uknow.Deck{
+		{Number: 0, Color: uknow.ColorRed},
+		{Number: 7, Color: uknow.ColorRed},
+		{Number: 1, Color: uknow.ColorGreen},
+	}
+	table.HandOfPlayer["bob"] = uknow.Deck{{Number: 2, Color: uknow.ColorBlue}}
+	table.HandOfPlayer["carol"] = uknow.Deck{
+		{Number: 3, Color: uknow.ColorBlue},
+		{Number: 4, Color: uknow.ColorBlue},
+	}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+
+	table.PlayerOfNextTurn = "alice"
+	table.TableState = uknow.StartOfTurn
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 5
+	return table
+}
+
+func totalHandCount(table *uknow.Table) int {
+	count := 0
+	for _, hand := range table.HandOfPlayer {
+		count += hand.Len()
+	}
+	return count
+}
+
+func TestSevenZeroPlayingZeroRotatesHands(t *testing.T) {
+	table := newSevenZeroTable()
+	bobHand := table.HandOfPlayer["bob"].Encode()
+	carolHand := table.HandOfPlayer["carol"].Encode()
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	_, err := table.EvalPlayerDecision("alice", uknow.PlayerDecision{
+		Kind:       uknow.PlayerDecisionPlayHandCard,
+		ResultCard: uknow.Card{Number: 0, Color: uknow.ColorRed},
+	}, gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// alice's remaining hand goes to bob, bob's to carol and carol's to alice.
+	if table.HandOfPlayer["bob"].Encode() != "r7,g1" || table.HandOfPlayer["carol"].Encode() != bobHand || table.HandOfPlayer["alice"].Encode() != carolHand {
+		t.Logf("unexpected hands after rotation, alice: %s, bob: %s, carol: %s", table.HandOfPlayer["alice"], table.HandOfPlayer["bob"], table.HandOfPlayer["carol"])
+		t.Fail()
+	}
+
+	if totalHandCount(table) != 5 {
+		t.Logf("expected 5 cards in hands, got %d", totalHandCount(table))
+		t.Fail()
+	}
+
+	if table.PlayerOfNextTurn != "bob" || table.TableState != uknow.StartOfTurn {
+		t.Logf("expected bob's turn to start, got player %s, state %s", table.PlayerOfNextTurn, table.TableState)
+		t.Fail()
+	}
+}
+
+func TestSevenZeroPlayingSevenSwapsHands(t *testing.T) {
+	table := newSevenZeroTable()
+	carolHand := table.HandOfPlayer["carol"].Encode()
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	_, err := table.EvalPlayerDecision("alice", uknow.PlayerDecision{
+		Kind:       uknow.PlayerDecisionPlayHandCard,
+		ResultCard: uknow.Card{Number: 7, Color: uknow.ColorRed},
+	}, gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.TableState != uknow.AwaitingSwapTargetDecision || table.PlayerOfNextTurn != "alice" || !table.NeedMoreUserDecisionToFinishTurn() {
+		t.Fatalf("expected alice to choose a swap target, got player %s, state %s", table.PlayerOfNextTurn, table.TableState)
+	}
+
+	_, err = table.EvalPlayerDecision("alice", uknow.PlayerDecision{
+		Kind:             uknow.PlayerDecisionChooseSwapTarget,
+		SwapTargetPlayer: "alice",
+	}, gameEventChan)
+	if !errors.Is(err, uknow.ErrInvalidDecision) {
+		t.Logf("expected swapping with self to be invalid, got %v", err)
+		t.Fail()
+	}
+
+	_, err = table.EvalPlayerDecision("alice", uknow.PlayerDecision{
+		Kind:             uknow.PlayerDecisionChooseSwapTarget,
+		SwapTargetPlayer: "carol",
+	}, gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.HandOfPlayer["alice"].Encode() != carolHand || table.HandOfPlayer["carol"].Encode() != "r0,g1" {
+		t.Logf("unexpected hands after swap, alice: %s, carol: %s", table.HandOfPlayer["alice"], table.HandOfPlayer["carol"])
+		t.Fail()
+	}
+
+	if totalHandCount(table) != 5 {
+		t.Logf("expected 5 cards in hands, got %d", totalHandCount(table))
+		t.Fail()
+	}
+
+	if table.PlayerOfNextTurn != "bob" || table.TableState != uknow.StartOfTurn {
+		t.Logf("expected bob's turn to start, got player %s, state %s", table.PlayerOfNextTurn, table.TableState)
+		t.Fail()
+	}
+}
+
+func TestSevenZeroGameViewsAgree(t *testing.T) {
+	playerNames := []string{"alice", "bob", "carol"}
+	strategyOfPlayer := make(map[string]uknow.PlayerStrategy)
+	for _, playerName := range playerNames {
+		strategyOfPlayer[playerName] = uknow.FirstPlayableCardStrategy
+	}
+
+	game, err := uknow.NewGame(uknow.GameConfig{
+		Seed:             7,
+		Rules:            uknow.GameRules{HouseRules: uknow.HouseRules{SevenZero: true}},
+		PlayerNames:      playerNames,
+		StrategyOfPlayer: strategyOfPlayer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Draw deck can run out before a winner, which is fine here.
+	result, _ := game.Run()
+
+	for _, playerName := range playerNames {
+		for _, handOwner := range playerNames {
+			if game.TableOfPlayer(playerName).HandOfPlayer[handOwner].Encode() != result.Table.HandOfPlayer[handOwner].Encode() {
+				t.Fatalf("%s's view of %s's hand differs from admin's", playerName, handOwner)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+func TestServedCardsReachSlowUI(t *testing.T) {
+	done := make(chan struct{})
+
+	servedTable := uknow.NewAdminTable(log.Default())
+	servedTable.AddPlayer("alice")
+	servedTable.AddPlayer("bob")
+	servedTable.ShufflerName = "alice"
+	if err := servedTable.ShuffleDeckAndDistribute(uknow.DefaultStartingHandCount); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/player", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		writeServerEvent(t, w, messages.ExistingPlayersListEvent{PlayerNames: []string{"alice"}})
+		writeServerEvent(t, w, messages.ServedCardsEvent{Table: *servedTable})
+		<-done
+	})
+	mux.HandleFunc("/ack_player_added", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	fakeAdmin := httptest.NewServer(mux)
+	defer fakeAdmin.Close()
+	defer close(done)
+
+	adminAddr, err := utils.ResolveTCPAddress(strings.TrimPrefix(fakeAdmin.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logWindowChan := make(chan string, 64)
+	go func() {
+		for range logWindowChan {
+		}
+	}()
+	// Not closed, the client keeps sending game events after the test is done.
+	gameEventChan := drainGameEvents()
+	replCommandChan := make(chan *client.ReplCommand)
+
+	// Unbuffered so that the client has to wait for the UI.
+	uiCommandChan := make(chan client.UICommand)
+
+	c := client.NewPlayerClient(&client.ConfigNewPlayerClient{
+		ClientChannels: client.ClientChannels{
+			GeneralUICommandPushChan:       uiCommandChan,
+			NonDecisionReplCommandPullChan: replCommandChan,
+			LogWindowPushChan:              logWindowChan,
+			GameEventPushChan:              gameEventChan,
+		},
+		Table:            uknow.NewTable("bob", log.Default()),
+		DefaultAdminAddr: adminAddr,
+		LogDir:           t.TempDir(),
+	})
+	go c.RunGeneralCommandHandler()
+
+	replCommandChan <- &client.ReplCommand{Kind: client.CmdConnect}
+
+	// The UI is busy for longer than a single attempt of the client, which
+	// waits a second each time.
+	time.Sleep(1500 * time.Millisecond)
+
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case uiCommand := <-uiCommandChan:
+			switch uiCommand.(type) {
+			case *client.UICommandSetServedCards:
+				return
+			case *client.UICommandSetConnectionStatus:
+				// Shown once the client checks the connection, which can
+				// happen while the UI is busy.
+				continue
+			default:
+				t.Fatalf("expected the served cards command, got %T", uiCommand)
+			}
+		case <-timeout:
+			t.Fatalf("expected the client to retry sending the served cards to the UI")
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+	"github.com/nrawrx3/uknow/internal/utils"
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+// Starts a fake admin with the given /player handler and connects bob to it.
+func connectBobToFakeAdmin(t *testing.T, playerHandler http.HandlerFunc, carolAcked chan<- struct{}) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/player", playerHandler)
+	mux.HandleFunc("/ack_player_added", func(w http.ResponseWriter, r *http.Request) {
+		var ackMsg messages.AckNewPlayerAddedMessage
+		if err := messages.DecryptAndDecodeJSON(&ackMsg, r.Body, nil); err == nil && ackMsg.NewPlayer == "carol" {
+			carolAcked <- struct{}{}
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	fakeAdmin := httptest.NewServer(mux)
+	t.Cleanup(fakeAdmin.Close)
+
+	adminAddr, err := utils.ResolveTCPAddress(strings.TrimPrefix(fakeAdmin.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logWindowChan := make(chan string, 64)
+	go func() {
+		for range logWindowChan {
+		}
+	}()
+	replCommandChan := make(chan *client.ReplCommand)
+
+	c := client.NewPlayerClient(&client.ConfigNewPlayerClient{
+		ClientChannels: client.ClientChannels{
+			NonDecisionReplCommandPullChan: replCommandChan,
+			LogWindowPushChan:              logWindowChan,
+		},
+		Table:            uknow.NewTable("bob", log.Default()),
+		DefaultAdminAddr: adminAddr,
+		LogDir:           t.TempDir(),
+	})
+	go c.RunGeneralCommandHandler()
+
+	replCommandChan <- &client.ReplCommand{Kind: client.CmdConnect}
+}
+
+func TestMalformedEventFollowedByValidEvent(t *testing.T) {
+	carolAcked := make(chan struct{}, 4)
+	done := make(chan struct{})
+	defer close(done)
+
+	connectBobToFakeAdmin(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		writeServerEvent(t, w, messages.ExistingPlayersListEvent{PlayerNames: []string{"alice"}})
+
+		// Truncated write of an event
+		fmt.Fprintf(w, "{\"type\":\"%s\",\"event\":{\"player_na\n", messages.PlayerJoinedEvent{}.EventType())
+		w.(http.Flusher).Flush()
+
+		writeServerEvent(t, w, messages.PlayerJoinedEvent{PlayerName: "carol"})
+		<-done
+	}, carolAcked)
+
+	select {
+	case <-carolAcked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected bob to skip the truncated event and ack carol")
+	}
+}
+
+func TestRepeatedMalformedEventsReconnect(t *testing.T) {
+	var connectCount int32
+	carolAcked := make(chan struct{}, 4)
+	done := make(chan struct{})
+	defer close(done)
+
+	connectBobToFakeAdmin(t, func(w http.ResponseWriter, r *http.Request) {
+		count := atomic.AddInt32(&connectCount, 1)
+
+		w.WriteHeader(http.StatusOK)
+		writeServerEvent(t, w, messages.ExistingPlayersListEvent{PlayerNames: []string{"alice"}})
+
+		if count == 1 {
+			for i := 0; i < 5; i++ {
+				fmt.Fprintf(w, "{\"type\":\n")
+			}
+			w.(http.Flusher).Flush()
+		} else {
+			writeServerEvent(t, w, messages.PlayerJoinedEvent{PlayerName: "carol"})
+		}
+
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}, carolAcked)
+
+	select {
+	case <-carolAcked:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected bob to reconnect after the corrupt stream, connect count: %d", atomic.LoadInt32(&connectCount))
+	}
+
+	if count := atomic.LoadInt32(&connectCount); count != 2 {
+		t.Logf("expected exactly one reconnect, got %d connects", count)
+		t.Fail()
+	}
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"errors"
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+var (
+	blueThree    = uknow.Card{Number: 3, Color: uknow.ColorBlue}
+	greenThree   = uknow.Card{Number: 3, Color: uknow.ColorGreen}
+	yellowNine   = uknow.Card{Number: 9, Color: uknow.ColorYellow}
+	greenDrawTwo = uknow.Card{Number: uknow.NumberDrawTwo, Color: uknow.ColorGreen}
+)
+
+// Table at the start of alice's turn with a red 3 on top of the discard pile.
+func newStackTable(houseRules uknow.HouseRules) *uknow.Table {
+	table := uknow.NewAdminTable(log.Default())
+	table.AddPlayer("alice")
+	table.AddPlayer("bob")
+	table.AddPlayer("carol")
+	table.HouseRules = houseRules
+
+	table.HandOfPlayer["alice"] = uknow.Deck{blueThree, greenThree, yellowNine, redDrawTwo, greenDrawTwo}
+	table.HandOfPlayer["bob"] = uknow.Deck{{Number: 2, Color: uknow.ColorGreen}}
+	table.HandOfPlayer["carol"] = uknow.Deck{{Number: 4, Color: uknow.ColorGreen}}
+	table.DiscardedPile = uknow.Deck{{Number: 3, Color: uknow.ColorRed}}
+	table.DrawDeck = uknow.Deck{
+		{Number: 6, Color: uknow.ColorBlue},
+		{Number: 7, Color: uknow.ColorBlue},
+		{Number: 8, Color: uknow.ColorBlue},
+		{Number: 1, Color: uknow.ColorBlue},
+	}
+
+	table.PlayerOfNextTurn = "alice"
+	table.TableState = uknow.StartOfTurn
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.RequiredNumberOfCurrentTurn = 3
+	return table
+}
+
+func TestStackSameNumberDrop(t *testing.T) {
+	houseRules := uknow.HouseRules{StackSameNumber: true}
+	table := newStackTable(houseRules)
+	bobTable := newStackTable(houseRules)
+
+	gameEventChan := drainGameEvents()
+	decisions, err := table.EvalDropCardsDecision("alice", []uknow.Card{blueThree, greenThree}, gameEventChan)
+	close(gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decisions) != 2 || decisions[0].ResultCard != blueThree || decisions[1].ResultCard != greenThree {
+		t.Fatalf("expected a play decision for each card, got %+v", decisions)
+	}
+	if !table.DiscardedPile.MustTop().IsEqual(greenThree) || table.DiscardedPile.Len() != 3 {
+		t.Errorf("expected both 3s on the pile with the green one on top, got %s", table.DiscardedPile)
+	}
+	if !table.HandOfPlayer["alice"].Equal(uknow.Deck{yellowNine, redDrawTwo, greenDrawTwo}) {
+		t.Errorf("expected the 3s to leave alice's hand, got %s", table.HandOfPlayer["alice"])
+	}
+	if table.PlayerOfNextTurn != "bob" || table.RequiredColorOfCurrentTurn != uknow.ColorGreen || table.RequiredNumberOfCurrentTurn != 3 {
+		t.Errorf("expected bob to play on a green 3, got %s on %s %s", table.PlayerOfNextTurn, table.RequiredColorOfCurrentTurn.String(), table.RequiredNumberOfCurrentTurn.String())
+	}
+
+	// The other players sync the decisions as usual.
+	if err := bobTable.EvalDecisions("alice", decisions); err != nil {
+		t.Fatal(err)
+	}
+	if !bobTable.Equal(table) {
+		t.Errorf("expected bob's table to match after syncing the stacked drop")
+	}
+}
+
+func TestStackedDrawTwosAddUp(t *testing.T) {
+	table := newStackTable(uknow.HouseRules{StackSameNumber: true})
+	table.RequiredColorOfCurrentTurn = uknow.ColorRed
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+	table.RequiredNumberOfCurrentTurn = 5
+
+	gameEventChan := drainGameEvents()
+	_, err := table.EvalDropCardsDecision("alice", []uknow.Card{redDrawTwo, greenDrawTwo}, gameEventChan)
+	close(gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.HandOfPlayer["bob"].Len() != 5 || table.PlayerOfNextTurn != "carol" {
+		t.Fatalf("expected bob to draw 4 cards and carol to play next, got bob's hand %s and %s next", table.HandOfPlayer["bob"], table.PlayerOfNextTurn)
+	}
+}
+
+func TestStackedSkipsSkipTheNextPlayerOnce(t *testing.T) {
+	redSkip := uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed}
+	blueSkip := uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorBlue}
+
+	table := newStackTable(uknow.HouseRules{StackSameNumber: true})
+	table.HandOfPlayer["alice"] = uknow.Deck{redSkip, blueSkip, yellowNine}
+	table.DiscardedPile = uknow.Deck{{Number: 5, Color: uknow.ColorRed}}
+	table.RequiredNumberOfCurrentTurn = 5
+
+	gameEventChan := drainGameEvents()
+	_, err := table.EvalDropCardsDecision("alice", []uknow.Card{redSkip, blueSkip}, gameEventChan)
+	close(gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.PlayerOfNextTurn != "carol" || table.RequiredColorOfCurrentTurn != uknow.ColorBlue {
+		t.Fatalf("expected only bob to be skipped and carol to play on blue, got %s on %s", table.PlayerOfNextTurn, table.RequiredColorOfCurrentTurn.String())
+	}
+	if !table.HandOfPlayer["alice"].Equal(uknow.Deck{yellowNine}) {
+		t.Errorf("expected both skips to leave alice's hand, got %s", table.HandOfPlayer["alice"])
+	}
+}
+
+func TestIllegalStackedDropIsRejectedWhole(t *testing.T) {
+	cases := []struct {
+		houseRules uknow.HouseRules
+		cards      []uknow.Card
+		wantErr    error
+	}{
+		{uknow.HouseRules{}, []uknow.Card{blueThree, greenThree}, uknow.ErrStackingNotAllowed},
+		{uknow.HouseRules{StackSameNumber: true}, []uknow.Card{blueThree, yellowNine}, uknow.ErrIllegalStackedPlay},
+		{uknow.HouseRules{StackSameNumber: true}, []uknow.Card{blueThree, greenThree, greenThree}, uknow.ErrCardNotInHand},
+	}
+
+	for _, c := range cases {
+		table := newStackTable(c.houseRules)
+		tableBefore := table.Clone()
+
+		gameEventChan := drainGameEvents()
+		decisions, err := table.EvalDropCardsDecision("alice", c.cards, gameEventChan)
+		close(gameEventChan)
+
+		if !errors.Is(err, c.wantErr) || decisions != nil {
+			t.Errorf("%+v, %v: expected %v, got %v and decisions %+v", c.houseRules, c.cards, c.wantErr, err, decisions)
+		}
+		if !table.Equal(tableBefore) {
+			t.Errorf("%+v, %v: expected none of the cards to be played, got pile %s and alice's hand %s", c.houseRules, c.cards, table.DiscardedPile, table.HandOfPlayer["alice"])
+		}
+	}
+
+	// Nor is a stacked play synced from another player accepted without the
+	// rule.
+	table := newStackTable(uknow.HouseRules{})
+	err := table.EvalDecisions("alice", []uknow.PlayerDecision{
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: blueThree},
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: greenThree},
+	})
+	if !errors.Is(err, uknow.ErrStackingNotAllowed) {
+		t.Errorf("expected synced stacked plays to be rejected with %v, got %v", uknow.ErrStackingNotAllowed, err)
+	}
+}
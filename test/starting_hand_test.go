@@ -0,0 +1,144 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func newTableWithPlayers(playerCount int) *uknow.Table {
+	table := uknow.NewAdminTable(log.Default())
+	for i := 0; i < playerCount; i++ {
+		table.AddPlayer(fmt.Sprintf("player_%c", 'a'+i))
+	}
+	table.ShufflerName = "player_a"
+	return table
+}
+
+func TestShuffleDeckAndDistributeHandSizes(t *testing.T) {
+	for _, playerCount := range []int{2, 4, 8} {
+		for _, handCount := range []int{1, uknow.DefaultStartingHandCount, uknow.MaxStartingHandCount} {
+			table := newTableWithPlayers(playerCount)
+			// An opening draw2 card would add to the first player's hand
+			table.HouseRules.IgnoreOpeningAction = true
+			deckSize := table.DrawDeck.Len()
+
+			if err := table.ShuffleDeckAndDistribute(handCount); err != nil {
+				t.Fatalf("players: %d, hand count: %d: %v", playerCount, handCount, err)
+			}
+
+			for playerName, hand := range table.HandOfPlayer {
+				if hand.Len() != handCount {
+					t.Logf("players: %d: expected %s to have %d cards, got %d", playerCount, playerName, handCount, hand.Len())
+					t.Fail()
+				}
+			}
+
+			wantDrawDeckSize := deckSize - playerCount*handCount - 1
+			if table.DrawDeck.Len() != wantDrawDeckSize {
+				t.Logf("players: %d, hand count: %d: expected draw deck size %d, got %d", playerCount, handCount, wantDrawDeckSize, table.DrawDeck.Len())
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestShuffleDeckAndDistributeInvalidHandSizes(t *testing.T) {
+	for _, handCount := range []int{-1, 0, uknow.MaxStartingHandCount + 1} {
+		table := newTableWithPlayers(2)
+		err := table.ShuffleDeckAndDistribute(handCount)
+		if !errors.Is(err, uknow.ErrInvalidStartingHandCount) {
+			t.Logf("hand count %d: expected ErrInvalidStartingHandCount, got %v", handCount, err)
+			t.Fail()
+		}
+		if table.IsShuffled {
+			t.Logf("hand count %d: table should not be shuffled", handCount)
+			t.Fail()
+		}
+	}
+
+	// 9 players with 12 cards each need more than the full deck has.
+	table := newTableWithPlayers(9)
+	if err := table.ValidateStartingHandCount(uknow.MaxStartingHandCount); !errors.Is(err, uknow.ErrInvalidStartingHandCount) {
+		t.Logf("expected ErrInvalidStartingHandCount when deck is too small, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestShuffleDeckAndDistributeSameSeedSameHands(t *testing.T) {
+	dealSeeded := func() *uknow.Table {
+		table := newTableWithPlayers(6)
+		table.Rand = rand.New(rand.NewSource(1234))
+		if err := table.ShuffleDeckAndDistribute(uknow.DefaultStartingHandCount); err != nil {
+			t.Fatal(err)
+		}
+		return table
+	}
+
+	want := dealSeeded()
+
+	// Map iteration order differs between runs, so deal a few times.
+	for i := 0; i < 10; i++ {
+		table := dealSeeded()
+		for _, playerName := range want.PlayerNames {
+			if table.HandOfPlayer[playerName].Encode() != want.HandOfPlayer[playerName].Encode() {
+				t.Fatalf("deal %d: expected %s to get %s, got %s", i, playerName, want.HandOfPlayer[playerName].Encode(), table.HandOfPlayer[playerName].Encode())
+			}
+		}
+
+		if table.DrawDeck.Encode() != want.DrawDeck.Encode() || table.DiscardedPile.Encode() != want.DiscardedPile.Encode() {
+			t.Fatalf("deal %d: expected same draw deck and discard pile", i)
+		}
+	}
+}
+
+func TestStartingHandCountWithMultipleDecks(t *testing.T) {
+	// 10 players with 12 cards each need more than a single deck has.
+	table := newTableWithPlayers(10)
+	if err := table.ValidateStartingHandCount(uknow.MaxStartingHandCount); !errors.Is(err, uknow.ErrInvalidStartingHandCount) {
+		t.Fatalf("expected ErrInvalidStartingHandCount with a single deck, got %v", err)
+	}
+
+	table.DrawDeck = uknow.NewFullDeckN(2)
+	if err := table.ShuffleDeckAndDistribute(uknow.MaxStartingHandCount); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestShuffleDeckAndDistributeDeckSizeBoundary(t *testing.T) {
+	const playerCount = 4
+	const handCount = 7
+	requiredCards := playerCount*handCount + 1
+
+	plainDeck := func(size int) uknow.Deck {
+		deck := make(uknow.Deck, 0, size)
+		for i := 0; i < size; i++ {
+			deck = append(deck, uknow.Card{Number: uknow.Number(i % 10), Color: uknow.ColorRed})
+		}
+		return deck
+	}
+
+	table := newTableWithPlayers(playerCount)
+	table.DrawDeck = plainDeck(requiredCards - 1)
+	err := table.ShuffleDeckAndDistribute(handCount)
+	if !errors.Is(err, uknow.ErrNotEnoughCardsToDeal) || !errors.Is(err, uknow.ErrInvalidStartingHandCount) {
+		t.Fatalf("expected ErrNotEnoughCardsToDeal with %d cards, got %v", requiredCards-1, err)
+	}
+	if table.IsShuffled || table.DrawDeck.Len() != requiredCards-1 {
+		t.Fatalf("expected the draw deck to be left untouched, have %d cards", table.DrawDeck.Len())
+	}
+
+	table = newTableWithPlayers(playerCount)
+	table.DrawDeck = plainDeck(requiredCards)
+	if err := table.ShuffleDeckAndDistribute(handCount); err != nil {
+		t.Fatalf("expected dealing with exactly %d cards to succeed, got %v", requiredCards, err)
+	}
+	if table.DrawDeck.Len() != 0 || table.DiscardedPile.Len() != 1 {
+		t.Logf("expected an empty draw deck and the opening card on the discard pile, got %d and %d cards", table.DrawDeck.Len(), table.DiscardedPile.Len())
+		t.Fail()
+	}
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestHandOfKnownAndUnknownPlayers(t *testing.T) {
+	table := newStackTable(uknow.HouseRules{JumpIn: true})
+
+	hand, err := table.Hand("alice")
+	if err != nil || !hand.Equal(table.HandOfPlayer["alice"]) {
+		t.Fatalf("expected alice's hand %s, got %s, %v", table.HandOfPlayer["alice"], hand, err)
+	}
+	if hand := table.MustHand("bob"); !hand.Equal(table.HandOfPlayer["bob"]) {
+		t.Fatalf("expected bob's hand %s, got %s", table.HandOfPlayer["bob"], hand)
+	}
+
+	if hand, err := table.Hand("dave"); !errors.Is(err, uknow.ErrUnknownPlayer) || hand != nil {
+		t.Fatalf("expected %v for dave, got %s, %v", uknow.ErrUnknownPlayer, hand, err)
+	}
+
+	func() {
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, uknow.ErrUnknownPlayer) {
+				t.Errorf("expected MustHand to panic with %v for dave, got %v", uknow.ErrUnknownPlayer, err)
+			}
+		}()
+		table.MustHand("dave")
+	}()
+
+	// Decisions of an unknown player are rejected without touching the table.
+	tableBefore := table.Clone()
+	for _, decision := range []uknow.PlayerDecision{
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: blueThree},
+		{Kind: uknow.PlayerDecisionJumpIn, ResultCard: uknow.Card{Number: 3, Color: uknow.ColorRed}},
+	} {
+		if _, err := table.EvalDecision("dave", decision); !errors.Is(err, uknow.ErrUnknownPlayer) {
+			t.Errorf("%s: expected %v, got %v", decision.String(), uknow.ErrUnknownPlayer, err)
+		}
+	}
+	if !table.Equal(tableBefore) {
+		t.Errorf("expected the table to be unchanged")
+	}
+
+	// Nor can an unknown player undo a draw.
+	table.HouseRules.UndoDrawReturnsCard = true
+	table.TableState = uknow.AwaitingDropOrPass
+	table.LastDrawnCard = yellowNine
+	undo := uknow.PlayerDecision{Kind: uknow.PlayerDecisionUndoDraw}
+	if _, err := table.EvalDecision("dave", undo); !errors.Is(err, uknow.ErrUnknownPlayer) {
+		t.Errorf("%s: expected %v, got %v", undo.String(), uknow.ErrUnknownPlayer, err)
+	}
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestStatsCountDrawsPlaysAndSkips(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: uknow.NumberSkip, Color: uknow.ColorRed})
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	if err := table.EvalPlayerDecisions("alice", drawThenPlayRedSkip, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := table.Stats()
+	wantAlice := uknow.PlayerStats{CardsPlayed: 1, CardsDrawn: 1, SkipsDealt: 1}
+	wantBob := uknow.PlayerStats{SkipsReceived: 1}
+	if stats["alice"] != wantAlice || stats["bob"] != wantBob {
+		t.Fatalf("expected stats %+v for alice and %+v for bob, got %+v", wantAlice, wantBob, stats)
+	}
+
+	// The copy returned by Stats doesn't change with the table.
+	if err := table.EvalPlayerDecisions("alice", []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionPullFromDeck}}, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+	if stats["alice"].CardsDrawn != 1 || table.Stats()["alice"].CardsDrawn != 2 {
+		t.Logf("expected 1 draw in the old stats and 2 in the new ones, got %d and %d", stats["alice"].CardsDrawn, table.Stats()["alice"].CardsDrawn)
+		t.Fail()
+	}
+}
+
+func TestStatsUndoneDrawIsNotCounted(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: 3, Color: uknow.ColorYellow})
+	table.HouseRules.UndoDrawReturnsCard = true
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	if err := table.EvalPlayerDecisions("alice", drawThenUndo, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats := table.Stats()["alice"]; stats.CardsDrawn != 0 {
+		t.Fatalf("expected the undone draw not to be counted, got %+v", stats)
+	}
+}
+
+func TestStatsCountChallenges(t *testing.T) {
+	testCases := []struct {
+		otherCard          uknow.Card
+		wantAlice, wantBob uknow.PlayerStats
+	}{
+		// alice held a red card, so bob wins and alice draws 4.
+		{
+			uknow.Card{Number: 2, Color: uknow.ColorRed},
+			uknow.PlayerStats{CardsPlayed: 1, WildsPlayed: 1, CardsDrawn: 4, ChallengesLost: 1},
+			uknow.PlayerStats{ChallengesWon: 1},
+		},
+		// alice held no red card, so bob loses and draws 4.
+		{
+			uknow.Card{Number: 8, Color: uknow.ColorGreen},
+			uknow.PlayerStats{CardsPlayed: 1, WildsPlayed: 1, ChallengesWon: 1},
+			uknow.PlayerStats{CardsDrawn: 4, ChallengesLost: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		table := challengeWildDraw4(t, tc.otherCard)
+
+		stats := table.Stats()
+		if stats["alice"] != tc.wantAlice || stats["bob"] != tc.wantBob {
+			t.Logf("%s: expected stats %+v for alice and %+v for bob, got %+v", tc.otherCard.String(), tc.wantAlice, tc.wantBob, stats)
+			t.Fail()
+		}
+	}
+}
+
+func TestPlayerHasWonEventHasStats(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: 3, Color: uknow.ColorYellow})
+	table.HandOfPlayer["alice"] = uknow.Deck{{Number: 5, Color: uknow.ColorBlue}}
+
+	recorder := &uknow.GameEventRecorder{}
+	table.EventSink = recorder
+
+	decision := uknow.PlayerDecision{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: 5, Color: uknow.ColorBlue}}
+	if err := table.EvalDecisions("alice", []uknow.PlayerDecision{decision}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, event := range recorder.Events {
+		if wonEvent, ok := event.(uknow.PlayerHasWonEvent); ok {
+			if wonEvent.Stats["alice"].CardsPlayed != 1 {
+				t.Fatalf("expected the winning card to be counted, got %+v", wonEvent.Stats)
+			}
+			return
+		}
+	}
+	t.Fatal("expected a PlayerHasWonEvent")
+}
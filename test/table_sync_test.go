@@ -0,0 +1,133 @@
+package test
+
+import (
+	"encoding/json"
+	"log"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+	"github.com/nrawrx3/uknow/internal/messages"
+)
+
+// Serves the admin's table to the given player the same way the admin does,
+// through a ServedCardsEvent.
+func serveTable(t *testing.T, adminTable *uknow.Table, playerName string) *uknow.Table {
+	b, err := json.Marshal(messages.ServedCardsEvent{Table: *adminTable})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var servedCardsEvent messages.ServedCardsEvent
+	if err := json.Unmarshal(b, &servedCardsEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	if !servedCardsEvent.Table.TableState.IsKnown() {
+		t.Fatalf("served table has unknown state %q", servedCardsEvent.Table.TableState)
+	}
+
+	playerTable := uknow.NewTable(playerName, log.Default())
+	servedCardsEvent.Table.LocalPlayerName = playerName
+	playerTable.Set(&servedCardsEvent.Table)
+	return playerTable
+}
+
+func TestServedTableKeepsChallengeState(t *testing.T) {
+	adminTable := newLastCardTable(uknow.Card{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild})
+	adminTable.HandOfPlayer["alice"] = adminTable.HandOfPlayer["alice"].Push(uknow.Card{Number: 1, Color: uknow.ColorRed})
+	adminTable.DrawDeck = append(uknow.Deck{
+		{Number: 1, Color: uknow.ColorYellow},
+		{Number: 2, Color: uknow.ColorYellow},
+		{Number: 3, Color: uknow.ColorYellow},
+		{Number: 4, Color: uknow.ColorYellow},
+	}, adminTable.DrawDeck...)
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	err := adminTable.EvalPlayerDecisions("alice", []uknow.PlayerDecision{
+		{Kind: uknow.PlayerDecisionPlayHandCard, ResultCard: uknow.Card{Number: uknow.NumberWildDrawFour, Color: uknow.ColorWild}},
+		{Kind: uknow.PlayerDecisionWildCardChooseColor, WildCardChosenColor: uknow.ColorBlue},
+	}, gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bobTable := serveTable(t, adminTable, "bob")
+
+	if bobTable.TableState != uknow.AwaitingWildDraw4ChallengeDecision ||
+		bobTable.RequiredNumberBeforeWild4 != adminTable.RequiredNumberBeforeWild4 ||
+		bobTable.WinnerPlayerName != adminTable.WinnerPlayerName ||
+		bobTable.TurnsCompleted != adminTable.TurnsCompleted {
+		t.Fatalf("served table differs from admin's, state: %s, number before wild4: %s", bobTable.TableState, bobTable.RequiredNumberBeforeWild4.String())
+	}
+
+	// alice had a red 1 to play on the red 5, so bob's challenge succeeds on
+	// both tables only if the number before wild4 was synced.
+	challenge := []uknow.PlayerDecision{{Kind: uknow.PlayerDecisionDoChallenge}}
+	if err := adminTable.EvalPlayerDecisions("bob", challenge, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+	if err := bobTable.EvalPlayerDecisions("bob", challenge, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, playerName := range adminTable.PlayerNames {
+		if bobTable.HandOfPlayer[playerName].Encode() != adminTable.HandOfPlayer[playerName].Encode() {
+			t.Logf("expected %s's hand %s on bob's table, got %s", playerName, adminTable.HandOfPlayer[playerName].Encode(), bobTable.HandOfPlayer[playerName].Encode())
+			t.Fail()
+		}
+	}
+}
+
+func TestUnknownTableState(t *testing.T) {
+	if uknow.TableState("awaiting_something").IsKnown() || uknow.TableState("").IsKnown() {
+		t.Fatal("expected unknown table states to be rejected")
+	}
+	if !uknow.AwaitingSwapTargetDecision.IsKnown() {
+		t.Fatal("expected known table state to be accepted")
+	}
+}
+
+func TestTableEqual(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: 7, Color: uknow.ColorRed})
+
+	b, err := json.Marshal(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTripped := uknow.NewAdminTable(log.Default())
+	if err := json.Unmarshal(b, roundTripped); err != nil {
+		t.Fatal(err)
+	}
+	if !table.Equal(roundTripped) || !roundTripped.Equal(table) {
+		t.Fatalf("expected the table to equal its JSON round trip")
+	}
+
+	// The local player name is not part of the game state.
+	clone := table.Clone()
+	clone.LocalPlayerName = "bob"
+	if !table.Equal(clone) {
+		t.Fatalf("expected a clone to equal the table")
+	}
+
+	mutations := map[string]func(*uknow.Table){
+		"alice's hand": func(t *uknow.Table) {
+			t.HandOfPlayer["alice"] = t.HandOfPlayer["alice"].Push(uknow.Card{Number: 3, Color: uknow.ColorGreen})
+		},
+		"draw deck":      func(t *uknow.Table) { t.DrawDeck = t.DrawDeck.MustPop() },
+		"next player":    func(t *uknow.Table) { t.PlayerOfNextTurn = "bob" },
+		"required color": func(t *uknow.Table) { t.RequiredColorOfCurrentTurn = uknow.ColorGreen },
+		"house rules":    func(t *uknow.Table) { t.HouseRules.JumpIn = true },
+		"missing hand":   func(t *uknow.Table) { delete(t.HandOfPlayer, "bob") },
+	}
+
+	for name, mutate := range mutations {
+		mutated := table.Clone()
+		mutate(mutated)
+		if table.Equal(mutated) || mutated.Equal(table) {
+			t.Logf("%s: expected the mutated table to differ", name)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+var drawThenUndo = []uknow.PlayerDecision{
+	{Kind: uknow.PlayerDecisionPullFromDeck},
+	{Kind: uknow.PlayerDecisionUndoDraw},
+}
+
+func TestUndoDrawPassesWithDrawnCard(t *testing.T) {
+	drawnCard := uknow.Card{Number: 3, Color: uknow.ColorYellow}
+	table := newDrawPlayTable(drawnCard)
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	if err := table.EvalPlayerDecisions("alice", drawThenUndo, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	if table.PlayerOfNextTurn != "bob" || table.TableState != uknow.StartOfTurn {
+		t.Logf("expected bob's turn, got %s's in state %s", table.PlayerOfNextTurn, table.TableState)
+		t.Fail()
+	}
+
+	if _, err := table.HandOfPlayer["alice"].FindCard(drawnCard); err != nil || table.DrawDeck.Len() != 1 {
+		t.Logf("expected alice to keep the drawn card, hand: %s, draw deck: %s", table.HandOfPlayer["alice"], table.DrawDeck)
+		t.Fail()
+	}
+}
+
+func TestUndoDrawReturnsCardToDeck(t *testing.T) {
+	drawnCard := uknow.Card{Number: 3, Color: uknow.ColorYellow}
+	table := newDrawPlayTable(drawnCard)
+	table.HouseRules.UndoDrawReturnsCard = true
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	if err := table.EvalPlayerDecisions("alice", drawThenUndo, gameEventChan); err != nil {
+		t.Fatal(err)
+	}
+
+	if table.PlayerOfNextTurn != "alice" || table.TableState != uknow.StartOfTurn {
+		t.Logf("expected alice to decide again, got %s's turn in state %s", table.PlayerOfNextTurn, table.TableState)
+		t.Fail()
+	}
+
+	if table.HandOfPlayer["alice"].Len() != 1 || !table.DrawDeck.MustTop().IsEqual(drawnCard) {
+		t.Fatalf("expected the drawn card back on top of the draw deck, hand: %s, draw deck: %s", table.HandOfPlayer["alice"], table.DrawDeck)
+	}
+
+	// alice can draw the same card again, but can't undo without drawing.
+	decision, err := table.EvalPlayerDecision("alice", uknow.PlayerDecision{Kind: uknow.PlayerDecisionUndoDraw}, gameEventChan)
+	if !errors.Is(err, uknow.ErrNothingToUndo) {
+		t.Fatalf("expected %v, got %v for %s", uknow.ErrNothingToUndo, err, decision.String())
+	}
+
+	decision, err = table.EvalPlayerDecision("alice", uknow.PlayerDecision{Kind: uknow.PlayerDecisionPullFromDeck}, gameEventChan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decision.ResultCard.IsEqual(drawnCard) {
+		t.Fatalf("expected to draw %s again, got %s", drawnCard.String(), decision.ResultCard.String())
+	}
+}
@@ -0,0 +1,29 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nrawrx3/uknow"
+	client "github.com/nrawrx3/uknow/player_client"
+)
+
+func TestAdminServesCardsOverWebSocket(t *testing.T) {
+	g := newTestGame(t)
+	g.webSocket = true
+	defer g.close()
+
+	// The acks of the joins and bob's set_ready go over the websockets too.
+	g.connect("alice", false, 0)
+	g.waitForAllPlayers(client.WaitingForAdminToServeCards, 5*time.Second)
+	g.connect("bob", true, 2)
+	g.waitForAllPlayers(client.WaitingForAdminToChoosePlayer, 10*time.Second)
+
+	for playerName, player := range g.players {
+		handCounts := player.client.HandCounts()
+		if handCounts[playerName] < uknow.DefaultStartingHandCount {
+			t.Logf("%s: expected the served cards over the websocket, got hand counts %v", playerName, handCounts)
+			t.Fail()
+		}
+	}
+}
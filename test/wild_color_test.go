@@ -0,0 +1,61 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nrawrx3/uknow"
+)
+
+func TestWildColorChosenAtWrongState(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: 7, Color: uknow.ColorRed})
+
+	if err := uknow.ValidateWildColorChoice(table.TableState, uknow.ColorBlue); !errors.Is(err, uknow.ErrNoWildColorToChoose) {
+		t.Fatalf("expected no wild card color to choose at %s, got %v", table.TableState, err)
+	}
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	_, err := table.EvalPlayerDecision("alice", uknow.PlayerDecision{Kind: uknow.PlayerDecisionWildCardChooseColor, WildCardChosenColor: uknow.ColorBlue}, gameEventChan)
+	if !errors.Is(err, uknow.ErrNoWildColorToChoose) {
+		t.Logf("expected the color choice to be rejected, got %v", err)
+		t.Fail()
+	}
+
+	if table.RequiredColorOfCurrentTurn != uknow.ColorRed || table.PlayerOfNextTurn != "alice" {
+		t.Logf("expected the table to be unchanged, got color %s, next player %s", table.RequiredColorOfCurrentTurn.String(), table.PlayerOfNextTurn)
+		t.Fail()
+	}
+}
+
+func TestWildIsNotAValidWildColor(t *testing.T) {
+	table := newDrawPlayTable(uknow.Card{Number: 7, Color: uknow.ColorRed})
+	table.TableState = uknow.AwaitingWildCardColorDecision
+
+	for _, color := range []uknow.Color{uknow.ColorWild, uknow.Color(9)} {
+		if err := uknow.ValidateWildColorChoice(table.TableState, color); !errors.Is(err, uknow.ErrInvalidWildColor) {
+			t.Logf("expected %d to be an invalid wild card color, got %v", color, err)
+			t.Fail()
+		}
+	}
+
+	gameEventChan := drainGameEvents()
+	defer close(gameEventChan)
+
+	_, err := table.EvalPlayerDecision("alice", uknow.PlayerDecision{Kind: uknow.PlayerDecisionWildCardChooseColor, WildCardChosenColor: uknow.ColorWild}, gameEventChan)
+	if !errors.Is(err, uknow.ErrInvalidWildColor) {
+		t.Logf("expected choosing wild to be rejected, got %v", err)
+		t.Fail()
+	}
+
+	if table.TableState != uknow.AwaitingWildCardColorDecision || table.RequiredColorOfCurrentTurn != uknow.ColorRed {
+		t.Logf("expected alice to still be choosing, got state %s, color %s", table.TableState, table.RequiredColorOfCurrentTurn.String())
+		t.Fail()
+	}
+
+	if err := uknow.ValidateWildColorChoice(table.TableState, uknow.ColorGreen); err != nil {
+		t.Logf("expected green to be a valid wild card color, got %v", err)
+		t.Fail()
+	}
+}
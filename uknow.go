@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/rand"
 	"sort"
 	"strings"
 )
@@ -37,6 +38,17 @@ const (
 	NumberWildDrawFour Number = 10 + iota
 )
 
+// Number of a card whose face is hidden from the local player, see
+// Table.RedactedFor.
+const NumberHidden Number = -1
+
+// Stands in for each card of an opponent's hand in a redacted table.
+var HiddenCard = Card{Number: NumberHidden, Color: ColorWild}
+
+func (c Card) IsHidden() bool {
+	return c.Number == NumberHidden
+}
+
 func (num Number) IsAction() bool {
 	return NumberSkip <= num && num <= NumberWildDrawFour
 }
@@ -45,6 +57,36 @@ func (c Card) IsWild() bool {
 	return c.Number == NumberWild || c.Number == NumberWildDrawFour
 }
 
+// Reports whether the card can be played on a turn requiring the given color
+// or number. Wild cards can always be played.
+func (c Card) IsPlayableOn(requiredColor Color, requiredNumber Number) bool {
+	return c.IsWild() || c.Color == requiredColor || c.Number == requiredNumber
+}
+
+// Points of the card in standard scoring. Number cards are worth their face
+// value, other action cards 20 and wild cards 50.
+func (c Card) Points() int {
+	switch {
+	case c.IsHidden():
+		return 0
+	case c.IsWild():
+		return 50
+	case c.Number.IsAction():
+		return 20
+	default:
+		return int(c.Number)
+	}
+}
+
+// Sum of the points of the given cards in standard scoring.
+func ScoreCards(cards Deck) int {
+	points := 0
+	for _, card := range cards {
+		points += card.Points()
+	}
+	return points
+}
+
 func (num *Number) String() string {
 	n := *num
 
@@ -63,6 +105,8 @@ func (num *Number) String() string {
 		return "Wild"
 	case NumberWildDrawFour:
 		return "WildDrawFour"
+	case NumberHidden:
+		return "Hidden"
 	default:
 		return fmt.Sprintf("invalid_number(= %d)", n)
 	}
@@ -75,6 +119,29 @@ func IntToNumber(n int) (Number, error) {
 	return 0, fmt.Errorf("InvalidCardNumber(%d)", n)
 }
 
+// Parses a card number, case-insensitively. Accepts the digits 0 to 9, the
+// names returned by Number.String() and the short and snake case spellings of
+// the action cards used by the repl and the hand configs.
+func ParseNumber(s string) (Number, error) {
+	switch strings.ToLower(s) {
+	case "skip":
+		return NumberSkip, nil
+	case "reverse", "rev":
+		return NumberReverse, nil
+	case "drawtwo", "draw2", "draw_2":
+		return NumberDrawTwo, nil
+	case "wild":
+		return NumberWild, nil
+	case "wilddrawfour", "wild4", "wild_draw_4":
+		return NumberWildDrawFour, nil
+	}
+
+	if len(s) == 1 && '0' <= s[0] && s[0] <= '9' {
+		return Number(s[0] - '0'), nil
+	}
+	return 0, fmt.Errorf("invalid card number: '%s'", s)
+}
+
 type Color int
 
 const (
@@ -85,6 +152,16 @@ const (
 	ColorYellow Color = 4
 )
 
+// The four colors of non-wild cards, in declaration order.
+func RealColors() []Color {
+	return []Color{ColorRed, ColorGreen, ColorBlue, ColorYellow}
+}
+
+// Reports whether the color is one of RealColors.
+func (c Color) IsReal() bool {
+	return c >= ColorRed && c <= ColorYellow
+}
+
 var ColorSymbol = [...]string{
 	"🌈",
 	"🔴",
@@ -126,6 +203,24 @@ func (c *Color) String() string {
 	}
 }
 
+// Parses a color name as returned by Color.String(), case-insensitively.
+func ParseColor(s string) (Color, error) {
+	switch strings.ToLower(s) {
+	case "red":
+		return ColorRed, nil
+	case "green":
+		return ColorGreen, nil
+	case "blue":
+		return ColorBlue, nil
+	case "yellow":
+		return ColorYellow, nil
+	case "wild":
+		return ColorWild, nil
+	default:
+		return ColorWild, fmt.Errorf("invalid color: '%s'", s)
+	}
+}
+
 type Deck []Card
 
 func (d Deck) String() string {
@@ -170,27 +265,44 @@ func NewEmptyDeck() Deck {
 	return make([]Card, 0, 1024)
 }
 
+const MaxDeckCount = 4
+
 func NewFullDeck() Deck {
-	// Non zero cards upto CardDrawTwo, 9 of them for each color
-	cards := make([]Card, 0, 9*8)
-	for color := 1; color <= 4; color++ {
-		for number := 1; number <= int(NumberDrawTwo); number++ {
-			cards = append(cards, Card{Number: Number(number), Color: Color(color)})
-		}
+	return NewFullDeckN(1)
+}
+
+// Returns n standard decks combined into one, for games with many players.
+// n less than 1 is treated as 1.
+func NewFullDeckN(n int) Deck {
+	if n < 1 {
+		n = 1
 	}
 
-	// Two copies of non-zero cards for each color
-	cards = append(cards, cards...)
+	cards := make([]Card, 0, 108*n)
 
-	// Zero cards are only one per color
-	for color := 1; color <= 4; color++ {
-		cards = append(cards, Card{Number: 0, Color: Color(color)})
-	}
+	for i := 0; i < n; i++ {
+		// Non zero cards upto CardDrawTwo, 9 of them for each color
+		nonZeroCards := make([]Card, 0, 12*4)
+		for _, color := range RealColors() {
+			for number := 1; number <= int(NumberDrawTwo); number++ {
+				nonZeroCards = append(nonZeroCards, Card{Number: Number(number), Color: color})
+			}
+		}
+
+		// Two copies of non-zero cards for each color
+		cards = append(cards, nonZeroCards...)
+		cards = append(cards, nonZeroCards...)
+
+		// Zero cards are only one per color
+		for _, color := range RealColors() {
+			cards = append(cards, Card{Number: 0, Color: color})
+		}
 
-	// 4 NumberWild and 4 NumberWildDrawFour cards
-	for i := 0; i < 4; i++ {
-		cards = append(cards, Card{Number: NumberWildDrawFour, Color: ColorWild})
-		cards = append(cards, Card{Number: NumberWild, Color: ColorWild})
+		// 4 NumberWild and 4 NumberWildDrawFour cards
+		for i := 0; i < 4; i++ {
+			cards = append(cards, Card{Number: NumberWildDrawFour, Color: ColorWild})
+			cards = append(cards, Card{Number: NumberWild, Color: ColorWild})
+		}
 	}
 
 	deck := Deck(cards)
@@ -236,8 +348,47 @@ func (d Deck) MustPop() Deck {
 	return d[0 : len(d)-1]
 }
 
-func (d Deck) RemoveCard(index int) Deck {
-	return append(d[0:index], d[index+1:]...)
+// Returns up to the top n cards of the deck, top card first. The returned deck
+// is a copy, the deck itself is left as is.
+func (d Deck) Peek(n int) Deck {
+	if n > len(d) {
+		n = len(d)
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	topCards := make(Deck, 0, n)
+	for i := 1; i <= n; i++ {
+		topCards = append(topCards, d[len(d)-i])
+	}
+	return topCards
+}
+
+// Counts the cards of the deck by color and by number. Wild cards are counted
+// under ColorWild.
+func (d Deck) Histogram() (map[Color]int, map[Number]int) {
+	countOfColor := make(map[Color]int)
+	countOfNumber := make(map[Number]int)
+	for _, card := range d {
+		countOfColor[card.Color]++
+		countOfNumber[card.Number]++
+	}
+	return countOfColor, countOfNumber
+}
+
+var ErrCardIndexOutOfRange = errors.New("card index out of range")
+
+// Returns a copy of the deck without the card at index. The deck itself is
+// left as is, so decks sharing its backing array are not affected.
+func (d Deck) RemoveCard(index int) (Deck, error) {
+	if index < 0 || index >= len(d) {
+		return d, fmt.Errorf("%w: index %d, deck has %d cards", ErrCardIndexOutOfRange, index, len(d))
+	}
+
+	remaining := make(Deck, 0, len(d)-1)
+	remaining = append(remaining, d[:index]...)
+	return append(remaining, d[index+1:]...), nil
 }
 
 func (d Deck) FindCard(wantedCard Card) (int, error) {
@@ -249,12 +400,62 @@ func (d Deck) FindCard(wantedCard Card) (int, error) {
 	return 0, fmt.Errorf("could not find card %s", wantedCard.String())
 }
 
+// Returns a deck of count hidden cards.
+func NewHiddenDeck(count int) Deck {
+	deck := make(Deck, count)
+	for i := range deck {
+		deck[i] = HiddenCard
+	}
+	return deck
+}
+
+func (d Deck) HasHiddenCards() bool {
+	for _, card := range d {
+		if card.IsHidden() {
+			return true
+		}
+	}
+	return false
+}
+
+// Reports whether both decks have the same cards in the same order.
+func (d Deck) Equal(other Deck) bool {
+	if len(d) != len(other) {
+		return false
+	}
+	for i := range d {
+		if !d[i].IsEqual(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reports whether both decks have the same cards, the same number of times
+// each, in any order.
+func (d Deck) EqualAsMultiset(other Deck) bool {
+	if len(d) != len(other) {
+		return false
+	}
+	countOfCard := make(map[Card]int, len(d))
+	for _, card := range d {
+		countOfCard[card]++
+	}
+	for _, card := range other {
+		countOfCard[card]--
+		if countOfCard[card] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
 func (d Deck) FindAndRemoveCard(wantedCard Card) (Deck, error) {
 	index, err := d.FindCard(wantedCard)
 	if err != nil {
 		return d, fmt.Errorf("could not remove card: %w", err)
 	}
-	return d.RemoveCard(index), nil
+	return d.RemoveCard(index)
 }
 
 func (d Deck) MustFindCard(wantedCard Card) int {
@@ -276,26 +477,59 @@ const (
 	AwaitingWildDraw4CardColorDecision TableState = "awaiting_wild_draw4_card_color_choice"
 	AwaitingWildDraw4ChallengeDecision TableState = "awaiting_wild_draw_4_challenge_choice"
 	HaveWinner                         TableState = "have_winner"
+	AwaitingSwapTargetDecision         TableState = "awaiting_swap_target_choice"
+	AwaitingOpeningWildColorDecision   TableState = "awaiting_opening_wild_color_choice"
 )
 
+// Reports whether the state is one of the TableState constants. Used to
+// validate tables received from the admin.
+func (s TableState) IsKnown() bool {
+	switch s {
+	case StartOfTurn,
+		AwaitingDropOrPass,
+		AwaitingWildCardColorDecision,
+		AwaitingWildDraw4CardColorDecision,
+		AwaitingWildDraw4ChallengeDecision,
+		HaveWinner,
+		AwaitingSwapTargetDecision,
+		AwaitingOpeningWildColorDecision:
+		return true
+	}
+	return false
+}
+
+// Reports whether a wild card color is to be chosen in this state.
+func (s TableState) AwaitsWildColorDecision() bool {
+	return s == AwaitingWildCardColorDecision || s == AwaitingWildDraw4CardColorDecision || s == AwaitingOpeningWildColorDecision
+}
+
 func EligibleCommandsAtState(turnState TableState) string {
 	switch turnState {
 	case StartOfTurn:
 		return "play a card or pull from deck"
 	case AwaitingDropOrPass:
-		return "play a card or pass"
+		return "play a card, pass or undo"
 	case AwaitingWildCardColorDecision:
 		return "wild_color <color>"
 	case AwaitingWildDraw4CardColorDecision:
 		return "wild_color <color>"
 	case AwaitingWildDraw4ChallengeDecision:
 		return "challenge or no_challenge"
+	case AwaitingSwapTargetDecision:
+		return "swap <player>"
+	case AwaitingOpeningWildColorDecision:
+		return "wild_color <color>"
 	}
 	return "unknown turnState"
 }
 
 type Table struct {
 	Logger *log.Logger `json:"-"`
+	Rand   *rand.Rand  `json:"-"` // Used for shuffling if set, otherwise the global source is used
+
+	// Receives the events of EvalDecision and EvalDecisions. Events are
+	// dropped if nil.
+	EventSink GameEventSink `json:"-"`
 
 	DrawDeck                    Deck            `json:"draw_deck"`
 	DiscardedPile               Deck            `json:"discarded_pile"`
@@ -309,13 +543,130 @@ type Table struct {
 	Direction                   int             `json:"direction"`
 	TurnsCompleted              int             `json:"turns_completed"`
 	TableState                  `json:"table_state"`
-	IsShuffled                  bool   `json:"is_shuffled"`
-	RequiredColorOfCurrentTurn  Color  `json:"required_color_of_current_turn"`
-	RequiredColorOfLastTurn     Color  `json:"required_color_of_last_turn"`
-	RequiredNumberOfCurrentTurn Number `json:"required_number_of_current_turn"`
-	RequiredNumberOfLastTurn    Number `json:"required_number_of_last_turn"`
-	RequiredNumberBeforeWild4   Number `json:"required_number_before_wild_4"`
-	WinnerPlayerName            string `json:"winner_player_name"`
+	IsShuffled                  bool             `json:"is_shuffled"`
+	RequiredColorOfCurrentTurn  Color            `json:"required_color_of_current_turn"`
+	RequiredColorOfLastTurn     Color            `json:"required_color_of_last_turn"`
+	RequiredNumberOfCurrentTurn Number           `json:"required_number_of_current_turn"`
+	RequiredNumberOfLastTurn    Number           `json:"required_number_of_last_turn"`
+	RequiredNumberBeforeWild4   Number           `json:"required_number_before_wild_4"`
+	WinnerPlayerName            string           `json:"winner_player_name"`
+	LastDrawnCard               Card             `json:"last_drawn_card"` // Card drawn by the player of the current turn, if any
+	HouseRules                  HouseRules       `json:"house_rules"`
+	OpeningCardEvent            OpeningCardEvent `json:"opening_card_event"`
+
+	// Counted as decisions are evaluated, see PlayerStats.
+	StatsOfPlayer map[string]PlayerStats `json:"stats_of_player"`
+}
+
+// Optional rules on top of the standard ones. All disabled by default, so the
+// zero value plays standard UNO. The rules are part of the Table, which is how
+// clients get them with the served cards, and decisions are evaluated against
+// them. New rule variants belong here rather than in separate Table fields.
+type HouseRules struct {
+	// Playing a 0 passes every hand to the next player in the direction of
+	// play. Playing a 7 swaps the player's hand with a player of their choice.
+	SevenZero bool `json:"seven_zero"`
+
+	// An action card flipped as the opening card only sets the color and
+	// number of the first turn, its action is not applied.
+	IgnoreOpeningAction bool `json:"ignore_opening_action"`
+
+	// Undoing a draw puts the drawn card back on top of the draw deck and lets
+	// the player decide again. Without it, undoing a draw is the same as
+	// passing with the drawn card kept in hand.
+	UndoDrawReturnsCard bool `json:"undo_draw_returns_card"`
+
+	// A wild flipped as the opening card lets the first player choose the
+	// color before playing. Without it, the color the first player holds
+	// most is chosen for them.
+	ChooseOpeningWildColor bool `json:"choose_opening_wild_color"`
+
+	// Any player can play a card identical in color and number to the top of
+	// the discard pile out of turn. Play continues from that player.
+	JumpIn bool `json:"jump_in"`
+
+	// A skip, draw two or reverse that the player drew and then played in the
+	// same turn is only discarded, setting the color and number of the next
+	// turn, and play passes to the next player. Without it, the drawn card's
+	// action applies as if it had been played from hand. Drawn wild cards are
+	// not affected.
+	DrawnActionCardHasNoEffect bool `json:"drawn_action_card_has_no_effect"`
+
+	// A card not of the required color must match the number of the top of
+	// the discard pile, the legacy matching. Without it, the card must match
+	// the required number of the turn. The two only differ on a table whose
+	// required number was set apart from the pile, e.g. one read from a hand
+	// file or synced from an older admin.
+	MatchDiscardTopNumber bool `json:"match_discard_top_number"`
+
+	// A player can play several cards of the same number in one turn, one
+	// after another, each applying its action. Wild cards, and with
+	// seven_zero 0s and 7s, can't be stacked. Each action applies from the
+	// deciding player as if the card was played alone, so stacked draw twos
+	// add up on the next player, stacked skips skip that same player only
+	// once, and a pair of reverses leaves the direction as it was.
+	StackSameNumber bool `json:"stack_same_number"`
+}
+
+var ErrUnknownHouseRule = errors.New("unknown house rule")
+
+// Names of the house rules as in their json tags, in declaration order.
+var HouseRuleNames = []string{"seven_zero", "ignore_opening_action", "undo_draw_returns_card", "choose_opening_wild_color", "jump_in", "drawn_action_card_has_no_effect", "match_discard_top_number", "stack_same_number"}
+
+func (r *HouseRules) ruleOfName(name string) (*bool, bool) {
+	switch name {
+	case "seven_zero":
+		return &r.SevenZero, true
+	case "ignore_opening_action":
+		return &r.IgnoreOpeningAction, true
+	case "undo_draw_returns_card":
+		return &r.UndoDrawReturnsCard, true
+	case "choose_opening_wild_color":
+		return &r.ChooseOpeningWildColor, true
+	case "jump_in":
+		return &r.JumpIn, true
+	case "drawn_action_card_has_no_effect":
+		return &r.DrawnActionCardHasNoEffect, true
+	case "match_discard_top_number":
+		return &r.MatchDiscardTopNumber, true
+	case "stack_same_number":
+		return &r.StackSameNumber, true
+	}
+	return nil, false
+}
+
+// Reports whether the house rule of the given name is enabled, see
+// HouseRuleNames.
+func (r *HouseRules) Enabled(name string) (bool, error) {
+	rule, ok := r.ruleOfName(name)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrUnknownHouseRule, name)
+	}
+	return *rule, nil
+}
+
+// Enables or disables the house rule of the given name, see HouseRuleNames.
+func (r *HouseRules) Set(name string, enabled bool) error {
+	rule, ok := r.ruleOfName(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownHouseRule, name)
+	}
+	*rule = enabled
+	return nil
+}
+
+// Names of the enabled house rules, or "none".
+func (r *HouseRules) String() string {
+	enabledNames := make([]string, 0, len(HouseRuleNames))
+	for _, name := range HouseRuleNames {
+		if rule, _ := r.ruleOfName(name); *rule {
+			enabledNames = append(enabledNames, name)
+		}
+	}
+	if len(enabledNames) == 0 {
+		return "none"
+	}
+	return strings.Join(enabledNames, ", ")
 }
 
 func NewTable(localPlayerName string, logger *log.Logger) *Table {
@@ -343,10 +694,145 @@ func (t *Table) Set(other *Table) {
 	t.RequiredColorOfLastTurn = other.RequiredColorOfLastTurn
 	t.RequiredNumberOfCurrentTurn = other.RequiredNumberOfCurrentTurn
 	t.RequiredNumberOfLastTurn = other.RequiredNumberOfLastTurn
+	t.RequiredNumberBeforeWild4 = other.RequiredNumberBeforeWild4
+	t.LastDrawnCard = other.LastDrawnCard
+	t.TableState = other.TableState
+	t.TurnsCompleted = other.TurnsCompleted
+	t.WinnerPlayerName = other.WinnerPlayerName
+	t.HouseRules = other.HouseRules
+	t.OpeningCardEvent = other.OpeningCardEvent
+	t.StatsOfPlayer = other.StatsOfPlayer
 
 	t.Logger.Printf("Setting t.RequiredNumberOfCurrentTurn = %s", t.RequiredNumberOfCurrentTurn.String())
 }
 
+// Returns a deep copy of the table. Decisions can be evaluated on the copy
+// without touching the receiver. The copy has no EventSink.
+func (t *Table) Clone() *Table {
+	clone := *t
+	clone.EventSink = nil
+	clone.DrawDeck = t.DrawDeck.Clone()
+	clone.DiscardedPile = t.DiscardedPile.Clone()
+	clone.PlayerNames = append(StringSlice{}, t.PlayerNames...)
+
+	clone.IndexOfPlayer = make(map[string]int, len(t.IndexOfPlayer))
+	for playerName, index := range t.IndexOfPlayer {
+		clone.IndexOfPlayer[playerName] = index
+	}
+
+	clone.HandOfPlayer = make(map[string]Deck, len(t.HandOfPlayer))
+	for playerName, hand := range t.HandOfPlayer {
+		clone.HandOfPlayer[playerName] = hand.Clone()
+	}
+
+	clone.StatsOfPlayer = t.Stats()
+	return &clone
+}
+
+// Reports whether both tables are in the same game state. The logger, the
+// random source, the event sink and the local player name are not compared.
+func (t *Table) Equal(other *Table) bool {
+	if len(t.HandOfPlayer) != len(other.HandOfPlayer) || len(t.IndexOfPlayer) != len(other.IndexOfPlayer) || len(t.PlayerNames) != len(other.PlayerNames) {
+		return false
+	}
+	for playerName, hand := range t.HandOfPlayer {
+		otherHand, ok := other.HandOfPlayer[playerName]
+		if !ok || !hand.Equal(otherHand) {
+			return false
+		}
+	}
+	for playerName, index := range t.IndexOfPlayer {
+		otherIndex, ok := other.IndexOfPlayer[playerName]
+		if !ok || index != otherIndex {
+			return false
+		}
+	}
+	for i := range t.PlayerNames {
+		if t.PlayerNames[i] != other.PlayerNames[i] {
+			return false
+		}
+	}
+
+	return t.DrawDeck.Equal(other.DrawDeck) &&
+		t.DiscardedPile.Equal(other.DiscardedPile) &&
+		t.ShufflerName == other.ShufflerName &&
+		t.PlayerOfNextTurn == other.PlayerOfNextTurn &&
+		t.PlayerOfLastTurn == other.PlayerOfLastTurn &&
+		t.Direction == other.Direction &&
+		t.TurnsCompleted == other.TurnsCompleted &&
+		t.TableState == other.TableState &&
+		t.IsShuffled == other.IsShuffled &&
+		t.RequiredColorOfCurrentTurn == other.RequiredColorOfCurrentTurn &&
+		t.RequiredColorOfLastTurn == other.RequiredColorOfLastTurn &&
+		t.RequiredNumberOfCurrentTurn == other.RequiredNumberOfCurrentTurn &&
+		t.RequiredNumberOfLastTurn == other.RequiredNumberOfLastTurn &&
+		t.RequiredNumberBeforeWild4 == other.RequiredNumberBeforeWild4 &&
+		t.WinnerPlayerName == other.WinnerPlayerName &&
+		t.LastDrawnCard == other.LastDrawnCard &&
+		t.HouseRules == other.HouseRules &&
+		statsEqual(t.StatsOfPlayer, other.StatsOfPlayer) &&
+		t.OpeningCardEvent == other.OpeningCardEvent
+}
+
+// Returns a copy of the table in which every hand other than the given player's
+// and the draw deck are replaced by as many hidden cards. The last drawn card is
+// kept only for the player who drew it, while they decide to play it or pass.
+// This is what the admin sends to each player so that a client only knows the
+// cards in its own hand.
+func (t *Table) RedactedFor(playerName string) *Table {
+	redacted := *t
+	redacted.HandOfPlayer = make(map[string]Deck, len(t.HandOfPlayer))
+	for handOwner, hand := range t.HandOfPlayer {
+		if handOwner == playerName {
+			redacted.HandOfPlayer[handOwner] = hand.Clone()
+		} else {
+			redacted.HandOfPlayer[handOwner] = NewHiddenDeck(hand.Len())
+		}
+	}
+	redacted.DrawDeck = NewHiddenDeck(t.DrawDeck.Len())
+	if t.TableState != AwaitingDropOrPass || playerName != t.PlayerOfNextTurn {
+		redacted.LastDrawnCard = Card{}
+	}
+	return &redacted
+}
+
+// Reports whether any hand or the draw deck on the table is redacted.
+func (t *Table) HasHiddenCards() bool {
+	for _, hand := range t.HandOfPlayer {
+		if hand.HasHiddenCards() {
+			return true
+		}
+	}
+	return t.DrawDeck.HasHiddenCards()
+}
+
+// Reports whether evaluating the decisions on a redacted table can leave it out
+// of sync with the admin's. A challenge depends on the hidden cards of the wild
+// draw 4 player, and swapping or rotating hands can pass hidden cards to the
+// local player. Called once the decisions are evaluated, so that cards the
+// local player was made to draw from the hidden draw deck are fetched too.
+func (t *Table) NeedsResyncAfter(decisions []PlayerDecision) bool {
+	if !t.HasHiddenCards() {
+		return false
+	}
+
+	if t.HandOfPlayer[t.LocalPlayerName].HasHiddenCards() {
+		return true
+	}
+
+	for _, decision := range decisions {
+		switch decision.Kind {
+		case PlayerDecisionDoChallenge, PlayerDecisionChooseSwapTarget:
+			return true
+		case PlayerDecisionPlayHandCard:
+			if t.HouseRules.SevenZero && decision.ResultCard.Number == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (t *Table) PrintHands(w io.Writer) {
 	for playerName, hand := range t.HandOfPlayer {
 		fmt.Fprintf(w, "---- %s ----\n", playerName)
@@ -359,13 +845,30 @@ func (t *Table) PrintHands(w io.Writer) {
 }
 
 func (t *Table) PrintDrawDeck(w io.Writer, count int) {
-	if count > len(t.DrawDeck) {
-		count = len(t.DrawDeck)
+	for i, card := range t.DrawDeck.Peek(count) {
+		fmt.Fprintf(w, "%02d: %s\n", i+1, card.String())
+	}
+}
+
+// Prints the whole discard pile from top to bottom, cardsPerLine cards per
+// line, each line prefixed with the positions of its cards from the top.
+func (t *Table) PrintDiscardPileHistory(w io.Writer, cardsPerLine int) {
+	if cardsPerLine < 1 {
+		cardsPerLine = 1
 	}
 
-	for i := 1; i <= count; i++ {
-		card := t.DrawDeck[len(t.DrawDeck)-i]
-		fmt.Fprintf(w, "%02d: %s\n", i, card.String())
+	for start := 1; start <= len(t.DiscardedPile); start += cardsPerLine {
+		end := start + cardsPerLine - 1
+		if end > len(t.DiscardedPile) {
+			end = len(t.DiscardedPile)
+		}
+
+		fmt.Fprintf(w, "%02d-%02d:", start, end)
+		for i := start; i <= end; i++ {
+			card := t.DiscardedPile[len(t.DiscardedPile)-i]
+			fmt.Fprintf(w, " %s", card.SymbolString())
+		}
+		fmt.Fprintln(w)
 	}
 }
 
@@ -375,17 +878,19 @@ func (t *Table) SetPlayerOfNextTurn(nextPlayer string) {
 }
 
 func (t *Table) SetRequiredColor(newColor Color, gameEventPushChan chan<- GameEvent) {
+	t.setRequiredColor(newColor, GameEventChan(gameEventPushChan))
+}
+
+func (t *Table) setRequiredColor(newColor Color, events GameEventSink) {
 	t.RequiredColorOfLastTurn = t.RequiredColorOfCurrentTurn
 	t.RequiredColorOfCurrentTurn = newColor
 
 	t.Logger.Printf("RequiredColorOfLastTurn: %v", t.RequiredColorOfLastTurn)
 	t.Logger.Printf("RequiredColorOfCurrentTurn: %v", t.RequiredColorOfCurrentTurn)
 
-	if gameEventPushChan != nil {
-		gameEventPushChan <- RequiredColorUpdatedEvent{
-			NewColor: newColor,
-		}
-	}
+	events.Emit(RequiredColorUpdatedEvent{
+		NewColor: newColor,
+	})
 }
 
 func (t *Table) SetRequiredNumber(newNumber Number) {
@@ -393,6 +898,22 @@ func (t *Table) SetRequiredNumber(newNumber Number) {
 	t.RequiredNumberOfCurrentTurn = newNumber
 }
 
+// Number a card not of the required color must match to be played, see
+// HouseRules.MatchDiscardTopNumber.
+func (t *Table) NumberToMatch() Number {
+	if t.HouseRules.MatchDiscardTopNumber {
+		if topOfPile, err := t.DiscardedPile.Top(); err == nil {
+			return topOfPile.Number
+		}
+	}
+	return t.RequiredNumberOfCurrentTurn
+}
+
+// Reports whether the card can be played on the current turn.
+func (t *Table) IsPlayable(card Card) bool {
+	return card.IsPlayableOn(t.RequiredColorOfCurrentTurn, t.NumberToMatch())
+}
+
 func NewAdminTable(logger *log.Logger) *Table {
 	return createNewTable(logger)
 }
@@ -426,6 +947,48 @@ func (t *Table) Summary() string {
 	return sb.String()
 }
 
+// Writes every field of the table, with the decks and hands in full, for bug
+// reports. Decks are listed from the top. Unlike Summary, the output is
+// deterministic for a given table.
+func (t *Table) DebugDump(w io.Writer) {
+	fmt.Fprintf(w, "TableState: %s\n", t.TableState)
+	fmt.Fprintf(w, "IsShuffled: %t\n", t.IsShuffled)
+	fmt.Fprintf(w, "LocalPlayerName: %s\n", t.LocalPlayerName)
+	fmt.Fprintf(w, "ShufflerName: %s\n", t.ShufflerName)
+	fmt.Fprintf(w, "PlayerOfLastTurn: %s\n", t.PlayerOfLastTurn)
+	fmt.Fprintf(w, "PlayerOfNextTurn: %s\n", t.PlayerOfNextTurn)
+	fmt.Fprintf(w, "Direction: %d\n", t.Direction)
+	fmt.Fprintf(w, "TurnsCompleted: %d\n", t.TurnsCompleted)
+	fmt.Fprintf(w, "RequiredColorOfCurrentTurn: %s\n", t.RequiredColorOfCurrentTurn.String())
+	fmt.Fprintf(w, "RequiredNumberOfCurrentTurn: %s\n", t.RequiredNumberOfCurrentTurn.String())
+	fmt.Fprintf(w, "RequiredColorOfLastTurn: %s\n", t.RequiredColorOfLastTurn.String())
+	fmt.Fprintf(w, "RequiredNumberOfLastTurn: %s\n", t.RequiredNumberOfLastTurn.String())
+	fmt.Fprintf(w, "RequiredNumberBeforeWild4: %s\n", t.RequiredNumberBeforeWild4.String())
+	fmt.Fprintf(w, "LastDrawnCard: %s\n", t.LastDrawnCard.String())
+	fmt.Fprintf(w, "WinnerPlayerName: %s\n", t.WinnerPlayerName)
+	fmt.Fprintf(w, "HouseRules: %+v\n", t.HouseRules)
+	fmt.Fprintf(w, "StatsOfPlayer: %+v\n", t.StatsOfPlayer)
+	fmt.Fprintf(w, "OpeningCardEvent: card %s, action %s, next player %s, affected player %s\n", t.OpeningCardEvent.Card.String(), t.OpeningCardEvent.Action, t.OpeningCardEvent.NextPlayer, t.OpeningCardEvent.AffectedPlayer)
+
+	for _, playerName := range t.PlayerNames {
+		hand := t.HandOfPlayer[playerName]
+		fmt.Fprintf(w, "---- Hand of %s (index %d, %d cards) ----\n", playerName, t.IndexOfPlayer[playerName], hand.Len())
+		debugDumpDeck(w, hand)
+	}
+
+	fmt.Fprintf(w, "---- DrawDeck (%d cards) ----\n", t.DrawDeck.Len())
+	debugDumpDeck(w, t.DrawDeck)
+
+	fmt.Fprintf(w, "---- DiscardedPile (%d cards) ----\n", t.DiscardedPile.Len())
+	debugDumpDeck(w, t.DiscardedPile)
+}
+
+func debugDumpDeck(w io.Writer, deck Deck) {
+	for i := len(deck) - 1; i >= 0; i-- {
+		fmt.Fprintf(w, "%03d: %s\n", len(deck)-i, deck[i].String())
+	}
+}
+
 func createNewTable(logger *log.Logger) *Table {
 	return &Table{
 		DrawDeck:      NewFullDeck(),
@@ -436,6 +999,7 @@ func createNewTable(logger *log.Logger) *Table {
 		Direction:     1,
 		Logger:        logger,
 		TableState:    StartOfTurn,
+		StatsOfPlayer: make(map[string]PlayerStats),
 	}
 }
 
@@ -458,6 +1022,17 @@ func (t *Table) AddPlayer(playerName string) error {
 	return nil
 }
 
+// Returns the name of an existing player whose name is equal to the given
+// name under case-folding, if any.
+func (t *Table) FindPlayerNameFold(playerName string) (string, bool) {
+	for _, existingName := range t.PlayerNames {
+		if strings.EqualFold(existingName, playerName) {
+			return existingName, true
+		}
+	}
+	return "", false
+}
+
 func (t *Table) PlayerIndicesSortedByTurn() []int {
 	sortedIndices := make([]int, t.PlayerCount())
 	curIndex := t.IndexOfPlayer[t.PlayerOfNextTurn]
@@ -468,6 +1043,37 @@ func (t *Table) PlayerIndicesSortedByTurn() []int {
 	return sortedIndices
 }
 
+// Players in the order of play starting from the player of the next turn,
+// e.g. "alice → bob → carol". The local player is written as "(you)".
+func (t *Table) TurnOrderString() string {
+	names := make([]string, 0, t.PlayerCount())
+	for _, playerIndex := range t.PlayerIndicesSortedByTurn() {
+		playerName := t.PlayerNames[playerIndex]
+		if playerName == t.LocalPlayerName {
+			playerName = "(you)"
+		}
+		names = append(names, playerName)
+	}
+	return strings.Join(names, " → ")
+}
+
+// Points of the cards remaining in the player's hand. Zero for unknown players.
+func (t *Table) HandPoints(playerName string) int {
+	return ScoreCards(t.HandOfPlayer[playerName])
+}
+
+// Sum of the hand points of every player other than the winner, i.e. what the
+// winner scores for the round.
+func (t *Table) TotalOpponentPoints(winner string) int {
+	points := 0
+	for _, playerName := range t.PlayerNames {
+		if playerName != winner {
+			points += t.HandPoints(playerName)
+		}
+	}
+	return points
+}
+
 func (t *Table) PlayerCount() int {
 	return len(t.PlayerNames)
 }
@@ -519,7 +1125,27 @@ func (t *Table) PlayerIndexFromName(playerName string) int {
 		}
 	}
 
-	panic(fmt.Errorf("non-existent player name: '%s'", playerName))
+	panic(fmt.Errorf("%w: '%s'", ErrUnknownPlayer, playerName))
+}
+
+// Returns the hand of the player, or ErrUnknownPlayer if there is no player of
+// the name at the table.
+func (t *Table) Hand(playerName string) (Deck, error) {
+	hand, ok := t.HandOfPlayer[playerName]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownPlayer, playerName)
+	}
+	return hand, nil
+}
+
+// Same as Hand, but panics for an unknown player. For players already known
+// to be at the table.
+func (t *Table) MustHand(playerName string) Deck {
+	hand, err := t.Hand(playerName)
+	if err != nil {
+		panic(err)
+	}
+	return hand
 }
 
 func (t *Table) SetIndexOfPlayer(indexOfPlayer map[string]int) error {
@@ -545,55 +1171,224 @@ func (t *Table) RearrangePlayerIndices(indices []int) {
 	}
 }
 
-func (t *Table) ShuffleDeckAndDistribute(startingHandCount int) {
+var ErrInvalidSeating = errors.New("seating must list every player exactly once")
+
+// Seats the players in the given order, which must be a permutation of
+// PlayerNames. Turns follow the seating in the direction of play.
+func (t *Table) SetSeating(order []string) error {
+	if len(order) != t.PlayerCount() {
+		return fmt.Errorf("%w: have %d players, seating lists %d", ErrInvalidSeating, t.PlayerCount(), len(order))
+	}
+
+	seated := make(map[string]bool, len(order))
+	for _, playerName := range order {
+		if _, ok := t.IndexOfPlayer[playerName]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownPlayer, playerName)
+		}
+		if seated[playerName] {
+			return fmt.Errorf("%w: %s is listed twice", ErrInvalidSeating, playerName)
+		}
+		seated[playerName] = true
+	}
+
+	t.PlayerNames = append(StringSlice{}, order...)
+	for i, playerName := range t.PlayerNames {
+		t.IndexOfPlayer[playerName] = i
+	}
+	return nil
+}
+
+const (
+	DefaultStartingHandCount = 7
+	MaxStartingHandCount     = 12
+)
+
+var ErrInvalidStartingHandCount = errors.New("invalid starting hand count")
+
+// The draw deck is too small to deal every player a starting hand and flip the
+// opening card. Also matches ErrInvalidStartingHandCount.
+var ErrNotEnoughCardsToDeal = fmt.Errorf("%w: not enough cards in the draw deck", ErrInvalidStartingHandCount)
+
+// Checks that each player can be dealt startingHandCount cards from the draw
+// deck, leaving at least one card to flip onto the discard pile.
+func (t *Table) ValidateStartingHandCount(startingHandCount int) error {
+	if startingHandCount <= 0 || startingHandCount > MaxStartingHandCount {
+		return fmt.Errorf("%w: %d, must be within 1 and %d", ErrInvalidStartingHandCount, startingHandCount, MaxStartingHandCount)
+	}
+
+	requiredCards := startingHandCount*t.PlayerCount() + 1
+	if requiredCards > len(t.DrawDeck) {
+		return fmt.Errorf("%w: dealing %d cards to %d players needs %d cards but draw deck has %d, combine more decks (at most %d) or lower the starting hand count", ErrNotEnoughCardsToDeal, startingHandCount, t.PlayerCount(), requiredCards, len(t.DrawDeck), MaxDeckCount)
+	}
+	return nil
+}
+
+var ErrInvalidTable = errors.New("invalid table")
+
+// Checks that a dealt table is consistent enough to start playing on, e.g. one
+// built from a hand-config.
+func (t *Table) Validate() error {
+	if t.PlayerCount() < 2 {
+		return fmt.Errorf("%w: need at least 2 players, have %d", ErrInvalidTable, t.PlayerCount())
+	}
+
+	for i, playerName := range t.PlayerNames {
+		if index, ok := t.IndexOfPlayer[playerName]; !ok || index != i {
+			return fmt.Errorf("%w: player %s is not at seat %d", ErrInvalidTable, playerName, i)
+		}
+		if _, ok := t.HandOfPlayer[playerName]; !ok {
+			return fmt.Errorf("%w: player %s has no hand", ErrInvalidTable, playerName)
+		}
+	}
+
+	if _, ok := t.IndexOfPlayer[t.PlayerOfNextTurn]; !ok {
+		return fmt.Errorf("%w: player of next turn '%s' is not at the table", ErrInvalidTable, t.PlayerOfNextTurn)
+	}
+
+	if t.DiscardedPile.IsEmpty() {
+		return fmt.Errorf("%w: %s", ErrInvalidTable, ErrDiscardPileIsEmpty)
+	}
+	return nil
+}
+
+func (t *Table) ShuffleDeckAndDistribute(startingHandCount int) error {
 	if t.IsShuffled {
 		t.Logger.Printf("WARNING: Already shuffled deck")
 	}
 
-	if startingHandCount <= 0 || startingHandCount > 12 {
-		panic("Let's not use too large of a starting hand count")
+	if err := t.ValidateStartingHandCount(startingHandCount); err != nil {
+		return err
 	}
 
 	deckSize := len(t.DrawDeck)
-	shuffledIndices := ShuffleIntRange(0, deckSize)
+	shuffledIndices := ShuffleIntRangeWithRand(t.Rand, 0, deckSize)
 
 	for i, j := range shuffledIndices {
 		t.DrawDeck.Swap(i, j)
 	}
 
-	// Distribute
-	for playerName := range t.IndexOfPlayer {
+	// Distribute in seating order so that the same shuffle deals the same hands
+	for _, playerName := range t.PlayerNames {
 		hand := make(Deck, 0, startingHandCount)
 		hand = append(hand, t.DrawDeck[0:startingHandCount]...)
 		t.HandOfPlayer[playerName] = hand
 		t.DrawDeck = t.DrawDeck[startingHandCount:len(t.DrawDeck)]
 	}
 
-	topCard, _ := t.DrawDeck.Top()
+	if err := t.FlipOpeningCard(); err != nil {
+		return err
+	}
+
+	t.IsShuffled = true
+	return nil
+}
+
+// What happens before the first turn when the opening card flipped onto the
+// discard pile is an action card.
+type OpeningAction string
+
+const (
+	OpeningActionNone    OpeningAction = "none"
+	OpeningActionSkip    OpeningAction = "skip"     // First player is skipped
+	OpeningActionDrawTwo OpeningAction = "draw_two" // First player draws two cards and is skipped
+	OpeningActionReverse OpeningAction = "reverse"  // Direction is flipped before the first turn
+)
+
+// Returns the opening action of the given card. Wild cards have no opening
+// action, the required color defaults to red for them.
+func OpeningActionOfCard(card Card, houseRules HouseRules) OpeningAction {
+	if houseRules.IgnoreOpeningAction {
+		return OpeningActionNone
+	}
+
+	switch card.Number {
+	case NumberSkip:
+		return OpeningActionSkip
+	case NumberDrawTwo:
+		return OpeningActionDrawTwo
+	case NumberReverse:
+		return OpeningActionReverse
+	default:
+		return OpeningActionNone
+	}
+}
+
+// Flips the top card of the draw deck onto the discard pile, applies its
+// opening action and chooses the player of the first turn. The shuffler must
+// be set. The resulting event is kept in OpeningCardEvent so that it can be
+// rendered by clients after they are served the table.
+func (t *Table) FlipOpeningCard() error {
+	if _, ok := t.IndexOfPlayer[t.ShufflerName]; !ok {
+		return fmt.Errorf("%w: shuffler '%s'", ErrUnknownPlayer, t.ShufflerName)
+	}
+
+	topCard, err := t.DrawDeck.Top()
+	if err != nil {
+		return ErrDrawDeckIsEmpty
+	}
 	t.DrawDeck = t.DrawDeck.MustPop()
 	t.DiscardedPile = t.DiscardedPile.Push(topCard)
 
 	t.Logger.Printf("Top card: %+v", topCard)
 
-	if topCard.Number == NumberReverse {
+	action := OpeningActionOfCard(topCard, t.HouseRules)
+
+	if action == OpeningActionReverse {
 		t.Direction = -t.Direction
 	}
 
 	if topCard.IsWild() {
-		// TODO(@rk): Make player choose the color if wild
-		t.SetRequiredColor(ColorRed, nil)
+		// Pick the color the first player holds most. With
+		// ChooseOpeningWildColor it's only a placeholder until they choose.
+		firstPlayer := t.PlayerNames[t.GetNextPlayerIndex(t.IndexOfPlayer[t.ShufflerName], 1)]
+		t.setRequiredColor(mostFrequentColor(t.HandOfPlayer[firstPlayer]), DiscardGameEvents)
 	} else {
-		t.SetRequiredColor(topCard.Color, nil)
+		t.setRequiredColor(topCard.Color, DiscardGameEvents)
 	}
 
 	indexOfNextPlayer := t.GetNextPlayerIndex(t.IndexOfPlayer[t.ShufflerName], 1)
+	affectedPlayer := ""
+
+	if action == OpeningActionSkip || action == OpeningActionDrawTwo {
+		affectedPlayer = t.PlayerNames[indexOfNextPlayer]
+		indexOfNextPlayer = t.GetNextPlayerIndex(indexOfNextPlayer, 1)
+	}
+
+	if action == OpeningActionDrawTwo {
+		for i := 0; i < 2; i++ {
+			card, err := t.DrawDeck.Top()
+			if err != nil {
+				t.Logger.Printf("draw deck ran out while %s was drawing for the opening draw2 card", affectedPlayer)
+				break
+			}
+			t.DrawDeck = t.DrawDeck.MustPop()
+			t.HandOfPlayer[affectedPlayer] = t.HandOfPlayer[affectedPlayer].Push(card)
+		}
+		sort.Sort(t.HandOfPlayer[affectedPlayer])
+	}
+
 	t.SetPlayerOfNextTurn(t.PlayerNames[indexOfNextPlayer])
 	t.SetRequiredNumber(topCard.Number)
 
 	// avoid keeping this as "", since shuffler was the indeed last player
 	t.PlayerOfLastTurn = t.ShufflerName
 
-	t.IsShuffled = true
+	chooseColor := topCard.IsWild() && t.HouseRules.ChooseOpeningWildColor
+	if chooseColor {
+		t.TableState = AwaitingOpeningWildColorDecision
+	}
+
+	t.OpeningCardEvent = OpeningCardEvent{
+		Card:                   topCard,
+		Action:                 action,
+		ShufflerName:           t.ShufflerName,
+		AffectedPlayer:         affectedPlayer,
+		NextPlayer:             t.PlayerOfNextTurn,
+		NextPlayerChoosesColor: chooseColor,
+	}
+
+	t.Logger.Printf("Opening card: %s", t.OpeningCardEvent.StringMessage(t.LocalPlayerName))
+	return nil
 }
 
 type PlayerDecisionKind int
@@ -607,12 +1402,17 @@ const (
 	PlayerDecisionWildCardChooseColor
 	PlayerDecisionDoChallenge
 	PlayerDecisionDontChallenge
+	PlayerDecisionChooseSwapTarget
+	PlayerDecisionUndoDraw
+	PlayerDecisionJumpIn
 )
 
 type PlayerDecision struct {
 	Kind                PlayerDecisionKind
-	ResultCard          Card  // Only required when Kind == PlayerDecisionPlayHandCard
-	WildCardChosenColor Color // Only required when Kind == PlayerDecisionPlayHandCard and ResultCard.Color = Wild
+	ResultCard          Card   // Only required when Kind == PlayerDecisionPlayHandCard or PlayerDecisionJumpIn
+	WildCardChosenColor Color  // Only required when Kind == PlayerDecisionPlayHandCard and ResultCard.Color = Wild
+	TimedOut            bool   // Set by the admin when it decides on behalf of a player who didn't decide in time
+	SwapTargetPlayer    string // Only required when Kind == PlayerDecisionChooseSwapTarget
 }
 
 func (e *PlayerDecision) IsWildDraw4() bool {
@@ -621,7 +1421,7 @@ func (e *PlayerDecision) IsWildDraw4() bool {
 
 func (e *PlayerDecision) String() string {
 	resultCard := ""
-	if e.Kind == PlayerDecisionPlayHandCard {
+	if e.Kind == PlayerDecisionPlayHandCard || e.Kind == PlayerDecisionJumpIn {
 		resultCard = ": " + e.ResultCard.String()
 	}
 	return fmt.Sprintf("%s%s", e.Kind.String(), resultCard)
@@ -631,19 +1431,36 @@ func (t *Table) EvalPlayerDecisionsNoTransferChan(decidingPlayer string, decisio
 	t.Logger.Printf("Syncing without transfer chans from decidingPlayer %s containing decisions: %+v", decidingPlayer, decisions)
 	defer t.Logger.Printf("DONE syncing without transfer chans from decidingPlayer %s", decidingPlayer)
 
-	dummyTransferChan := make(chan GameEvent)
-	go func() {
-		for range dummyTransferChan {
-		}
-	}()
-	err := t.EvalPlayerDecisions(decidingPlayer, decisions, dummyTransferChan)
+	err := t.evalPlayerDecisions(decidingPlayer, decisions, DiscardGameEvents)
 	t.Logger.Printf("EvalPlayerDecisionsNoTransferChan: Error: %v", err)
 	return err
 }
 
 func (t *Table) EvalPlayerDecisions(decidingPlayer string, decisions []PlayerDecision, gameEventPushChan chan<- GameEvent) error {
-	for _, decision := range decisions {
-		_, err := t.EvalPlayerDecision(decidingPlayer, decision, gameEventPushChan)
+	return t.evalPlayerDecisions(decidingPlayer, decisions, GameEventChan(gameEventPushChan))
+}
+
+// Same as EvalPlayerDecisions, with the events emitted to the table's
+// EventSink.
+func (t *Table) EvalDecisions(decidingPlayer string, decisions []PlayerDecision) error {
+	return t.evalPlayerDecisions(decidingPlayer, decisions, t.eventSink())
+}
+
+func (t *Table) evalPlayerDecisions(decidingPlayer string, decisions []PlayerDecision, events GameEventSink) error {
+	for i, decision := range decisions {
+		// Only a stacked play can follow the decision that finished the turn.
+		// It is still the deciding player's turn to play it.
+		if i > 0 && !t.turnNeedsMoreDecisions() {
+			if err := t.checkStackedPlay(decisions[i-1], decision); err != nil {
+				return err
+			}
+			if _, err := t.evalDecisionOfTurn(decidingPlayer, decision, events); err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := t.evalPlayerDecision(decidingPlayer, decision, events)
 		if err != nil {
 			return err
 		}
@@ -651,6 +1468,49 @@ func (t *Table) EvalPlayerDecisions(decidingPlayer string, decisions []PlayerDec
 	return nil
 }
 
+// Returns nil if the decision can be played on top of the previous one in the
+// same turn, see HouseRules.StackSameNumber.
+func (t *Table) checkStackedPlay(previous, decision PlayerDecision) error {
+	if !t.HouseRules.StackSameNumber {
+		return &EvalDecisionError{Decision: decision, Reason: ErrStackingNotAllowed}
+	}
+
+	isPlay := previous.Kind == PlayerDecisionPlayHandCard && decision.Kind == PlayerDecisionPlayHandCard
+	if !isPlay || decision.ResultCard.Number != previous.ResultCard.Number || !isStackable(decision.ResultCard, t.HouseRules) {
+		return &EvalDecisionError{Decision: decision, Reason: ErrIllegalStackedPlay}
+	}
+	return nil
+}
+
+// Cards needing another decision from the player, or moving hands around, are
+// not stacked.
+func isStackable(card Card, houseRules HouseRules) bool {
+	if card.IsWild() {
+		return false
+	}
+	return !houseRules.SevenZero || (card.Number != 0 && card.Number != 7)
+}
+
+// Plays the cards one after another in a single turn. More than one card can
+// only be played with the stack_same_number rule. The cards are checked as a
+// group on a copy of the table first, so either all of them are played or
+// none. Returns the evaluated decisions, to be synced as usual.
+func (t *Table) EvalDropCardsDecision(decidingPlayer string, cards []Card, gameEventPushChan chan<- GameEvent) ([]PlayerDecision, error) {
+	decisions := make([]PlayerDecision, len(cards))
+	for i, card := range cards {
+		decisions[i] = PlayerDecision{Kind: PlayerDecisionPlayHandCard, ResultCard: card}
+	}
+
+	if err := t.Clone().evalPlayerDecisions(decidingPlayer, decisions, DiscardGameEvents); err != nil {
+		return nil, err
+	}
+
+	if err := t.evalPlayerDecisions(decidingPlayer, decisions, GameEventChan(gameEventPushChan)); err != nil {
+		return nil, fmt.Errorf("EvalDropCardsDecision: decisions that passed on a copy of the table failed: %w", err)
+	}
+	return decisions, nil
+}
+
 type EvalDecisionError struct {
 	Decision PlayerDecision
 	Reason   error
@@ -682,37 +1542,172 @@ var ErrPassWithoutDrawOrDrop = errors.New("cannot pass without drawing a card or
 var ErrDrawDeckIsEmpty = errors.New("draw-deck is empty")
 var ErrDiscardPileIsEmpty = errors.New("discard pile is empty")
 var ErrUnknownPlayer = errors.New("unknown player")
+var ErrNothingToUndo = errors.New("no drawn card to undo this turn")
 var ErrInvalidDecision = errors.New("invalid decision")
 var ErrIllegalPlayCard = errors.New("card illegal")
 var ErrUnexpectedDecision = errors.New("unexpected decision")
+var ErrJumpInNotAllowed = errors.New("cannot jump in")
+var ErrNotYourTurn = errors.New("not your turn")
+var ErrNoWildColorToChoose = errors.New("no wild card color to choose")
+var ErrInvalidWildColor = errors.New("wild card color must be red, green, blue or yellow")
+var ErrHasLegalPlay = errors.New("a card in hand can be played, no need to draw")
+var ErrStackingNotAllowed = errors.New("cannot play another card this turn, the stack_same_number rule is off")
+var ErrIllegalStackedPlay = errors.New("only cards of the same number can be stacked, not wild cards or seven_zero's 0s and 7s")
+
+// Returns nil if the color can be chosen for a wild card in the given state.
+func ValidateWildColorChoice(state TableState, color Color) error {
+	if !state.AwaitsWildColorDecision() {
+		return ErrNoWildColorToChoose
+	}
+	if !color.IsReal() {
+		return fmt.Errorf("%w, got %s", ErrInvalidWildColor, color.String())
+	}
+	return nil
+}
+
+// Draws a card for the deciding player and, if the drawn card is playable,
+// plays it right away. Returns the decisions that were evaluated so that they
+// can be synced with other players as usual. If the drawn card is not playable
+// the table is left in AwaitingDropOrPass, same as a plain draw.
+func (t *Table) EvalDrawAndPlayDecision(decidingPlayer string, gameEventPushChan chan<- GameEvent) ([]PlayerDecision, error) {
+	return t.evalDrawAndPlayDecision(decidingPlayer, GameEventChan(gameEventPushChan))
+}
+
+func (t *Table) evalDrawAndPlayDecision(decidingPlayer string, events GameEventSink) ([]PlayerDecision, error) {
+	drawDecision, err := t.evalPlayerDecision(decidingPlayer, PlayerDecision{Kind: PlayerDecisionPullFromDeck}, events)
+	if err != nil {
+		return nil, err
+	}
+
+	decisions := []PlayerDecision{drawDecision}
+
+	if !t.IsPlayable(t.LastDrawnCard) {
+		t.Logger.Printf("Drawn card %s is not playable, not auto-playing it", t.LastDrawnCard.String())
+		return decisions, nil
+	}
+
+	playDecision, err := t.evalPlayerDecision(decidingPlayer, PlayerDecision{
+		Kind:       PlayerDecisionPlayHandCard,
+		ResultCard: t.LastDrawnCard,
+	}, events)
+	if err != nil {
+		return decisions, err
+	}
+
+	return append(decisions, playDecision), nil
+}
+
+// Cards in the player's hand that can be played on the current turn.
+func (t *Table) LegalPlays(playerName string) Deck {
+	legalPlays := NewEmptyDeck()
+	for _, card := range t.HandOfPlayer[playerName] {
+		if t.IsPlayable(card) {
+			legalPlays = legalPlays.Push(card)
+		}
+	}
+	return legalPlays
+}
+
+// Performs the draw a player without a legal play is forced into: draws a card
+// and plays it if it's playable, passes otherwise. Returns the evaluated
+// decisions same as EvalDrawAndPlayDecision.
+func (t *Table) EvalForcedDrawDecision(decidingPlayer string, gameEventPushChan chan<- GameEvent) ([]PlayerDecision, error) {
+	return t.evalForcedDrawDecision(decidingPlayer, GameEventChan(gameEventPushChan))
+}
+
+func (t *Table) evalForcedDrawDecision(decidingPlayer string, events GameEventSink) ([]PlayerDecision, error) {
+	if t.TableState == StartOfTurn && !t.LegalPlays(decidingPlayer).IsEmpty() {
+		return nil, ErrHasLegalPlay
+	}
+
+	decisions, err := t.evalDrawAndPlayDecision(decidingPlayer, events)
+	if err != nil || t.TableState != AwaitingDropOrPass {
+		return decisions, err
+	}
+
+	passDecision, err := t.evalPlayerDecision(decidingPlayer, PlayerDecision{Kind: PlayerDecisionPass}, events)
+	if err != nil {
+		return decisions, err
+	}
+	return append(decisions, passDecision), nil
+}
 
 func (t *Table) NeedMoreUserDecisionToFinishTurn() bool {
-	res := t.TableState == AwaitingWildCardColorDecision ||
-		t.TableState == AwaitingWildDraw4CardColorDecision ||
-		t.TableState == AwaitingDropOrPass
+	res := t.turnNeedsMoreDecisions()
 	t.Logger.Printf("Need more decision from %s? %v", t.LocalPlayerName, res)
 	return res
 }
 
+func (t *Table) turnNeedsMoreDecisions() bool {
+	return t.TableState == AwaitingWildCardColorDecision ||
+		t.TableState == AwaitingWildDraw4CardColorDecision ||
+		t.TableState == AwaitingDropOrPass ||
+		t.TableState == AwaitingSwapTargetDecision
+}
+
+// Evaluates the decision, sending the resulting game events on
+// gameEventPushChan. The events are dropped if the channel is nil.
 func (t *Table) EvalPlayerDecision(decidingPlayer string, decision PlayerDecision, gameEventPushChan chan<- GameEvent) (PlayerDecision, error) {
+	return t.evalPlayerDecision(decidingPlayer, decision, GameEventChan(gameEventPushChan))
+}
+
+// Same as EvalPlayerDecision, with the events emitted to the table's
+// EventSink.
+func (t *Table) EvalDecision(decidingPlayer string, decision PlayerDecision) (PlayerDecision, error) {
+	return t.evalPlayerDecision(decidingPlayer, decision, t.eventSink())
+}
+
+func (t *Table) eventSink() GameEventSink {
+	if t.EventSink == nil {
+		return DiscardGameEvents
+	}
+	return t.EventSink
+}
+
+func (t *Table) evalPlayerDecision(decidingPlayer string, decision PlayerDecision, events GameEventSink) (PlayerDecision, error) {
+	// The game is over once a player has won, nothing can be decided anymore.
+	if t.WinnerPlayerName != "" {
+		return decision, &EvalDecisionError{Decision: decision, Reason: fmt.Errorf("%w, %s has already won", ErrUnexpectedDecision, t.WinnerPlayerName)}
+	}
+
+	if _, err := t.Hand(decidingPlayer); err != nil {
+		return decision, &EvalDecisionError{Decision: decision, Reason: err}
+	}
+
+	// Only a jump-in can be decided out of turn, see CanJumpIn.
+	if decidingPlayer != t.PlayerOfNextTurn && decision.Kind != PlayerDecisionJumpIn {
+		return decision, &EvalDecisionError{Decision: decision, Reason: fmt.Errorf("%w: it's %s's turn", ErrNotYourTurn, t.PlayerOfNextTurn)}
+	}
+
+	return t.evalDecisionOfTurn(decidingPlayer, decision, events)
+}
+
+// Evaluates a decision of the player whose turn it is.
+func (t *Table) evalDecisionOfTurn(decidingPlayer string, decision PlayerDecision, events GameEventSink) (PlayerDecision, error) {
+	// The color of an opening wild must be chosen before anything else.
+	if t.TableState == AwaitingOpeningWildColorDecision && decision.Kind != PlayerDecisionWildCardChooseColor {
+		return decision, &EvalDecisionError{Decision: decision, Reason: ErrUnexpectedDecision}
+	}
+
 	switch decision.Kind {
 	case PlayerDecisionPullFromDeck:
 		if t.TableState != StartOfTurn {
 			return decision, &EvalDecisionError{Decision: decision, Reason: ErrAlreadyDrewCard}
 		}
 
-		topCard, err := t.pullCardFromDeckToPlayerHand(decidingPlayer, gameEventPushChan, decidingPlayer == t.LocalPlayerName)
+		topCard, err := t.pullCardFromDeckToPlayerHand(decidingPlayer, events, decidingPlayer == t.LocalPlayerName)
 		if err != nil {
 			return decision, &EvalDecisionError{Decision: decision, Reason: err}
 		}
 
 		decision.ResultCard = topCard
+		t.LastDrawnCard = topCard
 		t.TableState = AwaitingDropOrPass
 
-		gameEventPushChan <- AwaitingPlayOrPassEvent{
+		events.Emit(AwaitingPlayOrPassEvent{
 			Player:                     decidingPlayer,
 			AskDecisionFromLocalPlayer: decidingPlayer == t.LocalPlayerName,
-		}
+		})
 
 	case PlayerDecisionPass:
 		if t.TableState != AwaitingDropOrPass {
@@ -721,35 +1716,82 @@ func (t *Table) EvalPlayerDecision(decidingPlayer string, decision PlayerDecisio
 
 		t.setNeighborAsNextPlayer(decidingPlayer, StartOfTurn)
 
-		gameEventPushChan <- PlayerPassedTurnEvent{
+		events.Emit(PlayerPassedTurnEvent{
 			Player:            decidingPlayer,
 			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
 			PlayerOfNextTurn:  t.PlayerOfNextTurn,
+		})
+
+	case PlayerDecisionUndoDraw:
+		if t.TableState != AwaitingDropOrPass {
+			return decision, &EvalDecisionError{Decision: decision, Reason: ErrNothingToUndo}
+		}
+
+		if t.HouseRules.UndoDrawReturnsCard {
+			if err := t.undoLastDraw(decidingPlayer, events); err != nil {
+				return decision, &EvalDecisionError{Decision: decision, Reason: err}
+			}
+			break
 		}
 
+		// Same as passing, the drawn card stays in hand.
+		t.setNeighborAsNextPlayer(decidingPlayer, StartOfTurn)
+
+		events.Emit(UndoEvent{
+			Player:            decidingPlayer,
+			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
+		})
+
+		events.Emit(PlayerPassedTurnEvent{
+			Player:            decidingPlayer,
+			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
+			PlayerOfNextTurn:  t.PlayerOfNextTurn,
+		})
+
 	case PlayerDecisionPlayHandCard:
 		if t.TableState != StartOfTurn && t.TableState != AwaitingDropOrPass {
 			return decision, &EvalDecisionError{Decision: decision, Reason: ErrInvalidDecision}
 		}
 
-		decision, err := t.tryPlayCard(decidingPlayer, decision.ResultCard, gameEventPushChan)
+		decision, err := t.tryPlayCard(decidingPlayer, decision.ResultCard, events)
 		if err != nil {
 			return decision, err
 		}
 
-		t.checkIfPlayerHasWon(decidingPlayer, decision.ResultCard, gameEventPushChan)
+		if _, err := t.checkIfPlayerHasWon(decidingPlayer, decision.ResultCard, events); err != nil {
+			return decision, err
+		}
+
+	case PlayerDecisionJumpIn:
+		if err := t.CanJumpIn(decidingPlayer, decision.ResultCard); err != nil {
+			return decision, &EvalDecisionError{Decision: decision, Reason: err}
+		}
+
+		if err := t.evalJumpIn(decidingPlayer, decision.ResultCard, events); err != nil {
+			return decision, err
+		}
 
 	case PlayerDecisionWildCardChooseColor:
-		if t.TableState != AwaitingWildCardColorDecision && t.TableState != AwaitingWildDraw4CardColorDecision {
-			return decision, &EvalDecisionError{Decision: decision, Reason: ErrUnexpectedDecision}
+		if err := ValidateWildColorChoice(t.TableState, decision.WildCardChosenColor); err != nil {
+			return decision, &EvalDecisionError{Decision: decision, Reason: err}
 		}
 
-		t.SetRequiredColor(decision.WildCardChosenColor, gameEventPushChan)
+		t.setRequiredColor(decision.WildCardChosenColor, events)
 
 		t.Logger.Printf("Setting required color to wild card chosen color %s, previous color: %s", decision.WildCardChosenColor.String(), t.RequiredColorOfLastTurn.String())
 
-		// Two distinct cases for wild and wild_draw_4
-		if t.TableState == AwaitingWildCardColorDecision {
+		events.Emit(WildCardColorChosenEvent{
+			Player:            decidingPlayer,
+			ChosenColor:       decision.WildCardChosenColor,
+			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
+		})
+
+		// Three distinct cases for wild, wild_draw_4 and the opening wild.
+		// After choosing the opening color the player still has the first
+		// turn, which the admin starts as a new turn.
+		if t.TableState == AwaitingOpeningWildColorDecision {
+			t.TableState = StartOfTurn
+		} else if t.TableState == AwaitingWildCardColorDecision {
 			t.TableState = StartOfTurn
 			t.setNeighborAsNextPlayer(decidingPlayer, StartOfTurn)
 		} else {
@@ -762,14 +1804,7 @@ func (t *Table) EvalPlayerDecision(decidingPlayer string, decision PlayerDecisio
 			return decision, &EvalDecisionError{Decision: decision, Reason: ErrUnexpectedDecision}
 		}
 
-		hand := t.HandOfPlayer[t.PlayerOfLastTurn]
-		eligibleCards := NewEmptyDeck()
-
-		for _, card := range hand {
-			if !card.IsWild() && (card.Color == t.RequiredColorOfLastTurn || card.Number == t.RequiredNumberBeforeWild4) {
-				eligibleCards = append(eligibleCards, card)
-			}
-		}
+		eligibleCards := t.cardsMakingWildDraw4Illegal()
 
 		var sb strings.Builder
 
@@ -782,28 +1817,32 @@ func (t *Table) EvalPlayerDecision(decidingPlayer string, decision PlayerDecisio
 		Hands before challenge: %s`, t.PlayerOfLastTurn, t.PlayerOfNextTurn, t.RequiredColorOfLastTurn.String(), t.RequiredNumberBeforeWild4.String(), eligibleCards, sb.String())
 
 		if eligibleCards.Len() != 0 {
-			gameEventPushChan <- ChallengerSuccessEvent{
+			t.countChallenge(decidingPlayer, t.PlayerOfLastTurn)
+
+			events.Emit(ChallengerSuccessEvent{
 				ChallengerName:      decidingPlayer,
 				WildDraw4PlayerName: t.PlayerOfLastTurn,
 				EligibleCards:       eligibleCards,
 				IsFromLocalClient:   decidingPlayer == t.LocalPlayerName,
-			}
+			})
 
 			for i := 0; i < 4; i++ {
-				_, err := t.pullCardFromDeckToPlayerHand(t.PlayerOfLastTurn, gameEventPushChan, decidingPlayer == t.LocalPlayerName)
+				_, err := t.pullCardFromDeckToPlayerHand(t.PlayerOfLastTurn, events, decidingPlayer == t.LocalPlayerName)
 				if err != nil {
 					return decision, err
 				}
 			}
 		} else {
-			gameEventPushChan <- ChallengerFailedEvent{
+			t.countChallenge(t.PlayerOfLastTurn, decidingPlayer)
+
+			events.Emit(ChallengerFailedEvent{
 				ChallengerName:      decidingPlayer,
 				WildDraw4PlayerName: t.PlayerOfLastTurn,
 				IsFromLocalClient:   decidingPlayer == t.LocalPlayerName,
-			}
+			})
 
 			for i := 0; i < 4; i++ {
-				_, err := t.pullCardFromDeckToPlayerHand(decidingPlayer, gameEventPushChan, decidingPlayer == t.LocalPlayerName)
+				_, err := t.pullCardFromDeckToPlayerHand(decidingPlayer, events, decidingPlayer == t.LocalPlayerName)
 				if err != nil {
 					return decision, err
 				}
@@ -821,13 +1860,48 @@ func (t *Table) EvalPlayerDecision(decidingPlayer string, decision PlayerDecisio
 
 		t.setNeighborAsNextPlayer(decidingPlayer, StartOfTurn)
 
+	case PlayerDecisionChooseSwapTarget:
+		if t.TableState != AwaitingSwapTargetDecision {
+			return decision, &EvalDecisionError{Decision: decision, Reason: ErrUnexpectedDecision}
+		}
+
+		if _, ok := t.HandOfPlayer[decision.SwapTargetPlayer]; !ok || decision.SwapTargetPlayer == decidingPlayer {
+			return decision, &EvalDecisionError{Decision: decision, Reason: fmt.Errorf("%w: invalid swap target '%s'", ErrInvalidDecision, decision.SwapTargetPlayer)}
+		}
+
+		t.HandOfPlayer[decidingPlayer], t.HandOfPlayer[decision.SwapTargetPlayer] = t.HandOfPlayer[decision.SwapTargetPlayer], t.HandOfPlayer[decidingPlayer]
+
+		events.Emit(HandsSwappedEvent{
+			Player:            decidingPlayer,
+			TargetPlayer:      decision.SwapTargetPlayer,
+			HandCountOfPlayer: t.handCountOfPlayer(),
+			LocalPlayerHand:   t.HandOfPlayer[t.LocalPlayerName].Clone(),
+			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
+		})
+
+		t.setNeighborAsNextPlayer(decidingPlayer, StartOfTurn)
+
+		events.Emit(PlayerPassedTurnEvent{
+			Player:            decidingPlayer,
+			PlayerOfNextTurn:  t.PlayerOfNextTurn,
+			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
+		})
+
 	case PlayerDecisionDontChallenge:
 		if t.TableState != AwaitingWildDraw4ChallengeDecision {
 			return decision, &EvalDecisionError{Decision: decision, Reason: ErrUnexpectedDecision}
 		}
 
+		if decision.TimedOut {
+			events.Emit(ChallengeTimedOutEvent{
+				Player:              decidingPlayer,
+				WildDraw4PlayerName: t.PlayerOfLastTurn,
+				IsFromLocalClient:   decidingPlayer == t.LocalPlayerName,
+			})
+		}
+
 		for i := 0; i < 4; i++ {
-			_, err := t.pullCardFromDeckToPlayerHand(decidingPlayer, gameEventPushChan, decidingPlayer == t.LocalPlayerName)
+			_, err := t.pullCardFromDeckToPlayerHand(decidingPlayer, events, decidingPlayer == t.LocalPlayerName)
 			if err != nil {
 				return decision, err
 			}
@@ -841,6 +1915,31 @@ func (t *Table) EvalPlayerDecision(decidingPlayer string, decision PlayerDecisio
 	return decision, nil
 }
 
+// Passes each hand to the next player in the direction of play.
+func (t *Table) rotateHands(decidingPlayer string, events GameEventSink) {
+	rotatedHandOfPlayer := make(map[string]Deck, len(t.HandOfPlayer))
+	for i, playerName := range t.PlayerNames {
+		nextPlayerName := t.PlayerNames[t.GetNextPlayerIndex(i, 1)]
+		rotatedHandOfPlayer[nextPlayerName] = t.HandOfPlayer[playerName]
+	}
+	t.HandOfPlayer = rotatedHandOfPlayer
+
+	events.Emit(HandsRotatedEvent{
+		Player:            decidingPlayer,
+		HandCountOfPlayer: t.handCountOfPlayer(),
+		LocalPlayerHand:   t.HandOfPlayer[t.LocalPlayerName].Clone(),
+		IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
+	})
+}
+
+func (t *Table) handCountOfPlayer() map[string]int {
+	handCountOfPlayer := make(map[string]int, len(t.HandOfPlayer))
+	for playerName, hand := range t.HandOfPlayer {
+		handCountOfPlayer[playerName] = hand.Len()
+	}
+	return handCountOfPlayer
+}
+
 func (t *Table) setNeighborAsNextPlayer(currentPlayer string, nextState TableState) {
 	playerIndex := t.PlayerIndexFromName(currentPlayer)
 	nextPlayerIndex := t.GetNextPlayerIndex(playerIndex, t.Direction)
@@ -853,7 +1952,7 @@ func (t *Table) setNeighborAsNextPlayer(currentPlayer string, nextState TableSta
 // TODO(@rk): Evaluate the played card, emitting more transfer events and deciding NextPlayerToDraw
 
 // CONSIDER(@rk): For replay events, we shouldn't need to check rules.
-func (t *Table) tryPlayCard(decidingPlayer string, cardToPlay Card, gameEventPushChan chan<- GameEvent) (PlayerDecision, error) {
+func (t *Table) tryPlayCard(decidingPlayer string, cardToPlay Card, events GameEventSink) (PlayerDecision, error) {
 	// This procedure's precondition is that it was indeed the player's turn. Given that, it checks if the play is valid
 	decision := PlayerDecision{
 		Kind:       PlayerDecisionPlayHandCard,
@@ -861,15 +1960,17 @@ func (t *Table) tryPlayCard(decidingPlayer string, cardToPlay Card, gameEventPus
 	}
 
 	// cardToPlay must come from hand
-	playerHand, ok := t.HandOfPlayer[decidingPlayer]
-	if !ok {
-		return decision, &EvalDecisionError{
-			Decision: decision,
-			Reason:   fmt.Errorf("%w: %s", ErrUnknownPlayer, decidingPlayer),
-		}
+	playerHand, err := t.Hand(decidingPlayer)
+	if err != nil {
+		return decision, &EvalDecisionError{Decision: decision, Reason: err}
 	}
 
-	_, err := playerHand.FindCard(cardToPlay)
+	cardLoc, err := playerHand.FindCard(cardToPlay)
+	if err != nil && decidingPlayer != t.LocalPlayerName {
+		// An opponent's hand is redacted on a client, so any of its hidden
+		// cards stands in for the played one.
+		cardLoc, err = playerHand.FindCard(HiddenCard)
+	}
 	if err != nil {
 		return decision, &EvalDecisionError{
 			Decision: decision,
@@ -877,19 +1978,15 @@ func (t *Table) tryPlayCard(decidingPlayer string, cardToPlay Card, gameEventPus
 		}
 	}
 
-	// Check top of discard pile
-	topOfPile, err := t.DiscardedPile.Top()
-	if err != nil {
-		panic("Should be unreachable. Discard pile always has at least 1 card")
-	}
+	isPlayable := t.IsPlayable(cardToPlay)
 
-	// numberMatches := topOfPile.Number == cardToPlay.Number || cardToPlay.IsWild()
-	numberMatches := cardToPlay.Number == t.RequiredNumberOfCurrentTurn || cardToPlay.IsWild()
-	colorMatches := t.RequiredColorOfCurrentTurn == cardToPlay.Color
+	t.Logger.Printf("Card %s playable: %v", cardToPlay.String(), isPlayable)
 
-	t.Logger.Printf("Number matches: %v, Color matches: %v", numberMatches, colorMatches)
+	// If the hand also holds a copy of the drawn card, playing either counts
+	// as playing the drawn card.
+	playsDrawnCard := t.TableState == AwaitingDropOrPass && cardToPlay == t.LastDrawnCard
 
-	if !numberMatches && !colorMatches {
+	if !isPlayable {
 		t.Logger.Printf("CANNOT play card: %s", cardToPlay.String())
 
 		return decision, &EvalDecisionError{
@@ -897,7 +1994,7 @@ func (t *Table) tryPlayCard(decidingPlayer string, cardToPlay Card, gameEventPus
 			Reason: &IllegalPlayError{
 				Card:           cardToPlay,
 				ExpectedColor:  t.RequiredColorOfCurrentTurn,
-				ExpectedNumber: topOfPile.Number,
+				ExpectedNumber: t.NumberToMatch(),
 			},
 		}
 	}
@@ -905,43 +2002,82 @@ func (t *Table) tryPlayCard(decidingPlayer string, cardToPlay Card, gameEventPus
 	// Can play card
 
 	// Remove card from hand and put it on pile
-	hand := t.HandOfPlayer[decidingPlayer]
-	cardLoc := hand.MustFindCard(cardToPlay)
-	hand = append(hand[0:cardLoc], hand[cardLoc+1:]...)
+	hand, err := playerHand.RemoveCard(cardLoc)
+	if err != nil {
+		return decision, &EvalDecisionError{Decision: decision, Reason: err}
+	}
 	t.HandOfPlayer[decidingPlayer] = hand
 	t.DiscardedPile = t.DiscardedPile.Push(cardToPlay)
 
-	gameEventPushChan <- CardTransferEvent{
+	events.Emit(CardTransferEvent{
 		Source:            CardTransferNodePlayerHand,
 		Sink:              CardTransferNodePile,
 		SourcePlayer:      decidingPlayer,
 		Card:              cardToPlay,
 		IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
-	}
+	})
 
-	// TODO(@rk): If card player's hand is empty, switch to win state - some ideas around it. Think later.
+	t.updateStats(decidingPlayer, func(stats *PlayerStats) {
+		stats.CardsPlayed++
+		if cardToPlay.IsWild() {
+			stats.WildsPlayed++
+		}
+	})
 
-	if t.HandOfPlayer[decidingPlayer].Len() == 0 {
+	// Playing the last card wins the game. Its action is not applied so that
+	// the game doesn't wait for decisions from, or pass the turn on after, the
+	// winner.
+	if hand.Len() == 0 {
+		if !cardToPlay.IsWild() {
+			t.setRequiredColor(cardToPlay.Color, events)
+		}
+		t.SetRequiredNumber(cardToPlay.Number)
 		t.WinnerPlayerName = decidingPlayer
+		t.TableState = HaveWinner
+		return decision, nil
 	}
 
-	if cardToPlay.Number.IsAction() {
-		t.evalPlayedActionCard(decidingPlayer, cardToPlay, gameEventPushChan)
+	sevenZero := t.HouseRules.SevenZero
+	drawnActionHasNoEffect := t.HouseRules.DrawnActionCardHasNoEffect && playsDrawnCard && !cardToPlay.IsWild()
+
+	if cardToPlay.Number.IsAction() && !drawnActionHasNoEffect {
+		if err := t.evalPlayedActionCard(decidingPlayer, cardToPlay, events); err != nil {
+			return decision, &EvalDecisionError{Decision: decision, Reason: err}
+		}
+	} else if sevenZero && cardToPlay.Number == 7 {
+		// Player stays the same until they choose whom to swap with
+		t.TableState = AwaitingSwapTargetDecision
+		t.setRequiredColor(cardToPlay.Color, events)
+		t.SetRequiredNumber(cardToPlay.Number)
+
+		events.Emit(AwaitingSwapTargetDecisionEvent{
+			Player:                     decidingPlayer,
+			AskDecisionFromLocalPlayer: decidingPlayer == t.LocalPlayerName,
+			IsFromLocalClient:          decidingPlayer == t.LocalPlayerName,
+		})
 	} else {
 		// TODO(@rk): Better to handle in a separate function for all non-action card plays.
 		t.setNeighborAsNextPlayer(decidingPlayer, StartOfTurn)
 		t.TableState = StartOfTurn
-		t.SetRequiredColor(cardToPlay.Color, gameEventPushChan)
+		t.setRequiredColor(cardToPlay.Color, events)
 		t.SetRequiredNumber(cardToPlay.Number)
+
+		if drawnActionHasNoEffect {
+			t.Logger.Printf("Drawn action card %s discarded without effect", cardToPlay.String())
+		}
+
+		if sevenZero && cardToPlay.Number == 0 {
+			t.rotateHands(decidingPlayer, events)
+		}
 	}
 
 	if !t.NeedMoreUserDecisionToFinishTurn() {
 		// Let the UI know that the turn has passed
-		gameEventPushChan <- PlayerPassedTurnEvent{
+		events.Emit(PlayerPassedTurnEvent{
 			Player:            decidingPlayer,
 			PlayerOfNextTurn:  t.PlayerOfNextTurn,
 			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
-		}
+		})
 	}
 
 	return decision, nil
@@ -957,12 +2093,13 @@ func (t *Table) setNextPlayerSkipOne(decidingPlayer string) (skippedPlayer, next
 	return
 }
 
-func (t *Table) evalPlayedActionCard(decidingPlayer string, actionCard Card, gameEventPushChan chan<- GameEvent) {
+func (t *Table) evalPlayedActionCard(decidingPlayer string, actionCard Card, events GameEventSink) error {
 	switch actionCard.Number {
 	case NumberSkip:
 		skippedPlayer, nextPlayer := t.setNextPlayerSkipOne(decidingPlayer)
+		t.countSkip(decidingPlayer, skippedPlayer)
 		t.TableState = StartOfTurn
-		t.SetRequiredColor(actionCard.Color, gameEventPushChan)
+		t.setRequiredColor(actionCard.Color, events)
 		t.SetRequiredNumber(actionCard.Number)
 
 		event := SkipCardActionEvent{
@@ -974,29 +2111,42 @@ func (t *Table) evalPlayedActionCard(decidingPlayer string, actionCard Card, gam
 
 		t.Logger.Printf("evaluated skip card action: %s", event.StringMessage(t.LocalPlayerName))
 
-		gameEventPushChan <- event
+		events.Emit(event)
 
 	case NumberDrawTwo:
+		// A draw deck short of the two penalty cards is first refilled from
+		// the discard pile. If it is still short, the skipped player draws
+		// whatever is left, possibly nothing, and is skipped all the same.
+		// The penalty is sized up front so that the turn is always fully
+		// applied, on the admin and on every client.
+		if t.DrawDeck.Len() < 2 {
+			t.refillDrawDeckFromPile()
+		}
+
+		cardsToDraw := 2
+		if deckLen := t.DrawDeck.Len(); deckLen < cardsToDraw {
+			cardsToDraw = deckLen
+		}
+
 		skippedPlayer, nextPlayer := t.setNextPlayerSkipOne(decidingPlayer)
+		t.countSkip(decidingPlayer, skippedPlayer)
 		t.TableState = StartOfTurn
-		t.SetRequiredColor(actionCard.Color, gameEventPushChan)
+		t.setRequiredColor(actionCard.Color, events)
 		t.SetRequiredNumber(actionCard.Number)
 
 		event := DrawTwoCardActionEvent{
 			Player:            decidingPlayer,
 			SkippedPlayer:     skippedPlayer,
 			NextPlayer:        nextPlayer,
+			CardsShort:        2 - cardsToDraw,
 			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
 		}
 
-		gameEventPushChan <- event
-
-		for i := 0; i < 2; i++ {
-			_, err := t.pullCardFromDeckToPlayerHand(skippedPlayer, gameEventPushChan, decidingPlayer == t.LocalPlayerName)
+		events.Emit(event)
 
-			if err != nil {
-				t.Logger.Printf("failed to pull card from deck to hand of player %s as part of draw2 action: %v", skippedPlayer, err)
-				return
+		for i := 0; i < cardsToDraw; i++ {
+			if _, err := t.pullCardFromDeckToPlayerHand(skippedPlayer, events, decidingPlayer == t.LocalPlayerName); err != nil {
+				return fmt.Errorf("failed to pull card from deck to hand of player %s as part of draw2 action: %w", skippedPlayer, err)
 			}
 		}
 
@@ -1014,7 +2164,7 @@ func (t *Table) evalPlayedActionCard(decidingPlayer string, actionCard Card, gam
 		t.SetPlayerOfNextTurn(t.PlayerNames[nextPlayerIndex])
 		deniedPlayer := t.PlayerNames[deniedPlayerIndex]
 		t.TableState = StartOfTurn
-		t.SetRequiredColor(actionCard.Color, gameEventPushChan)
+		t.setRequiredColor(actionCard.Color, events)
 		t.SetRequiredNumber(actionCard.Number)
 
 		event := ReverseCardActionEvent{
@@ -1026,7 +2176,7 @@ func (t *Table) evalPlayedActionCard(decidingPlayer string, actionCard Card, gam
 
 		t.Logger.Printf("evaluated reverse card action: %s", event.StringMessage(t.LocalPlayerName))
 
-		gameEventPushChan <- event
+		events.Emit(event)
 
 	case NumberWild:
 		t.TableState = AwaitingWildCardColorDecision
@@ -1040,7 +2190,7 @@ func (t *Table) evalPlayedActionCard(decidingPlayer string, actionCard Card, gam
 			IsFromLocalClient:          decidingPlayer == t.LocalPlayerName,
 		}
 
-		gameEventPushChan <- event
+		events.Emit(event)
 
 	case NumberWildDrawFour:
 		t.TableState = AwaitingWildDraw4CardColorDecision
@@ -1061,15 +2211,40 @@ func (t *Table) evalPlayedActionCard(decidingPlayer string, actionCard Card, gam
 			IsFromLocalClient:          decidingPlayer == t.LocalPlayerName,
 		}
 
-		gameEventPushChan <- event
+		events.Emit(event)
 
 	default:
 		t.Logger.Panicf("failed to eval action card %s, not implemented", actionCard.String())
 	}
+	return nil
+}
+
+// Puts the discard pile, except its top card, under the draw deck. The admin
+// shuffles the cards first. A client can't know that shuffle, so it puts
+// hidden cards in their place and resyncs once a hidden card is drawn into its
+// hand, see NeedsResyncAfter.
+func (t *Table) refillDrawDeckFromPile() {
+	if t.DiscardedPile.Len() < 2 {
+		return
+	}
+
+	topOfPile := t.DiscardedPile.MustTop()
+	refill := t.DiscardedPile.MustPop().Clone()
+	if t.IsServerTable() {
+		for i, j := range ShuffleIntRangeWithRand(t.Rand, 0, refill.Len()) {
+			refill.Swap(i, j)
+		}
+	} else {
+		refill = NewHiddenDeck(refill.Len())
+	}
+
+	t.DrawDeck = append(refill, t.DrawDeck...)
+	t.DiscardedPile = Deck{topOfPile}
+	t.Logger.Printf("refilled the draw deck with %d cards from the discard pile", refill.Len())
 }
 
 // Pull top card from draw deck and put it in target player's hand. Returns the card pulled.
-func (t *Table) pullCardFromDeckToPlayerHand(targetPlayer string, gameEventPushChan chan<- GameEvent, eventIsFromLocalClient bool) (Card, error) {
+func (t *Table) pullCardFromDeckToPlayerHand(targetPlayer string, events GameEventSink, eventIsFromLocalClient bool) (Card, error) {
 	topCard, err := t.DrawDeck.Top()
 	if err != nil {
 		return topCard, ErrDrawDeckIsEmpty
@@ -1078,6 +2253,7 @@ func (t *Table) pullCardFromDeckToPlayerHand(targetPlayer string, gameEventPushC
 	t.HandOfPlayer[targetPlayer] = t.HandOfPlayer[targetPlayer].Push(topCard)
 	sort.Sort(t.HandOfPlayer[targetPlayer])
 	t.DrawDeck = t.DrawDeck.MustPop()
+	t.updateStats(targetPlayer, func(stats *PlayerStats) { stats.CardsDrawn++ })
 
 	event := CardTransferEvent{
 		Source:            CardTransferNodeDeck,
@@ -1087,22 +2263,172 @@ func (t *Table) pullCardFromDeckToPlayerHand(targetPlayer string, gameEventPushC
 		IsFromLocalClient: eventIsFromLocalClient,
 	}
 
-	gameEventPushChan <- event
+	events.Emit(event)
 	t.Logger.Printf("pullCardFromDeckToPlayerHand: %s", event.String(t.LocalPlayerName))
 	return topCard, nil
 }
 
-func (t *Table) checkIfPlayerHasWon(decidingPlayer string, lastCardDropped Card, gameEventPushChan chan<- GameEvent) bool {
-	hand := t.HandOfPlayer[decidingPlayer]
+// Returns the card of a jump-in if the decisions consist of just that.
+func JumpInCard(decisions []PlayerDecision) (Card, bool) {
+	if len(decisions) != 1 || decisions[0].Kind != PlayerDecisionJumpIn {
+		return Card{}, false
+	}
+	return decisions[0].ResultCard, true
+}
+
+// Reports whether any of the decisions was made on behalf of a player who
+// didn't decide in time. Only the admin makes such decisions.
+func DecidedOnTimeout(decisions []PlayerDecision) bool {
+	for _, decision := range decisions {
+		if decision.TimedOut {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns nil if the player can jump in with the card, i.e. play it out of
+// turn, otherwise the reason they can't. Only cards identical to the top of
+// the discard pile can be played, and only at the start of another player's
+// turn. Cards that need a further decision from the player, wild cards and a
+// 7 with the Seven-Zero rule, are not allowed.
+func (t *Table) CanJumpIn(playerName string, card Card) error {
+	if !t.HouseRules.JumpIn {
+		return fmt.Errorf("%w: the jump_in rule is off", ErrJumpInNotAllowed)
+	}
+
+	hand, err := t.Hand(playerName)
+	if err != nil {
+		return err
+	}
+
+	if playerName == t.PlayerOfNextTurn {
+		return fmt.Errorf("%w: it's %s's turn already", ErrJumpInNotAllowed, playerName)
+	}
+
+	if t.TableState != StartOfTurn {
+		return fmt.Errorf("%w: %s has already started the turn", ErrJumpInNotAllowed, t.PlayerOfNextTurn)
+	}
+
+	if card.IsWild() || (t.HouseRules.SevenZero && card.Number == 7) {
+		return fmt.Errorf("%w: %s needs a further decision", ErrJumpInNotAllowed, card.String())
+	}
+
+	topOfPile, err := t.DiscardedPile.Top()
+	if err != nil {
+		return ErrDiscardPileIsEmpty
+	}
+	if !card.IsEqual(topOfPile) {
+		return fmt.Errorf("%w: %s is not identical to the top card %s", ErrJumpInNotAllowed, card.String(), topOfPile.String())
+	}
+
+	// An opponent's hand is redacted on a client
+	if _, err := hand.FindCard(card); err != nil && (playerName == t.LocalPlayerName || !hand.HasHiddenCards()) {
+		return ErrCardNotInHand
+	}
+	return nil
+}
+
+// Plays the card of a jump-in as if it was the jumping player's turn, so play
+// continues from them. Must be checked with CanJumpIn first.
+func (t *Table) evalJumpIn(jumpingPlayer string, card Card, events GameEventSink) error {
+	interruptedPlayer := t.PlayerOfNextTurn
+	playerOfLastTurn := t.PlayerOfLastTurn
+	t.SetPlayerOfNextTurn(jumpingPlayer)
+
+	playDecision, err := t.tryPlayCard(jumpingPlayer, card, events)
+	if err != nil {
+		t.PlayerOfNextTurn = interruptedPlayer
+		t.PlayerOfLastTurn = playerOfLastTurn
+		return err
+	}
+
+	events.Emit(JumpInEvent{
+		Player:            jumpingPlayer,
+		InterruptedPlayer: interruptedPlayer,
+		Card:              card,
+		IsFromLocalClient: jumpingPlayer == t.LocalPlayerName,
+	})
+
+	_, err = t.checkIfPlayerHasWon(jumpingPlayer, playDecision.ResultCard, events)
+	return err
+}
+
+// Puts the card drawn this turn back on top of the draw deck and lets the
+// player decide again from the start of the turn.
+func (t *Table) undoLastDraw(decidingPlayer string, events GameEventSink) error {
+	hand, err := t.Hand(decidingPlayer)
+	if err != nil {
+		return err
+	}
+
+	hand, err = hand.FindAndRemoveCard(t.LastDrawnCard)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCardNotInHand, err)
+	}
+
+	t.HandOfPlayer[decidingPlayer] = hand
+	t.DrawDeck = t.DrawDeck.Push(t.LastDrawnCard)
+	t.TableState = StartOfTurn
+	t.updateStats(decidingPlayer, func(stats *PlayerStats) { stats.CardsDrawn-- })
+
+	events.Emit(CardTransferEvent{
+		Source:            CardTransferNodePlayerHand,
+		Sink:              CardTransferNodeDeck,
+		SourcePlayer:      decidingPlayer,
+		Card:              t.LastDrawnCard,
+		IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
+	})
+
+	events.Emit(UndoEvent{
+		Player:            decidingPlayer,
+		ReturnedCard:      true,
+		IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
+	})
+
+	t.LastDrawnCard = Card{}
+	return nil
+}
+
+// A wild draw 4 may only be played when the player holds no card of the color
+// required at the time. Holding a card of the required number, or another wild,
+// does not make it illegal. Returns the cards of the wild draw 4 player that
+// make the play illegal, the challenge succeeds if there are any.
+func (t *Table) cardsMakingWildDraw4Illegal() Deck {
+	eligibleCards := NewEmptyDeck()
+	for _, card := range t.HandOfPlayer[t.PlayerOfLastTurn] {
+		if !card.IsWild() && card.Color == t.RequiredColorOfLastTurn {
+			eligibleCards = append(eligibleCards, card)
+		}
+	}
+	return eligibleCards
+}
+
+// Only the player who just played out their hand can win, and only if nobody
+// has won yet. Other players whose hands are empty at that point, say after a
+// penalty moved cards around, don't win.
+func (t *Table) checkIfPlayerHasWon(decidingPlayer string, lastCardDropped Card, events GameEventSink) (bool, error) {
+	if t.WinnerPlayerName != "" && t.WinnerPlayerName != decidingPlayer {
+		return false, nil
+	}
+
+	hand, err := t.Hand(decidingPlayer)
+	if err != nil {
+		decision := PlayerDecision{Kind: PlayerDecisionPlayHandCard, ResultCard: lastCardDropped}
+		return false, &EvalDecisionError{Decision: decision, Reason: err}
+	}
+
 	if hand.Len() == 0 {
-		gameEventPushChan <- PlayerHasWonEvent{
+		events.Emit(PlayerHasWonEvent{
 			Player:            decidingPlayer,
 			IsFromLocalClient: decidingPlayer == t.LocalPlayerName,
-		}
+			Stats:             t.Stats(),
+		})
 
 		t.WinnerPlayerName = decidingPlayer
-		return true
+		t.TableState = HaveWinner
+		return true, nil
 	}
 
-	return false
+	return false, nil
 }
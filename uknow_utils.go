@@ -5,24 +5,67 @@ import (
 	"log"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"sync"
 )
 
-func CreateFileLogger(setAsDefault bool, playerName string) *log.Logger {
-	fileName := fmt.Sprintf("/tmp/%s_log.txt", playerName)
+var openedLogFilesMutex sync.Mutex
+var openedLogFiles = make(map[string]bool)
+
+// Creates a logger writing to <logDir>/<name>_log.txt, truncating the file if it
+// exists. logDir defaults to os.TempDir(). If the process already opened a log
+// file of the same name, e.g. when tests run clients sharing a player name, a
+// numeric suffix is added so that the loggers don't write to the same file.
+func CreateFileLogger(setAsDefault bool, logDir, name string) (*log.Logger, error) {
+	fileName := uniqueLogFileName(logDir, name)
+
 	f, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
 	if err != nil {
-		log.Fatalf("Failed to open/create log file: %s", fileName)
+		return nil, fmt.Errorf("failed to open/create log file %s: %w", fileName, err)
 	}
 
 	if setAsDefault {
 		log.SetOutput(f)
-		return log.Default()
+		return log.Default(), nil
 	} else {
-		return log.New(f, playerName+"|", log.Ltime|log.Lshortfile)
+		return log.New(f, name+"|", log.Ltime|log.Lshortfile), nil
+	}
+}
+
+func uniqueLogFileName(logDir, name string) string {
+	if logDir == "" {
+		logDir = os.TempDir()
+	}
+
+	openedLogFilesMutex.Lock()
+	defer openedLogFilesMutex.Unlock()
+
+	fileName := filepath.Join(logDir, fmt.Sprintf("%s_log.txt", name))
+	for i := 2; openedLogFiles[fileName]; i++ {
+		fileName = filepath.Join(logDir, fmt.Sprintf("%s_%d_log.txt", name, i))
+	}
+	openedLogFiles[fileName] = true
+	return fileName
+}
+
+// Same as CreateFileLogger, but falls back to the default logger if the log
+// file cannot be created.
+func CreateFileLoggerOrDefault(logDir, name string) *log.Logger {
+	logger, err := CreateFileLogger(false, logDir, name)
+	if err != nil {
+		log.Printf("%v, logging to default logger instead", err)
+		return log.Default()
 	}
+	return logger
 }
 
 func ShuffleIntRange(start, end int) []int {
+	return ShuffleIntRangeWithRand(nil, start, end)
+}
+
+// Same as ShuffleIntRange but uses the given rng. Uses the global source if rng
+// is nil.
+func ShuffleIntRangeWithRand(rng *rand.Rand, start, end int) []int {
 	if end < start {
 		panic(fmt.Errorf("end > start (%d > %d)", end, start))
 	}
@@ -36,7 +79,12 @@ func ShuffleIntRange(start, end int) []int {
 	}
 
 	for end := len(slice); end > 0; end-- {
-		randomIndex := rand.Intn(end)
+		var randomIndex int
+		if rng != nil {
+			randomIndex = rng.Intn(end)
+		} else {
+			randomIndex = rand.Intn(end)
+		}
 		slice[randomIndex], slice[end-1] = slice[end-1], slice[randomIndex]
 	}
 